@@ -0,0 +1,159 @@
+package app
+
+import (
+	"fmt"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config holds the settings read from the environment at startup
+type Config struct {
+	// RequireIfMatch rejects PUT and DELETE requests without an If-Match header
+	RequireIfMatch bool
+	// SoftDeleteRetention is how long deleted records can be restored before they are purged
+	SoftDeleteRetention time.Duration
+	// WebhookMaxAttempts is how often a webhook delivery is tried before it is dead-lettered
+	WebhookMaxAttempts int
+	// WebhookRetryBackoff is the delay before the first retry of a delivery; it doubles after every failure
+	WebhookRetryBackoff time.Duration
+	// WebhookTimeout bounds a single delivery attempt
+	WebhookTimeout time.Duration
+	// EventStreamBuffer is how many recent changes are kept for stream clients resuming with Last-Event-ID
+	EventStreamBuffer int
+	// IdempotencyTTL is how long the response to a POST with an Idempotency-Key is replayed to retries
+	IdempotencyTTL time.Duration
+	// ServiceName identifies the application in traces
+	ServiceName string
+	// TracingExporter selects where spans go: "otlp", "stdout" for local debugging, or "none"
+	TracingExporter string
+	// TracingEndpoint is the OTLP/HTTP collector URL spans are sent to by the otlp exporter
+	TracingEndpoint string
+	// LogLevel is the minimum level logged at startup: debug, info, warn or error; it can be changed
+	// while running through /api/admin/log-level
+	LogLevel string
+	// SlowQueryThreshold is the duration above which a database statement is logged as a warning
+	SlowQueryThreshold time.Duration
+	// HealthCheckTimeout bounds the dependency checks behind /readyz and /health
+	HealthCheckTimeout time.Duration
+	// ShutdownTimeout is how long a stopping server waits for requests in flight and background jobs
+	ShutdownTimeout time.Duration
+	// RateLimits are the token buckets of each route group below /api, e.g. "products", each client
+	// getting its own; groups without an entry fall back to "default"
+	RateLimits map[string]domain.RateLimit
+	// CacheSize is how many products the catalog cache holds; zero disables caching
+	CacheSize int
+	// CacheTTL is how long a cached product or category list is served before it is read again
+	CacheTTL time.Duration
+	// CORSAllowOrigins are the comma separated origins, e.g. of the web back office, allowed to call the
+	// API from a browser; empty disables CORS
+	CORSAllowOrigins string
+	// HSTSMaxAge is how long browsers keep to HTTPS after a response sent over it; zero sends no HSTS header
+	HSTSMaxAge time.Duration
+	// BodyLimit is the largest request body accepted, in bytes
+	BodyLimit int
+	// ImportBodyLimit is the largest file accepted by the product import, in bytes
+	ImportBodyLimit int
+	// OpenAPIValidation rejects API requests that do not match /openapi.json and, while logging at debug,
+	// logs responses that do not either
+	OpenAPIValidation bool
+}
+
+// LoadConfig reads the configuration from environment variables, falling back to defaults
+func LoadConfig() Config {
+	return Config{
+		RequireIfMatch:      envBool("REQUIRE_IF_MATCH", false),
+		SoftDeleteRetention: envDuration("SOFT_DELETE_RETENTION", 30*24*time.Hour),
+		WebhookMaxAttempts:  envInt("WEBHOOK_MAX_ATTEMPTS", 8),
+		WebhookRetryBackoff: envDuration("WEBHOOK_RETRY_BACKOFF", 30*time.Second),
+		WebhookTimeout:      envDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+		EventStreamBuffer:   envInt("EVENT_STREAM_BUFFER", 1000),
+		IdempotencyTTL:      envDuration("IDEMPOTENCY_TTL", 24*time.Hour),
+		ServiceName:         envString("OTEL_SERVICE_NAME", "go-restful-api"),
+		TracingExporter:     envString("TRACING_EXPORTER", "none"),
+		TracingEndpoint:     envString("OTEL_EXPORTER_OTLP_ENDPOINT", "http://localhost:4318"),
+		LogLevel:            envString("LOG_LEVEL", "info"),
+		SlowQueryThreshold:  envDuration("DB_SLOW_QUERY_THRESHOLD", 200*time.Millisecond),
+		HealthCheckTimeout:  envDuration("HEALTH_CHECK_TIMEOUT", 2*time.Second),
+		ShutdownTimeout:     envDuration("SHUTDOWN_TIMEOUT", 20*time.Second),
+		RateLimits:          envRateLimits("RATE_LIMITS", "default=600/1m,products=300/1m"),
+		CacheSize:           envInt("CACHE_SIZE", 10000),
+		CacheTTL:            envDuration("CACHE_TTL", 5*time.Minute),
+		CORSAllowOrigins:    envString("CORS_ALLOW_ORIGINS", ""),
+		HSTSMaxAge:          envDuration("HSTS_MAX_AGE", 365*24*time.Hour),
+		BodyLimit:           envInt("BODY_LIMIT", 1<<20),
+		ImportBodyLimit:     envInt("IMPORT_BODY_LIMIT", 16<<20),
+		OpenAPIValidation:   envBool("OPENAPI_VALIDATION", false),
+	}
+}
+
+func envString(key string, fallback string) string {
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		return fallback
+	}
+	return value
+}
+
+func envBool(key string, fallback bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+func envInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+func envDuration(key string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// envRateLimits reads comma separated group=limit/period entries, e.g. "default=600/1m,products=300/1m"
+func envRateLimits(key string, fallback string) map[string]domain.RateLimit {
+	limits, err := parseRateLimits(os.Getenv(key))
+	if err != nil || len(limits) == 0 {
+		limits, _ = parseRateLimits(fallback)
+	}
+	return limits
+}
+
+func parseRateLimits(value string) (map[string]domain.RateLimit, error) {
+	limits := make(map[string]domain.RateLimit)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		group, rate, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("rate limit %q has no group", entry)
+		}
+		count, period, ok := strings.Cut(rate, "/")
+		if !ok {
+			return nil, fmt.Errorf("rate limit %q has no period", entry)
+		}
+		limit, err := strconv.Atoi(count)
+		if err != nil || limit <= 0 {
+			return nil, fmt.Errorf("rate limit %q has an invalid limit", entry)
+		}
+		duration, err := time.ParseDuration(period)
+		if err != nil || duration <= 0 {
+			return nil, fmt.Errorf("rate limit %q has an invalid period", entry)
+		}
+		limits[strings.TrimSpace(group)] = domain.RateLimit{Limit: limit, Period: duration}
+	}
+	return limits, nil
+}
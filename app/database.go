@@ -1,26 +1,30 @@
 package app
 
 import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
-	"log"
+	"log/slog"
+	"os"
 	"time"
 )
 
 // NewDB initializes the database connection using GORM
-func NewDB() *gorm.DB {
+func NewDB(config Config) *gorm.DB {
 	dsn := "root:password.@tcp(localhost:3306)/sample_pos_db?charset=utf8mb4&parseTime=True&loc=Local"
 	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info), // Logging SQL queries
+		Logger: NewGormLogger(slog.Default(), config.SlowQueryThreshold), // SQL at debug level, slow queries as warnings
 	})
 	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		slog.Error("failed to connect to database", "error", err)
+		os.Exit(1)
 	}
 
 	sqlDB, err := db.DB()
 	if err != nil {
-		log.Fatalf("Failed to get database instance: %v", err)
+		slog.Error("failed to get database instance", "error", err)
+		os.Exit(1)
 	}
 
 	// Set database connection pool settings
@@ -29,6 +33,9 @@ func NewDB() *gorm.DB {
 	sqlDB.SetConnMaxLifetime(60 * time.Minute)
 	sqlDB.SetConnMaxIdleTime(10 * time.Minute)
 
-	log.Println("Database connected successfully!")
+	// Export the pool statistics (open, in use, wait count and duration) on /metrics
+	prometheus.MustRegister(collectors.NewDBStatsCollector(sqlDB, "sample_pos_db"))
+
+	slog.Info("database connected")
 	return db
 }
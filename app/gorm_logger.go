@@ -0,0 +1,66 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"log/slog"
+	"time"
+)
+
+// GormLogger writes GORM's output through slog: failed statements as errors, statements slower
+// than SlowThreshold as warnings and every other statement at debug level. The level is taken
+// from the slog logger, so GORM's own LogMode is ignored.
+type GormLogger struct {
+	Logger        *slog.Logger
+	SlowThreshold time.Duration
+}
+
+func NewGormLogger(slogLogger *slog.Logger, slowThreshold time.Duration) logger.Interface {
+	return &GormLogger{
+		Logger:        slogLogger,
+		SlowThreshold: slowThreshold,
+	}
+}
+
+func (gormLogger *GormLogger) LogMode(logger.LogLevel) logger.Interface {
+	return gormLogger
+}
+
+func (gormLogger *GormLogger) Info(ctx context.Context, message string, data ...interface{}) {
+	gormLogger.Logger.InfoContext(ctx, fmt.Sprintf(message, data...))
+}
+
+func (gormLogger *GormLogger) Warn(ctx context.Context, message string, data ...interface{}) {
+	gormLogger.Logger.WarnContext(ctx, fmt.Sprintf(message, data...))
+}
+
+func (gormLogger *GormLogger) Error(ctx context.Context, message string, data ...interface{}) {
+	gormLogger.Logger.ErrorContext(ctx, fmt.Sprintf(message, data...))
+}
+
+func (gormLogger *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	elapsed := time.Since(begin)
+	slow := gormLogger.SlowThreshold > 0 && elapsed > gormLogger.SlowThreshold
+	failed := err != nil && !errors.Is(err, gorm.ErrRecordNotFound)
+	if !failed && !slow && !gormLogger.Logger.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+
+	sql, rows := fc()
+	attrs := []any{
+		slog.String("sql", sql),
+		slog.Int64("rows", rows),
+		slog.Float64("duration_ms", float64(elapsed.Microseconds())/1000),
+	}
+	switch {
+	case failed:
+		gormLogger.Logger.ErrorContext(ctx, "query failed", append(attrs, slog.String("error", err.Error()))...)
+	case slow:
+		gormLogger.Logger.WarnContext(ctx, "slow query", append(attrs, slog.Duration("threshold", gormLogger.SlowThreshold))...)
+	default:
+		gormLogger.Logger.DebugContext(ctx, "query", attrs...)
+	}
+}
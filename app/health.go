@@ -0,0 +1,49 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"gorm.io/gorm"
+	"strings"
+	"sync/atomic"
+)
+
+// DatabaseCheck pings the database through the connection pool of db
+func DatabaseCheck(db *gorm.DB) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.PingContext(ctx)
+	}
+}
+
+// MigrationCheck fails while the schema is behind the models, e.g. when an instance of a newer
+// version has not migrated yet. Once the schema is up to date it stays so for this binary, so
+// the comparison is no longer repeated on every probe
+func MigrationCheck(db *gorm.DB) func(ctx context.Context) error {
+	return migrationCheck(func(ctx context.Context) ([]string, error) {
+		return PendingMigrations(ctx, db)
+	})
+}
+
+// migrationCheck runs pending until it reports none and remembers that afterwards
+func migrationCheck(pending func(ctx context.Context) ([]string, error)) func(ctx context.Context) error {
+	var upToDate atomic.Bool
+	return func(ctx context.Context) error {
+		if upToDate.Load() {
+			return nil
+		}
+
+		migrations, err := pending(ctx)
+		if err != nil {
+			return err
+		}
+		if len(migrations) > 0 {
+			return fmt.Errorf("%d pending migrations: %s", len(migrations), strings.Join(migrations, ", "))
+		}
+		upToDate.Store(true)
+		return nil
+	}
+}
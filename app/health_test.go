@@ -0,0 +1,32 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMigrationCheckCachesUpToDateSchema(t *testing.T) {
+	calls := 0
+	results := [][]string{{"products.barcode"}, nil}
+	var lookupErr error
+	check := migrationCheck(func(ctx context.Context) ([]string, error) {
+		calls++
+		if lookupErr != nil {
+			return nil, lookupErr
+		}
+		pending := results[0]
+		results = results[1:]
+		return pending, nil
+	})
+
+	lookupErr = errors.New("connection refused")
+	assert.ErrorIs(t, check(context.Background()), lookupErr)
+	lookupErr = nil
+
+	assert.EqualError(t, check(context.Background()), "1 pending migrations: products.barcode")
+	assert.NoError(t, check(context.Background()))
+	assert.NoError(t, check(context.Background()))
+	assert.Equal(t, 3, calls)
+}
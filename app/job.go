@@ -0,0 +1,59 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Job is a background task that runs on a fixed interval
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// StartJobs runs every job in its own goroutine until ctx is cancelled.
+// A run in progress is not interrupted by the cancellation; wait on the returned WaitGroup to let it finish.
+func StartJobs(ctx context.Context, jobs ...Job) *sync.WaitGroup {
+	runCtx := context.WithoutCancel(ctx)
+	wg := &sync.WaitGroup{}
+	for _, job := range jobs {
+		wg.Add(1)
+		go func(job Job) {
+			defer wg.Done()
+
+			ticker := time.NewTicker(job.Interval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					if err := job.Run(runCtx); err != nil {
+						slog.ErrorContext(runCtx, "job failed", "job", job.Name, "error", err)
+					}
+				}
+			}
+		}(job)
+	}
+	return wg
+}
+
+// WaitJobs waits for the runs in progress of the jobs behind wg, giving up when ctx is done
+func WaitJobs(ctx context.Context, wg *sync.WaitGroup) error {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
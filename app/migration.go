@@ -0,0 +1,124 @@
+package app
+
+import (
+	"context"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"gorm.io/gorm"
+	"reflect"
+)
+
+// LegacyCurrency is assigned to prices stored before amounts carried a currency
+const LegacyCurrency = "IDR"
+
+// migratedModels are the models whose tables Migrate creates and keeps up to date
+var migratedModels = []interface{}{
+	&domain.Category{},
+	&domain.Customer{},
+	&domain.Product{},
+	&domain.ProductBarcode{},
+	&domain.ProductOption{},
+	&domain.ProductVariant{},
+	&domain.ProductPrice{},
+	&domain.Employee{},
+	&domain.AuditLog{},
+	&domain.OutboxEvent{},
+	&domain.Webhook{},
+	&domain.WebhookDelivery{},
+	&domain.IdempotencyKey{},
+}
+
+// legacyMoneyColumn is a float64 price column replaced by a money.Money
+type legacyMoneyColumn struct {
+	Model  interface{}
+	Column string
+	Prefix string
+}
+
+var legacyMoneyColumns = []legacyMoneyColumn{
+	{Model: &domain.Product{}, Column: "product_price", Prefix: "product_price_"},
+	{Model: &domain.ProductPrice{}, Column: "price", Prefix: "price_"},
+}
+
+// Migrate creates or updates all tables and converts columns left over from older schemas
+func Migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(migratedModels...); err != nil {
+		return err
+	}
+
+	for _, legacy := range legacyMoneyColumns {
+		if err := migrateLegacyMoneyColumn(db, legacy.Model, legacy.Column, legacy.Prefix); err != nil {
+			return err
+		}
+	}
+	return normalizeBarcodes(db)
+}
+
+// PendingMigrations lists the tables and columns Migrate would still create, and the legacy columns
+// it would still convert; an empty list means the schema is up to date
+func PendingMigrations(ctx context.Context, db *gorm.DB) ([]string, error) {
+	db = db.WithContext(ctx)
+	migrator := db.Migrator()
+
+	var pending []string
+	for _, model := range migratedModels {
+		statement := &gorm.Statement{DB: db}
+		if err := statement.Parse(model); err != nil {
+			return nil, err
+		}
+		table := statement.Schema.Table
+		if !migrator.HasTable(table) {
+			pending = append(pending, "create table "+table)
+			continue
+		}
+
+		columnTypes, err := migrator.ColumnTypes(model)
+		if err != nil {
+			return nil, err
+		}
+		columns := make(map[string]bool, len(columnTypes))
+		for _, columnType := range columnTypes {
+			columns[columnType.Name()] = true
+		}
+		for _, field := range statement.Schema.Fields {
+			if field.DBName != "" && !field.IgnoreMigration && !columns[field.DBName] {
+				pending = append(pending, "add column "+table+"."+field.DBName)
+			}
+		}
+		for _, legacy := range legacyMoneyColumns {
+			if reflect.TypeOf(legacy.Model) == reflect.TypeOf(model) && columns[legacy.Column] {
+				pending = append(pending, "convert column "+table+"."+legacy.Column)
+			}
+		}
+	}
+	return pending, nil
+}
+
+// normalizeBarcodes rewrites UPC-A codes saved before barcodes were stored as EAN-13; a code whose
+// EAN-13 form is already registered is left alone, lookups find the EAN-13 row for it
+func normalizeBarcodes(db *gorm.DB) error {
+	return db.Exec("UPDATE IGNORE product_barcodes SET barcode_code = CONCAT('0', barcode_code) WHERE CHAR_LENGTH(barcode_code) = 12").Error
+}
+
+// migrateLegacyMoneyColumn copies a float64 price column into the DECIMAL amount and currency
+// columns of its money.Money replacement, then drops the old column
+func migrateLegacyMoneyColumn(db *gorm.DB, model interface{}, column string, prefix string) error {
+	migrator := db.Migrator()
+	if !migrator.HasColumn(model, column) {
+		return nil
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		return tx.Model(model).
+			Where(column + " IS NOT NULL").
+			Where("(" + prefix + "currency IS NULL OR " + prefix + "currency = '')").
+			Updates(map[string]interface{}{
+				prefix + "amount":   gorm.Expr("CAST(" + column + " AS DECIMAL(19,4))"),
+				prefix + "currency": LegacyCurrency,
+			}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	return migrator.DropColumn(model, column)
+}
@@ -0,0 +1,656 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/aronipurwanto/go-restful-api/helper"
+	"github.com/aronipurwanto/go-restful-api/middleware"
+	"github.com/aronipurwanto/go-restful-api/model/money"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/openapi"
+	"github.com/gofiber/fiber/v2"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// endpoint documents a route of NewRouter; its path parameters are read from the route itself
+type endpoint struct {
+	Summary     string
+	Tag         string
+	Public      bool                 // served without an API key
+	Parameters  []*openapi.Parameter // query and header parameters
+	Request     interface{}          // a JSON body, decoded into a value of this type
+	PathFields  []string             // properties of Request the handler takes from the path instead
+	RequestBody *openapi.RequestBody // a body that is not a JSON object, e.g. a file upload
+	Status      int                  // the success status, 200 when zero
+	Response    interface{}          // the data of the WebResponse envelope, nil when it is null
+	Content     map[string]*openapi.MediaType
+	Errors      []int                         // error statuses besides those derived from the route
+	Security    []openapi.SecurityRequirement // replaces the document's API key header
+	Manager     bool                          // only for an X-Employee-ID of a manager
+}
+
+var (
+	ifMatchParameter        = &openapi.Parameter{Ref: openapi.Ref("parameters", "IfMatch")}
+	ifNoneMatchParameter    = &openapi.Parameter{Ref: openapi.Ref("parameters", "IfNoneMatch")}
+	includeDeletedParameter = &openapi.Parameter{Ref: openapi.Ref("parameters", "IncludeDeleted")}
+	employeeIdParameter     = &openapi.Parameter{Ref: openapi.Ref("parameters", "EmployeeId")}
+	idempotencyKeyParameter = &openapi.Parameter{Ref: openapi.Ref("parameters", "IdempotencyKey")}
+)
+
+// browserSecurity lets EventSource and WebSocket clients, which cannot set headers, pass the key in the URL or a cookie
+var browserSecurity = []openapi.SecurityRequirement{{"ApiKey": {}}, {"ApiKeyQuery": {}}, {"ApiKeyCookie": {}}}
+
+// openAPITags group the operations, in the order documentation shows them
+var openAPITags = []openapi.Tag{
+	{Name: "Categories", Description: "Product categories, nested into a tree"},
+	{Name: "Products", Description: "Products with their barcodes, variants and scheduled prices"},
+	{Name: "Customers"},
+	{Name: "Employees"},
+	{Name: "Webhooks", Description: "Endpoints notified of changes, and their delivery log"},
+	{Name: "Events", Description: "Live change notifications"},
+	{Name: "Audit", Description: "Who changed what, and when"},
+	{Name: "Admin"},
+	{Name: "Operations", Description: "Health, metrics and this documentation; no API key needed"},
+}
+
+// openAPIEndpoints documents every route of NewRouter, keyed by method and OpenAPI path
+var openAPIEndpoints = map[string]endpoint{
+	"GET /metrics": {
+		Summary: "Prometheus metrics", Tag: "Operations", Public: true,
+		Content: map[string]*openapi.MediaType{"text/plain": {Schema: &openapi.Schema{Type: "string"}}},
+	},
+	"GET /healthz": {
+		Summary: "Liveness probe, up while the process serves requests", Tag: "Operations", Public: true,
+		Response: web.HealthResponse{},
+	},
+	"GET /readyz": {
+		Summary: "Readiness probe, up while every dependency is", Tag: "Operations", Public: true,
+		Response: web.HealthResponse{}, Errors: []int{http.StatusServiceUnavailable},
+	},
+	"GET /health": {
+		Summary: "Status and latency of every dependency check", Tag: "Operations", Public: true,
+		Response: web.HealthResponse{}, Errors: []int{http.StatusServiceUnavailable},
+	},
+	"GET /openapi.json": {
+		Summary: "This document", Tag: "Operations", Public: true,
+		Content: map[string]*openapi.MediaType{fiber.MIMEApplicationJSON: {Schema: &openapi.Schema{Type: "object"}}},
+	},
+	"GET /docs": {
+		Summary: "Interactive documentation of this API", Tag: "Operations", Public: true,
+		Content: map[string]*openapi.MediaType{fiber.MIMETextHTML: {Schema: &openapi.Schema{Type: "string"}}},
+	},
+
+	"GET /api/categories": {
+		Summary: "List categories", Tag: "Categories",
+		Parameters: []*openapi.Parameter{includeDeletedParameter},
+		Response:   []web.CategoryResponse{},
+	},
+	"GET /api/categories/tree": {
+		Summary: "List the category tree", Tag: "Categories",
+		Parameters: []*openapi.Parameter{withCountsParameter},
+		Response:   []web.CategoryResponse{},
+	},
+	"GET /api/categories/{categoryId}": {
+		Summary: "Get a category", Tag: "Categories",
+		Parameters: []*openapi.Parameter{includeDeletedParameter, ifNoneMatchParameter},
+		Response:   web.CategoryResponse{},
+	},
+	"GET /api/categories/{categoryId}/tree": {
+		Summary: "List the subtree of a category", Tag: "Categories",
+		Parameters: []*openapi.Parameter{withCountsParameter},
+		Response:   []web.CategoryResponse{},
+	},
+	"GET /api/categories/{categoryId}/products": {
+		Summary: "List the products of a category and of its subcategories", Tag: "Categories",
+		Response: []web.ProductResponse{},
+	},
+	"POST /api/categories": {
+		Summary: "Create a category", Tag: "Categories",
+		Request: web.CategoryCreateRequest{}, Status: http.StatusCreated, Response: web.CategoryResponse{},
+	},
+	"POST /api/categories/batch": {
+		Summary: "Create, update and delete categories in one request", Tag: "Categories",
+		Request: web.BatchRequest{}, Response: web.BatchResponse{},
+	},
+	"PUT /api/categories/{categoryId}": {
+		Summary: "Update a category", Tag: "Categories",
+		Parameters: []*openapi.Parameter{ifMatchParameter},
+		Request:    web.CategoryUpdateRequest{}, PathFields: []string{"id"}, Response: web.CategoryResponse{},
+	},
+	"PUT /api/categories/{categoryId}/move": {
+		Summary: "Move a category with its subtree under another parent", Tag: "Categories",
+		Parameters: []*openapi.Parameter{ifMatchParameter},
+		Request:    web.CategoryMoveRequest{}, Response: web.CategoryResponse{},
+	},
+	"PATCH /api/categories/{categoryId}": {
+		Summary: "Patch a category", Tag: "Categories",
+		Parameters:  []*openapi.Parameter{ifMatchParameter},
+		RequestBody: patchBody, Response: web.CategoryResponse{}, Errors: []int{http.StatusUnsupportedMediaType},
+	},
+	"DELETE /api/categories/{categoryId}": {
+		Summary: "Delete a category; it can be restored until it is purged", Tag: "Categories",
+		Parameters: []*openapi.Parameter{ifMatchParameter},
+	},
+	"POST /api/categories/{categoryId}/restore": {
+		Summary: "Restore a deleted category; managers only", Tag: "Categories",
+		Response: web.CategoryResponse{}, Manager: true,
+	},
+
+	"GET /api/products": {
+		Summary: "List products", Tag: "Products",
+		Parameters: []*openapi.Parameter{includeDeletedParameter},
+		Response:   []web.ProductResponse{},
+	},
+	"GET /api/products/lookup": {
+		Summary: "Find the product of a scanned barcode", Tag: "Products",
+		Parameters: []*openapi.Parameter{
+			queryParameter("code", &openapi.Schema{Type: "string"}, true, "The scanned barcode"),
+		},
+		Response: web.ProductLookupResponse{}, Errors: []int{http.StatusNotFound},
+	},
+	"GET /api/products/export": {
+		Summary: "Download products", Tag: "Products",
+		Parameters: append(exportParameters,
+			queryParameter("category_id", &openapi.Schema{Type: "integer", Format: "int64"}, false, "Only products of this category and its subcategories")),
+		Content: exportContent,
+	},
+	"GET /api/products/{productId}": {
+		Summary: "Get a product", Tag: "Products",
+		Parameters: []*openapi.Parameter{includeDeletedParameter, ifNoneMatchParameter},
+		Response:   web.ProductResponse{},
+	},
+	"POST /api/products": {
+		Summary: "Create a product", Tag: "Products",
+		Request: web.ProductCreateRequest{}, Status: http.StatusCreated, Response: web.ProductResponse{},
+	},
+	"POST /api/products/batch": {
+		Summary: "Create, update and delete products in one request", Tag: "Products",
+		Request: web.BatchRequest{}, Response: web.BatchResponse{},
+	},
+	"POST /api/products/import": {
+		Summary: "Create and update products from a CSV or XLSX file, matched by SKU", Tag: "Products",
+		Parameters: []*openapi.Parameter{
+			queryParameter("format", &openapi.Schema{Type: "string", Enum: []interface{}{"csv", "xlsx"}}, false, "Format of the file, taken from its extension when absent"),
+			queryParameter("dry_run", &openapi.Schema{Type: "boolean"}, false, "Check the file without saving anything"),
+		},
+		RequestBody: &openapi.RequestBody{
+			Required: true,
+			Content: map[string]*openapi.MediaType{fiber.MIMEMultipartForm: {Schema: &openapi.Schema{
+				Type:       "object",
+				Properties: map[string]*openapi.Schema{"file": {Type: "string", Format: "binary"}},
+				Required:   []string{"file"},
+			}}},
+		},
+		Response: web.ProductImportResponse{},
+	},
+	"PUT /api/products/{productId}": {
+		Summary: "Update a product", Tag: "Products",
+		Parameters: []*openapi.Parameter{ifMatchParameter},
+		Request:    web.ProductUpdateRequest{}, PathFields: []string{"id"}, Response: web.ProductResponse{},
+	},
+	"PATCH /api/products/{productId}": {
+		Summary: "Patch a product", Tag: "Products",
+		Parameters:  []*openapi.Parameter{ifMatchParameter},
+		RequestBody: patchBody, Response: web.ProductResponse{}, Errors: []int{http.StatusUnsupportedMediaType},
+	},
+	"DELETE /api/products/{productId}": {
+		Summary: "Delete a product; it can be restored until it is purged", Tag: "Products",
+		Parameters: []*openapi.Parameter{ifMatchParameter},
+	},
+	"POST /api/products/{productId}/restore": {
+		Summary: "Restore a deleted product; managers only", Tag: "Products",
+		Response: web.ProductResponse{}, Manager: true,
+	},
+	"GET /api/products/{productId}/variants": {
+		Summary: "List the variants of a product", Tag: "Products",
+		Response: []web.ProductVariantResponse{},
+	},
+	"POST /api/products/{productId}/variants": {
+		Summary: "Create a variant", Tag: "Products",
+		Request: web.ProductVariantCreateRequest{}, Status: http.StatusCreated, Response: web.ProductVariantResponse{},
+	},
+	"POST /api/products/{productId}/variants/generate": {
+		Summary: "Replace the options of a product and generate a variant for every combination", Tag: "Products",
+		Request: web.ProductVariantGenerateRequest{}, Response: []web.ProductVariantResponse{},
+	},
+	"PUT /api/products/{productId}/variants/{variantId}": {
+		Summary: "Update a variant", Tag: "Products",
+		Request: web.ProductVariantUpdateRequest{}, Response: web.ProductVariantResponse{},
+	},
+	"DELETE /api/products/{productId}/variants/{variantId}": {
+		Summary: "Delete a variant", Tag: "Products",
+	},
+	"GET /api/products/{productId}/prices": {
+		Summary: "List the applied and the pending prices of a product", Tag: "Products",
+		Response: web.ProductPriceHistoryResponse{},
+	},
+	"POST /api/products/{productId}/prices": {
+		Summary: "Schedule a price change", Tag: "Products",
+		Request: web.ProductPriceScheduleRequest{}, Status: http.StatusCreated, Response: web.ProductPriceResponse{},
+	},
+	"DELETE /api/products/{productId}/prices/{priceId}": {
+		Summary: "Cancel a pending price change", Tag: "Products",
+	},
+
+	"GET /api/employees": {
+		Summary: "List employees", Tag: "Employees",
+		Parameters: []*openapi.Parameter{includeDeletedParameter},
+		Response:   []web.EmployeeResponse{},
+	},
+	"GET /api/employees/export": {
+		Summary: "Download employees", Tag: "Employees",
+		Parameters: exportParameters, Content: exportContent,
+	},
+	"GET /api/employees/{employeeId}": {
+		Summary: "Get an employee", Tag: "Employees",
+		Parameters: []*openapi.Parameter{includeDeletedParameter, ifNoneMatchParameter},
+		Response:   web.EmployeeResponse{},
+	},
+	"POST /api/employees": {
+		Summary: "Create an employee", Tag: "Employees",
+		Request: web.EmployeeCreateRequest{}, Status: http.StatusCreated, Response: web.EmployeeResponse{},
+	},
+	"POST /api/employees/batch": {
+		Summary: "Create, update and delete employees in one request", Tag: "Employees",
+		Request: web.BatchRequest{}, Response: web.BatchResponse{},
+	},
+	"PUT /api/employees/{employeeId}": {
+		Summary: "Update an employee", Tag: "Employees",
+		Parameters: []*openapi.Parameter{ifMatchParameter},
+		Request:    web.EmployeeUpdateRequest{}, PathFields: []string{"id"}, Response: web.EmployeeResponse{},
+	},
+	"PATCH /api/employees/{employeeId}": {
+		Summary: "Patch an employee", Tag: "Employees",
+		Parameters:  []*openapi.Parameter{ifMatchParameter},
+		RequestBody: patchBody, Response: web.EmployeeResponse{}, Errors: []int{http.StatusUnsupportedMediaType},
+	},
+	"DELETE /api/employees/{employeeId}": {
+		Summary: "Delete an employee; it can be restored until it is purged", Tag: "Employees",
+		Parameters: []*openapi.Parameter{ifMatchParameter},
+	},
+	"POST /api/employees/{employeeId}/restore": {
+		Summary: "Restore a deleted employee; managers only", Tag: "Employees",
+		Response: web.EmployeeResponse{}, Manager: true,
+	},
+
+	"GET /api/customers": {
+		Summary: "List customers", Tag: "Customers",
+		Parameters: []*openapi.Parameter{includeDeletedParameter},
+		Response:   []web.CustomerResponse{},
+	},
+	"GET /api/customers/export": {
+		Summary: "Download customers", Tag: "Customers",
+		Parameters: exportParameters, Content: exportContent,
+	},
+	"GET /api/customers/{customerId}": {
+		Summary: "Get a customer", Tag: "Customers",
+		Parameters: []*openapi.Parameter{includeDeletedParameter, ifNoneMatchParameter},
+		Response:   web.CustomerResponse{},
+	},
+	"POST /api/customers": {
+		Summary: "Create a customer", Tag: "Customers",
+		Request: web.CustomerCreateRequest{}, Status: http.StatusCreated, Response: web.CustomerResponse{},
+	},
+	"POST /api/customers/batch": {
+		Summary: "Create, update and delete customers in one request", Tag: "Customers",
+		Request: web.BatchRequest{}, Response: web.BatchResponse{},
+	},
+	"PUT /api/customers/{customerId}": {
+		Summary: "Update a customer", Tag: "Customers",
+		Parameters: []*openapi.Parameter{ifMatchParameter},
+		Request:    web.CustomerUpdateRequest{}, PathFields: []string{"id"}, Response: web.CustomerResponse{},
+	},
+	"PATCH /api/customers/{customerId}": {
+		Summary: "Patch a customer", Tag: "Customers",
+		Parameters:  []*openapi.Parameter{ifMatchParameter},
+		RequestBody: patchBody, Response: web.CustomerResponse{}, Errors: []int{http.StatusUnsupportedMediaType},
+	},
+	"DELETE /api/customers/{customerId}": {
+		Summary: "Delete a customer; it can be restored until it is purged", Tag: "Customers",
+		Parameters: []*openapi.Parameter{ifMatchParameter},
+	},
+	"POST /api/customers/{customerId}/restore": {
+		Summary: "Restore a deleted customer; managers only", Tag: "Customers",
+		Response: web.CustomerResponse{}, Manager: true,
+	},
+
+	"GET /api/audit": {
+		Summary: "List audit log entries, newest first", Tag: "Audit",
+		Parameters: []*openapi.Parameter{
+			queryParameter("entity", &openapi.Schema{Type: "string"}, false, "Only changes of this kind of row"),
+			queryParameter("id", &openapi.Schema{Type: "integer", Format: "int64"}, false, "Only changes of this row; needs entity"),
+			queryParameter("actor_id", &openapi.Schema{Type: "integer", Format: "int64"}, false, "Only changes made by this employee"),
+			queryParameter("limit", &openapi.Schema{Type: "integer", Format: "int64"}, false, "Number of entries, 100 when absent"),
+		},
+		Response: []web.AuditLogResponse{},
+	},
+
+	"GET /api/events/stream": {
+		Summary: "Follow changes as server-sent events", Tag: "Events",
+		Parameters: eventStreamParameters, Security: browserSecurity,
+		Content: map[string]*openapi.MediaType{"text/event-stream": {Schema: &openapi.Schema{Type: "string"}}},
+	},
+	"GET /api/events/ws": {
+		Summary: "Follow changes over a WebSocket, one JSON message per change", Tag: "Events",
+		Parameters: eventStreamParameters, Security: browserSecurity, Status: http.StatusSwitchingProtocols,
+		Errors: []int{http.StatusUpgradeRequired},
+	},
+
+	"GET /api/admin/log-level": {
+		Summary: "Get the log level", Tag: "Admin",
+		Response: web.LogLevelResponse{},
+	},
+	"PUT /api/admin/log-level": {
+		Summary: "Change the log level until the next restart; managers only", Tag: "Admin",
+		Request: web.LogLevelRequest{}, Response: web.LogLevelResponse{}, Manager: true,
+	},
+
+	"GET /api/webhooks": {
+		Summary: "List webhooks", Tag: "Webhooks",
+		Response: []web.WebhookResponse{},
+	},
+	"GET /api/webhooks/{webhookId}": {
+		Summary: "Get a webhook", Tag: "Webhooks",
+		Response: web.WebhookResponse{},
+	},
+	"GET /api/webhooks/{webhookId}/deliveries": {
+		Summary: "List the deliveries of a webhook", Tag: "Webhooks",
+		Response: []web.WebhookDeliveryResponse{},
+	},
+	"POST /api/webhooks": {
+		Summary: "Create a webhook; the response is the only one showing its secret", Tag: "Webhooks",
+		Request: web.WebhookCreateRequest{}, Status: http.StatusCreated, Response: web.WebhookResponse{},
+	},
+	"POST /api/webhooks/{webhookId}/deliveries/{deliveryId}/retry": {
+		Summary: "Queue a delivery to be attempted again", Tag: "Webhooks",
+		Status: http.StatusAccepted, Response: web.WebhookDeliveryResponse{},
+	},
+	"PUT /api/webhooks/{webhookId}": {
+		Summary: "Update a webhook", Tag: "Webhooks",
+		Request: web.WebhookUpdateRequest{}, PathFields: []string{"id"}, Response: web.WebhookResponse{},
+	},
+	"DELETE /api/webhooks/{webhookId}": {
+		Summary: "Delete a webhook", Tag: "Webhooks",
+	},
+}
+
+var withCountsParameter = queryParameter("with_counts", &openapi.Schema{Type: "boolean"}, false, "Add the number of products of every category")
+
+var exportParameters = []*openapi.Parameter{
+	queryParameter("format", &openapi.Schema{Type: "string", Enum: []interface{}{"csv", "jsonl", "xlsx"}}, false, "File format, csv when absent"),
+	queryParameter("columns", &openapi.Schema{Type: "string"}, false, "Comma separated columns, all of them when absent"),
+}
+
+var exportContent = map[string]*openapi.MediaType{
+	helper.SpreadsheetContentType("csv"):   {Schema: &openapi.Schema{Type: "string"}},
+	helper.SpreadsheetContentType("jsonl"): {Schema: &openapi.Schema{Type: "string"}},
+	helper.SpreadsheetContentType("xlsx"):  {Schema: &openapi.Schema{Type: "string", Format: "binary"}},
+}
+
+var eventStreamParameters = []*openapi.Parameter{
+	queryParameter("topics", &openapi.Schema{Type: "string"}, false, "Comma separated topics out of category, customer, employee and product; all of them when absent"),
+	queryParameter("last_event_id", &openapi.Schema{Type: "integer", Format: "int64"}, false, "Resume after this event, like the Last-Event-ID header"),
+	{Name: "Last-Event-ID", In: "header", Description: "Resume after this event", Schema: &openapi.Schema{Type: "integer", Format: "int64"}},
+}
+
+var mergePatchSchema = &openapi.Schema{
+	Type:        "object",
+	Description: "The fields to change, as in the body of PUT (RFC 7396)",
+}
+
+var patchBody = &openapi.RequestBody{
+	Required: true,
+	Content: map[string]*openapi.MediaType{
+		helper.MergePatchContentType: {Schema: mergePatchSchema},
+		// read as a merge patch, for clients that cannot set another Content-Type
+		fiber.MIMEApplicationJSON: {Schema: mergePatchSchema},
+		helper.JSONPatchContentType: {Schema: &openapi.Schema{
+			Type:        "array",
+			Description: "The operations to apply (RFC 6902)",
+			Items: &openapi.Schema{
+				Type: "object",
+				Properties: map[string]*openapi.Schema{
+					"op":    {Type: "string", Enum: []interface{}{"add", "remove", "replace", "move", "copy", "test"}},
+					"path":  {Type: "string"},
+					"from":  {Type: "string"},
+					"value": {},
+				},
+				Required: []string{"op", "path"},
+			},
+		}},
+	},
+}
+
+func queryParameter(name string, schema *openapi.Schema, required bool, description string) *openapi.Parameter {
+	return &openapi.Parameter{Name: name, In: "query", Description: description, Required: required, Schema: schema}
+}
+
+// NewOpenAPI documents the routes registered on app so far with openAPIEndpoints and the model/web
+// types they read and answer with; it fails when a route has no entry or an entry no route
+func NewOpenAPI(app *fiber.App) (openapi.Document, error) {
+	schemas := openapi.NewSchemas()
+	schemas.Define(money.Amount(0), &openapi.Schema{
+		Description: `A decimal amount, written as a string, e.g. "12.50"; requests may send a JSON number as well`,
+		OneOf: []*openapi.Schema{
+			{Type: "string", Pattern: `^-?[0-9]+(\.[0-9]+)?$`},
+			{Type: "number"},
+		},
+	})
+	schemas.Define(time.Time{}, &openapi.Schema{Type: "string", Format: "date-time"})
+	schemas.Define(json.RawMessage{}, &openapi.Schema{})
+
+	document := openapi.Document{
+		OpenAPI: openapi.Version,
+		Info: openapi.Info{
+			Title:       "Product Management API",
+			Description: "Products, categories, customers and employees. Responses are wrapped in {code, status, data}.",
+			Version:     "1.0.0",
+		},
+		Servers:  []openapi.Server{{URL: "/", Description: "The server this document is served from"}},
+		Security: []openapi.SecurityRequirement{{"ApiKey": {}}},
+		Tags:     openAPITags,
+		Paths:    make(map[string]*openapi.PathItem),
+		Components: openapi.Components{
+			Responses: make(map[string]*openapi.Response),
+			Parameters: map[string]*openapi.Parameter{
+				"IfMatch":        {Name: fiber.HeaderIfMatch, In: "header", Description: "Only write when the row still has one of these ETags, or exists at all for *", Schema: &openapi.Schema{Type: "string"}},
+				"IfNoneMatch":    {Name: fiber.HeaderIfNoneMatch, In: "header", Description: "Answer 304 when the row still has one of these ETags", Schema: &openapi.Schema{Type: "string"}},
+				"IncludeDeleted": queryParameter("include_deleted", &openapi.Schema{Type: "boolean"}, false, "Show deleted rows as well; managers only"),
+				"EmployeeId":     {Name: "X-Employee-ID", In: "header", Description: "The employee acting through the API key, recorded in the audit log", Schema: &openapi.Schema{Type: "integer", Format: "int64"}},
+				"IdempotencyKey": {Name: middleware.IdempotencyKeyHeader, In: "header", Description: "Retries with the same key get the first response replayed", Schema: &openapi.Schema{Type: "string", MaxLength: intPointer(255)}},
+			},
+			SecuritySchemes: map[string]*openapi.SecurityScheme{
+				"ApiKey":       {Type: "apiKey", In: "header", Name: middleware.APIKeyHeader},
+				"ApiKeyQuery":  {Type: "apiKey", In: "query", Name: middleware.APIKeyQuery},
+				"ApiKeyCookie": {Type: "apiKey", In: "cookie", Name: middleware.APIKeyCookie},
+			},
+		},
+	}
+
+	documented := make(map[string]bool)
+	var undocumented []string
+	for _, route := range app.GetRoutes(true) {
+		if route.Method == fiber.MethodHead {
+			continue
+		}
+		path := openapi.Path(route.Path)
+		key := route.Method + " " + path
+		endpoint, ok := openAPIEndpoints[key]
+		if !ok {
+			undocumented = append(undocumented, key)
+			continue
+		}
+		documented[key] = true
+
+		item, ok := document.Paths[path]
+		if !ok {
+			item = &openapi.PathItem{}
+			document.Paths[path] = item
+		}
+		operation := endpoint.operation(route, schemas)
+		if !item.SetOperation(route.Method, operation) {
+			return openapi.Document{}, fmt.Errorf("openapi: %s has no operation field for its method", key)
+		}
+		for status, response := range operation.Responses {
+			if name, ok := strings.CutPrefix(response.Ref, openapi.Ref("responses", "")); ok {
+				code, _ := strconv.Atoi(status)
+				document.Components.Responses[name] = errorResponse(code)
+			}
+		}
+	}
+
+	for key := range openAPIEndpoints {
+		if !documented[key] {
+			undocumented = append(undocumented, key+" (no such route)")
+		}
+	}
+	if len(undocumented) > 0 {
+		sort.Strings(undocumented)
+		return openapi.Document{}, fmt.Errorf("openapi: routes and openAPIEndpoints differ: %s", strings.Join(undocumented, ", "))
+	}
+
+	document.Components.Schemas = schemas.Components()
+	document.Components.Schemas["ErrorResponse"] = &openapi.Schema{
+		Type: "object",
+		Properties: map[string]*openapi.Schema{
+			"code":   {Type: "integer", Format: "int64"},
+			"status": {Type: "string"},
+			"data":   {Type: "string", Nullable: true, Description: "What went wrong"},
+		},
+	}
+	return document, nil
+}
+
+func (endpoint endpoint) operation(route fiber.Route, schemas *openapi.Schemas) *openapi.Operation {
+	api := strings.HasPrefix(route.Path, "/api/")
+	operation := &openapi.Operation{
+		Tags:      []string{endpoint.Tag},
+		Summary:   endpoint.Summary,
+		Responses: make(map[string]*openapi.Response),
+	}
+	if endpoint.Public {
+		operation.Security = &[]openapi.SecurityRequirement{}
+	} else if endpoint.Security != nil {
+		operation.Security = &endpoint.Security
+	}
+
+	for _, name := range route.Params {
+		operation.Parameters = append(operation.Parameters, &openapi.Parameter{
+			Name: name, In: "path", Required: true,
+			Schema: &openapi.Schema{Type: "integer", Format: "int64", Minimum: floatPointer(1)},
+		})
+	}
+	operation.Parameters = append(operation.Parameters, endpoint.Parameters...)
+	if api {
+		operation.Parameters = append(operation.Parameters, employeeIdParameter)
+	}
+	if api && route.Method == fiber.MethodPost {
+		operation.Parameters = append(operation.Parameters, idempotencyKeyParameter)
+	}
+
+	operation.RequestBody = endpoint.RequestBody
+	if endpoint.Request != nil {
+		operation.RequestBody = &openapi.RequestBody{
+			Required: true,
+			Content: map[string]*openapi.MediaType{
+				fiber.MIMEApplicationJSON: {Schema: requestSchema(schemas, endpoint.Request, endpoint.PathFields)},
+			},
+		}
+	}
+
+	status := endpoint.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := &openapi.Response{Description: http.StatusText(status), Content: endpoint.Content}
+	if endpoint.Content == nil && status != http.StatusSwitchingProtocols {
+		envelope := &openapi.Schema{
+			Type: "object",
+			Properties: map[string]*openapi.Schema{
+				"code":   {Type: "integer", Format: "int64"},
+				"status": {Type: "string"},
+			},
+		}
+		if endpoint.Response != nil {
+			envelope.Properties["data"] = schemas.Of(endpoint.Response)
+		}
+		success.Content = map[string]*openapi.MediaType{fiber.MIMEApplicationJSON: {Schema: envelope}}
+	}
+	operation.Responses[strconv.Itoa(status)] = success
+
+	errors := slices.Clone(endpoint.Errors)
+	if api {
+		errors = append(errors, http.StatusUnauthorized, http.StatusTooManyRequests, http.StatusInternalServerError)
+	}
+	if len(route.Params) > 0 {
+		errors = append(errors, http.StatusBadRequest, http.StatusNotFound)
+	}
+	if operation.RequestBody != nil {
+		errors = append(errors, http.StatusBadRequest, http.StatusRequestEntityTooLarge)
+	}
+	if api && route.Method == fiber.MethodPost {
+		errors = append(errors, http.StatusBadRequest, http.StatusConflict, http.StatusUnprocessableEntity)
+	}
+	if endpoint.Manager || slices.Contains(endpoint.Parameters, includeDeletedParameter) {
+		errors = append(errors, http.StatusForbidden)
+	}
+	if slices.Contains(endpoint.Parameters, ifNoneMatchParameter) {
+		errors = append(errors, http.StatusNotModified)
+	}
+	if slices.Contains(endpoint.Parameters, ifMatchParameter) {
+		errors = append(errors, http.StatusPreconditionFailed, http.StatusPreconditionRequired)
+	}
+	for _, code := range errors {
+		operation.Responses[strconv.Itoa(code)] = &openapi.Response{Ref: openapi.Ref("responses", responseName(code))}
+	}
+	return operation
+}
+
+// requestSchema is the schema of a JSON body, without the properties the handler takes from the path
+func requestSchema(schemas *openapi.Schemas, request interface{}, pathFields []string) *openapi.Schema {
+	schema := schemas.Request(request)
+	if len(pathFields) == 0 {
+		return schema
+	}
+
+	component := *schemas.Components()[strings.TrimPrefix(schema.Ref, openapi.Ref("schemas", ""))]
+	component.Properties = make(map[string]*openapi.Schema)
+	for name, property := range schemas.Components()[strings.TrimPrefix(schema.Ref, openapi.Ref("schemas", ""))].Properties {
+		if !slices.Contains(pathFields, name) {
+			component.Properties[name] = property
+		}
+	}
+	component.Required = slices.DeleteFunc(slices.Clone(component.Required), func(name string) bool {
+		return slices.Contains(pathFields, name)
+	})
+	return &component
+}
+
+// errorResponse documents a status answered with an ErrorResponse, or with no body for 304
+func errorResponse(code int) *openapi.Response {
+	response := &openapi.Response{Description: http.StatusText(code)}
+	if code != http.StatusNotModified {
+		response.Content = map[string]*openapi.MediaType{
+			fiber.MIMEApplicationJSON: {Schema: &openapi.Schema{Ref: openapi.Ref("schemas", "ErrorResponse")}},
+		}
+	}
+	return response
+}
+
+// responseName names the response component of a status, e.g. NotFound
+func responseName(code int) string {
+	return strings.NewReplacer(" ", "", "-", "").Replace(http.StatusText(code))
+}
+
+func intPointer(value int) *int {
+	return &value
+}
+
+func floatPointer(value float64) *float64 {
+	return &value
+}
@@ -0,0 +1,130 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	controllerMocks "github.com/aronipurwanto/go-restful-api/controller/mocks"
+	repositoryMocks "github.com/aronipurwanto/go-restful-api/repository/mocks"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+// go test ./app -run TestOpenAPI -update rewrites the committed spec after routes or web types change
+var update = flag.Bool("update", false, "rewrite apispec.json from the router")
+
+const specFile = "../apispec.json"
+
+func setupTestAppOpenAPI(t *testing.T) *fiber.App {
+	ctrl := gomock.NewController(t)
+	app := NewServer(LoadConfig())
+	NewRouter(app, LoadConfig(),
+		repositoryMocks.NewMockEmployeeRepository(ctrl),
+		repositoryMocks.NewMockIdempotencyRepository(ctrl),
+		repositoryMocks.NewMockRateLimitStore(ctrl),
+		controllerMocks.NewMockCategoryController(ctrl),
+		controllerMocks.NewMockCustomerController(ctrl),
+		controllerMocks.NewMockProductController(ctrl),
+		controllerMocks.NewMockEmployeeController(ctrl),
+		controllerMocks.NewMockProductVariantController(ctrl),
+		controllerMocks.NewMockProductPriceController(ctrl),
+		controllerMocks.NewMockProductImportController(ctrl),
+		controllerMocks.NewMockExportController(ctrl),
+		controllerMocks.NewMockBatchController(ctrl),
+		controllerMocks.NewMockAuditController(ctrl),
+		controllerMocks.NewMockWebhookController(ctrl),
+		controllerMocks.NewMockEventStreamController(ctrl),
+		controllerMocks.NewMockAdminController(ctrl),
+		controllerMocks.NewMockHealthController(ctrl),
+	)
+	return app
+}
+
+func TestOpenAPI(t *testing.T) {
+	app := setupTestAppOpenAPI(t)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/openapi.json", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, fiber.MIMEApplicationJSONCharsetUTF8, resp.Header.Get("Content-Type"))
+	assert.Equal(t, "require-corp", resp.Header.Get("Cross-Origin-Embedder-Policy"))
+	spec, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	var document map[string]interface{}
+	require.NoError(t, json.Unmarshal(spec, &document))
+	assert.Equal(t, "3.0.3", document["openapi"])
+
+	if *update {
+		require.NoError(t, os.WriteFile(specFile, append(spec, '\n'), 0644))
+	}
+	committed, err := os.ReadFile(specFile)
+	require.NoError(t, err)
+	assert.True(t, bytes.Equal(append(spec, '\n'), committed),
+		"apispec.json is stale; run go test ./app -run TestOpenAPI -update and commit it")
+}
+
+func TestOpenAPIDocs(t *testing.T) {
+	app := setupTestAppOpenAPI(t)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/docs", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, fiber.MIMETextHTMLCharsetUTF8, resp.Header.Get("Content-Type"))
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `url: "/openapi.json"`)
+	assert.Equal(t, "unsafe-none", resp.Header.Get("Cross-Origin-Embedder-Policy"))
+	assert.Equal(t, "DENY", resp.Header.Get("X-Frame-Options"))
+	assert.Equal(t, "nosniff", resp.Header.Get("X-Content-Type-Options"))
+}
+
+func TestNewOpenAPIUndocumentedRoute(t *testing.T) {
+	app := fiber.New()
+	app.Get("/healthz", func(c *fiber.Ctx) error { return nil })
+	app.Get("/api/unknown/:unknownId", func(c *fiber.Ctx) error { return nil })
+
+	_, err := NewOpenAPI(app)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "GET /api/unknown/{unknownId}")
+	assert.Contains(t, err.Error(), "GET /readyz (no such route)")
+}
+
+// Bodies the controllers accept have to pass the validation middleware, or tags like json:"column:email" slip through
+func TestOpenAPIRequestBodies(t *testing.T) {
+	app := setupTestAppOpenAPI(t)
+	document, err := NewOpenAPI(app)
+	require.NoError(t, err)
+
+	tests := []struct {
+		method string
+		path   string
+		body   string
+	}{
+		{method: "POST", path: "/api/categories", body: `{"name":"Drinks","parent_id":null}`},
+		{method: "PUT", path: "/api/categories/2", body: `{"name":"Hot drinks","version":3}`},
+		{method: "POST", path: "/api/products", body: `{"name":"Tea","description":"Green","price":{"amount":"2.50","currency":"USD"},"stock_qty":10,"category":1,"sku":"TEA-1","tax_rate":0.1,"barcodes":[{"code":"12345678","pack_qty":1}]}`},
+		{method: "PUT", path: "/api/products/1", body: `{"name":"Tea","price":{"amount":2.5,"currency":"USD"},"stock_qty":10,"category_id":1,"sku":"TEA-1","tax_rate":0.1,"barcodes":null,"version":1}`},
+		{method: "POST", path: "/api/customers", body: `{"name":"Ann","email":"ann@example.com","phone":"555","address":"Main St","loyalty_points":5}`},
+		{method: "POST", path: "/api/employees", body: `{"name":"Bob","email":"bob@example.com","phone":"555","date_hired":"2024-01-02"}`},
+		{method: "POST", path: "/api/webhooks", body: `{"url":"https://example.com/hook","secret":"","events":["ProductCreated"]}`},
+		{method: "PATCH", path: "/api/products/1", body: `{"stock_qty":4}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			operation, _ := document.Find(tt.method, tt.path)
+			require.NotNil(t, operation)
+			schema, ok := document.RequestSchema(operation, fiber.MIMEApplicationJSON)
+			require.True(t, ok)
+			assert.Empty(t, document.ValidateJSON("body", schema, []byte(tt.body)))
+		})
+	}
+}
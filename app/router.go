@@ -1,45 +1,257 @@
 package app
 
 import (
+	"encoding/json"
 	"github.com/aronipurwanto/go-restful-api/controller"
+	"github.com/aronipurwanto/go-restful-api/helper"
 	"github.com/aronipurwanto/go-restful-api/middleware"
+	"github.com/aronipurwanto/go-restful-api/openapi"
+	"github.com/aronipurwanto/go-restful-api/repository"
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/gofiber/fiber/v2/middleware/compress"
+	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/gofiber/fiber/v2/middleware/helmet"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"log/slog"
+	"strings"
 )
 
+// corsAllowHeaders are the request headers a browser may send cross-origin
+var corsAllowHeaders = []string{
+	fiber.HeaderContentType,
+	fiber.HeaderIfMatch,
+	fiber.HeaderIfNoneMatch,
+	fiber.HeaderXRequestID,
+	"X-API-Key",
+	"X-Employee-ID",
+	middleware.IdempotencyKeyHeader,
+	"Last-Event-ID",
+}
+
+// corsExposeHeaders are the response headers scripts of an allowed origin may read
+var corsExposeHeaders = []string{
+	fiber.HeaderETag,
+	fiber.HeaderLocation,
+	fiber.HeaderXRequestID,
+	fiber.HeaderRetryAfter,
+	middleware.IdempotentReplayedHeader,
+	middleware.RateLimitLimitHeader,
+	middleware.RateLimitRemainingHeader,
+	middleware.RateLimitResetHeader,
+	middleware.RateLimitPolicyHeader,
+}
+
+// NewServer creates the Fiber app; it reads bodies up to the largest limit NewRouter allows on any route
+func NewServer(config Config) *fiber.App {
+	return fiber.New(fiber.Config{
+		BodyLimit: max(config.BodyLimit, config.ImportBodyLimit),
+	})
+}
+
 func NewRouter(app *fiber.App,
+	config Config,
+	employeeRepository repository.EmployeeRepository,
+	idempotencyRepository repository.IdempotencyRepository,
+	rateLimitStore repository.RateLimitStore,
 	categoryController controller.CategoryController,
 	customerController controller.CustomerController,
 	productController controller.ProductController,
-	employeeController controller.EmployeeController) {
-	authMiddleware := middleware.NewAuthMiddleware()
+	employeeController controller.EmployeeController,
+	productVariantController controller.ProductVariantController,
+	productPriceController controller.ProductPriceController,
+	productImportController controller.ProductImportController,
+	exportController controller.ExportController,
+	batchController controller.BatchController,
+	auditController controller.AuditController,
+	webhookController controller.WebhookController,
+	eventStreamController controller.EventStreamController,
+	adminController controller.AdminController,
+	healthController controller.HealthController) {
+	// browsers follow the event stream with EventSource or WebSocket, which cannot send the key header
+	authMiddleware := middleware.NewAuthMiddleware("/api/events/stream", "/api/events/ws")
+	preconditionMiddleware := middleware.NewPreconditionMiddleware(config.RequireIfMatch)
+	includeDeletedMiddleware := middleware.NewIncludeDeletedMiddleware(employeeRepository)
+	managerMiddleware := middleware.NewManagerMiddleware(employeeRepository)
+	idempotencyMiddleware := middleware.NewIdempotencyMiddleware(idempotencyRepository, config.IdempotencyTTL)
+	rateLimitMiddleware := middleware.NewRateLimitMiddleware(rateLimitStore, config.RateLimits)
+	bodyLimitMiddleware := middleware.NewBodyLimitMiddleware(config.BodyLimit, map[string]int{
+		"/products/import": config.ImportBodyLimit,
+	})
+	cacheControlMiddleware := middleware.NewCacheControlMiddleware(map[string]string{
+		// clients may keep responses but revalidate them, cheaply through ETag and If-None-Match
+		middleware.DefaultCacheControlGroup: "private, no-cache",
+		// webhook secrets, the audit trail, live events and admin settings are never kept
+		"webhooks": middleware.CacheControlNoStore,
+		"audit":    middleware.CacheControlNoStore,
+		"events":   middleware.CacheControlNoStore,
+		"admin":    middleware.CacheControlNoStore,
+	})
+
+	app.Use(middleware.NewRequestIdMiddleware())
+	app.Use(middleware.NewTracingMiddleware())
+	app.Use(middleware.NewLoggingMiddleware(slog.Default()))
+	app.Use(middleware.NewMetricsMiddleware())
+	securityHeaders := helmet.Config{
+		Next: func(c *fiber.Ctx) bool {
+			return c.Path() == "/docs"
+		},
+		XFrameOptions: "DENY",
+		HSTSMaxAge:    int(config.HSTSMaxAge.Seconds()),
+	}
+	// Swagger UI loads its scripts and styles from a CDN, which the embedder policy would block,
+	// so /docs gets the same headers with only that policy relaxed
+	docsSecurityHeaders := securityHeaders
+	docsSecurityHeaders.Next = func(c *fiber.Ctx) bool {
+		return c.Path() != "/docs"
+	}
+	docsSecurityHeaders.CrossOriginEmbedderPolicy = "unsafe-none"
+	app.Use(helmet.New(securityHeaders))
+	app.Use(helmet.New(docsSecurityHeaders))
+	if config.CORSAllowOrigins != "" {
+		// Answers preflight requests itself, before they reach the API key check
+		app.Use(cors.New(cors.Config{
+			AllowOrigins:  config.CORSAllowOrigins,
+			AllowHeaders:  strings.Join(corsAllowHeaders, ","),
+			ExposeHeaders: strings.Join(corsExposeHeaders, ","),
+			MaxAge:        600,
+		}))
+	}
+	app.Use(compress.New(compress.Config{
+		// Event streams are flushed event by event, which compression would hold back
+		Next: func(c *fiber.Ctx) bool {
+			return strings.HasPrefix(c.Path(), "/api/events/")
+		},
+	}))
+
+	// Scraped by Prometheus, so it sits outside the API key check
+	app.Get("/metrics", adaptor.HTTPHandler(promhttp.Handler()))
+
+	// Probed by the orchestrator, so they sit outside the API key check as well
+	app.Get("/healthz", healthController.Live)
+	app.Get("/readyz", healthController.Ready)
+	app.Get("/health", healthController.Health)
 
-	api := app.Group("/api", authMiddleware)
+	// Filled in once every route is registered; the validation middleware reads it per request
+	document := &openapi.Document{}
+	apiMiddlewares := []fiber.Handler{cacheControlMiddleware, bodyLimitMiddleware, authMiddleware, rateLimitMiddleware}
+	if config.OpenAPIValidation {
+		// after the key and rate checks, and before an idempotency key is claimed for a malformed request
+		apiMiddlewares = append(apiMiddlewares, middleware.NewOpenAPIValidationMiddleware(document, slog.Default()))
+	}
+	apiMiddlewares = append(apiMiddlewares, includeDeletedMiddleware, idempotencyMiddleware)
+
+	api := app.Group("/api", apiMiddlewares...)
 	categories := api.Group("/categories")
 	products := api.Group("/products")
 	employees := api.Group("/employees")
 	customers := api.Group("/customers")
 
 	categories.Get("/", categoryController.FindAll)
+	categories.Get("/tree", categoryController.FindTree)
 	categories.Get("/:categoryId", categoryController.FindById)
+	categories.Get("/:categoryId/tree", categoryController.FindTree)
+	categories.Get("/:categoryId/products", productController.FindByCategory)
 	categories.Post("/", categoryController.Create)
-	categories.Put("/:categoryId", categoryController.Update)
-	categories.Delete("/:categoryId", categoryController.Delete)
+	categories.Post("/batch", batchController.Categories)
+	categories.Put("/:categoryId", preconditionMiddleware, categoryController.Update)
+	categories.Put("/:categoryId/move", preconditionMiddleware, categoryController.Move)
+	categories.Patch("/:categoryId", preconditionMiddleware, categoryController.Patch)
+	categories.Delete("/:categoryId", preconditionMiddleware, categoryController.Delete)
+	categories.Post("/:categoryId/restore", managerMiddleware, categoryController.Restore)
 
 	products.Get("/", productController.FindAll)
+	products.Get("/lookup", productController.Lookup)
+	products.Get("/export", exportController.ExportProducts)
 	products.Get("/:productId", productController.FindById)
 	products.Post("/", productController.Create)
-	products.Put("/:productId", productController.Update)
-	products.Delete("/:productId", productController.Delete)
+	products.Post("/batch", batchController.Products)
+	products.Post("/import", productImportController.Import)
+	products.Put("/:productId", preconditionMiddleware, productController.Update)
+	products.Patch("/:productId", preconditionMiddleware, productController.Patch)
+	products.Delete("/:productId", preconditionMiddleware, productController.Delete)
+	products.Post("/:productId/restore", managerMiddleware, productController.Restore)
+
+	variants := products.Group("/:productId/variants")
+	variants.Get("/", productVariantController.FindAll)
+	variants.Post("/", productVariantController.Create)
+	variants.Post("/generate", productVariantController.Generate)
+	variants.Put("/:variantId", productVariantController.Update)
+	variants.Delete("/:variantId", productVariantController.Delete)
+
+	prices := products.Group("/:productId/prices")
+	prices.Get("/", productPriceController.FindAll)
+	prices.Post("/", productPriceController.Schedule)
+	prices.Delete("/:priceId", productPriceController.Cancel)
 
 	employees.Get("/", employeeController.FindAll)
+	employees.Get("/export", exportController.ExportEmployees)
 	employees.Get("/:employeeId", employeeController.FindById)
 	employees.Post("/", employeeController.Create)
-	employees.Put("/:employeeId", employeeController.Update)
-	employees.Delete("/:employeeId", employeeController.Delete)
+	employees.Post("/batch", batchController.Employees)
+	employees.Put("/:employeeId", preconditionMiddleware, employeeController.Update)
+	employees.Patch("/:employeeId", preconditionMiddleware, employeeController.Patch)
+	employees.Delete("/:employeeId", preconditionMiddleware, employeeController.Delete)
+	employees.Post("/:employeeId/restore", managerMiddleware, employeeController.Restore)
 
 	customers.Get("/", customerController.FindAll)
+	customers.Get("/export", exportController.ExportCustomers)
 	customers.Get("/:customerId", customerController.FindById)
 	customers.Post("/", customerController.Create)
-	customers.Put("/:customerId", customerController.Update)
-	customers.Delete("/:customerId", customerController.Delete)
+	customers.Post("/batch", batchController.Customers)
+	customers.Put("/:customerId", preconditionMiddleware, customerController.Update)
+	customers.Patch("/:customerId", preconditionMiddleware, customerController.Patch)
+	customers.Delete("/:customerId", preconditionMiddleware, customerController.Delete)
+	customers.Post("/:customerId/restore", managerMiddleware, customerController.Restore)
+
+	api.Get("/audit", auditController.FindAll)
+
+	api.Get("/events/stream", eventStreamController.Stream)
+	api.Get("/events/ws", eventStreamController.WebSocket)
+
+	api.Get("/admin/log-level", adminController.GetLogLevel)
+	api.Put("/admin/log-level", managerMiddleware, adminController.SetLogLevel)
+
+	webhooks := api.Group("/webhooks")
+	webhooks.Get("/", webhookController.FindAll)
+	webhooks.Get("/:webhookId", webhookController.FindById)
+	webhooks.Get("/:webhookId/deliveries", webhookController.FindDeliveries)
+	webhooks.Post("/", webhookController.Create)
+	webhooks.Post("/:webhookId/deliveries/:deliveryId/retry", webhookController.Redeliver)
+	webhooks.Put("/:webhookId", webhookController.Update)
+	webhooks.Delete("/:webhookId", webhookController.Delete)
+
+	// Documents every route above, so it is built last; the docs are public like the probes
+	var spec []byte
+	app.Get("/openapi.json", func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSONCharsetUTF8)
+		return c.Send(spec)
+	})
+	app.Get("/docs", func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+		return c.SendString(docsPage)
+	})
+	var err error
+	*document, err = NewOpenAPI(app)
+	helper.PanicIfError(err)
+	spec, err = json.MarshalIndent(document, "", "  ")
+	helper.PanicIfError(err)
 }
+
+// docsPage renders /openapi.json with Swagger UI
+const docsPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Product Management API</title>
+  <link rel="stylesheet" href="https://cdn.jsdelivr.net/npm/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://cdn.jsdelivr.net/npm/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    SwaggerUIBundle({url: "/openapi.json", dom_id: "#swagger-ui"});
+  </script>
+</body>
+</html>
+`
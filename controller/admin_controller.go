@@ -0,0 +1,10 @@
+package controller
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+type AdminController interface {
+	GetLogLevel(c *fiber.Ctx) error
+	SetLogLevel(c *fiber.Ctx) error
+}
@@ -0,0 +1,58 @@
+package controller
+
+import (
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/gofiber/fiber/v2"
+	"log/slog"
+	"strings"
+)
+
+type AdminControllerImpl struct {
+	LogLevel *slog.LevelVar
+}
+
+func NewAdminController(logLevel *slog.LevelVar) AdminController {
+	return &AdminControllerImpl{
+		LogLevel: logLevel,
+	}
+}
+
+// Get the current log level
+func (controller *AdminControllerImpl) GetLogLevel(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   web.LogLevelResponse{Level: strings.ToLower(controller.LogLevel.Level().String())},
+	})
+}
+
+// Set the log level of the running process: debug, info, warn or error
+func (controller *AdminControllerImpl) SetLogLevel(c *fiber.Ctx) error {
+	request := new(web.LogLevelRequest)
+	if err := c.BodyParser(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Bad Request",
+			Data:   err.Error(),
+		})
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(request.Level)); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Bad Request",
+			Data:   "level must be one of debug, info, warn or error",
+		})
+	}
+
+	previous := controller.LogLevel.Level()
+	controller.LogLevel.Set(level)
+	slog.WarnContext(c.Context(), "log level changed", "from", previous.String(), "to", level.String())
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   web.LogLevelResponse{Level: strings.ToLower(level.String())},
+	})
+}
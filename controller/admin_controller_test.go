@@ -0,0 +1,76 @@
+package controller
+
+import (
+	"encoding/json"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func setupTestAppAdmin(logLevel *slog.LevelVar) *fiber.App {
+	app := fiber.New()
+	adminController := NewAdminController(logLevel)
+
+	app.Get("/api/admin/log-level", adminController.GetLogLevel)
+	app.Put("/api/admin/log-level", adminController.SetLogLevel)
+
+	return app
+}
+
+func TestAdminController(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		body           string
+		expectedStatus int
+		expectedLevel  slog.Level
+	}{
+		{
+			name:           "Get log level",
+			method:         "GET",
+			expectedStatus: http.StatusOK,
+			expectedLevel:  slog.LevelInfo,
+		},
+		{
+			name:           "Set log level",
+			method:         "PUT",
+			body:           `{"level":"debug"}`,
+			expectedStatus: http.StatusOK,
+			expectedLevel:  slog.LevelDebug,
+		},
+		{
+			name:           "Set unknown log level",
+			method:         "PUT",
+			body:           `{"level":"verbose"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedLevel:  slog.LevelInfo,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logLevel := new(slog.LevelVar)
+			app := setupTestAppAdmin(logLevel)
+
+			req := httptest.NewRequest(tt.method, "/api/admin/log-level", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			assert.Equal(t, tt.expectedLevel, logLevel.Level())
+
+			if tt.expectedStatus == http.StatusOK {
+				var response struct {
+					Data web.LogLevelResponse `json:"data"`
+				}
+				assert.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+				assert.Equal(t, strings.ToLower(tt.expectedLevel.String()), response.Data.Level)
+			}
+		})
+	}
+}
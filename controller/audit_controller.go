@@ -0,0 +1,9 @@
+package controller
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+type AuditController interface {
+	FindAll(c *fiber.Ctx) error
+}
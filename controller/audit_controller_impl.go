@@ -0,0 +1,58 @@
+package controller
+
+import (
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/service"
+	"github.com/gofiber/fiber/v2"
+	"strconv"
+)
+
+type AuditControllerImpl struct {
+	AuditService service.AuditService
+}
+
+func NewAuditController(auditService service.AuditService) AuditController {
+	return &AuditControllerImpl{
+		AuditService: auditService,
+	}
+}
+
+// Find audit log entries filtered by ?entity=product&id=42, actor_id and limit
+func (controller *AuditControllerImpl) FindAll(c *fiber.Ctx) error {
+	request := web.AuditRequest{Entity: c.Query("entity")}
+	for name, target := range map[string]**uint64{"id": &request.Id, "actor_id": &request.ActorId} {
+		if value := c.Query(name); value != "" {
+			id, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+					Code:   fiber.StatusBadRequest,
+					Status: "Bad Request",
+					Data:   "invalid " + name + ": " + err.Error(),
+				})
+			}
+			*target = &id
+		}
+	}
+	if limit := c.Query("limit"); limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+				Code:   fiber.StatusBadRequest,
+				Status: "Bad Request",
+				Data:   "invalid limit: " + err.Error(),
+			})
+		}
+		request.Limit = value
+	}
+
+	logResponses, err := controller.AuditService.FindAll(c.Context(), request)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   logResponses,
+	})
+}
@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/service/mocks"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func setupTestAppAudit(mockService *mocks.MockAuditService) *fiber.App {
+	app := fiber.New()
+	auditController := NewAuditController(mockService)
+
+	app.Get("/api/audit", auditController.FindAll)
+
+	return app
+}
+
+func TestAuditController(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockAuditService(ctrl)
+	app := setupTestAppAudit(mockService)
+	productId := uint64(42)
+	actorId := uint64(7)
+
+	tests := []struct {
+		name           string
+		url            string
+		setupMock      func()
+		expectedStatus int
+	}{
+		{
+			name: "Find by entity and id",
+			url:  "/api/audit?entity=product&id=42",
+			setupMock: func() {
+				mockService.EXPECT().FindAll(gomock.Any(), web.AuditRequest{Entity: "product", Id: &productId}).
+					Return([]web.AuditLogResponse{{Id: 1, Entity: "product", EntityId: 42, Action: "update"}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "Find by actor with limit",
+			url:  "/api/audit?actor_id=7&limit=5",
+			setupMock: func() {
+				mockService.EXPECT().FindAll(gomock.Any(), web.AuditRequest{ActorId: &actorId, Limit: 5}).Return(nil, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Invalid id",
+			url:            "/api/audit?entity=product&id=abc",
+			setupMock:      func() {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Unknown entity",
+			url:  "/api/audit?entity=invoice",
+			setupMock: func() {
+				mockService.EXPECT().FindAll(gomock.Any(), gomock.Any()).Return(nil, exception.NewBadRequestError("invalid entity"))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+
+			resp, _ := app.Test(httptest.NewRequest("GET", tt.url, nil))
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+		})
+	}
+}
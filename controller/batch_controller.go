@@ -0,0 +1,12 @@
+package controller
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+type BatchController interface {
+	Products(c *fiber.Ctx) error
+	Customers(c *fiber.Ctx) error
+	Employees(c *fiber.Ctx) error
+	Categories(c *fiber.Ctx) error
+}
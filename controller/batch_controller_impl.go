@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"context"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/service"
+	"github.com/gofiber/fiber/v2"
+	"net/http"
+)
+
+type BatchControllerImpl struct {
+	BatchService service.BatchService
+}
+
+func NewBatchController(batchService service.BatchService) BatchController {
+	return &BatchControllerImpl{
+		BatchService: batchService,
+	}
+}
+
+// Batch of Product operations
+func (controller *BatchControllerImpl) Products(c *fiber.Ctx) error {
+	return batch(c, controller.BatchService.Products)
+}
+
+// Batch of Customer operations
+func (controller *BatchControllerImpl) Customers(c *fiber.Ctx) error {
+	return batch(c, controller.BatchService.Customers)
+}
+
+// Batch of Employee operations
+func (controller *BatchControllerImpl) Employees(c *fiber.Ctx) error {
+	return batch(c, controller.BatchService.Employees)
+}
+
+// Batch of Category operations
+func (controller *BatchControllerImpl) Categories(c *fiber.Ctx) error {
+	return batch(c, controller.BatchService.Categories)
+}
+
+func batch(c *fiber.Ctx, run func(ctx context.Context, request web.BatchRequest) (web.BatchResponse, error)) error {
+	batchRequest := new(web.BatchRequest)
+	if err := c.BodyParser(batchRequest); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Bad Request",
+			Data:   err.Error(),
+		})
+	}
+
+	batchResponse, err := run(c.Context(), *batchRequest)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	// A rolled back atomic batch answers with the status of the operation that failed
+	code := fiber.StatusOK
+	if batchResponse.Atomic && batchResponse.Failed > 0 {
+		for _, result := range batchResponse.Results {
+			if result.Status == "failed" {
+				code = result.Code
+			}
+		}
+	}
+
+	return c.Status(code).JSON(web.WebResponse{
+		Code:   code,
+		Status: http.StatusText(code),
+		Data:   batchResponse,
+	})
+}
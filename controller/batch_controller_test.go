@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/service/mocks"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func setupTestAppBatch(mockService *mocks.MockBatchService) *fiber.App {
+	app := fiber.New()
+	batchController := NewBatchController(mockService)
+
+	app.Post("/api/products/batch", batchController.Products)
+	app.Post("/api/categories/batch", batchController.Categories)
+
+	return app
+}
+
+func TestBatchController(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockBatchService(ctrl)
+	app := setupTestAppBatch(mockService)
+
+	tests := []struct {
+		name           string
+		url            string
+		body           string
+		setupMock      func()
+		expectedStatus int
+	}{
+		{
+			name: "Batch products - best effort with failures",
+			url:  "/api/products/batch",
+			body: `{"operations":[{"op":"delete","id":1},{"op":"delete","id":2}]}`,
+			setupMock: func() {
+				mockService.EXPECT().
+					Products(gomock.Any(), web.BatchRequest{Operations: []web.BatchOperation{{Op: "delete", Id: 1}, {Op: "delete", Id: 2}}}).
+					Return(web.BatchResponse{Succeeded: 1, Failed: 1, Results: []web.BatchResultResponse{
+						{Index: 0, Op: "delete", Id: 1, Code: http.StatusOK, Status: "deleted"},
+						{Index: 1, Op: "delete", Id: 2, Code: http.StatusNotFound, Status: "failed", Error: "Product not found"},
+					}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "Batch categories - atomic rolled back",
+			url:  "/api/categories/batch",
+			body: `{"atomic":true,"operations":[{"op":"delete","id":1},{"op":"delete","id":2}]}`,
+			setupMock: func() {
+				mockService.EXPECT().
+					Categories(gomock.Any(), gomock.Any()).
+					Return(web.BatchResponse{Atomic: true, Failed: 2, Results: []web.BatchResultResponse{
+						{Index: 0, Op: "delete", Id: 1, Code: http.StatusOK, Status: "rolled_back"},
+						{Index: 1, Op: "delete", Id: 2, Code: http.StatusNotFound, Status: "failed", Error: "Category not found"},
+					}}, nil)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Batch products - malformed body",
+			url:            "/api/products/batch",
+			body:           `{"operations":`,
+			setupMock:      func() {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+
+			req := httptest.NewRequest(http.MethodPost, tt.url, bytes.NewReader([]byte(tt.body)))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, _ := app.Test(req)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			var respBody web.WebResponse
+			json.NewDecoder(resp.Body).Decode(&respBody)
+			assert.Equal(t, tt.expectedStatus, respBody.Code)
+		})
+	}
+}
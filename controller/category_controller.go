@@ -7,7 +7,11 @@ import (
 type CategoryController interface {
 	Create(c *fiber.Ctx) error
 	Update(c *fiber.Ctx) error
+	Patch(c *fiber.Ctx) error
 	Delete(c *fiber.Ctx) error
+	Restore(c *fiber.Ctx) error
 	FindById(c *fiber.Ctx) error
 	FindAll(c *fiber.Ctx) error
+	FindTree(c *fiber.Ctx) error
+	Move(c *fiber.Ctx) error
 }
@@ -2,6 +2,7 @@ package controller
 
 import (
 	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/helper"
 	"github.com/aronipurwanto/go-restful-api/model/web"
 	"github.com/aronipurwanto/go-restful-api/service"
 	"github.com/gofiber/fiber/v2"
@@ -31,6 +32,13 @@ func (controller *CategoryControllerImpl) Create(c *fiber.Ctx) error {
 
 	categoryResponse, err := controller.CategoryService.Create(c.Context(), *categoryCreateRequest)
 	if err != nil {
+		if _, ok := err.(exception.NotFoundError); ok {
+			return c.Status(fiber.StatusNotFound).JSON(web.WebResponse{
+				Code:   fiber.StatusNotFound,
+				Status: "Not Found",
+				Data:   err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(web.WebResponse{
 			Code:   fiber.StatusInternalServerError,
 			Status: "Internal Server Error",
@@ -38,6 +46,8 @@ func (controller *CategoryControllerImpl) Create(c *fiber.Ctx) error {
 		})
 	}
 
+	c.Set(fiber.HeaderETag, helper.ETag(categoryResponse.Version))
+
 	return c.Status(fiber.StatusCreated).JSON(web.WebResponse{
 		Code:   fiber.StatusCreated,
 		Status: "Created",
@@ -66,22 +76,61 @@ func (controller *CategoryControllerImpl) Update(c *fiber.Ctx) error {
 	}
 	categoryUpdateRequest.Id = id
 
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	if version != 0 {
+		categoryUpdateRequest.Version = version
+	}
+
 	categoryResponse, err := controller.CategoryService.Update(c.Context(), *categoryUpdateRequest)
 	if err != nil {
-		if _, ok := err.(exception.NotFoundError); ok {
-			return c.Status(fiber.StatusNotFound).JSON(web.WebResponse{
-				Code:   fiber.StatusNotFound,
-				Status: "Not Found",
-				Data:   err.Error(),
-			})
-		}
-		return c.Status(fiber.StatusInternalServerError).JSON(web.WebResponse{
-			Code:   fiber.StatusInternalServerError,
-			Status: "Internal Server Error",
+		return errorResponse(c, err)
+	}
+
+	c.Set(fiber.HeaderETag, helper.ETag(categoryResponse.Version))
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   categoryResponse,
+	})
+}
+
+// Patch Category
+func (controller *CategoryControllerImpl) Patch(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("categoryId"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Invalid Category ID",
 			Data:   err.Error(),
 		})
 	}
 
+	contentType, ok := patchContentType(c)
+	if !ok {
+		return unsupportedPatch(c)
+	}
+
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	categoryResponse, err := controller.CategoryService.Patch(c.Context(), web.PatchRequest{
+		Id:          id,
+		ContentType: contentType,
+		Patch:       append([]byte(nil), c.Body()...),
+		Version:     version,
+	})
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	c.Set(fiber.HeaderETag, helper.ETag(categoryResponse.Version))
+
 	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
 		Code:   fiber.StatusOK,
 		Status: "OK",
@@ -100,25 +149,44 @@ func (controller *CategoryControllerImpl) Delete(c *fiber.Ctx) error {
 		})
 	}
 
-	err = controller.CategoryService.Delete(c.Context(), id)
+	version, err := ifMatchVersion(c)
 	if err != nil {
-		if _, ok := err.(exception.NotFoundError); ok {
-			return c.Status(fiber.StatusNotFound).JSON(web.WebResponse{
-				Code:   fiber.StatusNotFound,
-				Status: "Not Found",
-				Data:   err.Error(),
-			})
-		}
-		return c.Status(fiber.StatusInternalServerError).JSON(web.WebResponse{
-			Code:   fiber.StatusInternalServerError,
-			Status: "Internal Server Error",
+		return errorResponse(c, err)
+	}
+
+	err = controller.CategoryService.Delete(c.Context(), id, version)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "Deleted Successfully",
+	})
+}
+
+// Restore soft-deleted Category
+func (controller *CategoryControllerImpl) Restore(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("categoryId"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Invalid Category ID",
 			Data:   err.Error(),
 		})
 	}
 
+	categoryResponse, err := controller.CategoryService.Restore(c.Context(), id)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	c.Set(fiber.HeaderETag, helper.ETag(categoryResponse.Version))
+
 	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
 		Code:   fiber.StatusOK,
-		Status: "Deleted Successfully",
+		Status: "Restored Successfully",
+		Data:   categoryResponse,
 	})
 }
 
@@ -149,6 +217,10 @@ func (controller *CategoryControllerImpl) FindById(c *fiber.Ctx) error {
 		})
 	}
 
+	if notModified(c, categoryResponse.Version) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
 	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
 		Code:   fiber.StatusOK,
 		Status: "OK",
@@ -173,3 +245,84 @@ func (controller *CategoryControllerImpl) FindAll(c *fiber.Ctx) error {
 		Data:   categoryResponses,
 	})
 }
+
+// Find Category Tree, optionally rooted at a category and with product counts
+func (controller *CategoryControllerImpl) FindTree(c *fiber.Ctx) error {
+	var rootId *uint64
+	if c.Params("categoryId") != "" {
+		id, err := strconv.ParseUint(c.Params("categoryId"), 10, 64)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+				Code:   fiber.StatusBadRequest,
+				Status: "Invalid Category ID",
+				Data:   err.Error(),
+			})
+		}
+		rootId = &id
+	}
+
+	categoryResponses, err := controller.CategoryService.FindTree(c.Context(), rootId, c.QueryBool("with_counts"))
+	if err != nil {
+		if _, ok := err.(exception.NotFoundError); ok {
+			return c.Status(fiber.StatusNotFound).JSON(web.WebResponse{
+				Code:   fiber.StatusNotFound,
+				Status: "Not Found",
+				Data:   err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(web.WebResponse{
+			Code:   fiber.StatusInternalServerError,
+			Status: "Internal Server Error",
+			Data:   err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   categoryResponses,
+	})
+}
+
+// Move Category subtree under another parent
+func (controller *CategoryControllerImpl) Move(c *fiber.Ctx) error {
+	categoryMoveRequest := new(web.CategoryMoveRequest)
+	if err := c.BodyParser(categoryMoveRequest); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Bad Request",
+			Data:   err.Error(),
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("categoryId"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Invalid Category ID",
+			Data:   err.Error(),
+		})
+	}
+	categoryMoveRequest.Id = id
+
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	if version != 0 {
+		categoryMoveRequest.Version = version
+	}
+
+	categoryResponse, err := controller.CategoryService.Move(c.Context(), *categoryMoveRequest)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	c.Set(fiber.HeaderETag, helper.ETag(categoryResponse.Version))
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   categoryResponse,
+	})
+}
@@ -3,6 +3,7 @@ package controller
 import (
 	"bytes"
 	"encoding/json"
+	"github.com/aronipurwanto/go-restful-api/exception"
 	"github.com/aronipurwanto/go-restful-api/model/web"
 	"github.com/aronipurwanto/go-restful-api/service/mocks"
 	"github.com/gofiber/fiber/v2"
@@ -24,6 +25,7 @@ func setupTestAppCategory(mockService *mocks.MockCategoryService) *fiber.App {
 	categories.Delete("/:categoryId", categoryController.Delete)
 	categories.Get("/:categoryId", categoryController.FindById)
 	categories.Get("/", categoryController.FindAll)
+	categories.Put("/:categoryId/move", categoryController.Move)
 
 	return app
 }
@@ -61,6 +63,57 @@ func TestCategoryController(t *testing.T) {
 				Data:   web.CategoryResponse{Id: 1, Name: "Updated"},
 			},
 		},
+		{
+			name:   "Move category - success",
+			method: "PUT",
+			url:    "/api/categories/2/move",
+			body:   web.CategoryMoveRequest{},
+			setupMock: func() {
+				mockService.EXPECT().
+					Move(gomock.Any(), web.CategoryMoveRequest{Id: 2}).
+					Return(web.CategoryResponse{Id: 2, Name: "Drinks"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: web.WebResponse{
+				Code:   http.StatusOK,
+				Status: "OK",
+				Data:   web.CategoryResponse{Id: 2, Name: "Drinks"},
+			},
+		},
+		{
+			name:   "Move category - cycle",
+			method: "PUT",
+			url:    "/api/categories/1/move",
+			body:   web.CategoryMoveRequest{},
+			setupMock: func() {
+				mockService.EXPECT().
+					Move(gomock.Any(), gomock.Any()).
+					Return(web.CategoryResponse{}, exception.NewBadRequestError("Category cannot be moved below itself or its descendants"))
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: web.WebResponse{
+				Code:   http.StatusBadRequest,
+				Status: "Bad Request",
+				Data:   "Category cannot be moved below itself or its descendants",
+			},
+		},
+		{
+			name:   "Move category - modified since",
+			method: "PUT",
+			url:    "/api/categories/1/move",
+			body:   web.CategoryMoveRequest{Version: 3},
+			setupMock: func() {
+				mockService.EXPECT().
+					Move(gomock.Any(), web.CategoryMoveRequest{Id: 1, Version: 3}).
+					Return(web.CategoryResponse{}, exception.NewPreconditionFailedError("Category has been modified by another request"))
+			},
+			expectedStatus: http.StatusPreconditionFailed,
+			expectedBody: web.WebResponse{
+				Code:   http.StatusPreconditionFailed,
+				Status: "Precondition Failed",
+				Data:   "Category has been modified by another request",
+			},
+		},
 	}
 
 	for _, tt := range tests {
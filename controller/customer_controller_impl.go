@@ -2,6 +2,7 @@ package controller
 
 import (
 	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/helper"
 	"github.com/aronipurwanto/go-restful-api/model/web"
 	"github.com/aronipurwanto/go-restful-api/service"
 	"github.com/gofiber/fiber/v2"
@@ -38,6 +39,8 @@ func (controller *CustomerControllerImpl) Create(c *fiber.Ctx) error {
 		})
 	}
 
+	c.Set(fiber.HeaderETag, helper.ETag(customerResponse.Version))
+
 	return c.Status(fiber.StatusCreated).JSON(web.WebResponse{
 		Code:   fiber.StatusCreated,
 		Status: "Created",
@@ -66,22 +69,61 @@ func (controller *CustomerControllerImpl) Update(c *fiber.Ctx) error {
 	}
 	customerUpdateRequest.Id = id
 
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	if version != 0 {
+		customerUpdateRequest.Version = version
+	}
+
 	customerResponse, err := controller.CustomerService.Update(c.Context(), *customerUpdateRequest)
 	if err != nil {
-		if _, ok := err.(exception.NotFoundError); ok {
-			return c.Status(fiber.StatusNotFound).JSON(web.WebResponse{
-				Code:   fiber.StatusNotFound,
-				Status: "Not Found",
-				Data:   err.Error(),
-			})
-		}
-		return c.Status(fiber.StatusInternalServerError).JSON(web.WebResponse{
-			Code:   fiber.StatusInternalServerError,
-			Status: "Internal Server Error",
+		return errorResponse(c, err)
+	}
+
+	c.Set(fiber.HeaderETag, helper.ETag(customerResponse.Version))
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   customerResponse,
+	})
+}
+
+// Patch Customer
+func (controller *CustomerControllerImpl) Patch(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("customerId"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Invalid Customer ID",
 			Data:   err.Error(),
 		})
 	}
 
+	contentType, ok := patchContentType(c)
+	if !ok {
+		return unsupportedPatch(c)
+	}
+
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	customerResponse, err := controller.CustomerService.Patch(c.Context(), web.PatchRequest{
+		Id:          id,
+		ContentType: contentType,
+		Patch:       append([]byte(nil), c.Body()...),
+		Version:     version,
+	})
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	c.Set(fiber.HeaderETag, helper.ETag(customerResponse.Version))
+
 	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
 		Code:   fiber.StatusOK,
 		Status: "OK",
@@ -100,25 +142,44 @@ func (controller *CustomerControllerImpl) Delete(c *fiber.Ctx) error {
 		})
 	}
 
-	err = controller.CustomerService.Delete(c.Context(), id)
+	version, err := ifMatchVersion(c)
 	if err != nil {
-		if _, ok := err.(exception.NotFoundError); ok {
-			return c.Status(fiber.StatusNotFound).JSON(web.WebResponse{
-				Code:   fiber.StatusNotFound,
-				Status: "Not Found",
-				Data:   err.Error(),
-			})
-		}
-		return c.Status(fiber.StatusInternalServerError).JSON(web.WebResponse{
-			Code:   fiber.StatusInternalServerError,
-			Status: "Internal Server Error",
+		return errorResponse(c, err)
+	}
+
+	err = controller.CustomerService.Delete(c.Context(), id, version)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "Deleted Successfully",
+	})
+}
+
+// Restore soft-deleted Customer
+func (controller *CustomerControllerImpl) Restore(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("customerId"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Invalid Customer ID",
 			Data:   err.Error(),
 		})
 	}
 
+	customerResponse, err := controller.CustomerService.Restore(c.Context(), id)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	c.Set(fiber.HeaderETag, helper.ETag(customerResponse.Version))
+
 	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
 		Code:   fiber.StatusOK,
-		Status: "Deleted Successfully",
+		Status: "Restored Successfully",
+		Data:   customerResponse,
 	})
 }
 
@@ -149,6 +210,10 @@ func (controller *CustomerControllerImpl) FindById(c *fiber.Ctx) error {
 		})
 	}
 
+	if notModified(c, customerResponse.Version) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
 	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
 		Code:   fiber.StatusOK,
 		Status: "OK",
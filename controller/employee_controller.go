@@ -7,7 +7,9 @@ import (
 type EmployeeController interface {
 	Create(c *fiber.Ctx) error
 	Update(c *fiber.Ctx) error
+	Patch(c *fiber.Ctx) error
 	Delete(c *fiber.Ctx) error
+	Restore(c *fiber.Ctx) error
 	FindById(c *fiber.Ctx) error
 	FindAll(c *fiber.Ctx) error
 }
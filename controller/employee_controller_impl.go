@@ -2,6 +2,7 @@ package controller
 
 import (
 	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/helper"
 	"github.com/aronipurwanto/go-restful-api/model/web"
 	"github.com/aronipurwanto/go-restful-api/service"
 	"github.com/gofiber/fiber/v2"
@@ -38,6 +39,8 @@ func (controller *EmployeeControllerImpl) Create(c *fiber.Ctx) error {
 		})
 	}
 
+	c.Set(fiber.HeaderETag, helper.ETag(employeeResponse.Version))
+
 	return c.Status(fiber.StatusCreated).JSON(web.WebResponse{
 		Code:   fiber.StatusCreated,
 		Status: "Created",
@@ -66,22 +69,61 @@ func (controller *EmployeeControllerImpl) Update(c *fiber.Ctx) error {
 	}
 	employeeUpdateRequest.Id = id
 
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	if version != 0 {
+		employeeUpdateRequest.Version = version
+	}
+
 	employeeResponse, err := controller.EmployeeService.Update(c.Context(), *employeeUpdateRequest)
 	if err != nil {
-		if _, ok := err.(exception.NotFoundError); ok {
-			return c.Status(fiber.StatusNotFound).JSON(web.WebResponse{
-				Code:   fiber.StatusNotFound,
-				Status: "Not Found",
-				Data:   err.Error(),
-			})
-		}
-		return c.Status(fiber.StatusInternalServerError).JSON(web.WebResponse{
-			Code:   fiber.StatusInternalServerError,
-			Status: "Internal Server Error",
+		return errorResponse(c, err)
+	}
+
+	c.Set(fiber.HeaderETag, helper.ETag(employeeResponse.Version))
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   employeeResponse,
+	})
+}
+
+// Patch Employee
+func (controller *EmployeeControllerImpl) Patch(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("employeeId"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Invalid Employee ID",
 			Data:   err.Error(),
 		})
 	}
 
+	contentType, ok := patchContentType(c)
+	if !ok {
+		return unsupportedPatch(c)
+	}
+
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	employeeResponse, err := controller.EmployeeService.Patch(c.Context(), web.PatchRequest{
+		Id:          id,
+		ContentType: contentType,
+		Patch:       append([]byte(nil), c.Body()...),
+		Version:     version,
+	})
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	c.Set(fiber.HeaderETag, helper.ETag(employeeResponse.Version))
+
 	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
 		Code:   fiber.StatusOK,
 		Status: "OK",
@@ -100,25 +142,44 @@ func (controller *EmployeeControllerImpl) Delete(c *fiber.Ctx) error {
 		})
 	}
 
-	err = controller.EmployeeService.Delete(c.Context(), id)
+	version, err := ifMatchVersion(c)
 	if err != nil {
-		if _, ok := err.(exception.NotFoundError); ok {
-			return c.Status(fiber.StatusNotFound).JSON(web.WebResponse{
-				Code:   fiber.StatusNotFound,
-				Status: "Not Found",
-				Data:   err.Error(),
-			})
-		}
-		return c.Status(fiber.StatusInternalServerError).JSON(web.WebResponse{
-			Code:   fiber.StatusInternalServerError,
-			Status: "Internal Server Error",
+		return errorResponse(c, err)
+	}
+
+	err = controller.EmployeeService.Delete(c.Context(), id, version)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "Deleted Successfully",
+	})
+}
+
+// Restore soft-deleted Employee
+func (controller *EmployeeControllerImpl) Restore(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("employeeId"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Invalid Employee ID",
 			Data:   err.Error(),
 		})
 	}
 
+	employeeResponse, err := controller.EmployeeService.Restore(c.Context(), id)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	c.Set(fiber.HeaderETag, helper.ETag(employeeResponse.Version))
+
 	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
 		Code:   fiber.StatusOK,
-		Status: "Deleted Successfully",
+		Status: "Restored Successfully",
+		Data:   employeeResponse,
 	})
 }
 
@@ -149,6 +210,10 @@ func (controller *EmployeeControllerImpl) FindById(c *fiber.Ctx) error {
 		})
 	}
 
+	if notModified(c, employeeResponse.Version) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
 	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
 		Code:   fiber.StatusOK,
 		Status: "OK",
@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+// errorResponse maps service errors to 404, 400, 412, 503 or 500 responses
+func errorResponse(c *fiber.Ctx, err error) error {
+	if _, ok := err.(exception.NotFoundError); ok {
+		return c.Status(fiber.StatusNotFound).JSON(web.WebResponse{
+			Code:   fiber.StatusNotFound,
+			Status: "Not Found",
+			Data:   err.Error(),
+		})
+	}
+	if _, ok := err.(validator.ValidationErrors); ok {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Bad Request",
+			Data:   err.Error(),
+		})
+	}
+	if _, ok := err.(exception.BadRequestError); ok {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Bad Request",
+			Data:   err.Error(),
+		})
+	}
+	if _, ok := err.(exception.PreconditionFailedError); ok {
+		return c.Status(fiber.StatusPreconditionFailed).JSON(web.WebResponse{
+			Code:   fiber.StatusPreconditionFailed,
+			Status: "Precondition Failed",
+			Data:   err.Error(),
+		})
+	}
+	if _, ok := err.(exception.ServiceUnavailableError); ok {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(web.WebResponse{
+			Code:   fiber.StatusServiceUnavailable,
+			Status: "Service Unavailable",
+			Data:   err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(web.WebResponse{
+		Code:   fiber.StatusInternalServerError,
+		Status: "Internal Server Error",
+		Data:   err.Error(),
+	})
+}
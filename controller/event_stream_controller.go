@@ -0,0 +1,10 @@
+package controller
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+type EventStreamController interface {
+	Stream(c *fiber.Ctx) error
+	WebSocket(c *fiber.Ctx) error
+}
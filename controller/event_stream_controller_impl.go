@@ -0,0 +1,170 @@
+package controller
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/service"
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// eventStreamHeartbeat keeps idle connections open through proxies and notices clients that left
+	eventStreamHeartbeat = 15 * time.Second
+	// eventStreamWriteTimeout bounds a single WebSocket write
+	eventStreamWriteTimeout = 10 * time.Second
+	// eventStreamRetry is the reconnection delay suggested to EventSource clients, in milliseconds
+	eventStreamRetry = 3000
+	// resetAction tells a client that resumed too late to reload what it shows
+	resetAction = "reset"
+)
+
+type EventStreamControllerImpl struct {
+	EventStreamService service.EventStreamService
+}
+
+func NewEventStreamController(eventStreamService service.EventStreamService) EventStreamController {
+	return &EventStreamControllerImpl{
+		EventStreamService: eventStreamService,
+	}
+}
+
+// Stream changes as Server-Sent Events, filtered by ?topics=product,category and resumed after Last-Event-ID
+func (controller *EventStreamControllerImpl) Stream(c *fiber.Ctx) error {
+	subscription, err := controller.subscribe(c)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		// flush right away, so the client sees the stream open before the first event or heartbeat
+		fmt.Fprintf(w, "retry: %d\n\n", eventStreamRetry)
+		if w.Flush() != nil {
+			subscription.Close()
+			return
+		}
+		pumpEvents(subscription, nil, func(event web.ChangeEvent) error {
+			data, err := json.Marshal(event)
+			if err != nil {
+				return err
+			}
+			if event.Action == resetAction {
+				fmt.Fprintf(w, "event: %s\ndata: %s\n\n", resetAction, data)
+			} else {
+				fmt.Fprintf(w, "id: %d\ndata: %s\n\n", event.Id, data)
+			}
+			return w.Flush()
+		}, func() error {
+			fmt.Fprint(w, ": heartbeat\n\n")
+			return w.Flush()
+		})
+	})
+	return nil
+}
+
+// WebSocket streams the same changes as JSON messages over a WebSocket connection
+func (controller *EventStreamControllerImpl) WebSocket(c *fiber.Ctx) error {
+	if !websocket.IsWebSocketUpgrade(c) {
+		return c.Status(fiber.StatusUpgradeRequired).JSON(web.WebResponse{
+			Code:   fiber.StatusUpgradeRequired,
+			Status: "Upgrade Required",
+			Data:   "connect with a WebSocket client",
+		})
+	}
+
+	subscription, err := controller.subscribe(c)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	err = websocket.New(func(conn *websocket.Conn) {
+		// the client sends nothing, reading only notices when it closes the connection
+		closed := make(chan struct{})
+		go func() {
+			defer close(closed)
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}()
+
+		pumpEvents(subscription, closed, func(event web.ChangeEvent) error {
+			_ = conn.SetWriteDeadline(time.Now().Add(eventStreamWriteTimeout))
+			return conn.WriteJSON(event)
+		}, func() error {
+			return conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(eventStreamWriteTimeout))
+		})
+	})(c)
+	if err != nil {
+		subscription.Close()
+	}
+	return err
+}
+
+// subscribe reads the topics and the event to resume after from the request
+func (controller *EventStreamControllerImpl) subscribe(c *fiber.Ctx) (web.EventSubscription, error) {
+	var topics []string
+	for _, topic := range strings.Split(c.Query("topics"), ",") {
+		if topic = strings.TrimSpace(topic); topic != "" {
+			topics = append(topics, topic)
+		}
+	}
+
+	// EventSource sends Last-Event-ID when it reconnects; other clients may pass ?last_event_id=
+	lastEventId := uint64(0)
+	value := c.Get("Last-Event-ID", c.Query("last_event_id"))
+	if value != "" {
+		id, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return web.EventSubscription{}, exception.NewBadRequestError("Invalid Last-Event-ID " + value)
+		}
+		lastEventId = id
+	}
+
+	return controller.EventStreamService.Subscribe(topics, lastEventId)
+}
+
+// pumpEvents sends a subscription until it ends, a send fails or closed is closed
+func pumpEvents(subscription web.EventSubscription, closed <-chan struct{}, send func(event web.ChangeEvent) error, heartbeat func() error) {
+	defer subscription.Close()
+
+	if subscription.Reset {
+		if send(web.ChangeEvent{Action: resetAction, OccurredAt: time.Now()}) != nil {
+			return
+		}
+	}
+	for _, event := range subscription.Missed {
+		if send(event) != nil {
+			return
+		}
+	}
+
+	ticker := time.NewTicker(eventStreamHeartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case event, ok := <-subscription.Events:
+			if !ok || send(event) != nil {
+				return
+			}
+		case <-ticker.C:
+			if heartbeat() != nil {
+				return
+			}
+		case <-closed:
+			return
+		}
+	}
+}
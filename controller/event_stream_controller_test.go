@@ -0,0 +1,274 @@
+package controller
+
+import (
+	"bufio"
+	"context"
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/middleware"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/service/mocks"
+	"github.com/fasthttp/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func setupTestAppEventStream(mockService *mocks.MockEventStreamService) *fiber.App {
+	app := fiber.New()
+	eventStreamController := NewEventStreamController(mockService)
+
+	app.Get("/api/events/stream", eventStreamController.Stream)
+	app.Get("/api/events/ws", eventStreamController.WebSocket)
+
+	return app
+}
+
+// endedSubscription replays missed events and then ends, as when the subscriber is dropped
+func endedSubscription(reset bool, missed ...web.ChangeEvent) web.EventSubscription {
+	events := make(chan web.ChangeEvent)
+	close(events)
+	return web.EventSubscription{Missed: missed, Reset: reset, Events: events, Close: func() {}}
+}
+
+func TestEventStreamController(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockEventStreamService(ctrl)
+	app := setupTestAppEventStream(mockService)
+	occurredAt := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		url            string
+		headers        map[string]string
+		setupMock      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:    "Resume after Last-Event-ID",
+			url:     "/api/events/stream?topics=product,%20category",
+			headers: map[string]string{"Last-Event-ID": "41"},
+			setupMock: func() {
+				mockService.EXPECT().Subscribe([]string{"product", "category"}, uint64(41)).
+					Return(endedSubscription(false, web.ChangeEvent{Id: 42, Topic: "product", Action: "update", EntityId: 7, OccurredAt: occurredAt}), nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: "retry: 3000\n\n" +
+				`id: 42` + "\n" + `data: {"id":42,"topic":"product","action":"update","entity_id":7,"occurred_at":"2026-10-19T09:00:00Z"}` + "\n\n",
+		},
+		{
+			name: "Resume too late",
+			url:  "/api/events/stream?last_event_id=3",
+			setupMock: func() {
+				mockService.EXPECT().Subscribe(nil, uint64(3)).Return(endedSubscription(true), nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "event: reset\ndata: ",
+		},
+		{
+			name:           "Invalid Last-Event-ID",
+			url:            "/api/events/stream",
+			headers:        map[string]string{"Last-Event-ID": "abc"},
+			setupMock:      func() {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Unknown topic",
+			url:  "/api/events/stream?topics=invoice",
+			setupMock: func() {
+				mockService.EXPECT().Subscribe([]string{"invoice"}, uint64(0)).Return(web.EventSubscription{}, exception.NewBadRequestError("Unknown topic invoice"))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "WebSocket without upgrade",
+			url:            "/api/events/ws",
+			setupMock:      func() {},
+			expectedStatus: http.StatusUpgradeRequired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+
+			req := httptest.NewRequest("GET", tt.url, nil)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			resp, _ := app.Test(req)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			body, _ := io.ReadAll(resp.Body)
+			assert.Contains(t, string(body), tt.expectedBody)
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+			}
+		})
+	}
+}
+
+func TestEventStreamControllerWebSocket(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockEventStreamService(ctrl)
+	app := setupTestAppEventStream(mockService)
+
+	events := make(chan web.ChangeEvent, 1)
+	closed := make(chan struct{})
+	mockService.EXPECT().Subscribe([]string{"customer"}, uint64(0)).Return(web.EventSubscription{
+		Events: events,
+		Close:  func() { close(closed) },
+	}, nil)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	go func() { _ = app.Listener(listener) }()
+	defer app.Shutdown()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws://"+listener.Addr().String()+"/api/events/ws?topics=customer", nil)
+	assert.NoError(t, err)
+
+	events <- web.ChangeEvent{Id: 9, Topic: "customer", Action: "create", EntityId: 3}
+	var received web.ChangeEvent
+	assert.NoError(t, conn.ReadJSON(&received))
+	assert.Equal(t, web.ChangeEvent{Id: 9, Topic: "customer", Action: "create", EntityId: 3}, received)
+
+	// closing the connection ends the subscription
+	assert.NoError(t, conn.Close())
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("subscription was not closed")
+	}
+}
+
+func TestEventStreamControllerShutdown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockEventStreamService(ctrl)
+	app := setupTestAppEventStream(mockService)
+
+	events := make(chan web.ChangeEvent)
+	mockService.EXPECT().Subscribe(gomock.Any(), uint64(0)).Return(web.EventSubscription{
+		Events: events,
+		Close:  func() {},
+	}, nil)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	go func() { _ = app.Listener(listener) }()
+
+	resp, err := http.Get("http://" + listener.Addr().String() + "/api/events/stream")
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	assert.NoError(t, err)
+	assert.Equal(t, "retry: 3000\n", line)
+
+	// the service ends its subscriptions on shutdown, which lets the open stream drain
+	close(events)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.NoError(t, app.ShutdownWithContext(ctx))
+}
+
+// Browsers cannot set X-API-Key on EventSource or WebSocket, so these routes take the key from the URL or a cookie
+func TestEventStreamControllerBrowserAuth(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockEventStreamService(ctrl)
+	app := fiber.New()
+	eventStreamController := NewEventStreamController(mockService)
+	api := app.Group("/api", middleware.NewAuthMiddleware("/api/events/stream", "/api/events/ws"))
+	api.Get("/events/stream", eventStreamController.Stream)
+	api.Get("/events/ws", eventStreamController.WebSocket)
+	api.Get("/events/other", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+
+	tests := []struct {
+		name           string
+		url            string
+		cookie         string
+		setupMock      func()
+		expectedStatus int
+	}{
+		{
+			name: "Key in query",
+			url:  "/api/events/stream?api_key=RAHASIA",
+			setupMock: func() {
+				mockService.EXPECT().Subscribe(nil, uint64(0)).Return(endedSubscription(false), nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "Key in cookie",
+			url:    "/api/events/stream",
+			cookie: "RAHASIA",
+			setupMock: func() {
+				mockService.EXPECT().Subscribe(nil, uint64(0)).Return(endedSubscription(false), nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Wrong key in query",
+			url:            "/api/events/stream?api_key=WRONG",
+			setupMock:      func() {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "No key",
+			url:            "/api/events/stream",
+			setupMock:      func() {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Key in query of another route",
+			url:            "/api/events/other?api_key=RAHASIA",
+			setupMock:      func() {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+
+			req := httptest.NewRequest("GET", tt.url, nil)
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: middleware.APIKeyCookie, Value: tt.cookie})
+			}
+			resp, _ := app.Test(req)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+		})
+	}
+
+	// a WebSocket dialed without any header, as a browser does
+	events := make(chan web.ChangeEvent)
+	close(events)
+	mockService.EXPECT().Subscribe(nil, uint64(0)).Return(web.EventSubscription{Events: events, Close: func() {}}, nil)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	go func() { _ = app.Listener(listener) }()
+	defer app.Shutdown()
+
+	_, resp, err := websocket.DefaultDialer.Dial("ws://"+listener.Addr().String()+"/api/events/ws", nil)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws://"+listener.Addr().String()+"/api/events/ws?api_key=RAHASIA", nil)
+	if assert.NoError(t, err) {
+		conn.Close()
+	}
+}
@@ -0,0 +1,11 @@
+package controller
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+type ExportController interface {
+	ExportProducts(c *fiber.Ctx) error
+	ExportCustomers(c *fiber.Ctx) error
+	ExportEmployees(c *fiber.Ctx) error
+}
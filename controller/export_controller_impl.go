@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/service"
+	"github.com/gofiber/fiber/v2"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+type ExportControllerImpl struct {
+	ExportService service.ExportService
+}
+
+func NewExportController(exportService service.ExportService) ExportController {
+	return &ExportControllerImpl{
+		ExportService: exportService,
+	}
+}
+
+// Export Products, optionally limited to a category and its descendants
+func (controller *ExportControllerImpl) ExportProducts(c *fiber.Ctx) error {
+	return controller.export(c, "products")
+}
+
+// Export Customers
+func (controller *ExportControllerImpl) ExportCustomers(c *fiber.Ctx) error {
+	return controller.export(c, "customers")
+}
+
+// Export Employees
+func (controller *ExportControllerImpl) ExportEmployees(c *fiber.Ctx) error {
+	return controller.export(c, "employees")
+}
+
+func (controller *ExportControllerImpl) export(c *fiber.Ctx, resource string) error {
+	request := web.ExportRequest{Resource: resource, Format: c.Query("format", "csv")}
+	for _, column := range strings.Split(c.Query("columns"), ",") {
+		if column = strings.TrimSpace(column); column != "" {
+			request.Columns = append(request.Columns, column)
+		}
+	}
+	if categoryId := c.Query("category_id"); categoryId != "" {
+		id, err := strconv.ParseUint(categoryId, 10, 64)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+				Code:   fiber.StatusBadRequest,
+				Status: "Invalid Category ID",
+				Data:   err.Error(),
+			})
+		}
+		request.CategoryId = &id
+	}
+
+	file, err := controller.ExportService.Prepare(request)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, file.ContentType)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, file.Filename))
+
+	ctx := c.Context()
+	ctx.SetBodyStreamWriter(func(w *bufio.Writer) {
+		// The status line is already sent, so a failure can only cut the file short
+		if err := controller.ExportService.Export(ctx, request, w); err != nil {
+			slog.ErrorContext(ctx, "export failed", "resource", resource, "error", err)
+		}
+	})
+	return nil
+}
@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"context"
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/service/mocks"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func setupTestAppExport(mockService *mocks.MockExportService) *fiber.App {
+	app := fiber.New()
+	exportController := NewExportController(mockService)
+
+	app.Get("/api/products/export", exportController.ExportProducts)
+	app.Get("/api/customers/export", exportController.ExportCustomers)
+	app.Get("/api/employees/export", exportController.ExportEmployees)
+
+	return app
+}
+
+func TestExportController(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockExportService(ctrl)
+	app := setupTestAppExport(mockService)
+	categoryId := uint64(3)
+
+	tests := []struct {
+		name                string
+		url                 string
+		setupMock           func()
+		expectedStatus      int
+		expectedBody        string
+		expectedDisposition string
+	}{
+		{
+			name: "Export products - csv with filter and columns",
+			url:  "/api/products/export?category_id=3&columns=sku,%20price",
+			setupMock: func() {
+				request := web.ExportRequest{Resource: "products", Format: "csv", Columns: []string{"sku", "price"}, CategoryId: &categoryId}
+				mockService.EXPECT().Prepare(request).
+					Return(web.ExportFileResponse{Filename: "products.csv", ContentType: "text/csv; charset=utf-8"}, nil)
+				mockService.EXPECT().Export(gomock.Any(), request, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, request web.ExportRequest, w io.Writer) error {
+						_, err := io.WriteString(w, "sku,price\nSKU-1,85000.00\n")
+						return err
+					})
+			},
+			expectedStatus:      http.StatusOK,
+			expectedBody:        "sku,price\nSKU-1,85000.00\n",
+			expectedDisposition: `attachment; filename="products.csv"`,
+		},
+		{
+			name: "Export customers - bad column",
+			url:  "/api/customers/export?format=jsonl&columns=password",
+			setupMock: func() {
+				mockService.EXPECT().Prepare(web.ExportRequest{Resource: "customers", Format: "jsonl", Columns: []string{"password"}}).
+					Return(web.ExportFileResponse{}, exception.NewBadRequestError("Unknown column password"))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Export employees - invalid category id",
+			url:            "/api/employees/export?category_id=abc",
+			setupMock:      func() {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			resp, _ := app.Test(req)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			if tt.expectedBody != "" {
+				body, _ := io.ReadAll(resp.Body)
+				assert.Equal(t, tt.expectedBody, string(body))
+				assert.Equal(t, tt.expectedDisposition, resp.Header.Get(fiber.HeaderContentDisposition))
+			}
+		})
+	}
+}
@@ -0,0 +1,11 @@
+package controller
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+type HealthController interface {
+	Live(c *fiber.Ctx) error
+	Ready(c *fiber.Ctx) error
+	Health(c *fiber.Ctx) error
+}
@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+type HealthControllerImpl struct {
+	HealthService service.HealthService
+}
+
+func NewHealthController(healthService service.HealthService) HealthController {
+	return &HealthControllerImpl{
+		HealthService: healthService,
+	}
+}
+
+// Live answers as long as the process can serve requests
+func (controller *HealthControllerImpl) Live(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   web.HealthResponse{Status: web.HealthUp},
+	})
+}
+
+// Ready answers 503 while a dependency check fails, so no traffic is routed to the instance
+func (controller *HealthControllerImpl) Ready(c *fiber.Ctx) error {
+	report := controller.HealthService.Check(c.Context())
+	return healthResponse(c, web.HealthResponse{Status: report.Status})
+}
+
+// Health reports the status and latency of every dependency
+func (controller *HealthControllerImpl) Health(c *fiber.Ctx) error {
+	return healthResponse(c, controller.HealthService.Check(c.Context()))
+}
+
+func healthResponse(c *fiber.Ctx, report web.HealthResponse) error {
+	if report.Status != web.HealthUp {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(web.WebResponse{
+			Code:   fiber.StatusServiceUnavailable,
+			Status: "Service Unavailable",
+			Data:   report,
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   report,
+	})
+}
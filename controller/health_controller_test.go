@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"encoding/json"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/service/mocks"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func setupTestAppHealth(mockService *mocks.MockHealthService) *fiber.App {
+	app := fiber.New()
+	healthController := NewHealthController(mockService)
+
+	app.Get("/healthz", healthController.Live)
+	app.Get("/readyz", healthController.Ready)
+	app.Get("/health", healthController.Health)
+
+	return app
+}
+
+func TestHealthController(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockHealthService(ctrl)
+	app := setupTestAppHealth(mockService)
+
+	healthy := web.HealthResponse{Status: web.HealthUp, Checks: []web.HealthCheckResponse{
+		{Name: "database", Status: web.HealthUp, LatencyMs: 1.5},
+	}}
+	unhealthy := web.HealthResponse{Status: web.HealthDown, Checks: []web.HealthCheckResponse{
+		{Name: "database", Status: web.HealthDown, LatencyMs: 2000, Error: "context deadline exceeded"},
+	}}
+
+	tests := []struct {
+		name           string
+		url            string
+		setupMock      func()
+		expectedStatus int
+		expectedChecks int
+	}{
+		{
+			name:           "Live without checks",
+			url:            "/healthz",
+			setupMock:      func() {},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "Ready",
+			url:  "/readyz",
+			setupMock: func() {
+				mockService.EXPECT().Check(gomock.Any()).Return(healthy)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "Not ready",
+			url:  "/readyz",
+			setupMock: func() {
+				mockService.EXPECT().Check(gomock.Any()).Return(unhealthy)
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name: "Health report",
+			url:  "/health",
+			setupMock: func() {
+				mockService.EXPECT().Check(gomock.Any()).Return(healthy)
+			},
+			expectedStatus: http.StatusOK,
+			expectedChecks: 1,
+		},
+		{
+			name: "Health report with a failing dependency",
+			url:  "/health",
+			setupMock: func() {
+				mockService.EXPECT().Check(gomock.Any()).Return(unhealthy)
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedChecks: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+			resp, err := app.Test(httptest.NewRequest("GET", tt.url, nil))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			var response struct {
+				Data web.HealthResponse `json:"data"`
+			}
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+			assert.Len(t, response.Data.Checks, tt.expectedChecks)
+		})
+	}
+}
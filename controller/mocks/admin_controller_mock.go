@@ -0,0 +1,69 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: controller/admin_controller.go
+//
+// Generated by this command:
+//
+//	mockgen -source=controller/admin_controller.go -destination=controller/mocks/admin_controller_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	fiber "github.com/gofiber/fiber/v2"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAdminController is a mock of AdminController interface.
+type MockAdminController struct {
+	ctrl     *gomock.Controller
+	recorder *MockAdminControllerMockRecorder
+	isgomock struct{}
+}
+
+// MockAdminControllerMockRecorder is the mock recorder for MockAdminController.
+type MockAdminControllerMockRecorder struct {
+	mock *MockAdminController
+}
+
+// NewMockAdminController creates a new mock instance.
+func NewMockAdminController(ctrl *gomock.Controller) *MockAdminController {
+	mock := &MockAdminController{ctrl: ctrl}
+	mock.recorder = &MockAdminControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAdminController) EXPECT() *MockAdminControllerMockRecorder {
+	return m.recorder
+}
+
+// GetLogLevel mocks base method.
+func (m *MockAdminController) GetLogLevel(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLogLevel", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetLogLevel indicates an expected call of GetLogLevel.
+func (mr *MockAdminControllerMockRecorder) GetLogLevel(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogLevel", reflect.TypeOf((*MockAdminController)(nil).GetLogLevel), c)
+}
+
+// SetLogLevel mocks base method.
+func (m *MockAdminController) SetLogLevel(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLogLevel", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetLogLevel indicates an expected call of SetLogLevel.
+func (mr *MockAdminControllerMockRecorder) SetLogLevel(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLogLevel", reflect.TypeOf((*MockAdminController)(nil).SetLogLevel), c)
+}
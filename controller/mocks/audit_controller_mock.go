@@ -0,0 +1,55 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: controller/audit_controller.go
+//
+// Generated by this command:
+//
+//	mockgen -source=controller/audit_controller.go -destination=controller/mocks/audit_controller_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	fiber "github.com/gofiber/fiber/v2"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAuditController is a mock of AuditController interface.
+type MockAuditController struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditControllerMockRecorder
+	isgomock struct{}
+}
+
+// MockAuditControllerMockRecorder is the mock recorder for MockAuditController.
+type MockAuditControllerMockRecorder struct {
+	mock *MockAuditController
+}
+
+// NewMockAuditController creates a new mock instance.
+func NewMockAuditController(ctrl *gomock.Controller) *MockAuditController {
+	mock := &MockAuditController{ctrl: ctrl}
+	mock.recorder = &MockAuditControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditController) EXPECT() *MockAuditControllerMockRecorder {
+	return m.recorder
+}
+
+// FindAll mocks base method.
+func (m *MockAuditController) FindAll(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockAuditControllerMockRecorder) FindAll(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockAuditController)(nil).FindAll), c)
+}
@@ -0,0 +1,97 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: controller/batch_controller.go
+//
+// Generated by this command:
+//
+//	mockgen -source=controller/batch_controller.go -destination=controller/mocks/batch_controller_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	fiber "github.com/gofiber/fiber/v2"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBatchController is a mock of BatchController interface.
+type MockBatchController struct {
+	ctrl     *gomock.Controller
+	recorder *MockBatchControllerMockRecorder
+	isgomock struct{}
+}
+
+// MockBatchControllerMockRecorder is the mock recorder for MockBatchController.
+type MockBatchControllerMockRecorder struct {
+	mock *MockBatchController
+}
+
+// NewMockBatchController creates a new mock instance.
+func NewMockBatchController(ctrl *gomock.Controller) *MockBatchController {
+	mock := &MockBatchController{ctrl: ctrl}
+	mock.recorder = &MockBatchControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBatchController) EXPECT() *MockBatchControllerMockRecorder {
+	return m.recorder
+}
+
+// Categories mocks base method.
+func (m *MockBatchController) Categories(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Categories", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Categories indicates an expected call of Categories.
+func (mr *MockBatchControllerMockRecorder) Categories(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Categories", reflect.TypeOf((*MockBatchController)(nil).Categories), c)
+}
+
+// Customers mocks base method.
+func (m *MockBatchController) Customers(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Customers", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Customers indicates an expected call of Customers.
+func (mr *MockBatchControllerMockRecorder) Customers(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Customers", reflect.TypeOf((*MockBatchController)(nil).Customers), c)
+}
+
+// Employees mocks base method.
+func (m *MockBatchController) Employees(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Employees", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Employees indicates an expected call of Employees.
+func (mr *MockBatchControllerMockRecorder) Employees(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Employees", reflect.TypeOf((*MockBatchController)(nil).Employees), c)
+}
+
+// Products mocks base method.
+func (m *MockBatchController) Products(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Products", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Products indicates an expected call of Products.
+func (mr *MockBatchControllerMockRecorder) Products(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Products", reflect.TypeOf((*MockBatchController)(nil).Products), c)
+}
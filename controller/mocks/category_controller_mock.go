@@ -96,6 +96,62 @@ func (mr *MockCategoryControllerMockRecorder) FindById(c any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockCategoryController)(nil).FindById), c)
 }
 
+// FindTree mocks base method.
+func (m *MockCategoryController) FindTree(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindTree", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FindTree indicates an expected call of FindTree.
+func (mr *MockCategoryControllerMockRecorder) FindTree(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTree", reflect.TypeOf((*MockCategoryController)(nil).FindTree), c)
+}
+
+// Move mocks base method.
+func (m *MockCategoryController) Move(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Move", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Move indicates an expected call of Move.
+func (mr *MockCategoryControllerMockRecorder) Move(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Move", reflect.TypeOf((*MockCategoryController)(nil).Move), c)
+}
+
+// Patch mocks base method.
+func (m *MockCategoryController) Patch(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Patch", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Patch indicates an expected call of Patch.
+func (mr *MockCategoryControllerMockRecorder) Patch(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Patch", reflect.TypeOf((*MockCategoryController)(nil).Patch), c)
+}
+
+// Restore mocks base method.
+func (m *MockCategoryController) Restore(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockCategoryControllerMockRecorder) Restore(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockCategoryController)(nil).Restore), c)
+}
+
 // Update mocks base method.
 func (m *MockCategoryController) Update(c *fiber.Ctx) error {
 	m.ctrl.T.Helper()
@@ -96,6 +96,34 @@ func (mr *MockCustomerControllerMockRecorder) FindById(c any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockCustomerController)(nil).FindById), c)
 }
 
+// Patch mocks base method.
+func (m *MockCustomerController) Patch(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Patch", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Patch indicates an expected call of Patch.
+func (mr *MockCustomerControllerMockRecorder) Patch(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Patch", reflect.TypeOf((*MockCustomerController)(nil).Patch), c)
+}
+
+// Restore mocks base method.
+func (m *MockCustomerController) Restore(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockCustomerControllerMockRecorder) Restore(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockCustomerController)(nil).Restore), c)
+}
+
 // Update mocks base method.
 func (m *MockCustomerController) Update(c *fiber.Ctx) error {
 	m.ctrl.T.Helper()
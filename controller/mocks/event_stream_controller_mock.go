@@ -0,0 +1,69 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: controller/event_stream_controller.go
+//
+// Generated by this command:
+//
+//	mockgen -source=controller/event_stream_controller.go -destination=controller/mocks/event_stream_controller_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	fiber "github.com/gofiber/fiber/v2"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockEventStreamController is a mock of EventStreamController interface.
+type MockEventStreamController struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventStreamControllerMockRecorder
+	isgomock struct{}
+}
+
+// MockEventStreamControllerMockRecorder is the mock recorder for MockEventStreamController.
+type MockEventStreamControllerMockRecorder struct {
+	mock *MockEventStreamController
+}
+
+// NewMockEventStreamController creates a new mock instance.
+func NewMockEventStreamController(ctrl *gomock.Controller) *MockEventStreamController {
+	mock := &MockEventStreamController{ctrl: ctrl}
+	mock.recorder = &MockEventStreamControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventStreamController) EXPECT() *MockEventStreamControllerMockRecorder {
+	return m.recorder
+}
+
+// Stream mocks base method.
+func (m *MockEventStreamController) Stream(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stream", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Stream indicates an expected call of Stream.
+func (mr *MockEventStreamControllerMockRecorder) Stream(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stream", reflect.TypeOf((*MockEventStreamController)(nil).Stream), c)
+}
+
+// WebSocket mocks base method.
+func (m *MockEventStreamController) WebSocket(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WebSocket", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WebSocket indicates an expected call of WebSocket.
+func (mr *MockEventStreamControllerMockRecorder) WebSocket(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WebSocket", reflect.TypeOf((*MockEventStreamController)(nil).WebSocket), c)
+}
@@ -0,0 +1,83 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: controller/export_controller.go
+//
+// Generated by this command:
+//
+//	mockgen -source=controller/export_controller.go -destination=controller/mocks/export_controller_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	fiber "github.com/gofiber/fiber/v2"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockExportController is a mock of ExportController interface.
+type MockExportController struct {
+	ctrl     *gomock.Controller
+	recorder *MockExportControllerMockRecorder
+	isgomock struct{}
+}
+
+// MockExportControllerMockRecorder is the mock recorder for MockExportController.
+type MockExportControllerMockRecorder struct {
+	mock *MockExportController
+}
+
+// NewMockExportController creates a new mock instance.
+func NewMockExportController(ctrl *gomock.Controller) *MockExportController {
+	mock := &MockExportController{ctrl: ctrl}
+	mock.recorder = &MockExportControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExportController) EXPECT() *MockExportControllerMockRecorder {
+	return m.recorder
+}
+
+// ExportCustomers mocks base method.
+func (m *MockExportController) ExportCustomers(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportCustomers", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportCustomers indicates an expected call of ExportCustomers.
+func (mr *MockExportControllerMockRecorder) ExportCustomers(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportCustomers", reflect.TypeOf((*MockExportController)(nil).ExportCustomers), c)
+}
+
+// ExportEmployees mocks base method.
+func (m *MockExportController) ExportEmployees(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportEmployees", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportEmployees indicates an expected call of ExportEmployees.
+func (mr *MockExportControllerMockRecorder) ExportEmployees(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportEmployees", reflect.TypeOf((*MockExportController)(nil).ExportEmployees), c)
+}
+
+// ExportProducts mocks base method.
+func (m *MockExportController) ExportProducts(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportProducts", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportProducts indicates an expected call of ExportProducts.
+func (mr *MockExportControllerMockRecorder) ExportProducts(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportProducts", reflect.TypeOf((*MockExportController)(nil).ExportProducts), c)
+}
@@ -0,0 +1,83 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: controller/health_controller.go
+//
+// Generated by this command:
+//
+//	mockgen -source=controller/health_controller.go -destination=controller/mocks/health_controller_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	fiber "github.com/gofiber/fiber/v2"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockHealthController is a mock of HealthController interface.
+type MockHealthController struct {
+	ctrl     *gomock.Controller
+	recorder *MockHealthControllerMockRecorder
+	isgomock struct{}
+}
+
+// MockHealthControllerMockRecorder is the mock recorder for MockHealthController.
+type MockHealthControllerMockRecorder struct {
+	mock *MockHealthController
+}
+
+// NewMockHealthController creates a new mock instance.
+func NewMockHealthController(ctrl *gomock.Controller) *MockHealthController {
+	mock := &MockHealthController{ctrl: ctrl}
+	mock.recorder = &MockHealthControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHealthController) EXPECT() *MockHealthControllerMockRecorder {
+	return m.recorder
+}
+
+// Health mocks base method.
+func (m *MockHealthController) Health(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Health", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Health indicates an expected call of Health.
+func (mr *MockHealthControllerMockRecorder) Health(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Health", reflect.TypeOf((*MockHealthController)(nil).Health), c)
+}
+
+// Live mocks base method.
+func (m *MockHealthController) Live(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Live", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Live indicates an expected call of Live.
+func (mr *MockHealthControllerMockRecorder) Live(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Live", reflect.TypeOf((*MockHealthController)(nil).Live), c)
+}
+
+// Ready mocks base method.
+func (m *MockHealthController) Ready(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ready", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ready indicates an expected call of Ready.
+func (mr *MockHealthControllerMockRecorder) Ready(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ready", reflect.TypeOf((*MockHealthController)(nil).Ready), c)
+}
@@ -82,6 +82,20 @@ func (mr *MockProductControllerMockRecorder) FindAll(c any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockProductController)(nil).FindAll), c)
 }
 
+// FindByCategory mocks base method.
+func (m *MockProductController) FindByCategory(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByCategory", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FindByCategory indicates an expected call of FindByCategory.
+func (mr *MockProductControllerMockRecorder) FindByCategory(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByCategory", reflect.TypeOf((*MockProductController)(nil).FindByCategory), c)
+}
+
 // FindById mocks base method.
 func (m *MockProductController) FindById(c *fiber.Ctx) error {
 	m.ctrl.T.Helper()
@@ -96,6 +110,48 @@ func (mr *MockProductControllerMockRecorder) FindById(c any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockProductController)(nil).FindById), c)
 }
 
+// Lookup mocks base method.
+func (m *MockProductController) Lookup(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lookup", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Lookup indicates an expected call of Lookup.
+func (mr *MockProductControllerMockRecorder) Lookup(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lookup", reflect.TypeOf((*MockProductController)(nil).Lookup), c)
+}
+
+// Patch mocks base method.
+func (m *MockProductController) Patch(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Patch", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Patch indicates an expected call of Patch.
+func (mr *MockProductControllerMockRecorder) Patch(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Patch", reflect.TypeOf((*MockProductController)(nil).Patch), c)
+}
+
+// Restore mocks base method.
+func (m *MockProductController) Restore(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockProductControllerMockRecorder) Restore(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockProductController)(nil).Restore), c)
+}
+
 // Update mocks base method.
 func (m *MockProductController) Update(c *fiber.Ctx) error {
 	m.ctrl.T.Helper()
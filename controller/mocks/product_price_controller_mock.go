@@ -0,0 +1,83 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: controller/product_price_controller.go
+//
+// Generated by this command:
+//
+//	mockgen -source=controller/product_price_controller.go -destination=controller/mocks/product_price_controller_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	fiber "github.com/gofiber/fiber/v2"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockProductPriceController is a mock of ProductPriceController interface.
+type MockProductPriceController struct {
+	ctrl     *gomock.Controller
+	recorder *MockProductPriceControllerMockRecorder
+	isgomock struct{}
+}
+
+// MockProductPriceControllerMockRecorder is the mock recorder for MockProductPriceController.
+type MockProductPriceControllerMockRecorder struct {
+	mock *MockProductPriceController
+}
+
+// NewMockProductPriceController creates a new mock instance.
+func NewMockProductPriceController(ctrl *gomock.Controller) *MockProductPriceController {
+	mock := &MockProductPriceController{ctrl: ctrl}
+	mock.recorder = &MockProductPriceControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProductPriceController) EXPECT() *MockProductPriceControllerMockRecorder {
+	return m.recorder
+}
+
+// Cancel mocks base method.
+func (m *MockProductPriceController) Cancel(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockProductPriceControllerMockRecorder) Cancel(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockProductPriceController)(nil).Cancel), c)
+}
+
+// FindAll mocks base method.
+func (m *MockProductPriceController) FindAll(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockProductPriceControllerMockRecorder) FindAll(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockProductPriceController)(nil).FindAll), c)
+}
+
+// Schedule mocks base method.
+func (m *MockProductPriceController) Schedule(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Schedule", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Schedule indicates an expected call of Schedule.
+func (mr *MockProductPriceControllerMockRecorder) Schedule(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Schedule", reflect.TypeOf((*MockProductPriceController)(nil).Schedule), c)
+}
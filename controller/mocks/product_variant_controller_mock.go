@@ -0,0 +1,111 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: controller/product_variant_controller.go
+//
+// Generated by this command:
+//
+//	mockgen -source=controller/product_variant_controller.go -destination=controller/mocks/product_variant_controller_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	fiber "github.com/gofiber/fiber/v2"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockProductVariantController is a mock of ProductVariantController interface.
+type MockProductVariantController struct {
+	ctrl     *gomock.Controller
+	recorder *MockProductVariantControllerMockRecorder
+	isgomock struct{}
+}
+
+// MockProductVariantControllerMockRecorder is the mock recorder for MockProductVariantController.
+type MockProductVariantControllerMockRecorder struct {
+	mock *MockProductVariantController
+}
+
+// NewMockProductVariantController creates a new mock instance.
+func NewMockProductVariantController(ctrl *gomock.Controller) *MockProductVariantController {
+	mock := &MockProductVariantController{ctrl: ctrl}
+	mock.recorder = &MockProductVariantControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProductVariantController) EXPECT() *MockProductVariantControllerMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockProductVariantController) Create(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockProductVariantControllerMockRecorder) Create(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockProductVariantController)(nil).Create), c)
+}
+
+// Delete mocks base method.
+func (m *MockProductVariantController) Delete(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockProductVariantControllerMockRecorder) Delete(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockProductVariantController)(nil).Delete), c)
+}
+
+// FindAll mocks base method.
+func (m *MockProductVariantController) FindAll(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockProductVariantControllerMockRecorder) FindAll(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockProductVariantController)(nil).FindAll), c)
+}
+
+// Generate mocks base method.
+func (m *MockProductVariantController) Generate(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Generate", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Generate indicates an expected call of Generate.
+func (mr *MockProductVariantControllerMockRecorder) Generate(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Generate", reflect.TypeOf((*MockProductVariantController)(nil).Generate), c)
+}
+
+// Update mocks base method.
+func (m *MockProductVariantController) Update(c *fiber.Ctx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", c)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockProductVariantControllerMockRecorder) Update(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockProductVariantController)(nil).Update), c)
+}
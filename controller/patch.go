@@ -0,0 +1,28 @@
+package controller
+
+import (
+	"github.com/aronipurwanto/go-restful-api/helper"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/gofiber/fiber/v2"
+	"strings"
+)
+
+// patchContentType returns the media type of a PATCH body when it is one the services can apply
+func patchContentType(c *fiber.Ctx) (string, bool) {
+	contentType := strings.ToLower(strings.TrimSpace(strings.Split(c.Get(fiber.HeaderContentType), ";")[0]))
+	switch contentType {
+	case helper.MergePatchContentType, helper.JSONPatchContentType, fiber.MIMEApplicationJSON:
+		return contentType, true
+	}
+	return contentType, false
+}
+
+// unsupportedPatch answers a PATCH whose body is neither a merge patch nor a JSON patch
+func unsupportedPatch(c *fiber.Ctx) error {
+	c.Set("Accept-Patch", helper.MergePatchContentType+", "+helper.JSONPatchContentType)
+	return c.Status(fiber.StatusUnsupportedMediaType).JSON(web.WebResponse{
+		Code:   fiber.StatusUnsupportedMediaType,
+		Status: "Unsupported Media Type",
+		Data:   "Content-Type must be " + helper.MergePatchContentType + " or " + helper.JSONPatchContentType,
+	})
+}
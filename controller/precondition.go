@@ -0,0 +1,36 @@
+package controller
+
+import (
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/helper"
+	"github.com/gofiber/fiber/v2"
+	"strings"
+)
+
+// ifMatchVersion reads the version a write is conditional on, or 0 when If-Match is absent or "*".
+// A list of several entity tags is left in the request context, where checkVersion accepts any of them
+func ifMatchVersion(c *fiber.Ctx) (uint64, error) {
+	header := strings.TrimSpace(c.Get(fiber.HeaderIfMatch))
+	if header == "" {
+		return 0, nil
+	}
+
+	versions, any := helper.ParseIfMatch(header)
+	switch {
+	case any:
+		return 0, nil
+	case len(versions) == 0:
+		return 0, exception.NewPreconditionFailedError("If-Match does not match the current version")
+	case len(versions) == 1:
+		return versions[0], nil
+	}
+	c.Locals(helper.IfMatchKey, versions)
+	return 0, nil
+}
+
+// notModified sets the ETag of a single resource and reports whether If-None-Match already lists it
+func notModified(c *fiber.Ctx, version uint64) bool {
+	etag := helper.ETag(version)
+	c.Set(fiber.HeaderETag, etag)
+	return helper.ETagMatchesAny(c.Get(fiber.HeaderIfNoneMatch), etag)
+}
@@ -2,6 +2,7 @@ package controller
 
 import (
 	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/helper"
 	"github.com/aronipurwanto/go-restful-api/model/web"
 	"github.com/aronipurwanto/go-restful-api/service"
 	"github.com/gofiber/fiber/v2"
@@ -31,6 +32,13 @@ func (controller *ProductControllerImpl) Create(c *fiber.Ctx) error {
 
 	productResponse, err := controller.ProductService.Create(c.Context(), *productCreateRequest)
 	if err != nil {
+		if _, ok := err.(exception.BadRequestError); ok {
+			return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+				Code:   fiber.StatusBadRequest,
+				Status: "Bad Request",
+				Data:   err.Error(),
+			})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(web.WebResponse{
 			Code:   fiber.StatusInternalServerError,
 			Status: "Internal Server Error",
@@ -38,6 +46,8 @@ func (controller *ProductControllerImpl) Create(c *fiber.Ctx) error {
 		})
 	}
 
+	c.Set(fiber.HeaderETag, helper.ETag(productResponse.Version))
+
 	return c.Status(fiber.StatusCreated).JSON(web.WebResponse{
 		Code:   fiber.StatusCreated,
 		Status: "Created",
@@ -66,22 +76,61 @@ func (controller *ProductControllerImpl) Update(c *fiber.Ctx) error {
 	}
 	productUpdateRequest.Id = id
 
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	if version != 0 {
+		productUpdateRequest.Version = version
+	}
+
 	productResponse, err := controller.ProductService.Update(c.Context(), *productUpdateRequest)
 	if err != nil {
-		if _, ok := err.(exception.NotFoundError); ok {
-			return c.Status(fiber.StatusNotFound).JSON(web.WebResponse{
-				Code:   fiber.StatusNotFound,
-				Status: "Not Found",
-				Data:   err.Error(),
-			})
-		}
-		return c.Status(fiber.StatusInternalServerError).JSON(web.WebResponse{
-			Code:   fiber.StatusInternalServerError,
-			Status: "Internal Server Error",
+		return errorResponse(c, err)
+	}
+
+	c.Set(fiber.HeaderETag, helper.ETag(productResponse.Version))
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   productResponse,
+	})
+}
+
+// Patch Product
+func (controller *ProductControllerImpl) Patch(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("productId"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Invalid Product ID",
 			Data:   err.Error(),
 		})
 	}
 
+	contentType, ok := patchContentType(c)
+	if !ok {
+		return unsupportedPatch(c)
+	}
+
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	productResponse, err := controller.ProductService.Patch(c.Context(), web.PatchRequest{
+		Id:          id,
+		ContentType: contentType,
+		Patch:       append([]byte(nil), c.Body()...),
+		Version:     version,
+	})
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	c.Set(fiber.HeaderETag, helper.ETag(productResponse.Version))
+
 	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
 		Code:   fiber.StatusOK,
 		Status: "OK",
@@ -100,25 +149,44 @@ func (controller *ProductControllerImpl) Delete(c *fiber.Ctx) error {
 		})
 	}
 
-	err = controller.ProductService.Delete(c.Context(), id)
+	version, err := ifMatchVersion(c)
 	if err != nil {
-		if _, ok := err.(exception.NotFoundError); ok {
-			return c.Status(fiber.StatusNotFound).JSON(web.WebResponse{
-				Code:   fiber.StatusNotFound,
-				Status: "Not Found",
-				Data:   err.Error(),
-			})
-		}
-		return c.Status(fiber.StatusInternalServerError).JSON(web.WebResponse{
-			Code:   fiber.StatusInternalServerError,
-			Status: "Internal Server Error",
+		return errorResponse(c, err)
+	}
+
+	err = controller.ProductService.Delete(c.Context(), id, version)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "Deleted Successfully",
+	})
+}
+
+// Restore soft-deleted Product
+func (controller *ProductControllerImpl) Restore(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("productId"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Invalid Product ID",
 			Data:   err.Error(),
 		})
 	}
 
+	productResponse, err := controller.ProductService.Restore(c.Context(), id)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	c.Set(fiber.HeaderETag, helper.ETag(productResponse.Version))
+
 	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
 		Code:   fiber.StatusOK,
-		Status: "Deleted Successfully",
+		Status: "Restored Successfully",
+		Data:   productResponse,
 	})
 }
 
@@ -149,6 +217,10 @@ func (controller *ProductControllerImpl) FindById(c *fiber.Ctx) error {
 		})
 	}
 
+	if notModified(c, productResponse.Version) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
 	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
 		Code:   fiber.StatusOK,
 		Status: "OK",
@@ -173,3 +245,71 @@ func (controller *ProductControllerImpl) FindAll(c *fiber.Ctx) error {
 		Data:   productResponses,
 	})
 }
+
+// Find Products of a Category and its descendants
+func (controller *ProductControllerImpl) FindByCategory(c *fiber.Ctx) error {
+	categoryId, err := strconv.ParseUint(c.Params("categoryId"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Invalid Category ID",
+			Data:   err.Error(),
+		})
+	}
+
+	productResponses, err := controller.ProductService.FindByCategory(c.Context(), categoryId)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(web.WebResponse{
+			Code:   fiber.StatusInternalServerError,
+			Status: "Internal Server Error",
+			Data:   err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   productResponses,
+	})
+}
+
+// Lookup Product By scanned barcode
+func (controller *ProductControllerImpl) Lookup(c *fiber.Ctx) error {
+	code := c.Query("code")
+	if code == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Bad Request",
+			Data:   "code is required",
+		})
+	}
+
+	lookupResponse, err := controller.ProductService.Lookup(c.Context(), code)
+	if err != nil {
+		if _, ok := err.(exception.BadRequestError); ok {
+			return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+				Code:   fiber.StatusBadRequest,
+				Status: "Bad Request",
+				Data:   err.Error(),
+			})
+		}
+		if _, ok := err.(exception.NotFoundError); ok {
+			return c.Status(fiber.StatusNotFound).JSON(web.WebResponse{
+				Code:   fiber.StatusNotFound,
+				Status: "Not Found",
+				Data:   err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(web.WebResponse{
+			Code:   fiber.StatusInternalServerError,
+			Status: "Internal Server Error",
+			Data:   err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   lookupResponse,
+	})
+}
@@ -2,7 +2,11 @@ package controller
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/helper"
+	"github.com/aronipurwanto/go-restful-api/model/money"
 	"github.com/aronipurwanto/go-restful-api/model/web"
 	"github.com/aronipurwanto/go-restful-api/service/mocks"
 	"github.com/gofiber/fiber/v2"
@@ -10,6 +14,7 @@ import (
 	"go.uber.org/mock/gomock"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"testing"
 )
 
@@ -21,6 +26,7 @@ func setupTestAppProduct(mockService *mocks.MockProductService) *fiber.App {
 	products := api.Group("/products")
 	products.Post("/", productController.Create)
 	products.Put("/:productId", productController.Update)
+	products.Patch("/:productId", productController.Patch)
 	products.Delete("/:productId", productController.Delete)
 	products.Get("/:productId", productController.FindById)
 	products.Get("/", productController.FindAll)
@@ -48,17 +54,17 @@ func TestProductController(t *testing.T) {
 			name:   "Update product - success",
 			method: "PUT",
 			url:    "/api/products/1",
-			body:   web.ProductUpdateRequest{Id: 1, Name: "Updated Test", Description: "Test", Price: 1, StockQty: 1, CategoryID: 1, SKU: "test", TaxRate: 1.0},
+			body:   web.ProductUpdateRequest{Id: 1, Name: "Updated Test", Description: "Test", Price: money.FromInt(1, "IDR"), StockQty: 1, CategoryID: 1, SKU: "test", TaxRate: money.AmountFromInt(1)},
 			setupMock: func() {
 				mockService.EXPECT().
 					Update(gomock.Any(), gomock.Any()).
-					Return(web.ProductResponse{Id: 1, Name: "Updated Test", Description: "Test", Price: 1, StockQty: 1, CategoryID: 1, SKU: "test", TaxRate: 1.0}, nil)
+					Return(web.ProductResponse{Id: 1, Name: "Updated Test", Description: "Test", Price: money.FromInt(1, "IDR"), StockQty: 1, CategoryID: 1, SKU: "test", TaxRate: money.AmountFromInt(1)}, nil)
 			},
 			expectedStatus: http.StatusOK,
 			expectedBody: web.WebResponse{
 				Code:   http.StatusOK,
 				Status: "OK",
-				Data:   web.ProductResponse{Id: 1, Name: "Updated Test", Description: "Test", Price: 1, StockQty: 1, CategoryID: 1, SKU: "test", TaxRate: 1.0},
+				Data:   web.ProductResponse{Id: 1, Name: "Updated Test", Description: "Test", Price: money.FromInt(1, "IDR"), StockQty: 1, CategoryID: 1, SKU: "test", TaxRate: money.AmountFromInt(1)},
 			},
 		},
 	}
@@ -86,11 +92,11 @@ func TestProductController(t *testing.T) {
 					Id:          uint64(dataMap["id"].(float64)),
 					Name:        dataMap["name"].(string),
 					Description: dataMap["description"].(string),
-					Price:       dataMap["price"].(float64),
+					Price:       money.MustParse(dataMap["price"].(map[string]interface{})["amount"].(string), dataMap["price"].(map[string]interface{})["currency"].(string)),
 					StockQty:    int(dataMap["stock_qty"].(float64)),
 					CategoryID:  uint64(dataMap["category_id"].(float64)),
 					SKU:         dataMap["sku"].(string),
-					TaxRate:     dataMap["tax_rate"].(float64),
+					TaxRate:     money.MustParse(dataMap["tax_rate"].(string), "").Amount,
 				}
 			}
 
@@ -98,3 +104,260 @@ func TestProductController(t *testing.T) {
 		})
 	}
 }
+
+func TestProductControllerLookup(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockProductService(ctrl)
+	app := fiber.New()
+	app.Get("/api/products/lookup", NewProductController(mockService).Lookup)
+
+	tests := []struct {
+		name           string
+		url            string
+		setupMock      func()
+		expectedStatus int
+	}{
+		{
+			name: "Lookup product - success",
+			url:  "/api/products/lookup?code=4006381333931",
+			setupMock: func() {
+				mockService.EXPECT().
+					Lookup(gomock.Any(), "4006381333931").
+					Return(web.ProductLookupResponse{Code: "4006381333931", PackQty: 12, Product: web.ProductResponse{Id: 1, Name: "Test"}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Lookup product - missing code",
+			url:            "/api/products/lookup",
+			setupMock:      func() {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Lookup product - invalid barcode",
+			url:  "/api/products/lookup?code=123",
+			setupMock: func() {
+				mockService.EXPECT().
+					Lookup(gomock.Any(), "123").
+					Return(web.ProductLookupResponse{}, exception.NewBadRequestError("Invalid barcode 123"))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Lookup product - not found",
+			url:  "/api/products/lookup?code=96385074",
+			setupMock: func() {
+				mockService.EXPECT().
+					Lookup(gomock.Any(), "96385074").
+					Return(web.ProductLookupResponse{}, exception.NewNotFoundError("Product not found"))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+
+			req := httptest.NewRequest("GET", tt.url, nil)
+			resp, _ := app.Test(req)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestProductControllerPreconditions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockProductService(ctrl)
+	app := setupTestAppProduct(mockService)
+
+	tests := []struct {
+		name           string
+		method         string
+		url            string
+		headers        map[string]string
+		setupMock      func()
+		expectedStatus int
+		expectedETag   string
+	}{
+		{
+			name:    "Find product - not modified",
+			method:  "GET",
+			url:     "/api/products/1",
+			headers: map[string]string{fiber.HeaderIfNoneMatch: `"1", W/"2"`},
+			setupMock: func() {
+				mockService.EXPECT().FindById(gomock.Any(), uint64(1)).Return(web.ProductResponse{Id: 1, Version: 2}, nil)
+			},
+			expectedStatus: http.StatusNotModified,
+			expectedETag:   `"2"`,
+		},
+		{
+			name:    "Find product - changed since",
+			method:  "GET",
+			url:     "/api/products/1",
+			headers: map[string]string{fiber.HeaderIfNoneMatch: `"1"`},
+			setupMock: func() {
+				mockService.EXPECT().FindById(gomock.Any(), uint64(1)).Return(web.ProductResponse{Id: 1, Version: 2}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedETag:   `"2"`,
+		},
+		{
+			name:    "Update product - version moved",
+			method:  "PUT",
+			url:     "/api/products/1",
+			headers: map[string]string{fiber.HeaderIfMatch: `"2"`},
+			setupMock: func() {
+				mockService.EXPECT().
+					Update(gomock.Any(), gomock.Cond(func(request web.ProductUpdateRequest) bool { return request.Version == 2 })).
+					Return(web.ProductResponse{}, exception.NewPreconditionFailedError("Product has been modified by another request"))
+			},
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:    "Update product - new etag",
+			method:  "PUT",
+			url:     "/api/products/1",
+			headers: map[string]string{fiber.HeaderIfMatch: `"2"`},
+			setupMock: func() {
+				mockService.EXPECT().Update(gomock.Any(), gomock.Any()).Return(web.ProductResponse{Id: 1, Version: 3}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedETag:   `"3"`,
+		},
+		{
+			name:           "Delete product - weak etag never matches",
+			method:         "DELETE",
+			url:            "/api/products/1",
+			headers:        map[string]string{fiber.HeaderIfMatch: `W/"2"`},
+			setupMock:      func() {},
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:    "Delete product - matching version",
+			method:  "DELETE",
+			url:     "/api/products/1",
+			headers: map[string]string{fiber.HeaderIfMatch: `"2"`},
+			setupMock: func() {
+				mockService.EXPECT().Delete(gomock.Any(), uint64(1), uint64(2)).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:    "Delete product - any of several versions",
+			method:  "DELETE",
+			url:     "/api/products/1",
+			headers: map[string]string{fiber.HeaderIfMatch: `W/"1", "2" , "3"`},
+			setupMock: func() {
+				ifMatch := gomock.Cond(func(ctx context.Context) bool {
+					return slices.Equal([]uint64{2, 3}, helper.IfMatchFromContext(ctx))
+				})
+				mockService.EXPECT().Delete(ifMatch, uint64(1), uint64(0)).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:    "Delete product - any existing version",
+			method:  "DELETE",
+			url:     "/api/products/1",
+			headers: map[string]string{fiber.HeaderIfMatch: `*`},
+			setupMock: func() {
+				mockService.EXPECT().Delete(gomock.Any(), uint64(1), uint64(0)).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+
+			req := httptest.NewRequest(tt.method, tt.url, bytes.NewReader([]byte(`{}`)))
+			req.Header.Set("Content-Type", "application/json")
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+
+			resp, _ := app.Test(req)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			if tt.expectedETag != "" {
+				assert.Equal(t, tt.expectedETag, resp.Header.Get(fiber.HeaderETag))
+			}
+		})
+	}
+}
+
+func TestProductControllerPatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockProductService(ctrl)
+	app := setupTestAppProduct(mockService)
+
+	tests := []struct {
+		name           string
+		contentType    string
+		headers        map[string]string
+		body           string
+		setupMock      func()
+		expectedStatus int
+		expectedETag   string
+	}{
+		{
+			name:        "Merge patch",
+			contentType: "application/merge-patch+json; charset=utf-8",
+			headers:     map[string]string{fiber.HeaderIfMatch: `"2"`},
+			body:        `{"description":null}`,
+			setupMock: func() {
+				mockService.EXPECT().Patch(gomock.Any(), web.PatchRequest{
+					Id:          1,
+					ContentType: "application/merge-patch+json",
+					Patch:       []byte(`{"description":null}`),
+					Version:     2,
+				}).Return(web.ProductResponse{Id: 1, Version: 3}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedETag:   `"3"`,
+		},
+		{
+			name:        "JSON patch rejected",
+			contentType: "application/json-patch+json",
+			body:        `[{"op":"test","path":"/sku","value":"other"}]`,
+			setupMock: func() {
+				mockService.EXPECT().
+					Patch(gomock.Any(), gomock.Cond(func(request web.PatchRequest) bool { return request.ContentType == "application/json-patch+json" })).
+					Return(web.ProductResponse{}, exception.NewBadRequestError("Invalid patch: operation 0 (test /sku): test failed"))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Unsupported content type",
+			contentType:    "text/plain",
+			body:           `name=Patched`,
+			setupMock:      func() {},
+			expectedStatus: http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+
+			req := httptest.NewRequest("PATCH", "/api/products/1", bytes.NewReader([]byte(tt.body)))
+			req.Header.Set("Content-Type", tt.contentType)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+
+			resp, _ := app.Test(req)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			if tt.expectedETag != "" {
+				assert.Equal(t, tt.expectedETag, resp.Header.Get(fiber.HeaderETag))
+			}
+		})
+	}
+}
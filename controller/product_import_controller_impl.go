@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/service"
+	"github.com/gofiber/fiber/v2"
+	"path/filepath"
+	"strings"
+)
+
+type ProductImportControllerImpl struct {
+	ProductImportService service.ProductImportService
+}
+
+func NewProductImportController(productImportService service.ProductImportService) ProductImportController {
+	return &ProductImportControllerImpl{
+		ProductImportService: productImportService,
+	}
+}
+
+// Import Products from an uploaded CSV or XLSX file
+func (controller *ProductImportControllerImpl) Import(c *fiber.Ctx) error {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Bad Request",
+			Data:   "file is required",
+		})
+	}
+
+	// The format defaults to the file extension
+	format := c.Query("format")
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(fileHeader.Filename)), ".")
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return errorResponse(c, err)
+	}
+	defer file.Close()
+
+	importResponse, err := controller.ProductImportService.Import(c.Context(), file, format, c.QueryBool("dry_run"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   importResponse,
+	})
+}
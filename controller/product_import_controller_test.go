@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"bytes"
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/service/mocks"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func setupTestAppProductImport(mockService *mocks.MockProductImportService) *fiber.App {
+	app := fiber.New()
+	importController := NewProductImportController(mockService)
+
+	app.Post("/api/products/import", importController.Import)
+
+	return app
+}
+
+func TestProductImportController(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockProductImportService(ctrl)
+	app := setupTestAppProductImport(mockService)
+
+	tests := []struct {
+		name           string
+		url            string
+		filename       string
+		setupMock      func()
+		expectedStatus int
+	}{
+		{
+			name:     "Import - dry run from extension",
+			url:      "/api/products/import?dry_run=true",
+			filename: "products.CSV",
+			setupMock: func() {
+				mockService.EXPECT().
+					Import(gomock.Any(), gomock.Any(), "csv", true).
+					Return(web.ProductImportResponse{DryRun: true, Total: 1, Created: 1}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:     "Import - explicit format",
+			url:      "/api/products/import?format=xlsx",
+			filename: "upload",
+			setupMock: func() {
+				mockService.EXPECT().
+					Import(gomock.Any(), gomock.Any(), "xlsx", false).
+					Return(web.ProductImportResponse{}, exception.NewBadRequestError("Missing column sku"))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Import - missing file",
+			url:            "/api/products/import",
+			setupMock:      func() {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+
+			var reqBody bytes.Buffer
+			writer := multipart.NewWriter(&reqBody)
+			if tt.filename != "" {
+				part, _ := writer.CreateFormFile("file", tt.filename)
+				part.Write([]byte("name,sku\n"))
+			}
+			writer.Close()
+
+			req := httptest.NewRequest(http.MethodPost, tt.url, &reqBody)
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+
+			resp, _ := app.Test(req)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/service"
+	"github.com/gofiber/fiber/v2"
+	"strconv"
+)
+
+type ProductPriceControllerImpl struct {
+	ProductPriceService service.ProductPriceService
+}
+
+func NewProductPriceController(productPriceService service.ProductPriceService) ProductPriceController {
+	return &ProductPriceControllerImpl{
+		ProductPriceService: productPriceService,
+	}
+}
+
+// Schedule Price change
+func (controller *ProductPriceControllerImpl) Schedule(c *fiber.Ctx) error {
+	scheduleRequest := new(web.ProductPriceScheduleRequest)
+	if err := c.BodyParser(scheduleRequest); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Bad Request",
+			Data:   err.Error(),
+		})
+	}
+
+	productId, err := strconv.ParseUint(c.Params("productId"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Invalid Product ID",
+			Data:   err.Error(),
+		})
+	}
+	scheduleRequest.ProductId = productId
+
+	priceResponse, err := controller.ProductPriceService.Schedule(c.Context(), *scheduleRequest)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(web.WebResponse{
+		Code:   fiber.StatusCreated,
+		Status: "Created",
+		Data:   priceResponse,
+	})
+}
+
+// Cancel pending Price change
+func (controller *ProductPriceControllerImpl) Cancel(c *fiber.Ctx) error {
+	productId, err := strconv.ParseUint(c.Params("productId"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Invalid Product ID",
+			Data:   err.Error(),
+		})
+	}
+
+	priceId, err := strconv.ParseUint(c.Params("priceId"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Invalid Price ID",
+			Data:   err.Error(),
+		})
+	}
+
+	err = controller.ProductPriceService.Cancel(c.Context(), productId, priceId)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "Deleted Successfully",
+	})
+}
+
+// Find Price history and pending changes
+func (controller *ProductPriceControllerImpl) FindAll(c *fiber.Ctx) error {
+	productId, err := strconv.ParseUint(c.Params("productId"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Invalid Product ID",
+			Data:   err.Error(),
+		})
+	}
+
+	historyResponse, err := controller.ProductPriceService.FindAll(c.Context(), productId)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   historyResponse,
+	})
+}
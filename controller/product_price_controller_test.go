@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/model/money"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/service/mocks"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func setupTestAppProductPrice(mockService *mocks.MockProductPriceService) *fiber.App {
+	app := fiber.New()
+	priceController := NewProductPriceController(mockService)
+
+	prices := app.Group("/api/products/:productId/prices")
+	prices.Get("/", priceController.FindAll)
+	prices.Post("/", priceController.Schedule)
+	prices.Delete("/:priceId", priceController.Cancel)
+
+	return app
+}
+
+func TestProductPriceController(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockProductPriceService(ctrl)
+	app := setupTestAppProductPrice(mockService)
+	monday := time.Date(2030, 1, 7, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		method         string
+		url            string
+		body           interface{}
+		setupMock      func()
+		expectedStatus int
+	}{
+		{
+			name:   "Schedule price - success",
+			method: "POST",
+			url:    "/api/products/1/prices",
+			body:   web.ProductPriceScheduleRequest{Price: money.FromInt(80, "IDR"), EffectiveFrom: monday},
+			setupMock: func() {
+				mockService.EXPECT().
+					Schedule(gomock.Any(), web.ProductPriceScheduleRequest{ProductId: 1, Price: money.FromInt(80, "IDR"), EffectiveFrom: monday}).
+					Return(web.ProductPriceResponse{Id: 3, ProductId: 1, Price: money.FromInt(80, "IDR"), EffectiveFrom: monday}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:   "Cancel price - already applied",
+			method: "DELETE",
+			url:    "/api/products/1/prices/3",
+			setupMock: func() {
+				mockService.EXPECT().
+					Cancel(gomock.Any(), uint64(1), uint64(3)).
+					Return(exception.NewBadRequestError("Price has already been applied"))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Find prices - product not found",
+			method: "GET",
+			url:    "/api/products/9/prices",
+			setupMock: func() {
+				mockService.EXPECT().
+					FindAll(gomock.Any(), uint64(9)).
+					Return(web.ProductPriceHistoryResponse{}, exception.NewNotFoundError("Product not found"))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+
+			var reqBody []byte
+			if tt.body != nil {
+				reqBody, _ = json.Marshal(tt.body)
+			}
+
+			req := httptest.NewRequest(tt.method, tt.url, bytes.NewReader(reqBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, _ := app.Test(req)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package controller
+
+import (
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/service"
+	"github.com/gofiber/fiber/v2"
+	"strconv"
+)
+
+type ProductVariantControllerImpl struct {
+	ProductVariantService service.ProductVariantService
+}
+
+func NewProductVariantController(productVariantService service.ProductVariantService) ProductVariantController {
+	return &ProductVariantControllerImpl{
+		ProductVariantService: productVariantService,
+	}
+}
+
+// Create Variant
+func (controller *ProductVariantControllerImpl) Create(c *fiber.Ctx) error {
+	variantCreateRequest := new(web.ProductVariantCreateRequest)
+	if err := c.BodyParser(variantCreateRequest); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Bad Request",
+			Data:   err.Error(),
+		})
+	}
+
+	productId, err := strconv.ParseUint(c.Params("productId"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Invalid Product ID",
+			Data:   err.Error(),
+		})
+	}
+	variantCreateRequest.ProductId = productId
+
+	variantResponse, err := controller.ProductVariantService.Create(c.Context(), *variantCreateRequest)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(web.WebResponse{
+		Code:   fiber.StatusCreated,
+		Status: "Created",
+		Data:   variantResponse,
+	})
+}
+
+// Update Variant
+func (controller *ProductVariantControllerImpl) Update(c *fiber.Ctx) error {
+	variantUpdateRequest := new(web.ProductVariantUpdateRequest)
+	if err := c.BodyParser(variantUpdateRequest); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Bad Request",
+			Data:   err.Error(),
+		})
+	}
+
+	productId, err := strconv.ParseUint(c.Params("productId"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Invalid Product ID",
+			Data:   err.Error(),
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("variantId"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Invalid Variant ID",
+			Data:   err.Error(),
+		})
+	}
+	variantUpdateRequest.ProductId = productId
+	variantUpdateRequest.Id = id
+
+	variantResponse, err := controller.ProductVariantService.Update(c.Context(), *variantUpdateRequest)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   variantResponse,
+	})
+}
+
+// Delete Variant
+func (controller *ProductVariantControllerImpl) Delete(c *fiber.Ctx) error {
+	productId, err := strconv.ParseUint(c.Params("productId"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Invalid Product ID",
+			Data:   err.Error(),
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("variantId"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Invalid Variant ID",
+			Data:   err.Error(),
+		})
+	}
+
+	err = controller.ProductVariantService.Delete(c.Context(), productId, id)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "Deleted Successfully",
+	})
+}
+
+// Find All Variants of a Product
+func (controller *ProductVariantControllerImpl) FindAll(c *fiber.Ctx) error {
+	productId, err := strconv.ParseUint(c.Params("productId"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Invalid Product ID",
+			Data:   err.Error(),
+		})
+	}
+
+	variantResponses, err := controller.ProductVariantService.FindAll(c.Context(), productId)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   variantResponses,
+	})
+}
+
+// Generate Variants from option dimensions
+func (controller *ProductVariantControllerImpl) Generate(c *fiber.Ctx) error {
+	generateRequest := new(web.ProductVariantGenerateRequest)
+	if err := c.BodyParser(generateRequest); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Bad Request",
+			Data:   err.Error(),
+		})
+	}
+
+	productId, err := strconv.ParseUint(c.Params("productId"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+			Code:   fiber.StatusBadRequest,
+			Status: "Invalid Product ID",
+			Data:   err.Error(),
+		})
+	}
+	generateRequest.ProductId = productId
+
+	variantResponses, err := controller.ProductVariantService.Generate(c.Context(), *generateRequest)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(web.WebResponse{
+		Code:   fiber.StatusOK,
+		Status: "OK",
+		Data:   variantResponses,
+	})
+}
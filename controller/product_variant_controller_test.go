@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/service/mocks"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func setupTestAppProductVariant(mockService *mocks.MockProductVariantService) *fiber.App {
+	app := fiber.New()
+	variantController := NewProductVariantController(mockService)
+
+	variants := app.Group("/api/products/:productId/variants")
+	variants.Get("/", variantController.FindAll)
+	variants.Post("/", variantController.Create)
+	variants.Post("/generate", variantController.Generate)
+	variants.Put("/:variantId", variantController.Update)
+	variants.Delete("/:variantId", variantController.Delete)
+
+	return app
+}
+
+func TestProductVariantController(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockProductVariantService(ctrl)
+	app := setupTestAppProductVariant(mockService)
+
+	tests := []struct {
+		name           string
+		method         string
+		url            string
+		body           interface{}
+		setupMock      func()
+		expectedStatus int
+	}{
+		{
+			name:   "Generate variants - success",
+			method: "POST",
+			url:    "/api/products/1/variants/generate",
+			body:   web.ProductVariantGenerateRequest{Options: []web.ProductOptionRequest{{Name: "Size", Values: []string{"S", "M"}}}},
+			setupMock: func() {
+				mockService.EXPECT().
+					Generate(gomock.Any(), web.ProductVariantGenerateRequest{ProductId: 1, Options: []web.ProductOptionRequest{{Name: "Size", Values: []string{"S", "M"}}}}).
+					Return([]web.ProductVariantResponse{{Id: 1, ProductId: 1, SKU: "TS-S"}, {Id: 2, ProductId: 1, SKU: "TS-M"}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "Create variant - invalid option value",
+			method: "POST",
+			url:    "/api/products/1/variants",
+			body:   web.ProductVariantCreateRequest{Options: map[string]string{"Size": "XL"}, SKU: "TS-XL"},
+			setupMock: func() {
+				mockService.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					Return(web.ProductVariantResponse{}, exception.NewBadRequestError("Invalid value XL for option Size"))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Update variant - not found",
+			method: "PUT",
+			url:    "/api/products/1/variants/9",
+			body:   web.ProductVariantUpdateRequest{SKU: "TS-M"},
+			setupMock: func() {
+				mockService.EXPECT().
+					Update(gomock.Any(), web.ProductVariantUpdateRequest{Id: 9, ProductId: 1, SKU: "TS-M"}).
+					Return(web.ProductVariantResponse{}, exception.NewNotFoundError("Variant not found"))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "Delete variant - success",
+			method: "DELETE",
+			url:    "/api/products/1/variants/2",
+			setupMock: func() {
+				mockService.EXPECT().Delete(gomock.Any(), uint64(1), uint64(2)).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "Find all variants - success",
+			method: "GET",
+			url:    "/api/products/1/variants",
+			setupMock: func() {
+				mockService.EXPECT().FindAll(gomock.Any(), uint64(1)).Return([]web.ProductVariantResponse{{Id: 1, ProductId: 1, SKU: "TS-S"}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+
+			var reqBody []byte
+			if tt.body != nil {
+				reqBody, _ = json.Marshal(tt.body)
+			}
+
+			req := httptest.NewRequest(tt.method, tt.url, bytes.NewReader(reqBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, _ := app.Test(req)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+		})
+	}
+}
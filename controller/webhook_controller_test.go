@@ -0,0 +1,134 @@
+package controller
+
+import (
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/service/mocks"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func setupTestAppWebhook(mockService *mocks.MockWebhookService) *fiber.App {
+	app := fiber.New()
+	webhookController := NewWebhookController(mockService)
+
+	app.Get("/api/webhooks", webhookController.FindAll)
+	app.Get("/api/webhooks/:webhookId", webhookController.FindById)
+	app.Get("/api/webhooks/:webhookId/deliveries", webhookController.FindDeliveries)
+	app.Post("/api/webhooks", webhookController.Create)
+	app.Post("/api/webhooks/:webhookId/deliveries/:deliveryId/retry", webhookController.Redeliver)
+	app.Put("/api/webhooks/:webhookId", webhookController.Update)
+	app.Delete("/api/webhooks/:webhookId", webhookController.Delete)
+
+	return app
+}
+
+func TestWebhookController(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockWebhookService(ctrl)
+	app := setupTestAppWebhook(mockService)
+
+	tests := []struct {
+		name           string
+		method         string
+		url            string
+		body           string
+		setupMock      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "Create webhook",
+			method: "POST",
+			url:    "/api/webhooks",
+			body:   `{"url":"https://shop.example.com/hooks","events":["PriceChanged"]}`,
+			setupMock: func() {
+				mockService.EXPECT().Create(gomock.Any(), web.WebhookCreateRequest{Url: "https://shop.example.com/hooks", Events: []string{"PriceChanged"}}).
+					Return(web.WebhookResponse{Id: 1, Url: "https://shop.example.com/hooks", Secret: "s3cr3t", Events: []string{"PriceChanged"}, Active: true}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `"secret":"s3cr3t"`,
+		},
+		{
+			name:   "Update webhook",
+			method: "PUT",
+			url:    "/api/webhooks/1",
+			body:   `{"url":"https://shop.example.com/hooks","active":false}`,
+			setupMock: func() {
+				mockService.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, request web.WebhookUpdateRequest) (web.WebhookResponse, error) {
+					assert.Equal(t, uint64(1), request.Id)
+					assert.False(t, *request.Active)
+					return web.WebhookResponse{Id: 1, Url: request.Url}, nil
+				})
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "Find unknown webhook",
+			method: "GET",
+			url:    "/api/webhooks/9",
+			setupMock: func() {
+				mockService.EXPECT().FindById(gomock.Any(), uint64(9)).Return(web.WebhookResponse{}, exception.NewNotFoundError("Webhook not found"))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "Delivery log",
+			method: "GET",
+			url:    "/api/webhooks/1/deliveries",
+			setupMock: func() {
+				mockService.EXPECT().FindDeliveries(gomock.Any(), uint64(1)).
+					Return([]web.WebhookDeliveryResponse{{Id: 3, WebhookId: 1, EventType: "PriceChanged", Status: "dead", Attempts: 8}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"status":"dead"`,
+		},
+		{
+			name:   "Retry delivery",
+			method: "POST",
+			url:    "/api/webhooks/1/deliveries/3/retry",
+			setupMock: func() {
+				mockService.EXPECT().Redeliver(gomock.Any(), uint64(1), uint64(3)).Return(web.WebhookDeliveryResponse{Id: 3, Status: "pending"}, nil)
+			},
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name:           "Invalid delivery id",
+			method:         "POST",
+			url:            "/api/webhooks/1/deliveries/abc/retry",
+			setupMock:      func() {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Delete webhook",
+			method: "DELETE",
+			url:    "/api/webhooks/1",
+			setupMock: func() {
+				mockService.EXPECT().Delete(gomock.Any(), uint64(1)).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+
+			req := httptest.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, _ := app.Test(req)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			body, _ := io.ReadAll(resp.Body)
+			assert.Contains(t, string(body), tt.expectedBody)
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package exception
+
+type PreconditionFailedError struct {
+	Message string
+}
+
+func (e PreconditionFailedError) Error() string {
+	return e.Message
+}
+
+func NewPreconditionFailedError(message string) error {
+	return PreconditionFailedError{Message: message}
+}
@@ -0,0 +1,13 @@
+package exception
+
+type ServiceUnavailableError struct {
+	Message string
+}
+
+func (e ServiceUnavailableError) Error() string {
+	return e.Message
+}
+
+func NewServiceUnavailableError(message string) error {
+	return ServiceUnavailableError{Message: message}
+}
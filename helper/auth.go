@@ -0,0 +1,44 @@
+package helper
+
+import "context"
+
+type contextKey string
+
+// EmployeeIdKey holds the id of the acting employee, taken from the X-Employee-ID header
+const EmployeeIdKey contextKey = "employeeId"
+
+// EmployeeIdFromContext returns the acting employee of the request, or nil when unknown
+func EmployeeIdFromContext(ctx context.Context) *uint64 {
+	if employeeId, ok := ctx.Value(EmployeeIdKey).(uint64); ok {
+		return &employeeId
+	}
+	return nil
+}
+
+// IncludeDeletedKey is set when a manager listed soft-deleted rows with ?include_deleted=true
+const IncludeDeletedKey contextKey = "includeDeleted"
+
+// IncludeDeletedFromContext reports whether reads of the request should also return soft-deleted rows
+func IncludeDeletedFromContext(ctx context.Context) bool {
+	includeDeleted, _ := ctx.Value(IncludeDeletedKey).(bool)
+	return includeDeleted
+}
+
+// RequestIdKey holds the id of the request, taken from X-Request-ID or generated when absent
+const RequestIdKey contextKey = "requestId"
+
+// RequestIdFromContext returns the id of the request, or an empty string outside of one
+func RequestIdFromContext(ctx context.Context) string {
+	requestId, _ := ctx.Value(RequestIdKey).(string)
+	return requestId
+}
+
+// IfMatchKey holds the versions of an If-Match header that lists more than one entity tag
+const IfMatchKey contextKey = "ifMatch"
+
+// IfMatchFromContext returns the versions a write of the request may apply to, or nil when
+// If-Match did not list several of them
+func IfMatchFromContext(ctx context.Context) []uint64 {
+	versions, _ := ctx.Value(IfMatchKey).([]uint64)
+	return versions
+}
@@ -0,0 +1,41 @@
+package helper
+
+// IsValidBarcode checks the length and check digit of an EAN-8, UPC-A (12 digits) or EAN-13 code
+func IsValidBarcode(code string) bool {
+	switch len(code) {
+	case 8, 12, 13:
+	default:
+		return false
+	}
+
+	sum := 0
+	for i := 0; i < len(code)-1; i++ {
+		digit := code[i]
+		if digit < '0' || digit > '9' {
+			return false
+		}
+
+		// Weights alternate 3,1,3,1... counting from the digit next to the check digit
+		weight := 1
+		if (len(code)-2-i)%2 == 0 {
+			weight = 3
+		}
+		sum += int(digit-'0') * weight
+	}
+
+	checkDigit := code[len(code)-1]
+	if checkDigit < '0' || checkDigit > '9' {
+		return false
+	}
+
+	return (10-sum%10)%10 == int(checkDigit-'0')
+}
+
+// NormalizeBarcode stores a UPC-A code in its EAN-13 form, which is the same digits behind a
+// leading 0 with the same check digit, so both forms of an item are one code
+func NormalizeBarcode(code string) string {
+	if len(code) == 12 {
+		return "0" + code
+	}
+	return code
+}
@@ -0,0 +1,47 @@
+package helper
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ETag is the strong entity tag of a row version, e.g. "3"
+func ETag(version uint64) string {
+	return `"` + strconv.FormatUint(version, 10) + `"`
+}
+
+// ParseETag reads the version back from a strong entity tag; weak tags never match If-Match
+func ParseETag(tag string) (uint64, bool) {
+	tag = strings.TrimSpace(tag)
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return 0, false
+	}
+	version, err := strconv.ParseUint(tag[1:len(tag)-1], 10, 64)
+	return version, err == nil
+}
+
+// ParseIfMatch reads the versions listed by an If-Match header, e.g. `"3", "4"`; any is set
+// when it holds "*". Weak and malformed tags never match, so they are left out
+func ParseIfMatch(header string) (versions []uint64, any bool) {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return nil, true
+		}
+		if version, ok := ParseETag(tag); ok {
+			versions = append(versions, version)
+		}
+	}
+	return versions, false
+}
+
+// ETagMatchesAny reports whether an If-None-Match header lists etag, comparing weakly
+func ETagMatchesAny(header string, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+	return false
+}
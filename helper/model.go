@@ -2,13 +2,19 @@ package helper
 
 import (
 	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/money"
 	"github.com/aronipurwanto/go-restful-api/model/web"
+	"gorm.io/gorm"
+	"time"
 )
 
 func ToCategoryResponse(category domain.Category) web.CategoryResponse {
 	return web.CategoryResponse{
-		Id:   category.Id,
-		Name: category.Name,
+		Id:        category.Id,
+		Name:      category.Name,
+		ParentId:  category.ParentId,
+		Version:   category.Version,
+		DeletedAt: deletedAt(category.DeletedAt),
 	}
 }
 
@@ -28,6 +34,8 @@ func ToCustomerResponse(customer domain.Customer) web.CustomerResponse {
 		Phone:      customer.Phone,
 		Address:    customer.Address,
 		LoyaltyPts: customer.LoyaltyPts,
+		Version:    customer.Version,
+		DeletedAt:  deletedAt(customer.DeletedAt),
 	}
 }
 
@@ -49,6 +57,11 @@ func ToProductResponse(product domain.Product) web.ProductResponse {
 		CategoryID:  product.CategoryId,
 		SKU:         product.SKU,
 		TaxRate:     product.TaxRate,
+		Barcodes:    ToProductBarcodeResponses(product.Barcodes),
+		Options:     ToProductOptionResponses(product.Options),
+		Variants:    ToProductVariantResponses(product, product.Variants),
+		Version:     product.Version,
+		DeletedAt:   deletedAt(product.DeletedAt),
 	}
 }
 
@@ -60,6 +73,97 @@ func ToProductResponses(products []domain.Product) []web.ProductResponse {
 	return productResponses
 }
 
+func ToProductBarcodeResponses(barcodes []domain.ProductBarcode) []web.ProductBarcodeResponse {
+	var barcodeResponses []web.ProductBarcodeResponse
+	for _, barcode := range barcodes {
+		barcodeResponses = append(barcodeResponses, web.ProductBarcodeResponse{
+			Code:    barcode.Code,
+			PackQty: barcode.PackQty,
+		})
+	}
+	return barcodeResponses
+}
+
+func ToProductOptionResponses(options []domain.ProductOption) []web.ProductOptionResponse {
+	var optionResponses []web.ProductOptionResponse
+	for _, option := range options {
+		optionResponses = append(optionResponses, web.ProductOptionResponse{
+			Name:   option.Name,
+			Values: option.Values,
+		})
+	}
+	return optionResponses
+}
+
+// ToProductVariantResponse resolves the variant price from the parent product when no override is set
+func ToProductVariantResponse(product domain.Product, variant domain.ProductVariant) web.ProductVariantResponse {
+	price := product.Price
+	var priceOverride *money.Money
+	if variant.PriceOverride != nil {
+		price = money.New(*variant.PriceOverride, product.Price.Currency)
+		priceOverride = &price
+	}
+
+	return web.ProductVariantResponse{
+		Id:            variant.Id,
+		ProductId:     variant.ProductId,
+		SKU:           variant.SKU,
+		Options:       variant.Options,
+		Price:         price,
+		PriceOverride: priceOverride,
+		StockQty:      variant.StockQty,
+	}
+}
+
+func ToProductVariantResponses(product domain.Product, variants []domain.ProductVariant) []web.ProductVariantResponse {
+	var variantResponses []web.ProductVariantResponse
+	for _, variant := range variants {
+		variantResponses = append(variantResponses, ToProductVariantResponse(product, variant))
+	}
+	return variantResponses
+}
+
+func ToProductPriceResponse(price domain.ProductPrice) web.ProductPriceResponse {
+	var previousPrice *money.Money
+	if price.PreviousPrice != nil {
+		previous := money.New(*price.PreviousPrice, price.Price.Currency)
+		previousPrice = &previous
+	}
+
+	return web.ProductPriceResponse{
+		Id:            price.Id,
+		ProductId:     price.ProductId,
+		Price:         price.Price,
+		PreviousPrice: previousPrice,
+		EffectiveFrom: price.EffectiveFrom,
+		AppliedAt:     price.AppliedAt,
+		EmployeeId:    price.EmployeeId,
+	}
+}
+
+func ToProductPriceHistoryResponse(prices []domain.ProductPrice) web.ProductPriceHistoryResponse {
+	response := web.ProductPriceHistoryResponse{
+		History: []web.ProductPriceResponse{},
+		Pending: []web.ProductPriceResponse{},
+	}
+	for _, price := range prices {
+		if price.AppliedAt == nil {
+			response.Pending = append(response.Pending, ToProductPriceResponse(price))
+		} else {
+			response.History = append(response.History, ToProductPriceResponse(price))
+		}
+	}
+	return response
+}
+
+func ToProductLookupResponse(barcode domain.ProductBarcode) web.ProductLookupResponse {
+	return web.ProductLookupResponse{
+		Code:    barcode.Code,
+		PackQty: barcode.PackQty,
+		Product: ToProductResponse(barcode.Product),
+	}
+}
+
 func ToEmployeeResponse(employee domain.Employee) web.EmployeeResponse {
 	return web.EmployeeResponse{
 		Id:        employee.EmployeeID,
@@ -67,6 +171,8 @@ func ToEmployeeResponse(employee domain.Employee) web.EmployeeResponse {
 		Email:     employee.Email,
 		Phone:     employee.Phone,
 		DateHired: employee.DateHired,
+		Version:   employee.Version,
+		DeletedAt: deletedAt(employee.DeletedAt),
 	}
 }
 
@@ -77,3 +183,147 @@ func ToEmployeeResponses(employees []domain.Employee) []web.EmployeeResponse {
 	}
 	return employeeResponses
 }
+
+func ToProductImportErrorResponses(productErrors []domain.ProductError) []web.ProductImportErrorResponse {
+	responses := make([]web.ProductImportErrorResponse, 0, len(productErrors))
+	for _, productError := range productErrors {
+		responses = append(responses, web.ProductImportErrorResponse{
+			Line:  productError.Line,
+			SKU:   productError.Product.SKU,
+			Name:  productError.Product.Name,
+			Error: productError.Error.Error(),
+		})
+	}
+	return responses
+}
+
+func ToCategoryUpdateRequest(category domain.Category) web.CategoryUpdateRequest {
+	return web.CategoryUpdateRequest{
+		Id:      category.Id,
+		Name:    category.Name,
+		Version: category.Version,
+	}
+}
+
+func ToCustomerUpdateRequest(customer domain.Customer) web.CustomerUpdateRequest {
+	return web.CustomerUpdateRequest{
+		Id:         customer.CustomerID,
+		Name:       customer.Name,
+		Email:      customer.Email,
+		Phone:      customer.Phone,
+		Address:    customer.Address,
+		LoyaltyPts: customer.LoyaltyPts,
+		Version:    customer.Version,
+	}
+}
+
+func ToEmployeeUpdateRequest(employee domain.Employee) web.EmployeeUpdateRequest {
+	return web.EmployeeUpdateRequest{
+		Id:        employee.EmployeeID,
+		Name:      employee.Name,
+		Email:     employee.Email,
+		Phone:     employee.Phone,
+		DateHired: employee.DateHired,
+		Version:   employee.Version,
+	}
+}
+
+func ToProductUpdateRequest(product domain.Product) web.ProductUpdateRequest {
+	var barcodes []web.ProductBarcodeRequest
+	for _, barcode := range product.Barcodes {
+		barcodes = append(barcodes, web.ProductBarcodeRequest{Code: barcode.Code, PackQty: barcode.PackQty})
+	}
+
+	return web.ProductUpdateRequest{
+		Id:          product.ProductID,
+		Name:        product.Name,
+		Description: product.Description,
+		Price:       product.Price,
+		StockQty:    product.StockQty,
+		CategoryID:  int(product.CategoryId),
+		SKU:         product.SKU,
+		TaxRate:     product.TaxRate,
+		Barcodes:    barcodes,
+		Version:     product.Version,
+	}
+}
+
+// deletedAt exposes the deletion time of a soft-deleted row, nil while it is live
+func deletedAt(deleted gorm.DeletedAt) *time.Time {
+	if !deleted.Valid {
+		return nil
+	}
+	return &deleted.Time
+}
+
+func ToAuditLogResponses(logs []domain.AuditLog) []web.AuditLogResponse {
+	var logResponses []web.AuditLogResponse
+	for _, log := range logs {
+		logResponses = append(logResponses, web.AuditLogResponse{
+			Id:        log.Id,
+			Entity:    log.Entity,
+			EntityId:  log.EntityId,
+			Action:    log.Action,
+			ActorId:   log.ActorId,
+			RequestId: log.RequestId,
+			Changes:   log.Changes,
+			CreatedAt: log.CreatedAt,
+		})
+	}
+	return logResponses
+}
+
+// ToWebhookResponse leaves the secret out; it is only shown once, when the webhook is created
+func ToWebhookResponse(webhook domain.Webhook) web.WebhookResponse {
+	return web.WebhookResponse{
+		Id:        webhook.Id,
+		Url:       webhook.Url,
+		Events:    webhook.EventList(),
+		Active:    webhook.Active,
+		CreatedAt: webhook.CreatedAt,
+		UpdatedAt: webhook.UpdatedAt,
+	}
+}
+
+func ToWebhookResponses(webhooks []domain.Webhook) []web.WebhookResponse {
+	var webhookResponses []web.WebhookResponse
+	for _, webhook := range webhooks {
+		webhookResponses = append(webhookResponses, ToWebhookResponse(webhook))
+	}
+	return webhookResponses
+}
+
+func ToWebhookDeliveryResponse(delivery domain.WebhookDelivery) web.WebhookDeliveryResponse {
+	return web.WebhookDeliveryResponse{
+		Id:             delivery.Id,
+		WebhookId:      delivery.WebhookId,
+		EventId:        delivery.EventId,
+		EventType:      delivery.Event.Type,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		NextAttemptAt:  delivery.NextAttemptAt,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		DeliveredAt:    delivery.DeliveredAt,
+		CreatedAt:      delivery.CreatedAt,
+	}
+}
+
+func ToWebhookDeliveryResponses(deliveries []domain.WebhookDelivery) []web.WebhookDeliveryResponse {
+	var deliveryResponses []web.WebhookDeliveryResponse
+	for _, delivery := range deliveries {
+		deliveryResponses = append(deliveryResponses, ToWebhookDeliveryResponse(delivery))
+	}
+	return deliveryResponses
+}
+
+func ToWebhookEvent(event domain.OutboxEvent) web.WebhookEvent {
+	return web.WebhookEvent{
+		Id:            event.Id,
+		Type:          event.Type,
+		AggregateType: event.AggregateType,
+		AggregateId:   event.AggregateId,
+		OccurredAt:    event.CreatedAt,
+		Data:          event.Payload,
+	}
+}
@@ -0,0 +1,55 @@
+package helper
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestApplyJSONPatch(t *testing.T) {
+	document := `{"name":"Tea","tags":["hot","green"],"a/b":1,"m~n":2,"meta":{"origin":"JP"}}`
+
+	tests := []struct {
+		name     string
+		patch    string
+		expected string
+		err      string
+	}{
+		{name: "add member", patch: `[{"op":"add","path":"/price","value":3}]`, expected: `{"name":"Tea","tags":["hot","green"],"a/b":1,"m~n":2,"meta":{"origin":"JP"},"price":3}`},
+		{name: "add at index", patch: `[{"op":"add","path":"/tags/1","value":"iced"}]`, expected: `{"name":"Tea","tags":["hot","iced","green"],"a/b":1,"m~n":2,"meta":{"origin":"JP"}}`},
+		{name: "add with end index", patch: `[{"op":"add","path":"/tags/-","value":"iced"}]`, expected: `{"name":"Tea","tags":["hot","green","iced"],"a/b":1,"m~n":2,"meta":{"origin":"JP"}}`},
+		{name: "replace", patch: `[{"op":"replace","path":"/name","value":"Coffee"}]`, expected: `{"name":"Coffee","tags":["hot","green"],"a/b":1,"m~n":2,"meta":{"origin":"JP"}}`},
+		{name: "remove member", patch: `[{"op":"remove","path":"/meta"}]`, expected: `{"name":"Tea","tags":["hot","green"],"a/b":1,"m~n":2}`},
+		{name: "remove element", patch: `[{"op":"remove","path":"/tags/0"}]`, expected: `{"name":"Tea","tags":["green"],"a/b":1,"m~n":2,"meta":{"origin":"JP"}}`},
+		{name: "escaped slash", patch: `[{"op":"replace","path":"/a~1b","value":10}]`, expected: `{"name":"Tea","tags":["hot","green"],"a/b":10,"m~n":2,"meta":{"origin":"JP"}}`},
+		{name: "escaped tilde", patch: `[{"op":"remove","path":"/m~0n"}]`, expected: `{"name":"Tea","tags":["hot","green"],"a/b":1,"meta":{"origin":"JP"}}`},
+		{name: "move member", patch: `[{"op":"move","from":"/meta/origin","path":"/origin"}]`, expected: `{"name":"Tea","tags":["hot","green"],"a/b":1,"m~n":2,"meta":{},"origin":"JP"}`},
+		{name: "move element to end", patch: `[{"op":"move","from":"/tags/0","path":"/tags/-"}]`, expected: `{"name":"Tea","tags":["green","hot"],"a/b":1,"m~n":2,"meta":{"origin":"JP"}}`},
+		{name: "move onto itself", patch: `[{"op":"move","from":"/meta","path":"/meta"}]`, expected: document},
+		{name: "copy", patch: `[{"op":"copy","from":"/meta","path":"/origin"},{"op":"replace","path":"/origin/origin","value":"CN"}]`, expected: `{"name":"Tea","tags":["hot","green"],"a/b":1,"m~n":2,"meta":{"origin":"JP"},"origin":{"origin":"CN"}}`},
+		{name: "test passes", patch: `[{"op":"test","path":"/tags","value":["hot","green"]}]`, expected: document},
+		{name: "test fails", patch: `[{"op":"test","path":"/name","value":"Coffee"}]`, err: "operation 0 (test /name): test failed"},
+		{name: "move into child", patch: `[{"op":"move","from":"/meta","path":"/meta/inner"}]`, err: "operation 0 (move /meta/inner): cannot move a value into one of its children"},
+		{name: "move onto missing", patch: `[{"op":"move","from":"/missing","path":"/missing"}]`, err: "operation 0 (move /missing)"},
+		{name: "remove missing", patch: `[{"op":"remove","path":"/missing"}]`, err: "operation 0 (remove /missing)"},
+		{name: "end index outside add", patch: `[{"op":"remove","path":"/tags/-"}]`, err: "operation 0 (remove /tags/-)"},
+		{name: "index out of range", patch: `[{"op":"add","path":"/tags/3","value":"iced"}]`, err: "operation 0 (add /tags/3)"},
+		{name: "pointer without slash", patch: `[{"op":"remove","path":"name"}]`, err: "operation 0 (remove name)"},
+		{name: "unknown op", patch: `[{"op":"rename","path":"/name"}]`, err: "operation 0 (rename /name)"},
+		{name: "all or nothing", patch: `[{"op":"remove","path":"/name"},{"op":"remove","path":"/name"}]`, err: "operation 1 (remove /name)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patched, err := ApplyJSONPatch([]byte(document), []byte(tt.patch))
+			if tt.err != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), tt.err)
+				}
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.JSONEq(t, tt.expected, string(patched))
+			}
+		})
+	}
+}
@@ -0,0 +1,245 @@
+package helper
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/xuri/excelize/v2"
+	"io"
+	"strings"
+)
+
+// SpreadsheetRow is a non-empty row of an uploaded sheet with its 1-based line number
+type SpreadsheetRow struct {
+	Line  int
+	Cells []string
+}
+
+// ReadSpreadsheet reads every non-empty row of a CSV file or of the first sheet of an XLSX workbook
+func ReadSpreadsheet(r io.Reader, format string) ([]SpreadsheetRow, error) {
+	switch strings.ToLower(format) {
+	case "csv":
+		return readCSV(r)
+	case "xlsx":
+		return readXLSX(r)
+	default:
+		return nil, fmt.Errorf("unsupported format %q, expected csv or xlsx", format)
+	}
+}
+
+func readCSV(r io.Reader) ([]SpreadsheetRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var rows []SpreadsheetRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// Quoted cells may span lines, so ask the reader where the record started
+		line, _ := reader.FieldPos(0)
+		if len(rows) == 0 {
+			record[0] = strings.TrimPrefix(record[0], "\ufeff")
+		}
+		if isBlankRow(record) {
+			continue
+		}
+		rows = append(rows, SpreadsheetRow{Line: line, Cells: record})
+	}
+	return rows, nil
+}
+
+func readXLSX(r io.Reader) ([]SpreadsheetRow, error) {
+	file, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	sheets := file.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, errors.New("workbook has no sheets")
+	}
+
+	// Raw values keep numbers such as barcodes and prices free of display formatting
+	records, err := file.GetRows(sheets[0], excelize.Options{RawCellValue: true})
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []SpreadsheetRow
+	for i, record := range records {
+		if isBlankRow(record) {
+			continue
+		}
+		rows = append(rows, SpreadsheetRow{Line: i + 1, Cells: record})
+	}
+	return rows, nil
+}
+
+func isBlankRow(cells []string) bool {
+	for _, cell := range cells {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// SpreadsheetWriter writes rows below the header given to NewSpreadsheetWriter
+type SpreadsheetWriter interface {
+	WriteRow(values []interface{}) error
+	Close() error
+}
+
+// SpreadsheetContentType is the media type of an export format
+func SpreadsheetContentType(format string) string {
+	switch format {
+	case "csv":
+		return "text/csv; charset=utf-8"
+	case "jsonl":
+		return "application/jsonl"
+	case "xlsx":
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// NewSpreadsheetWriter starts a CSV, JSON Lines or XLSX document with the given columns
+func NewSpreadsheetWriter(w io.Writer, format string, columns []string) (SpreadsheetWriter, error) {
+	switch format {
+	case "csv":
+		writer := &csvWriter{writer: csv.NewWriter(w)}
+		if err := writer.writer.Write(columns); err != nil {
+			return nil, err
+		}
+		return writer, nil
+	case "jsonl":
+		return &jsonlWriter{writer: w, columns: columns}, nil
+	case "xlsx":
+		return newXLSXWriter(w, columns)
+	default:
+		return nil, fmt.Errorf("unsupported format %q, expected csv, jsonl or xlsx", format)
+	}
+}
+
+type csvWriter struct {
+	writer *csv.Writer
+}
+
+// WriteRow writes one record; text that a spreadsheet would read as a formula is quoted, numbers are not
+func (writer *csvWriter) WriteRow(values []interface{}) error {
+	record := make([]string, len(values))
+	for i, value := range values {
+		if text, ok := value.(string); ok {
+			record[i] = escapeFormula(text)
+		} else {
+			record[i] = fmt.Sprint(value)
+		}
+	}
+	return writer.writer.Write(record)
+}
+
+// escapeFormula prefixes text starting like a formula with an apostrophe, so Excel shows a name
+// such as "=HYPERLINK(...)" as text instead of evaluating it
+func escapeFormula(text string) string {
+	if text != "" && strings.ContainsRune("=+-@\t\r", rune(text[0])) {
+		return "'" + text
+	}
+	return text
+}
+
+func (writer *csvWriter) Close() error {
+	writer.writer.Flush()
+	return writer.writer.Error()
+}
+
+type jsonlWriter struct {
+	writer  io.Writer
+	columns []string
+}
+
+// WriteRow writes one JSON object per line, keeping the keys in column order
+func (writer *jsonlWriter) WriteRow(values []interface{}) error {
+	var line bytes.Buffer
+	line.WriteByte('{')
+	for i, value := range values {
+		if i > 0 {
+			line.WriteByte(',')
+		}
+		key, _ := json.Marshal(writer.columns[i])
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		line.Write(key)
+		line.WriteByte(':')
+		line.Write(encoded)
+	}
+	line.WriteString("}\n")
+
+	_, err := writer.writer.Write(line.Bytes())
+	return err
+}
+
+func (writer *jsonlWriter) Close() error {
+	return nil
+}
+
+// xlsxWriter buffers rows in excelize's stream writer, which spills to a temporary
+// file for large sheets; the zip container can only be written out on Close
+type xlsxWriter struct {
+	writer io.Writer
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	row    int
+}
+
+func newXLSXWriter(w io.Writer, columns []string) (SpreadsheetWriter, error) {
+	file := excelize.NewFile()
+	stream, err := file.NewStreamWriter(file.GetSheetName(0))
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	writer := &xlsxWriter{writer: w, file: file, stream: stream}
+	header := make([]interface{}, len(columns))
+	for i, column := range columns {
+		header[i] = column
+	}
+	if err := writer.WriteRow(header); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return writer, nil
+}
+
+// WriteRow appends one row; text is always stored as an inline string cell, which Excel never
+// evaluates, so it needs no escaping
+func (writer *xlsxWriter) WriteRow(values []interface{}) error {
+	writer.row++
+	cell, err := excelize.CoordinatesToCellName(1, writer.row)
+	if err != nil {
+		return err
+	}
+	return writer.stream.SetRow(cell, values)
+}
+
+func (writer *xlsxWriter) Close() error {
+	defer writer.file.Close()
+	if err := writer.stream.Flush(); err != nil {
+		return err
+	}
+	_, err := writer.file.WriteTo(writer.writer)
+	return err
+}
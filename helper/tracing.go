@@ -0,0 +1,22 @@
+package helper
+
+import (
+	"context"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SpanKey holds the server span of the request. Controllers pass services the fasthttp request
+// context, which only carries values stored with c.Locals, so the span is kept there as well.
+const SpanKey contextKey = "span"
+
+// ContextWithRequestSpan returns ctx with the request span stored under SpanKey as its current span,
+// so spans started from it become children of the request
+func ContextWithRequestSpan(ctx context.Context) context.Context {
+	if trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx
+	}
+	if span, ok := ctx.Value(SpanKey).(trace.Span); ok {
+		return trace.ContextWithSpan(ctx, span)
+	}
+	return ctx
+}
@@ -0,0 +1,28 @@
+package helper
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+// Headers sent with every webhook delivery
+const (
+	WebhookEventIdHeader   = "X-Event-Id"
+	WebhookEventTypeHeader = "X-Event-Type"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+// SignWebhook returns the X-Webhook-Signature of a delivery: "sha256=" and the hex HMAC-SHA256,
+// keyed with the webhook secret, of the unix timestamp, a dot and the body. Receivers recompute
+// it to authenticate the sender and reject old timestamps to stop replays.
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
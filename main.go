@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"github.com/aronipurwanto/go-restful-api/app"
 	"github.com/aronipurwanto/go-restful-api/controller"
 	"github.com/aronipurwanto/go-restful-api/helper"
@@ -9,46 +10,208 @@ import (
 	"github.com/aronipurwanto/go-restful-api/service"
 	"github.com/go-playground/validator/v10"
 	_ "github.com/go-sql-driver/mysql"
-	"github.com/gofiber/fiber/v2"
-	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 )
 
 func main() {
 
-	server := fiber.New()
+	config := app.LoadConfig()
+	server := app.NewServer(config)
+
+	// Initialize Logger, its level can be changed while running through /api/admin/log-level
+	logLevel := new(slog.LevelVar)
+	err := logLevel.UnmarshalText([]byte(config.LogLevel))
+	helper.PanicIfError(err)
+	slog.SetDefault(helper.NewLogger(os.Stdout, logLevel))
+
+	// Initialize Tracing
+	shutdownTracing, err := app.NewTracing(context.Background(), config)
+	helper.PanicIfError(err)
 
 	// Initialize Database
-	db := app.NewDB()
+	db := app.NewDB(config)
+
+	// Record every mutation of the audited tables in the audit log
+	err = db.Use(repository.NewAuditPlugin())
+	helper.PanicIfError(err)
+
+	// Observe the duration of every statement on /metrics
+	err = db.Use(repository.NewMetricsPlugin())
+	helper.PanicIfError(err)
+
+	// Trace every statement as a child of the service span that ran it
+	err = db.Use(repository.NewTracingPlugin())
+	helper.PanicIfError(err)
 
 	// Run Auto Migration (Opsional, bisa dihapus jika tidak diperlukan)
-	err := db.AutoMigrate(&domain.Category{}, &domain.Customer{}, &domain.Product{}, &domain.Employee{})
+	err = app.Migrate(db)
 	helper.PanicIfError(err)
 
 	// Initialize Validator
 	validate := validator.New()
 
 	// Initialize Repository, Service, and Controller
-	categoryRepository := repository.NewCategoryRepository(db)
-	categoryService := service.NewCategoryService(categoryRepository, validate)
+	transactor := repository.NewTransactor(db)
+	outboxRepository := repository.NewOutboxRepository(db)
+
+	// Catalog lookups at the till are answered from memory; writes through the repositories invalidate them
+	productCache := repository.NewCache[uint64, domain.Product]("product", config.CacheSize, config.CacheTTL)
+	// the category cache holds a single entry, the list of all categories
+	categoryCache := repository.NewCache[string, []domain.Category]("category", min(config.CacheSize, 1), config.CacheTTL)
+
+	categoryRepository := repository.NewCachedCategoryRepository(repository.NewCategoryRepository(db), categoryCache)
+	categoryService := service.NewCategoryService(categoryRepository, transactor, validate)
 	categoryController := controller.NewCategoryController(categoryService)
 
-	employeeRepository := repository.NewCategoryRepository(db)
-	employeeService := service.NewCategoryService(employeeRepository, validate)
-	employeeController := controller.NewCategoryController(employeeService)
+	employeeRepository := repository.NewEmployeeRepository(db)
+	employeeService := service.NewEmployeeService(employeeRepository, validate)
+	employeeController := controller.NewEmployeeController(employeeService)
+
+	productRepository := repository.NewCachedProductRepository(repository.NewProductRepository(db), productCache)
+	productService := service.NewProductService(productRepository, outboxRepository, transactor, validate)
+	productController := controller.NewProductController(productService)
+
+	productVariantRepository := repository.NewCachedProductVariantRepository(repository.NewProductVariantRepository(db), productCache)
+	productVariantService := service.NewProductVariantService(productVariantRepository, productRepository, outboxRepository, transactor, validate)
+	productVariantController := controller.NewProductVariantController(productVariantService)
+
+	productPriceRepository := repository.NewCachedProductPriceRepository(repository.NewProductPriceRepository(db), productCache)
+	productPriceService := service.NewProductPriceService(productPriceRepository, productRepository, outboxRepository, transactor, validate)
+	productPriceController := controller.NewProductPriceController(productPriceService)
+
+	productImportService := service.NewProductImportService(productRepository, categoryRepository, outboxRepository, transactor, validate)
+	productImportController := controller.NewProductImportController(productImportService)
 
-	productRepository := repository.NewCategoryRepository(db)
-	productService := service.NewCategoryService(productRepository, validate)
-	productController := controller.NewCategoryController(productService)
+	customerRepository := repository.NewCustomerRepository(db)
+	customerService := service.NewCustomerService(customerRepository, outboxRepository, transactor, validate)
+	customerController := controller.NewCustomerController(customerService)
 
-	customerRepository := repository.NewCategoryRepository(db)
-	customerService := service.NewCategoryService(customerRepository, validate)
-	customerController := controller.NewCategoryController(customerService)
+	batchService := service.NewBatchService(productService, customerService, employeeService, categoryService, transactor, validate)
+	batchController := controller.NewBatchController(batchService)
+
+	exportService := service.NewExportService(productRepository, customerRepository, employeeRepository, validate)
+	exportController := controller.NewExportController(exportService)
+
+	trashService := service.NewTrashService(productRepository, categoryRepository, customerRepository, employeeRepository, config.SoftDeleteRetention)
+
+	auditRepository := repository.NewAuditRepository(db)
+	auditService := service.NewAuditService(auditRepository, validate)
+	auditController := controller.NewAuditController(auditService)
+
+	webhookRepository := repository.NewWebhookRepository(db)
+	webhookService := service.NewWebhookService(webhookRepository, validate)
+	webhookController := controller.NewWebhookController(webhookService)
+	webhookDispatcher := service.NewWebhookDispatcher(outboxRepository, webhookRepository, transactor, &http.Client{Timeout: config.WebhookTimeout}, config.WebhookMaxAttempts, config.WebhookRetryBackoff)
+
+	eventStreamService := service.NewEventStreamService(auditRepository, config.EventStreamBuffer)
+	eventStreamController := controller.NewEventStreamController(eventStreamService)
+
+	idempotencyRepository := repository.NewIdempotencyRepository(db)
+	rateLimitStore := repository.NewMemoryRateLimitStore()
+
+	adminController := controller.NewAdminController(logLevel)
+
+	healthService := service.NewHealthService([]service.HealthCheck{
+		{Name: "database", Check: app.DatabaseCheck(db)},
+		{Name: "migrations", Check: app.MigrationCheck(db)},
+	}, config.HealthCheckTimeout)
+	healthController := controller.NewHealthController(healthService)
 
 	// Setup Routes
-	app.NewRouter(server, categoryController, customerController, productController, employeeController)
+	app.NewRouter(server, config, employeeRepository, idempotencyRepository, rateLimitStore, categoryController, customerController, productController, employeeController, productVariantController, productPriceController, productImportController, exportController, batchController, auditController, webhookController, eventStreamController, adminController, healthController)
+
+	// Start Background Jobs
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	jobs := app.StartJobs(jobCtx, app.Job{
+		Name:     "price-scheduler",
+		Interval: time.Minute,
+		Run: func(ctx context.Context) error {
+			_, err := productPriceService.ApplyDue(ctx, time.Now())
+			return err
+		},
+	}, app.Job{
+		Name:     "trash-purge",
+		Interval: time.Hour,
+		Run: func(ctx context.Context) error {
+			_, err := trashService.Purge(ctx, time.Now())
+			return err
+		},
+	}, app.Job{
+		Name:     "webhook-dispatcher",
+		Interval: 5 * time.Second,
+		Run: func(ctx context.Context) error {
+			_, err := webhookDispatcher.Dispatch(ctx, time.Now())
+			return err
+		},
+	}, app.Job{
+		Name:     "event-stream",
+		Interval: time.Second,
+		Run: func(ctx context.Context) error {
+			_, err := eventStreamService.Poll(ctx)
+			return err
+		},
+	}, app.Job{
+		Name:     "idempotency-purge",
+		Interval: time.Hour,
+		Run: func(ctx context.Context) error {
+			_, err := idempotencyRepository.Purge(ctx, time.Now())
+			return err
+		},
+	})
 
 	// Start Server
-	log.Println("Server running on port 8080")
-	err = server.Listen(":8080")
-	helper.PanicIfError(err)
+	serverErrors := make(chan error, 1)
+	go func() {
+		slog.Info("server running", "port", 8080)
+		serverErrors <- server.Listen(":8080")
+	}()
+
+	// Run until SIGINT or SIGTERM, or until the server fails
+	signals, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	exitCode := 0
+	select {
+	case err := <-serverErrors:
+		slog.Error("server failed", "error", err)
+		exitCode = 1
+	case <-signals.Done():
+		slog.Info("shutting down", "timeout", config.ShutdownTimeout.String())
+	}
+	// A second signal kills the process right away
+	stopSignals()
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), config.ShutdownTimeout)
+
+	// Stop scheduling jobs, and end the event streams, which would otherwise keep their connections busy
+	stopJobs()
+	eventStreamService.Close()
+
+	// Stop accepting connections and wait for the requests in flight
+	if err := server.ShutdownWithContext(shutdownCtx); err != nil {
+		slog.Error("server did not drain in time", "error", err)
+		exitCode = 1
+	}
+	if err := app.WaitJobs(shutdownCtx, jobs); err != nil {
+		slog.Error("background jobs did not finish in time", "error", err)
+		exitCode = 1
+	}
+
+	if sqlDB, err := db.DB(); err == nil {
+		if err := sqlDB.Close(); err != nil {
+			slog.Error("failed to close database", "error", err)
+			exitCode = 1
+		}
+	}
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		slog.Error("failed to flush traces", "error", err)
+		exitCode = 1
+	}
+
+	slog.Info("shutdown complete", "exit_code", exitCode)
+	cancelShutdown()
+	os.Exit(exitCode)
 }
@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Namespace prefixes every metric the application exports
+const Namespace = "pos"
+
+// UnmatchedRoute labels requests no route handled, so unknown paths do not create new series
+const UnmatchedRoute = "unmatched"
+
+var (
+	// HTTPRequests counts handled requests by method, route template and status
+	HTTPRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "http_requests_total",
+		Help:      "Number of HTTP requests handled, by method, route template and status.",
+	}, []string{"method", "route", "status"})
+
+	// HTTPRequestDuration observes request latency by method, route template and status
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latency of HTTP requests, by method, route template and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	// DBQueryDuration observes GORM statements by table and operation
+	DBQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Duration of database statements run through GORM, by table and operation.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"table", "operation"})
+
+	// ProductsCreated counts products created through the API, the batch endpoint and imports
+	ProductsCreated = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "products_created_total",
+		Help:      "Number of products created.",
+	})
+
+	// AuthFailures counts rejected API requests by reason
+	AuthFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "auth_failures_total",
+		Help:      "Number of requests rejected by the API key check, by reason.",
+	}, []string{"reason"})
+
+	// RateLimited counts requests rejected by the rate limiter by route group
+	RateLimited = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "rate_limited_total",
+		Help:      "Number of requests rejected with 429 by the rate limiter, by route group.",
+	}, []string{"group"})
+
+	// CacheHits counts lookups answered from an in-process cache, by cache
+	CacheHits = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "cache_hits_total",
+		Help:      "Number of lookups answered from an in-process cache, by cache.",
+	}, []string{"cache"})
+
+	// CacheMisses counts lookups that had to load from the database, by cache
+	CacheMisses = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "cache_misses_total",
+		Help:      "Number of lookups an in-process cache had to load from the database, by cache.",
+	}, []string{"cache"})
+)
@@ -1,21 +1,59 @@
 package middleware
 
 import (
+	"github.com/aronipurwanto/go-restful-api/helper"
+	"github.com/aronipurwanto/go-restful-api/metrics"
 	"github.com/aronipurwanto/go-restful-api/model/web"
 	"github.com/gofiber/fiber/v2"
+	"slices"
+	"strconv"
 )
 
 type AuthMiddleware struct{}
 
-func NewAuthMiddleware() fiber.Handler {
+const (
+	APIKeyHeader = "X-API-Key"
+	// EventSource and WebSocket clients in a browser cannot set headers, so on the paths given to
+	// NewAuthMiddleware the key may come as this query parameter or cookie instead
+	APIKeyQuery  = "api_key"
+	APIKeyCookie = "api_key"
+)
+
+// apiKeyLocal holds the key the request was let in with, wherever it was read from
+const apiKeyLocal = "apiKey"
+
+// NewAuthMiddleware checks the API key of every request; browserPaths also take it from the
+// APIKeyQuery parameter or APIKeyCookie cookie
+func NewAuthMiddleware(browserPaths ...string) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		if c.Get("X-API-Key") == "RAHASIA" {
-			return c.Next()
+		apiKey := c.Get(APIKeyHeader)
+		if apiKey == "" && slices.Contains(browserPaths, c.Path()) {
+			apiKey = c.Query(APIKeyQuery, c.Cookies(APIKeyCookie))
+		}
+		if apiKey != "RAHASIA" {
+			metrics.AuthFailures.WithLabelValues("invalid_api_key").Inc()
+			return c.Status(fiber.StatusUnauthorized).JSON(web.WebResponse{
+				Code:   fiber.StatusUnauthorized,
+				Status: "UNAUTHORIZED",
+			})
+		}
+
+		c.Locals(apiKeyLocal, apiKey)
+
+		// The acting employee is optional, services read it back with helper.EmployeeIdFromContext
+		if header := c.Get("X-Employee-ID"); header != "" {
+			employeeId, err := strconv.ParseUint(header, 10, 64)
+			if err != nil {
+				metrics.AuthFailures.WithLabelValues("invalid_employee_id").Inc()
+				return c.Status(fiber.StatusUnauthorized).JSON(web.WebResponse{
+					Code:   fiber.StatusUnauthorized,
+					Status: "UNAUTHORIZED",
+					Data:   "Invalid X-Employee-ID",
+				})
+			}
+			c.Locals(helper.EmployeeIdKey, employeeId)
 		}
 
-		return c.Status(fiber.StatusUnauthorized).JSON(web.WebResponse{
-			Code:   fiber.StatusUnauthorized,
-			Status: "UNAUTHORIZED",
-		})
+		return c.Next()
 	}
 }
@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func setupTestAppBodyLimit() *fiber.App {
+	app := fiber.New()
+	api := app.Group("/api", NewBodyLimitMiddleware(16, map[string]int{"/products/import": 64}))
+	api.Post("/products", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusCreated)
+	})
+	api.Post("/products/import", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	return app
+}
+
+func TestBodyLimitMiddleware(t *testing.T) {
+	app := setupTestAppBodyLimit()
+
+	tests := []struct {
+		name           string
+		url            string
+		body           string
+		expectedStatus int
+	}{
+		{name: "Within the limit", url: "/api/products", body: strings.Repeat("a", 16), expectedStatus: http.StatusCreated},
+		{name: "Over the limit", url: "/api/products", body: strings.Repeat("a", 17), expectedStatus: http.StatusRequestEntityTooLarge},
+		{name: "Path with a larger limit", url: "/api/products/import", body: strings.Repeat("a", 64), expectedStatus: http.StatusOK},
+		{name: "Over the larger limit", url: "/api/products/import/", body: strings.Repeat("a", 65), expectedStatus: http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", tt.url, strings.NewReader(tt.body))
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+		})
+	}
+}
+
+func gzipped(body string) string {
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	writer.Write([]byte(body))
+	writer.Close()
+	return buffer.String()
+}
+
+func TestBodyLimitMiddlewareCompressed(t *testing.T) {
+	app := fiber.New()
+	api := app.Group("/api", NewBodyLimitMiddleware(1024, nil))
+	api.Post("/products", func(c *fiber.Ctx) error {
+		return c.Status(fiber.StatusCreated).Send(c.Body())
+	})
+
+	tests := []struct {
+		name           string
+		encoding       string
+		body           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{name: "Inflated within the limit", encoding: "gzip", body: gzipped(strings.Repeat("a", 1024)), expectedStatus: http.StatusCreated, expectedBody: strings.Repeat("a", 1024)},
+		{name: "Inflates over the limit", encoding: "gzip", body: gzipped(strings.Repeat("a", 1<<20)), expectedStatus: http.StatusRequestEntityTooLarge},
+		{name: "Not gzip", encoding: "gzip", body: "plain", expectedStatus: http.StatusBadRequest},
+		{name: "Unsupported encoding", encoding: "br", body: "plain", expectedStatus: http.StatusUnsupportedMediaType},
+		{name: "Identity", encoding: "identity", body: "plain", expectedStatus: http.StatusCreated, expectedBody: "plain"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/products", strings.NewReader(tt.body))
+			req.Header.Set("Content-Encoding", tt.encoding)
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			if tt.expectedBody != "" {
+				body, _ := io.ReadAll(resp.Body)
+				assert.Equal(t, tt.expectedBody, string(body))
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// DefaultCacheControlGroup is the policy of route groups without one of their own
+const DefaultCacheControlGroup = "default"
+
+// CacheControlNoStore keeps a response out of every cache
+const CacheControlNoStore = "no-store"
+
+// NewCacheControlMiddleware sets the Cache-Control header of successful GET and HEAD responses to the
+// policy of their route group, the first path segment below the group it is mounted on, falling back
+// to DefaultCacheControlGroup. Errors and the responses to other methods are never stored, and a
+// handler that sets Cache-Control itself keeps its own.
+func NewCacheControlMiddleware(policies map[string]string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		policy, ok := policies[routeGroup(c)]
+		if !ok {
+			policy = policies[DefaultCacheControlGroup]
+		}
+
+		err := c.Next()
+		if len(c.Response().Header.Peek(fiber.HeaderCacheControl)) > 0 {
+			return err
+		}
+		if err != nil || c.Response().StatusCode() >= fiber.StatusBadRequest ||
+			(c.Method() != fiber.MethodGet && c.Method() != fiber.MethodHead) {
+			policy = CacheControlNoStore
+		}
+		if policy != "" {
+			c.Set(fiber.HeaderCacheControl, policy)
+		}
+		return err
+	}
+}
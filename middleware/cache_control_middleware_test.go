@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func setupTestAppCacheControl() *fiber.App {
+	app := fiber.New()
+	api := app.Group("/api", NewCacheControlMiddleware(map[string]string{
+		DefaultCacheControlGroup: "private, no-cache",
+		"webhooks":               CacheControlNoStore,
+	}))
+	api.Get("/products/export", func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderCacheControl, "private, max-age=60")
+		return c.SendString("export")
+	})
+	api.Get("/products/:productId", func(c *fiber.Ctx) error {
+		if c.Params("productId") == "0" {
+			return c.SendStatus(fiber.StatusNotFound)
+		}
+		return c.SendString("product")
+	})
+	api.Post("/products", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusCreated)
+	})
+	api.Get("/webhooks", func(c *fiber.Ctx) error {
+		return c.SendString("webhooks")
+	})
+	return app
+}
+
+func TestCacheControlMiddleware(t *testing.T) {
+	app := setupTestAppCacheControl()
+
+	tests := []struct {
+		name                 string
+		method               string
+		url                  string
+		expectedStatus       int
+		expectedCacheControl string
+	}{
+		{name: "Default policy", method: "GET", url: "/api/products/1", expectedStatus: http.StatusOK, expectedCacheControl: "private, no-cache"},
+		{name: "Group policy", method: "GET", url: "/api/webhooks", expectedStatus: http.StatusOK, expectedCacheControl: "no-store"},
+		{name: "Handler keeps its own", method: "GET", url: "/api/products/export", expectedStatus: http.StatusOK, expectedCacheControl: "private, max-age=60"},
+		{name: "Errors are not stored", method: "GET", url: "/api/products/0", expectedStatus: http.StatusNotFound, expectedCacheControl: "no-store"},
+		{name: "Writes are not stored", method: "POST", url: "/api/products", expectedStatus: http.StatusCreated, expectedCacheControl: "no-store"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.url, nil)
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			assert.Equal(t, tt.expectedCacheControl, resp.Header.Get("Cache-Control"))
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/repository"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+	"log/slog"
+	"strconv"
+	"time"
+)
+
+const (
+	// IdempotencyKeyHeader names the client-chosen key, e.g. a UUID, shared by all retries of one request
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses replayed from an earlier request
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	idempotencyKeyMaxLength = 255
+	// idempotencyLease is how long a request that never finishes, e.g. because the server died, holds its key;
+	// a request still running renews it every third of the lease, however long it takes
+	idempotencyLease = time.Minute
+	// idempotencyWait is how long a duplicate waits for the request holding its key before giving up with 409
+	idempotencyWait         = 5 * time.Second
+	idempotencyPollInterval = 100 * time.Millisecond
+)
+
+// idempotencyReplayedHeaders are the response headers stored with a response and sent again on replay
+var idempotencyReplayedHeaders = []string{fiber.HeaderContentType, fiber.HeaderETag, fiber.HeaderLocation}
+
+// NewIdempotencyMiddleware makes POST requests carrying an Idempotency-Key safe to retry. The first request
+// with a key runs and its response is kept for ttl; retries get that response replayed. Reusing a key for a
+// different method, URL or body is rejected with 422, and a retry arriving while the first request still runs
+// waits for it, or gets 409 if it takes too long. Responses with a 5xx status are not kept, so they can be retried.
+func NewIdempotencyMiddleware(idempotencyRepository repository.IdempotencyRepository, ttl time.Duration) fiber.Handler {
+	return newIdempotencyMiddleware(idempotencyRepository, ttl, idempotencyLease)
+}
+
+func newIdempotencyMiddleware(idempotencyRepository repository.IdempotencyRepository, ttl time.Duration, lease time.Duration) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get(IdempotencyKeyHeader)
+		if c.Method() != fiber.MethodPost || key == "" {
+			return c.Next()
+		}
+		if len(key) > idempotencyKeyMaxLength {
+			return idempotencyError(c, fiber.StatusBadRequest, "Bad Request", "Idempotency-Key must not be longer than "+strconv.Itoa(idempotencyKeyMaxLength)+" characters")
+		}
+
+		fingerprint := requestFingerprint(c)
+		token, err := claimToken()
+		if err != nil {
+			return idempotencyError(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+		}
+		now := time.Now()
+		claimed, err := idempotencyRepository.Claim(c.Context(), domain.IdempotencyKey{
+			Key:         key,
+			Fingerprint: fingerprint,
+			Token:       token,
+			Status:      domain.IdempotencyInProgress,
+			ExpiresAt:   now.Add(lease),
+		}, now)
+		if err != nil {
+			return idempotencyError(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+		}
+		if !claimed {
+			return replay(c, idempotencyRepository, key, fingerprint)
+		}
+
+		stopRenewing := renewLease(idempotencyRepository, key, token, lease)
+		err = c.Next()
+		stopRenewing()
+		if err != nil {
+			_ = idempotencyRepository.Release(c.Context(), key, token)
+			return err
+		}
+
+		response := c.Response()
+		if response.StatusCode() >= fiber.StatusInternalServerError {
+			return claimLost(c, idempotencyRepository.Release(c.Context(), key, token))
+		}
+
+		headers := make(map[string]string)
+		for _, name := range idempotencyReplayedHeaders {
+			if value := response.Header.Peek(name); len(value) > 0 {
+				headers[name] = string(value)
+			}
+		}
+		encodedHeaders, err := json.Marshal(headers)
+		if err != nil {
+			return err
+		}
+		return claimLost(c, idempotencyRepository.Complete(c.Context(), domain.IdempotencyKey{
+			Key:             key,
+			Token:           token,
+			Status:          domain.IdempotencyCompleted,
+			ResponseStatus:  response.StatusCode(),
+			ResponseHeaders: encodedHeaders,
+			ResponseBody:    append([]byte(nil), response.Body()...),
+			ExpiresAt:       time.Now().Add(ttl),
+		}))
+	}
+}
+
+// renewLease keeps extending the claim on key while the request runs, e.g. a long import, so a retry
+// cannot take the key over and run the request twice; the returned func stops it and waits for it
+func renewLease(idempotencyRepository repository.IdempotencyRepository, key string, token string, lease time.Duration) func() {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(lease / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				// the request context belongs to the handler, so renewals use their own
+				ctx, cancel := context.WithTimeout(context.Background(), lease/3)
+				err := idempotencyRepository.Renew(ctx, key, token, time.Now().Add(lease))
+				cancel()
+				if err != nil {
+					slog.Warn("idempotency lease not renewed", "key", key, "error", err)
+				}
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// claimLost lets a response through whose key was taken over by a retry after the lease ran out;
+// the retry stores its own response
+func claimLost(c *fiber.Ctx, err error) error {
+	if errors.Is(err, repository.ErrIdempotencyClaimLost) {
+		slog.WarnContext(c.Context(), "idempotency key taken over before the response was stored", "key", c.Get(IdempotencyKeyHeader))
+		return nil
+	}
+	return err
+}
+
+func claimToken() (string, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(token), nil
+}
+
+// replay answers a request whose key is already taken with the stored response, once there is one
+func replay(c *fiber.Ctx, idempotencyRepository repository.IdempotencyRepository, key string, fingerprint string) error {
+	deadline := time.Now().Add(idempotencyWait)
+	for {
+		stored, err := idempotencyRepository.FindByKey(c.Context(), key)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// the request holding the key failed and released it in the meantime
+			return idempotencyError(c, fiber.StatusConflict, "Conflict", "A request with this Idempotency-Key failed, retry it")
+		} else if err != nil {
+			return idempotencyError(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+		}
+
+		if stored.Fingerprint != fingerprint {
+			return idempotencyError(c, fiber.StatusUnprocessableEntity, "Unprocessable Entity", "Idempotency-Key was already used for a different request")
+		}
+		if stored.Status == domain.IdempotencyCompleted {
+			var headers map[string]string
+			if err := json.Unmarshal(stored.ResponseHeaders, &headers); err != nil {
+				return idempotencyError(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+			}
+			for name, value := range headers {
+				c.Set(name, value)
+			}
+			c.Set(IdempotentReplayedHeader, "true")
+			return c.Status(stored.ResponseStatus).Send(stored.ResponseBody)
+		}
+
+		if time.Now().After(deadline) {
+			c.Set(fiber.HeaderRetryAfter, "1")
+			return idempotencyError(c, fiber.StatusConflict, "Conflict", "A request with this Idempotency-Key is still in progress")
+		}
+		time.Sleep(idempotencyPollInterval)
+	}
+}
+
+// requestFingerprint identifies what a request asks for, so a key cannot be reused for something else
+func requestFingerprint(c *fiber.Ctx) string {
+	hash := sha256.New()
+	hash.Write([]byte(c.Method() + " " + c.OriginalURL() + "\n"))
+	hash.Write(c.Body())
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func idempotencyError(c *fiber.Ctx, code int, status string, message string) error {
+	return c.Status(code).JSON(web.WebResponse{
+		Code:   code,
+		Status: status,
+		Data:   message,
+	})
+}
@@ -0,0 +1,241 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/repository"
+	"github.com/aronipurwanto/go-restful-api/repository/mocks"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func fingerprintOf(method string, url string, body string) string {
+	hash := sha256.Sum256([]byte(method + " " + url + "\n" + body))
+	return hex.EncodeToString(hash[:])
+}
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	const body = `{"name":"Tea"}`
+	fingerprint := fingerprintOf("POST", "/api/products", body)
+	completed := domain.IdempotencyKey{
+		Key:             "k1",
+		Fingerprint:     fingerprint,
+		Status:          domain.IdempotencyCompleted,
+		ResponseStatus:  http.StatusCreated,
+		ResponseHeaders: json.RawMessage(`{"Content-Type":"application/json","Etag":"\"1\""}`),
+		ResponseBody:    []byte(`{"code":201,"data":{"id":1}}`),
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		key            string
+		body           string
+		handlerStatus  int
+		setupMock      func(mockRepo *mocks.MockIdempotencyRepository)
+		expectedStatus int
+		expectedBody   string
+		handlerCalls   int
+	}{
+		{
+			name:          "First request is stored",
+			method:        "POST",
+			key:           "k1",
+			body:          body,
+			handlerStatus: http.StatusCreated,
+			setupMock: func(mockRepo *mocks.MockIdempotencyRepository) {
+				mockRepo.EXPECT().Claim(gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, key domain.IdempotencyKey, now time.Time) (bool, error) {
+						assert.Equal(t, fingerprint, key.Fingerprint)
+						assert.Equal(t, domain.IdempotencyInProgress, key.Status)
+						return true, nil
+					})
+				mockRepo.EXPECT().Complete(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, key domain.IdempotencyKey) error {
+					assert.Equal(t, domain.IdempotencyCompleted, key.Status)
+					assert.Equal(t, http.StatusCreated, key.ResponseStatus)
+					assert.Equal(t, `created`, string(key.ResponseBody))
+					assert.JSONEq(t, `{"Content-Type":"text/plain; charset=utf-8"}`, string(key.ResponseHeaders))
+					return nil
+				})
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   "created",
+			handlerCalls:   1,
+		},
+		{
+			name:   "Retry is replayed",
+			method: "POST",
+			key:    "k1",
+			body:   body,
+			setupMock: func(mockRepo *mocks.MockIdempotencyRepository) {
+				mockRepo.EXPECT().Claim(gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil)
+				mockRepo.EXPECT().FindByKey(gomock.Any(), "k1").Return(completed, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"code":201,"data":{"id":1}}`,
+		},
+		{
+			name:   "Key reused for another body",
+			method: "POST",
+			key:    "k1",
+			body:   `{"name":"Coffee"}`,
+			setupMock: func(mockRepo *mocks.MockIdempotencyRepository) {
+				mockRepo.EXPECT().Claim(gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil)
+				mockRepo.EXPECT().FindByKey(gomock.Any(), "k1").Return(completed, nil)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "Duplicate waits for the request in flight",
+			method: "POST",
+			key:    "k1",
+			body:   body,
+			setupMock: func(mockRepo *mocks.MockIdempotencyRepository) {
+				inProgress := completed
+				inProgress.Status = domain.IdempotencyInProgress
+				mockRepo.EXPECT().Claim(gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil)
+				gomock.InOrder(
+					mockRepo.EXPECT().FindByKey(gomock.Any(), "k1").Return(inProgress, nil).Times(2),
+					mockRepo.EXPECT().FindByKey(gomock.Any(), "k1").Return(completed, nil),
+				)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"code":201,"data":{"id":1}}`,
+		},
+		{
+			name:   "Request in flight failed",
+			method: "POST",
+			key:    "k1",
+			body:   body,
+			setupMock: func(mockRepo *mocks.MockIdempotencyRepository) {
+				mockRepo.EXPECT().Claim(gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil)
+				mockRepo.EXPECT().FindByKey(gomock.Any(), "k1").Return(domain.IdempotencyKey{}, gorm.ErrRecordNotFound)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:          "Server error is not kept",
+			method:        "POST",
+			key:           "k2",
+			body:          body,
+			handlerStatus: http.StatusInternalServerError,
+			setupMock: func(mockRepo *mocks.MockIdempotencyRepository) {
+				mockRepo.EXPECT().Claim(gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil)
+				mockRepo.EXPECT().Release(gomock.Any(), "k2", gomock.Any()).Return(nil)
+			},
+			expectedStatus: http.StatusInternalServerError,
+			handlerCalls:   1,
+		},
+		{
+			name:           "Key too long",
+			method:         "POST",
+			key:            strings.Repeat("k", 256),
+			body:           body,
+			setupMock:      func(mockRepo *mocks.MockIdempotencyRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Without key",
+			method:         "POST",
+			body:           body,
+			handlerStatus:  http.StatusCreated,
+			setupMock:      func(mockRepo *mocks.MockIdempotencyRepository) {},
+			expectedStatus: http.StatusCreated,
+			handlerCalls:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockRepo := mocks.NewMockIdempotencyRepository(ctrl)
+			tt.setupMock(mockRepo)
+
+			handlerCalls := 0
+			app := fiber.New()
+			app.Post("/api/products", NewIdempotencyMiddleware(mockRepo, time.Hour), func(c *fiber.Ctx) error {
+				handlerCalls++
+				return c.Status(tt.handlerStatus).SendString("created")
+			})
+
+			req := httptest.NewRequest(tt.method, "/api/products", strings.NewReader(tt.body))
+			if tt.key != "" {
+				req.Header.Set(IdempotencyKeyHeader, tt.key)
+			}
+			resp, err := app.Test(req, -1)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			assert.Equal(t, tt.handlerCalls, handlerCalls)
+
+			responseBody, _ := io.ReadAll(resp.Body)
+			assert.Contains(t, string(responseBody), tt.expectedBody)
+			if tt.expectedBody != "" && tt.handlerCalls == 0 {
+				assert.Equal(t, "true", resp.Header.Get(IdempotentReplayedHeader))
+				assert.Equal(t, `"1"`, resp.Header.Get(fiber.HeaderETag))
+			}
+		})
+	}
+}
+
+func TestIdempotencyMiddlewareLease(t *testing.T) {
+	tests := []struct {
+		name        string
+		completeErr error
+	}{
+		{name: "Renewed while the handler runs"},
+		{name: "Taken over after the lease ran out", completeErr: repository.ErrIdempotencyClaimLost},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockRepo := mocks.NewMockIdempotencyRepository(ctrl)
+
+			var token string
+			mockRepo.EXPECT().Claim(gomock.Any(), gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, key domain.IdempotencyKey, now time.Time) (bool, error) {
+					assert.Len(t, key.Token, 32)
+					token = key.Token
+					return true, nil
+				})
+			mockRepo.EXPECT().Renew(gomock.Any(), "k1", gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, key string, renewToken string, expiresAt time.Time) error {
+					assert.Equal(t, token, renewToken)
+					return nil
+				}).MinTimes(1)
+			mockRepo.EXPECT().Complete(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, key domain.IdempotencyKey) error {
+					assert.Equal(t, token, key.Token)
+					return tt.completeErr
+				})
+
+			app := fiber.New()
+			app.Post("/api/products/import", newIdempotencyMiddleware(mockRepo, time.Hour, 30*time.Millisecond), func(c *fiber.Ctx) error {
+				// outlives the lease several times over, like a large import
+				time.Sleep(100 * time.Millisecond)
+				return c.Status(fiber.StatusOK).SendString("imported")
+			})
+
+			req := httptest.NewRequest("POST", "/api/products/import", strings.NewReader("name\nTea"))
+			req.Header.Set(IdempotencyKeyHeader, "k1")
+			resp, err := app.Test(req, -1)
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			responseBody, _ := io.ReadAll(resp.Body)
+			assert.Equal(t, "imported", string(responseBody))
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"github.com/aronipurwanto/go-restful-api/helper"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/repository"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ManagerRole is the employee role allowed to see soft-deleted records
+const ManagerRole = "Manager"
+
+// NewIncludeDeletedMiddleware lets a manager, identified by X-Employee-ID, list soft-deleted rows
+// with ?include_deleted=true. It only applies to reads so a write can never bypass soft delete.
+func NewIncludeDeletedMiddleware(employeeRepository repository.EmployeeRepository) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if c.Method() != fiber.MethodGet || !c.QueryBool("include_deleted") {
+			return c.Next()
+		}
+
+		return requireManager(c, employeeRepository, "include_deleted", func() error {
+			c.Locals(helper.IncludeDeletedKey, true)
+			return c.Next()
+		})
+	}
+}
+
+func forbidden(c *fiber.Ctx, message string) error {
+	return c.Status(fiber.StatusForbidden).JSON(web.WebResponse{
+		Code:   fiber.StatusForbidden,
+		Status: "FORBIDDEN",
+		Data:   message,
+	})
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"errors"
+	"github.com/aronipurwanto/go-restful-api/helper"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/repository"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+	"strings"
+)
+
+// NewManagerMiddleware lets only a manager, identified by X-Employee-ID, through to the routes it guards
+func NewManagerMiddleware(employeeRepository repository.EmployeeRepository) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return requireManager(c, employeeRepository, c.Method()+" "+c.Path(), c.Next)
+	}
+}
+
+// requireManager calls next when X-Employee-ID names a manager, and answers 403 for what otherwise
+func requireManager(c *fiber.Ctx, employeeRepository repository.EmployeeRepository, what string, next func() error) error {
+	employeeId := helper.EmployeeIdFromContext(c.Context())
+	if employeeId == nil {
+		return forbidden(c, what+" requires X-Employee-ID of a manager")
+	}
+	employee, err := employeeRepository.FindById(c.Context(), *employeeId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return forbidden(c, what+" requires X-Employee-ID of a manager")
+	} else if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(web.WebResponse{
+			Code:   fiber.StatusInternalServerError,
+			Status: "Internal Server Error",
+			Data:   err.Error(),
+		})
+	}
+	if !strings.EqualFold(employee.Role, ManagerRole) {
+		return forbidden(c, what+" is only available to managers")
+	}
+	return next()
+}
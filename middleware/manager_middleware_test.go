@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"errors"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/repository/mocks"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestManagerMiddleware(t *testing.T) {
+	tests := []struct {
+		name           string
+		employeeId     string
+		setupMock      func(mockRepo *mocks.MockEmployeeRepository)
+		expectedStatus int
+		expectedData   string
+	}{
+		{
+			name:       "Manager",
+			employeeId: "7",
+			setupMock: func(mockRepo *mocks.MockEmployeeRepository) {
+				mockRepo.EXPECT().FindById(gomock.Any(), uint64(7)).Return(domain.Employee{EmployeeID: 7, Role: "manager"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:       "Cashier",
+			employeeId: "8",
+			setupMock: func(mockRepo *mocks.MockEmployeeRepository) {
+				mockRepo.EXPECT().FindById(gomock.Any(), uint64(8)).Return(domain.Employee{EmployeeID: 8, Role: "Cashier"}, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedData:   "PUT /api/admin/log-level is only available to managers",
+		},
+		{
+			name:           "No employee",
+			setupMock:      func(mockRepo *mocks.MockEmployeeRepository) {},
+			expectedStatus: http.StatusForbidden,
+			expectedData:   "PUT /api/admin/log-level requires X-Employee-ID of a manager",
+		},
+		{
+			name:       "Unknown employee",
+			employeeId: "9",
+			setupMock: func(mockRepo *mocks.MockEmployeeRepository) {
+				mockRepo.EXPECT().FindById(gomock.Any(), uint64(9)).Return(domain.Employee{}, gorm.ErrRecordNotFound)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:       "Repository error",
+			employeeId: "7",
+			setupMock: func(mockRepo *mocks.MockEmployeeRepository) {
+				mockRepo.EXPECT().FindById(gomock.Any(), uint64(7)).Return(domain.Employee{}, errors.New("connection refused"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockRepo := mocks.NewMockEmployeeRepository(ctrl)
+			tt.setupMock(mockRepo)
+
+			app := fiber.New()
+			api := app.Group("/api", NewAuthMiddleware())
+			api.Put("/admin/log-level", NewManagerMiddleware(mockRepo), func(c *fiber.Ctx) error {
+				return c.SendStatus(fiber.StatusOK)
+			})
+
+			req := httptest.NewRequest("PUT", "/api/admin/log-level", nil)
+			req.Header.Set("X-API-Key", "RAHASIA")
+			if tt.employeeId != "" {
+				req.Header.Set("X-Employee-ID", tt.employeeId)
+			}
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			body, _ := io.ReadAll(resp.Body)
+			assert.Contains(t, string(body), tt.expectedData)
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"errors"
+	"github.com/aronipurwanto/go-restful-api/metrics"
+	"github.com/gofiber/fiber/v2"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NewMetricsMiddleware records the count and latency of every request, labelled with the
+// route template (/api/products/:productId) rather than the raw path
+func NewMetricsMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+
+		route, status := routeAndStatus(c, err)
+		labels := []string{c.Method(), route, strconv.Itoa(status)}
+		metrics.HTTPRequests.WithLabelValues(labels...).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(labels...).Observe(time.Since(start).Seconds())
+		return err
+	}
+}
+
+// routeAndStatus returns the template of the route that handled the request, or metrics.UnmatchedRoute,
+// and the status of the response; when the handler returned an error the error handler has not
+// written the response yet, so the status it will send is used
+func routeAndStatus(c *fiber.Ctx, err error) (string, int) {
+	route := c.Route().Path
+	status := c.Response().StatusCode()
+	if err == nil {
+		return route, status
+	}
+
+	status = fiber.StatusInternalServerError
+	var fiberError *fiber.Error
+	if errors.As(err, &fiberError) {
+		status = fiberError.Code
+		// When no route matches, Fiber's router returns "Cannot GET /path" from the last
+		// middleware's Next, and c.Route() is still that middleware's prefix
+		if status == fiber.StatusNotFound && strings.HasPrefix(fiberError.Message, "Cannot ") {
+			route = metrics.UnmatchedRoute
+		}
+	}
+	return route, status
+}
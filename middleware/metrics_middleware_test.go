@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"github.com/aronipurwanto/go-restful-api/metrics"
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestMetricsMiddleware(t *testing.T) {
+	app := fiber.New()
+	app.Use(NewMetricsMiddleware())
+	api := app.Group("/api", func(c *fiber.Ctx) error {
+		return c.Next()
+	})
+	api.Get("/products/:productId", func(c *fiber.Ctx) error {
+		return c.SendString("product")
+	})
+	api.Get("/broken", func(c *fiber.Ctx) error {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "down")
+	})
+
+	tests := []struct {
+		name   string
+		path   string
+		route  string
+		status string
+	}{
+		{name: "Route template", path: "/api/products/1", route: "/api/products/:productId", status: "200"},
+		{name: "Returned error", path: "/api/broken", route: "/api/broken", status: "503"},
+		{name: "Unknown path", path: "/api/missing/42", route: metrics.UnmatchedRoute, status: "404"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counter := metrics.HTTPRequests.WithLabelValues("GET", tt.route, tt.status)
+			before := testutil.ToFloat64(counter)
+
+			resp, err := app.Test(httptest.NewRequest("GET", tt.path, nil))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.status, strconv.Itoa(resp.StatusCode))
+			assert.Equal(t, before+1, testutil.ToFloat64(counter))
+		})
+	}
+}
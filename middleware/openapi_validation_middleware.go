@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/openapi"
+	"github.com/gofiber/fiber/v2"
+	"log/slog"
+	"strings"
+)
+
+// NewOpenAPIValidationMiddleware rejects requests whose path parameters, query, headers or JSON body do
+// not match the operation document describes, with 400 before a controller parses them. While logger is
+// enabled for debug, it checks JSON responses as well and logs where they break the document. The
+// document is read per request, so it may be filled in after the routes it describes are registered.
+func NewOpenAPIValidationMiddleware(document *openapi.Document, logger *slog.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		operation, params := document.Find(c.Method(), c.Path())
+		if operation == nil {
+			// HEAD, OPTIONS and unknown routes are left to the router
+			return c.Next()
+		}
+
+		if errs := validateRequest(c, document, operation, params); len(errs) > 0 {
+			messages := make([]string, len(errs))
+			for i, err := range errs {
+				messages[i] = err.Error()
+			}
+			return c.Status(fiber.StatusBadRequest).JSON(web.WebResponse{
+				Code:   fiber.StatusBadRequest,
+				Status: "Bad Request",
+				Data:   strings.Join(messages, "; "),
+			})
+		}
+
+		// an error is only written by the error handler, after this middleware returns
+		err := c.Next()
+		if err == nil && logger.Enabled(c.UserContext(), slog.LevelDebug) {
+			validateResponse(c, document, operation, logger)
+		}
+		return err
+	}
+}
+
+func validateRequest(c *fiber.Ctx, document *openapi.Document, operation *openapi.Operation, params map[string]string) []openapi.ValidationError {
+	var errs []openapi.ValidationError
+	for _, parameter := range document.Parameters(operation) {
+		var raw string
+		var present bool
+		switch parameter.In {
+		case "path":
+			raw, present = params[parameter.Name]
+		case "query":
+			present = c.Context().QueryArgs().Has(parameter.Name)
+			raw = c.Query(parameter.Name)
+		case "header":
+			raw = c.Get(parameter.Name)
+			present = raw != ""
+		}
+		errs = append(errs, document.ValidateParameter(parameter, raw, present)...)
+	}
+
+	mediaType := mediaTypeOf(c.Get(fiber.HeaderContentType))
+	if !strings.HasSuffix(mediaType, "json") {
+		// file uploads and unsupported media types are up to the controller
+		return errs
+	}
+	schema, ok := document.RequestSchema(operation, mediaType)
+	if !ok {
+		return errs
+	}
+	if len(c.Body()) == 0 {
+		if operation.RequestBody.Required {
+			errs = append(errs, openapi.ValidationError{Path: "body", Message: "is required"})
+		}
+		return errs
+	}
+	return append(errs, document.ValidateJSON("body", schema, c.Body())...)
+}
+
+func validateResponse(c *fiber.Ctx, document *openapi.Document, operation *openapi.Operation, logger *slog.Logger) {
+	status := c.Response().StatusCode()
+	attrs := []any{
+		slog.String("method", c.Method()),
+		slog.String("route", c.Route().Path),
+		slog.Int("status", status),
+	}
+
+	response, ok := document.Response(operation, status)
+	if !ok {
+		logger.WarnContext(c.UserContext(), "response status is not in the OpenAPI document", attrs...)
+		return
+	}
+	mediaType := mediaTypeOf(string(c.Response().Header.ContentType()))
+	if !strings.HasSuffix(mediaType, "json") {
+		return
+	}
+	content, ok := response.Content[mediaType]
+	if !ok {
+		logger.WarnContext(c.UserContext(), "response media type is not in the OpenAPI document", append(attrs, slog.String("media_type", mediaType))...)
+		return
+	}
+
+	errs := document.ValidateJSON("response", content.Schema, c.Response().Body())
+	if len(errs) == 0 {
+		return
+	}
+	violations := make([]string, len(errs))
+	for i, err := range errs {
+		violations[i] = err.Error()
+	}
+	logger.WarnContext(c.UserContext(), "response does not match the OpenAPI document", append(attrs, slog.Any("violations", violations))...)
+}
+
+// mediaTypeOf strips the parameters of a Content-Type, e.g. application/json; charset=utf-8
+func mediaTypeOf(contentType string) string {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	return strings.ToLower(strings.TrimSpace(mediaType))
+}
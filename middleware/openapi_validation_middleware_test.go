@@ -0,0 +1,141 @@
+package middleware
+
+import (
+	"bytes"
+	"github.com/aronipurwanto/go-restful-api/openapi"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type testWidgetRequest struct {
+	Name  string `json:"name" validate:"required,min=1,max=8"`
+	Color string `json:"color" validate:"oneof=red blue"`
+}
+
+type testWidgetResponse struct {
+	Id   uint64 `json:"id"`
+	Name string `json:"name"`
+}
+
+func testOpenAPIDocument() *openapi.Document {
+	schemas := openapi.NewSchemas()
+	envelope := func(data interface{}) map[string]*openapi.MediaType {
+		return map[string]*openapi.MediaType{fiber.MIMEApplicationJSON: {Schema: &openapi.Schema{
+			Type:       "object",
+			Properties: map[string]*openapi.Schema{"data": schemas.Of(data)},
+		}}}
+	}
+	widgetId := &openapi.Parameter{Name: "widgetId", In: "path", Required: true, Schema: &openapi.Schema{Type: "integer", Format: "int64"}}
+
+	document := &openapi.Document{Paths: map[string]*openapi.PathItem{
+		"/api/widgets": {
+			Get: &openapi.Operation{
+				Parameters: []*openapi.Parameter{
+					{Name: "color", In: "query", Schema: &openapi.Schema{Type: "string", Enum: []interface{}{"red", "blue"}}},
+				},
+				Responses: map[string]*openapi.Response{"200": {Content: envelope([]testWidgetResponse{})}},
+			},
+			Post: &openapi.Operation{
+				RequestBody: &openapi.RequestBody{Required: true, Content: map[string]*openapi.MediaType{
+					fiber.MIMEApplicationJSON: {Schema: schemas.Request(testWidgetRequest{})},
+				}},
+				Responses: map[string]*openapi.Response{"201": {Content: envelope(testWidgetResponse{})}},
+			},
+		},
+		"/api/widgets/{widgetId}": {
+			Get: &openapi.Operation{
+				Parameters: []*openapi.Parameter{widgetId},
+				Responses:  map[string]*openapi.Response{"200": {Content: envelope(testWidgetResponse{})}},
+			},
+		},
+	}}
+	document.Components.Schemas = schemas.Components()
+	return document
+}
+
+func setupTestAppOpenAPIValidation(logger *slog.Logger) *fiber.App {
+	app := fiber.New()
+	api := app.Group("/api", NewOpenAPIValidationMiddleware(testOpenAPIDocument(), logger))
+	api.Get("/widgets", func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{"data": []fiber.Map{{"id": 1, "name": "Cog"}}})
+	})
+	api.Post("/widgets", func(c *fiber.Ctx) error {
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"data": fiber.Map{"id": 1, "name": "Cog"}})
+	})
+	api.Get("/widgets/:widgetId", func(c *fiber.Ctx) error {
+		// the contract says id is an integer
+		return c.JSON(fiber.Map{"data": fiber.Map{"id": "1", "name": "Cog"}})
+	})
+	return app
+}
+
+func TestOpenAPIValidationMiddleware(t *testing.T) {
+	app := setupTestAppOpenAPIValidation(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	tests := []struct {
+		name           string
+		method         string
+		url            string
+		body           string
+		expectedStatus int
+		expectedData   string
+	}{
+		{name: "Valid body", method: "POST", url: "/api/widgets", body: `{"name":"Cog","color":"red"}`, expectedStatus: http.StatusCreated},
+		{name: "Missing property", method: "POST", url: "/api/widgets", body: `{"color":"red"}`, expectedStatus: http.StatusBadRequest, expectedData: "body.name: is required"},
+		{name: "Too long", method: "POST", url: "/api/widgets", body: `{"name":"Cogwheel 9000","color":"red"}`, expectedStatus: http.StatusBadRequest, expectedData: "body.name: must be at most 8 characters"},
+		{name: "Misspelled optional property", method: "POST", url: "/api/widgets", body: `{"name":"Cog","colour":"red"}`, expectedStatus: http.StatusBadRequest, expectedData: "body.colour: is not a known property"},
+		{name: "Wrong type", method: "POST", url: "/api/widgets", body: `{"name":7,"color":"red"}`, expectedStatus: http.StatusBadRequest, expectedData: "body.name: must be a string"},
+		{name: "Not JSON", method: "POST", url: "/api/widgets", body: `{"name":`, expectedStatus: http.StatusBadRequest, expectedData: "body: is not valid JSON"},
+		{name: "Missing body", method: "POST", url: "/api/widgets", expectedStatus: http.StatusBadRequest, expectedData: "body: is required"},
+		{name: "Valid query", method: "GET", url: "/api/widgets?color=blue", expectedStatus: http.StatusOK},
+		{name: "Query not in enum", method: "GET", url: "/api/widgets?color=green", expectedStatus: http.StatusBadRequest, expectedData: "query.color: must be one of [red blue]"},
+		{name: "Path parameter not an integer", method: "GET", url: "/api/widgets/abc", expectedStatus: http.StatusBadRequest, expectedData: "path.widgetId: must be an integer"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			body, _ := io.ReadAll(resp.Body)
+			assert.Contains(t, string(body), tt.expectedData)
+		})
+	}
+}
+
+func TestOpenAPIValidationMiddlewareResponses(t *testing.T) {
+	tests := []struct {
+		name        string
+		level       slog.Level
+		url         string
+		expectedLog string
+	}{
+		{name: "Violation logged in debug", level: slog.LevelDebug, url: "/api/widgets/1", expectedLog: "response.data.id: must be an integer"},
+		{name: "Matching response", level: slog.LevelDebug, url: "/api/widgets", expectedLog: ""},
+		{name: "Not checked above debug", level: slog.LevelInfo, url: "/api/widgets/1", expectedLog: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			app := setupTestAppOpenAPIValidation(slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: tt.level})))
+
+			resp, err := app.Test(httptest.NewRequest("GET", tt.url, nil))
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			if tt.expectedLog == "" {
+				assert.Empty(t, logs.String())
+			} else {
+				assert.Contains(t, logs.String(), tt.expectedLog)
+			}
+		})
+	}
+}
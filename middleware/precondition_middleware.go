@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/gofiber/fiber/v2"
+)
+
+// NewPreconditionMiddleware answers 428 to writes without If-Match when required is set,
+// so clients cannot overwrite a version they never read
+func NewPreconditionMiddleware(required bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if required && c.Get(fiber.HeaderIfMatch) == "" {
+			return c.Status(fiber.StatusPreconditionRequired).JSON(web.WebResponse{
+				Code:   fiber.StatusPreconditionRequired,
+				Status: "Precondition Required",
+				Data:   "If-Match header is required",
+			})
+		}
+		return c.Next()
+	}
+}
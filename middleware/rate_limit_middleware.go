@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/aronipurwanto/go-restful-api/metrics"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/repository"
+	"github.com/gofiber/fiber/v2"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultRateLimitGroup is the limit of route groups without one of their own
+const DefaultRateLimitGroup = "default"
+
+const (
+	RateLimitLimitHeader     = "RateLimit-Limit"
+	RateLimitRemainingHeader = "RateLimit-Remaining"
+	RateLimitResetHeader     = "RateLimit-Reset"
+	RateLimitPolicyHeader    = "RateLimit-Policy"
+)
+
+// NewRateLimitMiddleware gives every client a token bucket per route group, the first path segment
+// below the group it is mounted on (/api/products/1 is in "products"). Groups without an entry in
+// limits share the DefaultRateLimitGroup limit; without that entry they are not limited.
+// Clients are told apart by API key and IP, see rateLimitClient.
+func NewRateLimitMiddleware(store repository.RateLimitStore, limits map[string]domain.RateLimit) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		group := routeGroup(c)
+		limit, ok := limits[group]
+		if !ok {
+			group = DefaultRateLimitGroup
+			limit, ok = limits[group]
+		}
+		if !ok || limit.Limit <= 0 || limit.Period <= 0 {
+			return c.Next()
+		}
+
+		result, err := store.Take(c.Context(), group+":"+rateLimitClient(c), limit, time.Now())
+		if err != nil {
+			// A broken store must not take the API down with it
+			slog.ErrorContext(c.Context(), "rate limit store failed", "error", err)
+			return c.Next()
+		}
+
+		c.Set(RateLimitLimitHeader, strconv.Itoa(limit.Limit))
+		c.Set(RateLimitRemainingHeader, strconv.Itoa(result.Remaining))
+		c.Set(RateLimitResetHeader, strconv.Itoa(ceilSeconds(result.Reset)))
+		c.Set(RateLimitPolicyHeader, fmt.Sprintf("%d;w=%d", limit.Limit, ceilSeconds(limit.Period)))
+		if !result.Allowed {
+			metrics.RateLimited.WithLabelValues(group).Inc()
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(ceilSeconds(result.RetryAfter)))
+			return c.Status(fiber.StatusTooManyRequests).JSON(web.WebResponse{
+				Code:   fiber.StatusTooManyRequests,
+				Status: "Too Many Requests",
+				Data:   "Rate limit of " + strconv.Itoa(limit.Limit) + " requests per " + limit.Period.String() + " exceeded",
+			})
+		}
+		return c.Next()
+	}
+}
+
+// routeGroup is the first path segment below the prefix the middleware is mounted on; it must be
+// called before c.Next, which moves c.Route on to the handler
+func routeGroup(c *fiber.Ctx) string {
+	path := strings.TrimPrefix(c.Path(), c.Route().Path)
+	group, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	return group
+}
+
+// rateLimitClient identifies the caller by API key and IP. X-Employee-ID is not checked against the
+// key, so it never picks a bucket: a client varying it would get a fresh one every request. API keys
+// are hashed so a shared store never holds them.
+func rateLimitClient(c *fiber.Ctx) string {
+	apiKey, _ := c.Locals(apiKeyLocal).(string)
+	if apiKey == "" {
+		apiKey = c.Get(APIKeyHeader)
+	}
+	if apiKey != "" {
+		hash := sha256.Sum256([]byte(apiKey))
+		return "key:" + hex.EncodeToString(hash[:8]) + ":ip:" + c.IP()
+	}
+	return "ip:" + c.IP()
+}
+
+func ceilSeconds(duration time.Duration) int {
+	return int(math.Ceil(duration.Seconds()))
+}
@@ -0,0 +1,125 @@
+package middleware
+
+import (
+	"errors"
+	"github.com/aronipurwanto/go-restful-api/helper"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/repository"
+	"github.com/aronipurwanto/go-restful-api/repository/mocks"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func setupTestAppRateLimit(store repository.RateLimitStore, limits map[string]domain.RateLimit) *fiber.App {
+	app := fiber.New()
+	identify := func(c *fiber.Ctx) error {
+		if c.Get("X-Employee-ID") == "7" {
+			c.Locals(helper.EmployeeIdKey, uint64(7))
+		}
+		return c.Next()
+	}
+	api := app.Group("/api", identify, NewRateLimitMiddleware(store, limits))
+	api.Get("/products/:productId", func(c *fiber.Ctx) error {
+		return c.SendString("product")
+	})
+	api.Get("/customers", func(c *fiber.Ctx) error {
+		return c.SendString("customers")
+	})
+	return app
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	app := setupTestAppRateLimit(repository.NewMemoryRateLimitStore(), map[string]domain.RateLimit{
+		DefaultRateLimitGroup: {Limit: 3, Period: time.Minute},
+		"products":            {Limit: 1, Period: time.Minute},
+	})
+
+	tests := []struct {
+		name              string
+		url               string
+		employeeId        string
+		expectedStatus    int
+		expectedLimit     string
+		expectedRemaining string
+		expectedRetry     string
+	}{
+		{name: "Group limit", url: "/api/products/1", expectedStatus: http.StatusOK, expectedLimit: "1", expectedRemaining: "0"},
+		{name: "Group limit exceeded", url: "/api/products/2", expectedStatus: http.StatusTooManyRequests, expectedLimit: "1", expectedRemaining: "0", expectedRetry: "60"},
+		{name: "Employee shares the bucket of the key", url: "/api/products/1", employeeId: "7", expectedStatus: http.StatusTooManyRequests, expectedLimit: "1", expectedRemaining: "0", expectedRetry: "60"},
+		{name: "Default limit of other groups", url: "/api/customers", expectedStatus: http.StatusOK, expectedLimit: "3", expectedRemaining: "2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.url, nil)
+			req.Header.Set("X-API-Key", "RAHASIA")
+			if tt.employeeId != "" {
+				req.Header.Set("X-Employee-ID", tt.employeeId)
+			}
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			assert.Equal(t, tt.expectedLimit, resp.Header.Get(RateLimitLimitHeader))
+			assert.Equal(t, tt.expectedRemaining, resp.Header.Get(RateLimitRemainingHeader))
+			assert.Equal(t, tt.expectedLimit+";w=60", resp.Header.Get(RateLimitPolicyHeader))
+			assert.Equal(t, tt.expectedRetry, resp.Header.Get(fiber.HeaderRetryAfter))
+		})
+	}
+}
+
+func TestRateLimitMiddlewareStore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tests := []struct {
+		name           string
+		limits         map[string]domain.RateLimit
+		setupMock      func(mockStore *mocks.MockRateLimitStore)
+		expectedStatus int
+	}{
+		{
+			name:           "Group without a limit",
+			limits:         map[string]domain.RateLimit{"products": {Limit: 1, Period: time.Minute}},
+			setupMock:      func(mockStore *mocks.MockRateLimitStore) {},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "Bucket keyed by group, API key and IP, not by employee",
+			limits: map[string]domain.RateLimit{DefaultRateLimitGroup: {Limit: 5, Period: time.Second}},
+			setupMock: func(mockStore *mocks.MockRateLimitStore) {
+				mockStore.EXPECT().Take(gomock.Any(), "default:key:67edf476f2bb8737:ip:0.0.0.0", domain.RateLimit{Limit: 5, Period: time.Second}, gomock.Any()).
+					Return(domain.RateLimitResult{Allowed: true, Remaining: 4}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "Failing store lets requests through",
+			limits: map[string]domain.RateLimit{DefaultRateLimitGroup: {Limit: 5, Period: time.Second}},
+			setupMock: func(mockStore *mocks.MockRateLimitStore) {
+				mockStore.EXPECT().Take(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(domain.RateLimitResult{}, errors.New("connection refused"))
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := mocks.NewMockRateLimitStore(ctrl)
+			tt.setupMock(mockStore)
+			app := setupTestAppRateLimit(mockStore, tt.limits)
+
+			req := httptest.NewRequest("GET", "/api/customers", nil)
+			req.Header.Set("X-API-Key", "RAHASIA")
+			req.Header.Set("X-Employee-ID", "7")
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"github.com/aronipurwanto/go-restful-api/helper"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"regexp"
+)
+
+// validRequestId accepts the ids a client may choose: short enough for audit_logs.request_id and
+// safe to log and echo back
+var validRequestId = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// NewRequestIdMiddleware keeps the X-Request-ID sent by the client when it is valid, or generates one,
+// echoes it in the response and stores it for helper.RequestIdFromContext
+func NewRequestIdMiddleware() fiber.Handler {
+	handler := requestid.New(requestid.Config{
+		Header:     fiber.HeaderXRequestID,
+		ContextKey: helper.RequestIdKey,
+	})
+	return func(c *fiber.Ctx) error {
+		if !validRequestId.MatchString(c.Get(fiber.HeaderXRequestID)) {
+			c.Request().Header.Del(fiber.HeaderXRequestID)
+		}
+		return handler(c)
+	}
+}
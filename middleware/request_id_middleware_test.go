@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"github.com/aronipurwanto/go-restful-api/helper"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequestIdMiddleware(t *testing.T) {
+	app := fiber.New()
+	app.Use(NewRequestIdMiddleware())
+	app.Get("/", func(c *fiber.Ctx) error {
+		requestId, _ := c.Locals(helper.RequestIdKey).(string)
+		return c.SendString(requestId)
+	})
+
+	tests := []struct {
+		name      string
+		requestId string
+		kept      bool
+	}{
+		{name: "Client id", requestId: "client-id_1.2", kept: true},
+		{name: "Longest id", requestId: strings.Repeat("a", 64), kept: true},
+		{name: "Missing id", requestId: ""},
+		{name: "Too long", requestId: strings.Repeat("a", 65)},
+		{name: "Unsafe characters", requestId: "id\"><script>"},
+		{name: "Spaces", requestId: "request id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+			if tt.requestId != "" {
+				req.Header.Set(fiber.HeaderXRequestID, tt.requestId)
+			}
+			resp, err := app.Test(req)
+			if !assert.NoError(t, err) {
+				return
+			}
+			body, _ := io.ReadAll(resp.Body)
+
+			requestId := resp.Header.Get(fiber.HeaderXRequestID)
+			if tt.kept {
+				assert.Equal(t, tt.requestId, requestId)
+			} else {
+				assert.NotEqual(t, tt.requestId, requestId)
+				assert.Regexp(t, validRequestId, requestId)
+			}
+			assert.Equal(t, requestId, string(body))
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"github.com/aronipurwanto/go-restful-api/helper"
+	"github.com/aronipurwanto/go-restful-api/metrics"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// fiberCarrier reads trace context from the request headers and writes it to the response headers
+type fiberCarrier struct {
+	c *fiber.Ctx
+}
+
+func (carrier fiberCarrier) Get(key string) string {
+	return carrier.c.Get(key)
+}
+
+func (carrier fiberCarrier) Set(key string, value string) {
+	carrier.c.Set(key, value)
+}
+
+func (carrier fiberCarrier) Keys() []string {
+	var keys []string
+	carrier.c.Request().Header.VisitAll(func(key, value []byte) {
+		keys = append(keys, string(key))
+	})
+	return keys
+}
+
+// NewTracingMiddleware starts the server span of every request, continuing the trace of an incoming
+// W3C traceparent header, and returns the traceparent of the span so clients can look the trace up
+func NewTracingMiddleware() fiber.Handler {
+	tracer := otel.Tracer("github.com/aronipurwanto/go-restful-api/middleware")
+
+	return func(c *fiber.Ctx) error {
+		propagator := otel.GetTextMapPropagator()
+		ctx := propagator.Extract(c.UserContext(), fiberCarrier{c: c})
+		ctx, span := tracer.Start(ctx, c.Method(),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", c.Method()),
+				attribute.String("url.path", c.Path()),
+				attribute.String("http.request.id", c.GetRespHeader(fiber.HeaderXRequestID)),
+			),
+		)
+		defer span.End()
+
+		c.SetUserContext(ctx)
+		c.Locals(helper.SpanKey, span)
+		propagator.Inject(ctx, fiberCarrier{c: c})
+
+		err := c.Next()
+
+		route, status := routeAndStatus(c, err)
+		if err != nil {
+			span.RecordError(err)
+		}
+		if route != metrics.UnmatchedRoute {
+			span.SetName(c.Method() + " " + route)
+			span.SetAttributes(attribute.String("http.route", route))
+		}
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		if status >= fiber.StatusInternalServerError {
+			span.SetStatus(codes.Error, utils.StatusMessage(status))
+		}
+		return err
+	}
+}
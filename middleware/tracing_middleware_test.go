@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"github.com/aronipurwanto/go-restful-api/helper"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTracingMiddleware(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	app := fiber.New()
+	app.Use(NewTracingMiddleware())
+	app.Get("/api/products/:productId", func(c *fiber.Ctx) error {
+		// Services start their spans from the fasthttp context the controllers hand them
+		_, span := otel.Tracer("test").Start(helper.ContextWithRequestSpan(c.Context()), "ProductService.FindById")
+		span.End()
+		return c.SendString("product")
+	})
+
+	const traceId = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest("GET", "/api/products/1", nil)
+	req.Header.Set("traceparent", "00-"+traceId+"-00f067aa0ba902b7-01")
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.True(t, strings.HasPrefix(resp.Header.Get("traceparent"), "00-"+traceId+"-"))
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+	serviceSpan, serverSpan := spans[0], spans[1]
+	assert.Equal(t, "GET /api/products/:productId", serverSpan.Name())
+	assert.Equal(t, traceId, serverSpan.SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", serverSpan.Parent().SpanID().String())
+	assert.Equal(t, serverSpan.SpanContext().SpanID(), serviceSpan.Parent().SpanID())
+}
@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditLog records one create, update, delete, restore or purge of an audited row. Rows are only ever inserted.
+type AuditLog struct {
+	Id        uint64          `gorm:"primaryKey;autoIncrement;column:id"`
+	Entity    string          `gorm:"column:entity;type:varchar(32);index:idx_audit_entity"`
+	EntityId  uint64          `gorm:"column:entity_id;index:idx_audit_entity"`
+	Action    string          `gorm:"column:action;type:varchar(16)"`
+	ActorId   *uint64         `gorm:"column:actor_id;index"` // nil for background jobs and anonymous requests
+	RequestId string          `gorm:"column:request_id;type:varchar(64)"`
+	Changes   json.RawMessage `gorm:"column:changes;type:json"` // {"column": {"before": ..., "after": ...}}
+	CreatedAt time.Time       `gorm:"column:created_at;index"`
+}
+
+// AuditFilter narrows audit log listings; zero fields are not filtered on
+type AuditFilter struct {
+	Entity   string
+	EntityId *uint64
+	ActorId  *uint64
+	Limit    int
+}
@@ -1,7 +1,13 @@
 package domain
 
+import "gorm.io/gorm"
+
 type Category struct {
-	Id      uint64    `gorm:"primary_key;autoIncrement;column:id"`
-	Name    string    `gorm:"column:name"`
-	Product []Product `gorm:"foreignkey:CategoryId;references:Id"`
+	Id        uint64         `gorm:"primary_key;autoIncrement;column:id"`
+	Name      string         `gorm:"column:name"`
+	ParentId  *uint64        `gorm:"column:parent_id;index"` // nil for a root category
+	Version   uint64         `gorm:"column:version;not null;default:1"`
+	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at;index"`
+	Children  []Category     `gorm:"foreignKey:ParentId;references:Id;constraint:OnDelete:RESTRICT"`
+	Product   []Product      `gorm:"foreignkey:CategoryId;references:Id"`
 }
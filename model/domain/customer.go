@@ -1,10 +1,14 @@
 package domain
 
+import "gorm.io/gorm"
+
 type Customer struct {
-	CustomerID uint64 `gorm:"primary_key;column:id;autoIncrement"`
-	Name       string `gorm:"column:customer_name; type:varchar(100);"`
-	Email      string `gorm:"column:customer_email; type:varchar(255);"`
-	Phone      string `gorm:"column:customer_phone; type:varchar(20);"`
-	Address    string `gorm:"column:customer_address; type:varchar(255);"`
-	LoyaltyPts int    `gorm:"column:loyalty_pts; type:int(11);"`
+	CustomerID uint64         `gorm:"primary_key;column:id;autoIncrement"`
+	Name       string         `gorm:"column:customer_name; type:varchar(100);"`
+	Email      string         `gorm:"column:customer_email; type:varchar(255);"`
+	Phone      string         `gorm:"column:customer_phone; type:varchar(20);"`
+	Address    string         `gorm:"column:customer_address; type:varchar(255);"`
+	LoyaltyPts int            `gorm:"column:loyalty_pts; type:int(11);"`
+	Version    uint64         `gorm:"column:version;not null;default:1"`
+	DeletedAt  gorm.DeletedAt `gorm:"column:deleted_at;index"`
 }
@@ -1,9 +1,16 @@
 package domain
 
+import "github.com/aronipurwanto/go-restful-api/model/money"
+
 type Discount struct {
-	DiscountID  string  `json:"discount_id"`
-	Description string  `json:"description"`
-	DiscountPct float64 `json:"discount_pct"` // e.g., 10 for 10%
-	ValidFrom   string  `json:"valid_from"`
-	ValidUntil  string  `json:"valid_until"`
+	DiscountID  string       `json:"discount_id"`
+	Description string       `json:"description"`
+	DiscountPct money.Amount `json:"discount_pct"` // e.g., 10 for 10%
+	ValidFrom   string       `json:"valid_from"`
+	ValidUntil  string       `json:"valid_until"`
+}
+
+// Of returns the discount off amount, rounded to the minor unit of its currency
+func (discount Discount) Of(amount money.Money) (money.Money, error) {
+	return amount.Percent(discount.DiscountPct)
 }
@@ -1,10 +1,14 @@
 package domain
 
+import "gorm.io/gorm"
+
 type Employee struct {
-	EmployeeID uint64 `gorm:"column:id;primary_key"`
-	Name       string `gorm:"column:name"`
-	Role       string `gorm:"column:role"` // e.g., Cashier, Manager
-	Email      string `gorm:"column:email"`
-	Phone      string `gorm:"column:phone"`
-	DateHired  string `gorm:"column:date_hired"`
+	EmployeeID uint64         `gorm:"column:id;primary_key"`
+	Name       string         `gorm:"column:name"`
+	Role       string         `gorm:"column:role"` // e.g., Cashier, Manager
+	Email      string         `gorm:"column:email"`
+	Phone      string         `gorm:"column:phone"`
+	DateHired  string         `gorm:"column:date_hired"`
+	Version    uint64         `gorm:"column:version;not null;default:1"`
+	DeletedAt  gorm.DeletedAt `gorm:"column:deleted_at;index"`
 }
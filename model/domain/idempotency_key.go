@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Idempotency key states
+const (
+	IdempotencyInProgress = "in_progress"
+	IdempotencyCompleted  = "completed"
+)
+
+// IdempotencyKey remembers the first response to a POST sent with an Idempotency-Key header,
+// so retries of the same request get that response instead of repeating its effect
+type IdempotencyKey struct {
+	Key             string          `gorm:"primaryKey;column:idempotency_key;type:varchar(255)"`
+	Fingerprint     string          `gorm:"column:fingerprint;type:char(64)"` // sha256 of method, URL and body
+	Token           string          `gorm:"column:token;type:char(32)"`       // random, identifies the request holding the claim
+	Status          string          `gorm:"column:status;type:varchar(16)"`
+	ResponseStatus  int             `gorm:"column:response_status"`
+	ResponseHeaders json.RawMessage `gorm:"column:response_headers;type:json"`
+	ResponseBody    []byte          `gorm:"column:response_body;type:longblob"`
+	CreatedAt       time.Time       `gorm:"column:created_at"`
+	ExpiresAt       time.Time       `gorm:"column:expires_at;index"`
+}
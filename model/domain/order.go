@@ -1,16 +1,18 @@
 package domain
 
+import "github.com/aronipurwanto/go-restful-api/model/money"
+
 type Order struct {
 	OrderID     string      `json:"order_id"`
 	CustomerID  string      `json:"customer_id"`
 	OrderDate   string      `json:"order_date"`
-	TotalAmount float64     `json:"total_amount"`
+	TotalAmount money.Money `json:"total_amount"`
 	OrderItems  []OrderItem `json:"order_items"`
 }
 
 type OrderItem struct {
-	ProductID  string  `json:"product_id"`
-	Quantity   int     `json:"quantity"`
-	UnitPrice  float64 `json:"unit_price"`
-	TotalPrice float64 `json:"total_price"`
+	ProductID  string      `json:"product_id"`
+	Quantity   int         `json:"quantity"`
+	UnitPrice  money.Money `json:"unit_price"`
+	TotalPrice money.Money `json:"total_price"`
 }
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Domain event types written to the outbox
+const (
+	ProductCreatedEvent  = "ProductCreated"
+	PriceChangedEvent    = "PriceChanged"
+	StockChangedEvent    = "StockChanged"
+	CustomerUpdatedEvent = "CustomerUpdated"
+)
+
+// EventTypes lists every domain event a webhook can subscribe to
+var EventTypes = []string{ProductCreatedEvent, PriceChangedEvent, StockChangedEvent, CustomerUpdatedEvent}
+
+// OutboxEvent is a domain event saved in the same transaction as the change it describes.
+// The webhook dispatcher fans it out to subscribers and then marks it dispatched.
+type OutboxEvent struct {
+	Id            uint64          `gorm:"primaryKey;autoIncrement;column:id"`
+	Type          string          `gorm:"column:type;type:varchar(32)"`
+	AggregateType string          `gorm:"column:aggregate_type;type:varchar(32)"`
+	AggregateId   uint64          `gorm:"column:aggregate_id"`
+	Payload       json.RawMessage `gorm:"column:payload;type:json"`
+	CreatedAt     time.Time       `gorm:"column:created_at"`
+	DispatchedAt  *time.Time      `gorm:"column:dispatched_at;index"`
+}
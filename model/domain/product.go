@@ -1,18 +1,34 @@
 package domain
 
+import (
+	"github.com/aronipurwanto/go-restful-api/model/money"
+	"gorm.io/gorm"
+)
+
 type Product struct {
-	ProductID   uint64   `gorm:"primaryKey;column:id"`
-	Name        string   `gorm:"column:product_name; length:255"`
-	Description string   `gorm:"column:product_description; length:255"`
-	Price       float64  `gorm:"column:product_price"`
-	StockQty    int      `gorm:"column:stock_qty"`
-	CategoryId  uint64   `gorm:"column:category_id"`
-	SKU         string   `gorm:"column:product_sku"`
-	TaxRate     float64  `gorm:"column:tax_rate"`
-	Category    Category `gorm:"foreignKey:CategoryId;references:Id"`
+	ProductID   uint64           `gorm:"primaryKey;column:id"`
+	Name        string           `gorm:"column:product_name; length:255"`
+	Description string           `gorm:"column:product_description; length:255"`
+	Price       money.Money      `gorm:"embedded;embeddedPrefix:product_price_"`
+	StockQty    int              `gorm:"column:stock_qty"`
+	CategoryId  uint64           `gorm:"column:category_id"`
+	SKU         string           `gorm:"column:product_sku"`
+	TaxRate     money.Amount     `gorm:"column:tax_rate;type:decimal(19,4)"` // percent, e.g. 11 for 11%
+	Version     uint64           `gorm:"column:version;not null;default:1"`
+	DeletedAt   gorm.DeletedAt   `gorm:"column:deleted_at;index"`
+	Category    Category         `gorm:"foreignKey:CategoryId;references:Id"`
+	Barcodes    []ProductBarcode `gorm:"foreignKey:ProductId;references:ProductID"`
+	Options     []ProductOption  `gorm:"foreignKey:ProductId;references:ProductID"`
+	Variants    []ProductVariant `gorm:"foreignKey:ProductId;references:ProductID"`
+}
+
+// ProductFilter narrows product listings; nil fields are not filtered on
+type ProductFilter struct {
+	CategoryId *uint64
 }
 
 type ProductError struct {
+	Line    int     `json:"line"`
 	Product Product `json:"product"`
 	Error   error   `json:"error"`
 }
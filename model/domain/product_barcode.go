@@ -0,0 +1,9 @@
+package domain
+
+type ProductBarcode struct {
+	Id        uint64  `gorm:"primaryKey;autoIncrement;column:id"`
+	ProductId uint64  `gorm:"column:product_id;index"`
+	Code      string  `gorm:"column:barcode_code;type:varchar(14);uniqueIndex"`
+	PackQty   int     `gorm:"column:pack_qty;default:1"` // e.g., 1 for a single unit, 12 for a case pack
+	Product   Product `gorm:"foreignKey:ProductId;references:ProductID"`
+}
@@ -0,0 +1,17 @@
+package domain
+
+import (
+	"github.com/aronipurwanto/go-restful-api/model/money"
+	"time"
+)
+
+type ProductPrice struct {
+	Id            uint64        `gorm:"primaryKey;autoIncrement;column:id"`
+	ProductId     uint64        `gorm:"column:product_id;index:idx_product_price_effective"`
+	Price         money.Money   `gorm:"embedded;embeddedPrefix:price_"`
+	PreviousPrice *money.Amount `gorm:"column:previous_price;type:decimal(19,4)"` // in the same currency, filled in when the price is applied
+	EffectiveFrom time.Time     `gorm:"column:effective_from;index:idx_product_price_effective"`
+	AppliedAt     *time.Time    `gorm:"column:applied_at;index"` // nil while the change is still pending
+	EmployeeId    *uint64       `gorm:"column:employee_id"`
+	CreatedAt     time.Time     `gorm:"column:created_at"`
+}
@@ -0,0 +1,21 @@
+package domain
+
+import "github.com/aronipurwanto/go-restful-api/model/money"
+
+type ProductOption struct {
+	Id        uint64   `gorm:"primaryKey;autoIncrement;column:id"`
+	ProductId uint64   `gorm:"column:product_id;index"`
+	Name      string   `gorm:"column:option_name;type:varchar(50)"`  // e.g., Size, Color
+	Values    []string `gorm:"column:option_values;serializer:json"` // e.g., ["S","M","L"]
+	Position  int      `gorm:"column:option_position"`               // order used for variant SKU and option key
+}
+
+type ProductVariant struct {
+	Id            uint64            `gorm:"primaryKey;autoIncrement;column:id"`
+	ProductId     uint64            `gorm:"column:product_id;uniqueIndex:idx_product_variant_option"`
+	OptionKey     string            `gorm:"column:option_key;type:varchar(255);uniqueIndex:idx_product_variant_option"` // e.g., Size=M;Color=Red
+	Options       map[string]string `gorm:"column:variant_options;serializer:json"`
+	SKU           string            `gorm:"column:variant_sku;type:varchar(100);uniqueIndex"`
+	PriceOverride *money.Amount     `gorm:"column:price_override;type:decimal(19,4)"` // in the parent currency, nil means the parent price applies
+	StockQty      int               `gorm:"column:stock_qty"`
+}
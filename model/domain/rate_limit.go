@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// RateLimit is a token bucket holding up to Limit requests, refilled at Limit requests per Period
+type RateLimit struct {
+	Limit  int
+	Period time.Duration
+}
+
+// RateLimitResult is the state of a bucket after a request took, or failed to take, a token from it
+type RateLimitResult struct {
+	Allowed    bool
+	Remaining  int
+	Reset      time.Duration // until the bucket is full again
+	RetryAfter time.Duration // until the next token, when the request was not allowed
+}
@@ -1,12 +1,14 @@
 package domain
 
+import "github.com/aronipurwanto/go-restful-api/model/money"
+
 type Receipt struct {
-	ReceiptID   string  `json:"receipt_id"`
-	OrderID     string  `json:"order_id"`
-	PaymentID   string  `json:"payment_id"`
-	ReceiptDate string  `json:"receipt_date"`
-	TotalAmount float64 `json:"total_amount"`
-	Taxes       float64 `json:"taxes"`
-	Discount    float64 `json:"discount"`
-	FinalAmount float64 `json:"final_amount"`
+	ReceiptID   string      `json:"receipt_id"`
+	OrderID     string      `json:"order_id"`
+	PaymentID   string      `json:"payment_id"`
+	ReceiptDate string      `json:"receipt_date"`
+	TotalAmount money.Money `json:"total_amount"`
+	Taxes       money.Money `json:"taxes"`
+	Discount    money.Money `json:"discount"`
+	FinalAmount money.Money `json:"final_amount"`
 }
@@ -1,8 +1,15 @@
 package domain
 
+import "github.com/aronipurwanto/go-restful-api/model/money"
+
 type Tax struct {
-	TaxID       string  `json:"tax_id"`
-	TaxRate     float64 `json:"tax_rate"` // Percentage value of the tax rate
-	TaxType     string  `json:"tax_type"` // e.g., Sales Tax, VAT
-	Description string  `json:"description"`
+	TaxID       string       `json:"tax_id"`
+	TaxRate     money.Amount `json:"tax_rate"` // Percentage value of the tax rate
+	TaxType     string       `json:"tax_type"` // e.g., Sales Tax, VAT
+	Description string       `json:"description"`
+}
+
+// Of returns the tax on amount, rounded to the minor unit of its currency
+func (tax Tax) Of(amount money.Money) (money.Money, error) {
+	return amount.Percent(tax.TaxRate)
 }
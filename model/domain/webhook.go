@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// Webhook delivery states
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryDead      = "dead"
+)
+
+// Webhook is an endpoint that receives signed POSTs for the domain events it subscribed to
+type Webhook struct {
+	Id        uint64    `gorm:"primaryKey;autoIncrement;column:id"`
+	Url       string    `gorm:"column:url;type:varchar(2048)"`
+	Secret    string    `gorm:"column:secret;type:varchar(128)"`
+	Events    string    `gorm:"column:events;type:varchar(255)"` // comma separated event types, empty for all
+	Active    bool      `gorm:"column:active;not null"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+	UpdatedAt time.Time `gorm:"column:updated_at"`
+}
+
+// EventList returns the subscribed event types, or nil when the webhook receives every event
+func (webhook Webhook) EventList() []string {
+	if webhook.Events == "" {
+		return nil
+	}
+	return strings.Split(webhook.Events, ",")
+}
+
+// Subscribes reports whether the webhook wants events of the given type
+func (webhook Webhook) Subscribes(eventType string) bool {
+	events := webhook.EventList()
+	if events == nil {
+		return true
+	}
+	for _, event := range events {
+		if event == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery is one event on its way to one webhook, and the log of its attempts
+type WebhookDelivery struct {
+	Id             uint64      `gorm:"primaryKey;autoIncrement;column:id"`
+	WebhookId      uint64      `gorm:"column:webhook_id;index:idx_delivery_webhook"`
+	Webhook        Webhook     `gorm:"foreignKey:WebhookId;references:Id"`
+	EventId        uint64      `gorm:"column:event_id"`
+	Event          OutboxEvent `gorm:"foreignKey:EventId;references:Id"`
+	Status         string      `gorm:"column:status;type:varchar(16);index:idx_delivery_due"`
+	Attempts       int         `gorm:"column:attempts"`
+	NextAttemptAt  time.Time   `gorm:"column:next_attempt_at;index:idx_delivery_due"`
+	LastStatusCode int         `gorm:"column:last_status_code"`
+	LastError      string      `gorm:"column:last_error;type:varchar(1024)"`
+	DeliveredAt    *time.Time  `gorm:"column:delivered_at"`
+	CreatedAt      time.Time   `gorm:"column:created_at;index:idx_delivery_webhook"`
+	UpdatedAt      time.Time   `gorm:"column:updated_at"`
+}
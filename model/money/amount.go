@@ -0,0 +1,176 @@
+package money
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Scale is the number of fractional digits kept by an Amount, matching DECIMAL(19,4) columns
+const Scale = 4
+
+const scaleFactor = 10000
+
+// Amount is an exact fixed-point decimal with four fractional digits, stored as
+// the number of ten-thousandths. It never goes through float64.
+type Amount int64
+
+// NewAmount returns the Amount for a whole number of units, or ErrOverflow when it does not fit
+func NewAmount(units int64) (Amount, error) {
+	if units > math.MaxInt64/scaleFactor || units < math.MinInt64/scaleFactor {
+		return 0, ErrOverflow
+	}
+	return Amount(units * scaleFactor), nil
+}
+
+// AmountFromInt is NewAmount for units known to fit, such as constants; it panics on overflow
+func AmountFromInt(units int64) Amount {
+	amount, err := NewAmount(units)
+	if err != nil {
+		panic(err)
+	}
+	return amount
+}
+
+// ParseAmount parses a decimal string such as "-12.5" or "15000000".
+// More than four fractional digits are rejected rather than silently rounded.
+func ParseAmount(value string) (Amount, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, errors.New("money: empty amount")
+	}
+
+	negative := false
+	switch value[0] {
+	case '-':
+		negative = true
+		value = value[1:]
+	case '+':
+		value = value[1:]
+	}
+
+	whole, fraction, _ := strings.Cut(value, ".")
+	if whole == "" && fraction == "" {
+		return 0, fmt.Errorf("money: invalid amount %q", value)
+	}
+	if len(fraction) > Scale {
+		return 0, fmt.Errorf("money: amount %q has more than %d decimal places", value, Scale)
+	}
+	fraction += strings.Repeat("0", Scale-len(fraction))
+	if whole == "" {
+		whole = "0"
+	}
+
+	for _, digit := range whole + fraction {
+		if digit < '0' || digit > '9' {
+			return 0, fmt.Errorf("money: invalid amount %q", value)
+		}
+	}
+
+	units, err := strconv.ParseInt(whole+fraction, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("money: amount %q is out of range", value)
+	}
+	if negative {
+		units = -units
+	}
+	return Amount(units), nil
+}
+
+// String formats the amount with all four fractional digits, e.g. "12.5000"
+func (a Amount) String() string {
+	return a.format(Scale)
+}
+
+// Decimal formats the amount without trailing fractional zeros, e.g. "12.5"
+func (a Amount) Decimal() string {
+	return a.format(0)
+}
+
+// format prints the amount with at least minDigits fractional digits, keeping any non-zero digit beyond that
+func (a Amount) format(minDigits int) string {
+	units := int64(a)
+	sign := ""
+	if units < 0 {
+		sign = "-"
+	}
+
+	abs := new(big.Int).Abs(big.NewInt(units)).String()
+	if len(abs) <= Scale {
+		abs = strings.Repeat("0", Scale-len(abs)+1) + abs
+	}
+	whole, fraction := abs[:len(abs)-Scale], abs[len(abs)-Scale:]
+
+	for len(fraction) > minDigits && fraction[len(fraction)-1] == '0' {
+		fraction = fraction[:len(fraction)-1]
+	}
+	if fraction == "" {
+		return sign + whole
+	}
+	return sign + whole + "." + fraction
+}
+
+// MarshalJSON encodes the amount as a string so clients never parse it as a float
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.Decimal())
+}
+
+// UnmarshalJSON accepts either a string ("12.50") or a bare JSON number (12.50),
+// reading the number text directly so no float rounding is involved
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	text := string(data)
+	if text == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(text); err == nil {
+		text = unquoted
+	}
+
+	amount, err := ParseAmount(text)
+	if err != nil {
+		return err
+	}
+	*a = amount
+	return nil
+}
+
+// Value stores the amount as a DECIMAL literal
+func (a Amount) Value() (driver.Value, error) {
+	return a.String(), nil
+}
+
+// Scan reads a DECIMAL column, which drivers return as text
+func (a *Amount) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*a = 0
+		return nil
+	case []byte:
+		return a.scanText(string(v))
+	case string:
+		return a.scanText(v)
+	case int64:
+		amount, err := NewAmount(v)
+		if err != nil {
+			return err
+		}
+		*a = amount
+		return nil
+	default:
+		return fmt.Errorf("money: cannot scan %T into Amount", value)
+	}
+}
+
+func (a *Amount) scanText(text string) error {
+	amount, err := ParseAmount(text)
+	if err != nil {
+		return err
+	}
+	*a = amount
+	return nil
+}
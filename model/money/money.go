@@ -0,0 +1,206 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"math/big"
+	"strings"
+)
+
+var ErrCurrencyMismatch = errors.New("money: currency mismatch")
+
+// ErrOverflow is returned when a result does not fit in an Amount
+var ErrOverflow = errors.New("money: amount out of range")
+
+// Money is an exact Amount in an ISO 4217 currency. Embed it in GORM models with
+// `gorm:"embedded;embeddedPrefix:<column>_"` to get a DECIMAL amount and a currency column.
+type Money struct {
+	Amount   Amount `gorm:"column:amount;type:decimal(19,4)" json:"amount"`
+	Currency string `gorm:"column:currency;type:char(3)" json:"currency" validate:"required,iso4217"`
+}
+
+// minorDigits lists the ISO 4217 currencies whose minor unit differs from two digits
+var minorDigits = map[string]int{
+	"BHD": 3, "BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "IQD": 3, "ISK": 0, "JOD": 3,
+	"JPY": 0, "KMF": 0, "KRW": 0, "KWD": 3, "LYD": 3, "OMR": 3, "PYG": 0, "RWF": 0,
+	"TND": 3, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+}
+
+// MinorDigits returns the number of decimal places of the currency's minor unit
+func MinorDigits(currency string) int {
+	if digits, ok := minorDigits[strings.ToUpper(currency)]; ok {
+		return digits
+	}
+	return 2
+}
+
+func New(amount Amount, currency string) Money {
+	return Money{Amount: amount, Currency: strings.ToUpper(currency)}
+}
+
+// FromInt returns a whole number of units, e.g. FromInt(15000, "IDR")
+func FromInt(units int64, currency string) Money {
+	return New(AmountFromInt(units), currency)
+}
+
+// Parse reads a decimal string such as "12.50" in the given currency
+func Parse(amount string, currency string) (Money, error) {
+	parsed, err := ParseAmount(amount)
+	if err != nil {
+		return Money{}, err
+	}
+	return New(parsed, currency), nil
+}
+
+func MustParse(amount string, currency string) Money {
+	m, err := Parse(amount, currency)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, ErrCurrencyMismatch
+	}
+	sum := m.Amount + other.Amount
+	// The sum of two amounts of the same sign wrapped around when its sign differs
+	if (m.Amount >= 0) == (other.Amount >= 0) && (sum >= 0) != (m.Amount >= 0) {
+		return Money{}, ErrOverflow
+	}
+	return New(sum, m.Currency), nil
+}
+
+func (m Money) Sub(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, ErrCurrencyMismatch
+	}
+	difference := m.Amount - other.Amount
+	// Subtracting an amount of the other sign wrapped around when the result's sign differs from m
+	if (m.Amount >= 0) != (other.Amount >= 0) && (difference >= 0) != (m.Amount >= 0) {
+		return Money{}, ErrOverflow
+	}
+	return New(difference, m.Currency), nil
+}
+
+func (m Money) Neg() (Money, error) {
+	if m.Amount == math.MinInt64 {
+		return Money{}, ErrOverflow
+	}
+	return New(-m.Amount, m.Currency), nil
+}
+
+// Cmp returns -1, 0 or +1 like big.Int.Cmp
+func (m Money) Cmp(other Money) (int, error) {
+	if m.Currency != other.Currency {
+		return 0, ErrCurrencyMismatch
+	}
+	switch {
+	case m.Amount < other.Amount:
+		return -1, nil
+	case m.Amount > other.Amount:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// Mul multiplies by a quantity, e.g. unit price times items ordered
+func (m Money) Mul(quantity int64) (Money, error) {
+	product := new(big.Int).Mul(big.NewInt(int64(m.Amount)), big.NewInt(quantity))
+	if !product.IsInt64() {
+		return Money{}, ErrOverflow
+	}
+	return New(Amount(product.Int64()), m.Currency), nil
+}
+
+// Percent returns rate percent of m, e.g. the tax for an 11% rate, rounded to the currency's minor unit
+func (m Money) Percent(rate Amount) (Money, error) {
+	step := big.NewInt(minorStep(m.Currency))
+	product := new(big.Int).Mul(big.NewInt(int64(m.Amount)), big.NewInt(int64(rate)))
+	units := roundHalfEven(product, new(big.Int).Mul(big.NewInt(100*scaleFactor), step))
+	units.Mul(units, step)
+	if !units.IsInt64() {
+		return Money{}, ErrOverflow
+	}
+	return New(Amount(units.Int64()), m.Currency), nil
+}
+
+// Round rounds to the currency's minor unit using banker's rounding (half to even)
+func (m Money) Round() (Money, error) {
+	step := big.NewInt(minorStep(m.Currency))
+	units := roundHalfEven(big.NewInt(int64(m.Amount)), step)
+	units.Mul(units, step)
+	if !units.IsInt64() {
+		return Money{}, ErrOverflow
+	}
+	return New(Amount(units.Int64()), m.Currency), nil
+}
+
+// Decimal formats the amount alone with the currency's minor digits, e.g. "12.50"
+func (m Money) Decimal() string {
+	return m.Amount.format(MinorDigits(m.Currency))
+}
+
+// String formats the money with the currency's minor digits, e.g. "12.50 USD"
+func (m Money) String() string {
+	return m.Decimal() + " " + m.Currency
+}
+
+// MarshalJSON writes {"amount":"12.50","currency":"USD"} with the amount as a string
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Amount   string `json:"amount"`
+		Currency string `json:"currency"`
+	}{
+		Amount:   m.Decimal(),
+		Currency: m.Currency,
+	})
+}
+
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Amount   Amount `json:"amount"`
+		Currency string `json:"currency"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*m = New(raw.Amount, raw.Currency)
+	return nil
+}
+
+// minorStep is the size of one minor unit of the currency in Amount units, e.g. 100 for cents
+func minorStep(currency string) int64 {
+	step := int64(1)
+	for i := MinorDigits(currency); i < Scale; i++ {
+		step *= 10
+	}
+	return step
+}
+
+// roundHalfEven divides num by den and rounds ties to the even quotient
+func roundHalfEven(num *big.Int, den *big.Int) *big.Int {
+	quotient, remainder := new(big.Int).QuoRem(num, den, new(big.Int))
+
+	twiceRemainder := new(big.Int).Abs(remainder)
+	twiceRemainder.Lsh(twiceRemainder, 1)
+	switch twiceRemainder.Cmp(den) {
+	case 1:
+		quotient.Add(quotient, big.NewInt(int64(num.Sign())))
+	case 0:
+		if quotient.Bit(0) == 1 {
+			quotient.Add(quotient, big.NewInt(int64(num.Sign())))
+		}
+	}
+	return quotient
+}
@@ -0,0 +1,155 @@
+package money
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		expect    Amount
+		expectErr bool
+	}{
+		{name: "whole", input: "15000000", expect: 150000000000},
+		{name: "fraction", input: "12.5", expect: 125000},
+		{name: "negative", input: "-0.01", expect: -100},
+		{name: "leading dot", input: ".25", expect: 2500},
+		{name: "too precise", input: "1.00001", expectErr: true},
+		{name: "not a number", input: "12a", expectErr: true},
+		{name: "empty", input: "", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseAmount(tt.input)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expect, result)
+			}
+		})
+	}
+}
+
+func TestRoundHalfEven(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  Money
+		expect Money
+	}{
+		{name: "tie rounds down to even", input: MustParse("2.125", "USD"), expect: MustParse("2.12", "USD")},
+		{name: "tie rounds up to even", input: MustParse("2.135", "USD"), expect: MustParse("2.14", "USD")},
+		{name: "above tie", input: MustParse("2.1251", "USD"), expect: MustParse("2.13", "USD")},
+		{name: "negative tie", input: MustParse("-2.125", "USD"), expect: MustParse("-2.12", "USD")},
+		{name: "zero digit currency", input: MustParse("10.5", "JPY"), expect: MustParse("10", "JPY")},
+		{name: "three digit currency", input: MustParse("1.0005", "KWD"), expect: MustParse("1.000", "KWD")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rounded, err := tt.input.Round()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expect, rounded)
+		})
+	}
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	price := MustParse("0.10", "USD")
+
+	total, err := price.Add(MustParse("0.20", "USD"))
+	assert.NoError(t, err)
+	assert.Equal(t, MustParse("0.30", "USD"), total)
+
+	_, err = price.Add(MustParse("1", "EUR"))
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+
+	product, err := price.Mul(12)
+	assert.NoError(t, err)
+	assert.Equal(t, MustParse("1.20", "USD"), product)
+
+	tax, err := MustParse("10", "IDR").Percent(MustParse("11", "").Amount)
+	assert.NoError(t, err)
+	assert.Equal(t, MustParse("1.10", "IDR"), tax)
+	tax, err = MustParse("0.25", "USD").Percent(AmountFromInt(10))
+	assert.NoError(t, err)
+	assert.Equal(t, MustParse("0.02", "USD"), tax)
+	tax, err = MustParse("100", "USD").Percent(MustParse("7.125", "").Amount)
+	assert.NoError(t, err)
+	assert.Equal(t, MustParse("7.12", "USD"), tax)
+}
+
+func TestMoneyOverflow(t *testing.T) {
+	large := MustParse("900000000000000", "USD")
+
+	_, err := large.Mul(2)
+	assert.ErrorIs(t, err, ErrOverflow)
+	_, err = large.Mul(-2)
+	assert.ErrorIs(t, err, ErrOverflow)
+	_, err = large.Percent(AmountFromInt(150))
+	assert.ErrorIs(t, err, ErrOverflow)
+
+	product, err := large.Mul(1)
+	assert.NoError(t, err)
+	assert.Equal(t, large, product)
+}
+
+func TestMoneyOverflowAtInt64Edges(t *testing.T) {
+	largest := New(math.MaxInt64, "USD")
+	smallest := New(math.MinInt64, "USD")
+	unit := New(1, "USD")
+
+	_, err := largest.Add(unit)
+	assert.ErrorIs(t, err, ErrOverflow)
+	_, err = smallest.Add(New(-1, "USD"))
+	assert.ErrorIs(t, err, ErrOverflow)
+	_, err = smallest.Sub(unit)
+	assert.ErrorIs(t, err, ErrOverflow)
+	_, err = largest.Sub(New(-1, "USD"))
+	assert.ErrorIs(t, err, ErrOverflow)
+	_, err = unit.Sub(smallest)
+	assert.ErrorIs(t, err, ErrOverflow)
+	_, err = smallest.Neg()
+	assert.ErrorIs(t, err, ErrOverflow)
+	_, err = New(math.MaxInt64, "JPY").Round()
+	assert.ErrorIs(t, err, ErrOverflow)
+
+	sum, err := largest.Add(smallest)
+	assert.NoError(t, err)
+	assert.Equal(t, New(-1, "USD"), sum)
+	difference, err := smallest.Sub(smallest)
+	assert.NoError(t, err)
+	assert.Equal(t, New(0, "USD"), difference)
+	rounded, err := New(math.MaxInt64-math.MaxInt64%100, "USD").Round()
+	assert.NoError(t, err)
+	assert.Equal(t, New(math.MaxInt64-math.MaxInt64%100, "USD"), rounded)
+
+	_, err = NewAmount(math.MaxInt64/scaleFactor + 1)
+	assert.ErrorIs(t, err, ErrOverflow)
+	_, err = NewAmount(math.MinInt64/scaleFactor - 1)
+	assert.ErrorIs(t, err, ErrOverflow)
+	amount, err := NewAmount(math.MaxInt64 / scaleFactor)
+	assert.NoError(t, err)
+	assert.Equal(t, Amount(math.MaxInt64/scaleFactor*scaleFactor), amount)
+	assert.Panics(t, func() { AmountFromInt(math.MaxInt64) })
+
+	var scanned Amount
+	assert.ErrorIs(t, scanned.Scan(int64(math.MaxInt64)), ErrOverflow)
+}
+
+func TestMoneyJSON(t *testing.T) {
+	data, err := json.Marshal(MustParse("15000000", "IDR"))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"amount":"15000000.00","currency":"IDR"}`, string(data))
+
+	var fromString, fromNumber Money
+	assert.NoError(t, json.Unmarshal([]byte(`{"amount":"19.99","currency":"usd"}`), &fromString))
+	assert.NoError(t, json.Unmarshal([]byte(`{"amount":19.99,"currency":"USD"}`), &fromNumber))
+	assert.Equal(t, MustParse("19.99", "USD"), fromString)
+	assert.Equal(t, fromString, fromNumber)
+}
@@ -0,0 +1,24 @@
+package web
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type AuditRequest struct {
+	Entity  string  `json:"entity" validate:"omitempty,oneof=category customer employee product product_price product_variant"`
+	Id      *uint64 `json:"id" validate:"omitempty,excluded_without=Entity"`
+	ActorId *uint64 `json:"actor_id"`
+	Limit   int     `json:"limit" validate:"omitempty,min=1,max=1000"`
+}
+
+type AuditLogResponse struct {
+	Id        uint64          `json:"id"`
+	Entity    string          `json:"entity"`
+	EntityId  uint64          `json:"entity_id"`
+	Action    string          `json:"action"`
+	ActorId   *uint64         `json:"actor_id"`
+	RequestId string          `json:"request_id,omitempty"`
+	Changes   json.RawMessage `json:"changes"`
+	CreatedAt time.Time       `json:"created_at"`
+}
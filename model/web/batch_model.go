@@ -0,0 +1,32 @@
+package web
+
+import "encoding/json"
+
+type BatchRequest struct {
+	Atomic     bool             `json:"atomic"`
+	Operations []BatchOperation `json:"operations" validate:"required,min=1,max=1000,dive"`
+}
+
+type BatchOperation struct {
+	Op      string          `json:"op" validate:"required,oneof=create update delete"`
+	Id      uint64          `json:"id" validate:"required_unless=Op create"`
+	Version uint64          `json:"version"`
+	Data    json.RawMessage `json:"data"`
+}
+
+type BatchResponse struct {
+	Atomic    bool                  `json:"atomic"`
+	Succeeded int                   `json:"succeeded"`
+	Failed    int                   `json:"failed"`
+	Results   []BatchResultResponse `json:"results"`
+}
+
+type BatchResultResponse struct {
+	Index  int         `json:"index"`
+	Op     string      `json:"op"`
+	Id     uint64      `json:"id,omitempty"`
+	Code   int         `json:"code"`
+	Status string      `json:"status"`
+	Data   interface{} `json:"data,omitempty"`
+	Error  string      `json:"error,omitempty"`
+}
@@ -1,15 +1,31 @@
 package web
 
+import "time"
+
 type CategoryCreateRequest struct {
-	Name string `validate:"required,min=1,max=100" json:"name"`
+	Name     string  `validate:"required,min=1,max=100" json:"name"`
+	ParentId *uint64 `json:"parent_id"`
 }
 
 type CategoryUpdateRequest struct {
-	Id   uint64 `validate:"required"`
-	Name string `validate:"required,max=200,min=1" json:"name"`
+	Id      uint64 `validate:"required" json:"id"`
+	Name    string `validate:"required,max=200,min=1" json:"name"`
+	Version uint64 `json:"version"`
+}
+
+type CategoryMoveRequest struct {
+	Id       uint64  `validate:"required"`
+	ParentId *uint64 `json:"parent_id"` // nil moves the subtree to the root
+	Version  uint64  `json:"version"`
 }
 
 type CategoryResponse struct {
-	Id   uint64 `json:"id"`
-	Name string `json:"name"`
+	Id                  uint64             `json:"id"`
+	Name                string             `json:"name"`
+	ParentId            *uint64            `json:"parent_id"`
+	Children            []CategoryResponse `json:"children,omitempty"`
+	ProductCount        *int64             `json:"product_count,omitempty"`
+	SubtreeProductCount *int64             `json:"subtree_product_count,omitempty"`
+	Version             uint64             `json:"version"`
+	DeletedAt           *time.Time         `json:"deleted_at,omitempty"`
 }
@@ -1,27 +1,32 @@
 package web
 
+import "time"
+
 type CustomerCreateRequest struct {
 	Name       string `validate:"required,min=1,max=100" json:"name"`
-	Email      string `validate:"required" json:"column:email"`
-	Phone      string `validate:"required,min=1,max=100" json:"column:phone"`
-	Address    string `validate:"required,min=1,max=100" json:"column:address"`
-	LoyaltyPts int    `validate:"required,min=0" json:"column:loyalty_points"`
+	Email      string `validate:"required" json:"email"`
+	Phone      string `validate:"required,min=1,max=100" json:"phone"`
+	Address    string `validate:"required,min=1,max=100" json:"address"`
+	LoyaltyPts int    `validate:"required,min=0" json:"loyalty_points"`
 }
 
 type CustomerUpdateRequest struct {
-	Id         uint64 `validate:"required"`
+	Id         uint64 `validate:"required" json:"id"`
 	Name       string `validate:"required,min=1,max=100" json:"name"`
-	Email      string `validate:"required" json:"column:email"`
-	Phone      string `validate:"required,min=1,max=100" json:"column:phone"`
-	Address    string `validate:"required,min=1,max=100" json:"column:address"`
-	LoyaltyPts int    `validate:"required,min=0" json:"column:loyalty_points"`
+	Email      string `validate:"required" json:"email"`
+	Phone      string `validate:"required,min=1,max=100" json:"phone"`
+	Address    string `validate:"required,min=1,max=100" json:"address"`
+	LoyaltyPts int    `validate:"required,min=0" json:"loyalty_points"`
+	Version    uint64 `json:"version"`
 }
 
 type CustomerResponse struct {
-	Id         uint64 `json:"id"`
-	Name       string `json:"name"`
-	Email      string `json:"email"`
-	Phone      string `json:"phone"`
-	Address    string `json:"address"`
-	LoyaltyPts int    `json:"loyalty_points"`
+	Id         uint64     `json:"id"`
+	Name       string     `json:"name"`
+	Email      string     `json:"email"`
+	Phone      string     `json:"phone"`
+	Address    string     `json:"address"`
+	LoyaltyPts int        `json:"loyalty_points"`
+	Version    uint64     `json:"version"`
+	DeletedAt  *time.Time `json:"deleted_at,omitempty"`
 }
@@ -1,24 +1,29 @@
 package web
 
+import "time"
+
 type EmployeeCreateRequest struct {
 	Name      string `validate:"required,min=1,max=100" json:"name"`
 	Email     string `validate:"required,min=1,max=100" json:"email"`
 	Phone     string `validate:"required,min=1,max=100" json:"phone"`
-	DateHired string `validate:"required,min=0" json:"column:date_hired"`
+	DateHired string `validate:"required,min=0" json:"date_hired"`
 }
 
 type EmployeeUpdateRequest struct {
-	Id        uint64 `validate:"required"`
+	Id        uint64 `validate:"required" json:"id"`
 	Name      string `validate:"required,max=200,min=1" json:"name"`
 	Email     string `validate:"required,min=1,max=100" json:"email"`
 	Phone     string `validate:"required,min=1,max=100" json:"phone"`
-	DateHired string `validate:"required,min=0" json:"column:date_hired"`
+	DateHired string `validate:"required,min=0" json:"date_hired"`
+	Version   uint64 `json:"version"`
 }
 
 type EmployeeResponse struct {
-	Id        uint64 `json:"id"`
-	Name      string `json:"name"`
-	Email     string `json:"email"`
-	Phone     string `json:"phone"`
-	DateHired string `json:"date_hired"`
+	Id        uint64     `json:"id"`
+	Name      string     `json:"name"`
+	Email     string     `json:"email"`
+	Phone     string     `json:"phone"`
+	DateHired string     `json:"date_hired"`
+	Version   uint64     `json:"version"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
@@ -0,0 +1,23 @@
+package web
+
+import "time"
+
+// ChangeEvent tells stream subscribers that a row of one of their topics changed
+type ChangeEvent struct {
+	Id         uint64    `json:"id"`
+	Topic      string    `json:"topic"`
+	Action     string    `json:"action"`
+	EntityId   uint64    `json:"entity_id,omitempty"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// EventSubscription delivers the changes of the subscribed topics until it is closed
+type EventSubscription struct {
+	// Missed holds the buffered events after the Last-Event-ID the subscriber resumed from
+	Missed []ChangeEvent
+	// Reset is set when Last-Event-ID is older than the buffer, so the subscriber must reload its data
+	Reset bool
+	// Events is closed when the subscriber falls too far behind; it can reconnect with its Last-Event-ID
+	Events <-chan ChangeEvent
+	Close  func()
+}
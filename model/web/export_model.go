@@ -0,0 +1,13 @@
+package web
+
+type ExportRequest struct {
+	Resource   string   `json:"resource" validate:"required,oneof=products customers employees"`
+	Format     string   `json:"format" validate:"required,oneof=csv jsonl xlsx"`
+	Columns    []string `json:"columns"`
+	CategoryId *uint64  `json:"category_id"`
+}
+
+type ExportFileResponse struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+}
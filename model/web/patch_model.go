@@ -0,0 +1,11 @@
+package web
+
+import "encoding/json"
+
+// PatchRequest carries a merge patch or a JSON patch document, told apart by ContentType
+type PatchRequest struct {
+	Id          uint64
+	ContentType string
+	Patch       json.RawMessage
+	Version     uint64
+}
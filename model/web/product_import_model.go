@@ -0,0 +1,17 @@
+package web
+
+type ProductImportResponse struct {
+	DryRun  bool                         `json:"dry_run"`
+	Total   int                          `json:"total"`
+	Created int                          `json:"created"`
+	Updated int                          `json:"updated"`
+	Failed  int                          `json:"failed"`
+	Errors  []ProductImportErrorResponse `json:"errors"`
+}
+
+type ProductImportErrorResponse struct {
+	Line  int    `json:"line"`
+	SKU   string `json:"sku"`
+	Name  string `json:"name"`
+	Error string `json:"error"`
+}
@@ -1,33 +1,62 @@
 package web
 
+import (
+	"github.com/aronipurwanto/go-restful-api/model/money"
+	"time"
+)
+
 type ProductCreateRequest struct {
-	Name        string  `json:"name" validate:"required,max=32,min=1"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price" validate:"required,gte=0"`
-	StockQty    int     `json:"stock_qty" validate:"required,gte=0"`
-	CategoryID  int     `json:"category" validate:"required"`
-	SKU         string  `json:"sku" validate:"required"`
-	TaxRate     float64 `json:"tax_rate" validate:"required,gte=0"`
+	Name        string                  `json:"name" validate:"required,max=32,min=1"`
+	Description string                  `json:"description"`
+	Price       money.Money             `json:"price" validate:"required"`
+	StockQty    int                     `json:"stock_qty" validate:"required,gte=0"`
+	CategoryID  int                     `json:"category" validate:"required"`
+	SKU         string                  `json:"sku" validate:"required"`
+	TaxRate     money.Amount            `json:"tax_rate" validate:"required,gte=0"`
+	Barcodes    []ProductBarcodeRequest `json:"barcodes" validate:"dive"`
 }
 
 type ProductUpdateRequest struct {
-	Id          uint64  `json:"id" validate:"required,gte=0"`
-	Name        string  `json:"name" validate:"required,max=32,min=1"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price" validate:"required,gte=0"`
-	StockQty    int     `json:"stock_qty" validate:"required,gte=0"`
-	CategoryID  int     `json:"category_id" validate:"required"`
-	SKU         string  `json:"sku" validate:"required"`
-	TaxRate     float64 `json:"tax_rate" validate:"required,gte=0"`
+	Id          uint64                  `json:"id" validate:"required,gte=0"`
+	Name        string                  `json:"name" validate:"required,max=32,min=1"`
+	Description string                  `json:"description"`
+	Price       money.Money             `json:"price" validate:"required"`
+	StockQty    int                     `json:"stock_qty" validate:"required,gte=0"`
+	CategoryID  int                     `json:"category_id" validate:"required"`
+	SKU         string                  `json:"sku" validate:"required"`
+	TaxRate     money.Amount            `json:"tax_rate" validate:"required,gte=0"`
+	Barcodes    []ProductBarcodeRequest `json:"barcodes" validate:"dive"`
+	Version     uint64                  `json:"version"`
+}
+
+type ProductBarcodeRequest struct {
+	Code    string `json:"code" validate:"required,numeric,min=8,max=13"`
+	PackQty int    `json:"pack_qty" validate:"required,gte=1"`
 }
 
 type ProductResponse struct {
-	Id          uint64  `json:"id"`
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price"`
-	StockQty    int     `json:"stock_qty"`
-	CategoryID  uint64  `json:"category_id"`
-	SKU         string  `json:"sku"`
-	TaxRate     float64 `json:"tax_rate"`
+	Id          uint64                   `json:"id"`
+	Name        string                   `json:"name"`
+	Description string                   `json:"description"`
+	Price       money.Money              `json:"price"`
+	StockQty    int                      `json:"stock_qty"`
+	CategoryID  uint64                   `json:"category_id"`
+	SKU         string                   `json:"sku"`
+	TaxRate     money.Amount             `json:"tax_rate"`
+	Barcodes    []ProductBarcodeResponse `json:"barcodes,omitempty"`
+	Options     []ProductOptionResponse  `json:"options,omitempty"`
+	Variants    []ProductVariantResponse `json:"variants,omitempty"`
+	Version     uint64                   `json:"version"`
+	DeletedAt   *time.Time               `json:"deleted_at,omitempty"`
+}
+
+type ProductBarcodeResponse struct {
+	Code    string `json:"code"`
+	PackQty int    `json:"pack_qty"`
+}
+
+type ProductLookupResponse struct {
+	Code    string          `json:"code"`
+	PackQty int             `json:"pack_qty"`
+	Product ProductResponse `json:"product"`
 }
@@ -0,0 +1,27 @@
+package web
+
+import (
+	"github.com/aronipurwanto/go-restful-api/model/money"
+	"time"
+)
+
+type ProductPriceScheduleRequest struct {
+	ProductId     uint64      `validate:"required"`
+	Price         money.Money `json:"price" validate:"required"`
+	EffectiveFrom time.Time   `json:"effective_from" validate:"required"`
+}
+
+type ProductPriceResponse struct {
+	Id            uint64       `json:"id"`
+	ProductId     uint64       `json:"product_id"`
+	Price         money.Money  `json:"price"`
+	PreviousPrice *money.Money `json:"previous_price"`
+	EffectiveFrom time.Time    `json:"effective_from"`
+	AppliedAt     *time.Time   `json:"applied_at"`
+	EmployeeId    *uint64      `json:"employee_id"`
+}
+
+type ProductPriceHistoryResponse struct {
+	History []ProductPriceResponse `json:"history"`
+	Pending []ProductPriceResponse `json:"pending"`
+}
@@ -0,0 +1,44 @@
+package web
+
+import "github.com/aronipurwanto/go-restful-api/model/money"
+
+type ProductOptionRequest struct {
+	Name   string   `json:"name" validate:"required,min=1,max=50"`
+	Values []string `json:"values" validate:"required,min=1,unique,dive,required,max=50"`
+}
+
+type ProductVariantGenerateRequest struct {
+	ProductId uint64                 `validate:"required"`
+	Options   []ProductOptionRequest `json:"options" validate:"required,min=1,max=3,dive"`
+}
+
+type ProductVariantCreateRequest struct {
+	ProductId     uint64            `validate:"required"`
+	Options       map[string]string `json:"options" validate:"required,min=1"`
+	SKU           string            `json:"sku" validate:"required,max=100"`
+	PriceOverride *money.Money      `json:"price_override" validate:"omitempty"`
+	StockQty      int               `json:"stock_qty" validate:"gte=0"`
+}
+
+type ProductVariantUpdateRequest struct {
+	Id            uint64       `validate:"required"`
+	ProductId     uint64       `validate:"required"`
+	SKU           string       `json:"sku" validate:"required,max=100"`
+	PriceOverride *money.Money `json:"price_override" validate:"omitempty"`
+	StockQty      int          `json:"stock_qty" validate:"gte=0"`
+}
+
+type ProductOptionResponse struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
+type ProductVariantResponse struct {
+	Id            uint64            `json:"id"`
+	ProductId     uint64            `json:"product_id"`
+	SKU           string            `json:"sku"`
+	Options       map[string]string `json:"options"`
+	Price         money.Money       `json:"price"`
+	PriceOverride *money.Money      `json:"price_override"`
+	StockQty      int               `json:"stock_qty"`
+}
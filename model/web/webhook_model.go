@@ -0,0 +1,71 @@
+package web
+
+import (
+	"encoding/json"
+	"github.com/aronipurwanto/go-restful-api/model/money"
+	"time"
+)
+
+type WebhookCreateRequest struct {
+	Url    string   `json:"url" validate:"required,url,max=2048"`
+	Secret string   `json:"secret" validate:"omitempty,min=16,max=128"` // generated when empty
+	Events []string `json:"events" validate:"dive,oneof=ProductCreated PriceChanged StockChanged CustomerUpdated"`
+	Active *bool    `json:"active"`
+}
+
+type WebhookUpdateRequest struct {
+	Id     uint64   `json:"id" validate:"required"`
+	Url    string   `json:"url" validate:"required,url,max=2048"`
+	Secret string   `json:"secret" validate:"omitempty,min=16,max=128"` // the current secret is kept when empty
+	Events []string `json:"events" validate:"dive,oneof=ProductCreated PriceChanged StockChanged CustomerUpdated"`
+	Active *bool    `json:"active"`
+}
+
+type WebhookResponse struct {
+	Id        uint64    `json:"id"`
+	Url       string    `json:"url"`
+	Secret    string    `json:"secret,omitempty"` // only returned when the webhook is created
+	Events    []string  `json:"events"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type WebhookDeliveryResponse struct {
+	Id             uint64     `json:"id"`
+	WebhookId      uint64     `json:"webhook_id"`
+	EventId        uint64     `json:"event_id"`
+	EventType      string     `json:"event_type"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at"`
+	LastStatusCode int        `json:"last_status_code,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// WebhookEvent is the body POSTed to webhook endpoints
+type WebhookEvent struct {
+	Id            uint64          `json:"id"`
+	Type          string          `json:"type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateId   uint64          `json:"aggregate_id"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	Data          json.RawMessage `json:"data"`
+}
+
+// PriceChangedEvent is the data of a PriceChanged event
+type PriceChangedEvent struct {
+	ProductId     uint64      `json:"product_id"`
+	PreviousPrice money.Money `json:"previous_price"`
+	Price         money.Money `json:"price"`
+}
+
+// StockChangedEvent is the data of a StockChanged event
+type StockChangedEvent struct {
+	ProductId        uint64  `json:"product_id"`
+	VariantId        *uint64 `json:"variant_id,omitempty"` // set when the stock of a variant changed
+	PreviousStockQty int     `json:"previous_stock_qty"`
+	StockQty         int     `json:"stock_qty"`
+}
@@ -0,0 +1,279 @@
+package openapi
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Schemas turns Go types into schemas the way encoding/json reads and writes them. Named structs
+// become components referenced with $ref, and validate tags become constraints. Fields without a
+// json tag are left out: handlers fill them from the path, never from the body.
+type Schemas struct {
+	components map[string]*Schema
+	overrides  map[reflect.Type]*Schema
+}
+
+func NewSchemas() *Schemas {
+	return &Schemas{
+		components: make(map[string]*Schema),
+		overrides:  make(map[reflect.Type]*Schema),
+	}
+}
+
+// Define sets the schema of a type whose JSON form reflection cannot see, e.g. one with a MarshalJSON method
+func (schemas *Schemas) Define(value interface{}, schema *Schema) {
+	schemas.overrides[reflect.TypeOf(value)] = schema
+}
+
+// Of returns the schema of the type of value
+func (schemas *Schemas) Of(value interface{}) *Schema {
+	return schemas.of(reflect.TypeOf(value))
+}
+
+// Request returns the schema of the type of value as a request body: its objects are closed, so a
+// misspelled property is rejected instead of silently ignored by encoding/json
+func (schemas *Schemas) Request(value interface{}) *Schema {
+	schema := schemas.Of(value)
+	schemas.close(schema, make(map[*Schema]bool))
+	return schema
+}
+
+// Components are the schemas of the named structs seen so far, keyed by type name
+func (schemas *Schemas) Components() map[string]*Schema {
+	return schemas.components
+}
+
+func (schemas *Schemas) of(t reflect.Type) *Schema {
+	if t == nil {
+		return &Schema{}
+	}
+	if override, ok := schemas.overrides[t]; ok {
+		copied := *override
+		return &copied
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return nullable(schemas.of(t.Elem()))
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32", Minimum: float(0)}
+	case reflect.Uint, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64", Minimum: float(0)}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		// encoding/json writes nil slices and maps as null
+		nilable := t.Kind() == reflect.Slice
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte", Nullable: nilable}
+		}
+		return &Schema{Type: "array", Items: schemas.of(t.Elem()), Nullable: nilable}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: schemas.of(t.Elem()), Nullable: true}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return schemas.object(t)
+		}
+		if _, ok := schemas.components[t.Name()]; !ok {
+			// registered before its fields, so recursive types end in a $ref
+			schemas.components[t.Name()] = &Schema{}
+			*schemas.components[t.Name()] = *schemas.object(t)
+		}
+		return &Schema{Ref: Ref("schemas", t.Name())}
+	default:
+		// interfaces hold any JSON value
+		return &Schema{}
+	}
+}
+
+// close closes the struct objects of schema, following $refs into the components
+func (schemas *Schemas) close(schema *Schema, seen map[*Schema]bool) {
+	if schema == nil || seen[schema] {
+		return
+	}
+	seen[schema] = true
+
+	if name, ok := strings.CutPrefix(schema.Ref, Ref("schemas", "")); ok {
+		schemas.close(schemas.components[name], seen)
+	}
+	if schema.Properties != nil && schema.AdditionalProperties == nil {
+		schema.Closed = true
+	}
+	for _, property := range schema.Properties {
+		schemas.close(property, seen)
+	}
+	schemas.close(schema.Items, seen)
+	schemas.close(schema.AdditionalProperties, seen)
+	for _, member := range append(schema.AllOf, schema.OneOf...) {
+		schemas.close(member, seen)
+	}
+}
+
+// object lists the JSON properties of a struct
+func (schemas *Schemas) object(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		property := schemas.of(field.Type)
+		if constrain(property, field.Type, field.Tag.Get("validate")) {
+			schema.Required = append(schema.Required, name)
+		}
+		schema.Properties[name] = property
+	}
+	return schema
+}
+
+// constrain applies the rules of a validate tag to schema and reports whether they make the field
+// required. Rules after dive apply to the items of a slice or the values of a map.
+func constrain(schema *Schema, t reflect.Type, tag string) bool {
+	required := false
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if schema.Ref != "" || len(schema.AllOf) > 0 {
+		// a $ref takes no sibling keywords; nested structs carry their own rules
+		for _, rule := range strings.Split(tag, ",") {
+			if rule == "dive" {
+				break
+			}
+			required = required || rule == "required"
+		}
+		return required
+	}
+
+	rules := strings.Split(tag, ",")
+	omitEmpty := false
+	for i, rule := range rules {
+		name, param, _ := strings.Cut(rule, "=")
+		if omitEmpty && name != "dive" {
+			// the zero value skips these rules, which a single schema cannot say
+			continue
+		}
+		switch name {
+		case "omitempty":
+			omitEmpty = true
+		case "required":
+			required = true
+		case "dive":
+			rest := strings.Join(rules[i+1:], ",")
+			if schema.Items != nil {
+				constrain(schema.Items, t.Elem(), rest)
+			} else if schema.AdditionalProperties != nil {
+				constrain(schema.AdditionalProperties, t.Elem(), rest)
+			}
+			return required
+		case "min", "gte":
+			setBound(schema, t, param, true, false)
+		case "max", "lte":
+			setBound(schema, t, param, false, false)
+		case "gt":
+			setBound(schema, t, param, true, true)
+		case "lt":
+			setBound(schema, t, param, false, true)
+		case "len":
+			setBound(schema, t, param, true, false)
+			setBound(schema, t, param, false, false)
+		case "oneof":
+			for _, value := range strings.Fields(param) {
+				schema.Enum = append(schema.Enum, enumValue(t, value))
+			}
+		case "email":
+			schema.Format = "email"
+		case "url":
+			schema.Format = "uri"
+		case "uuid", "uuid4":
+			schema.Format = "uuid"
+		case "numeric":
+			schema.Pattern = `^[-+]?[0-9]+(\.[0-9]+)?$`
+		case "iso4217":
+			schema.Pattern = "^[A-Z]{3}$"
+		case "unique":
+			schema.UniqueItems = true
+		}
+	}
+	return required
+}
+
+// setBound sets a lower or upper bound: a length for strings, a count for slices and maps, a value for numbers
+func setBound(schema *Schema, t reflect.Type, param string, lower bool, exclusive bool) {
+	value, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return
+	}
+
+	var count **int
+	switch t.Kind() {
+	case reflect.String:
+		count = pick(lower, &schema.MinLength, &schema.MaxLength)
+	case reflect.Slice, reflect.Array:
+		count = pick(lower, &schema.MinItems, &schema.MaxItems)
+	case reflect.Map:
+		count = pick(lower, &schema.MinProperties, &schema.MaxProperties)
+	default:
+		if lower {
+			schema.Minimum, schema.ExclusiveMinimum = float(value), exclusive
+		} else {
+			schema.Maximum, schema.ExclusiveMaximum = float(value), exclusive
+		}
+		return
+	}
+
+	n := int(value)
+	if exclusive && lower {
+		n++
+	} else if exclusive {
+		n--
+	}
+	*count = &n
+}
+
+func pick(lower bool, min **int, max **int) **int {
+	if lower {
+		return min
+	}
+	return max
+}
+
+// enumValue reads a oneof value as the type of the field
+func enumValue(t reflect.Type, value string) interface{} {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return n
+		}
+	}
+	return strings.Trim(value, "'")
+}
+
+// nullable allows null besides schema; a $ref cannot be marked itself, so it is wrapped
+func nullable(schema *Schema) *Schema {
+	if schema.Ref != "" {
+		return &Schema{AllOf: []*Schema{schema}, Nullable: true}
+	}
+	schema.Nullable = true
+	return schema
+}
+
+func float(value float64) *float64 {
+	return &value
+}
@@ -0,0 +1,79 @@
+package openapi
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type testNode struct {
+	Name     string            `json:"name" validate:"required,min=1,max=32"`
+	Kind     string            `json:"kind" validate:"oneof=leaf branch"`
+	Weight   float64           `json:"weight" validate:"gt=0"`
+	Tags     []string          `json:"tags" validate:"max=5,dive,email"`
+	Labels   map[string]int    `json:"labels" validate:"dive,lte=9"`
+	Parent   *testNode         `json:"parent"`
+	Children []testNode        `json:"children" validate:"required"`
+	Secret   string            `json:"-"`
+	Internal string            `validate:"required"`
+	Extra    map[string]string `json:"extra,omitempty"`
+	Token    string            `json:"token" validate:"omitempty,min=16"`
+}
+
+func TestSchemasOf(t *testing.T) {
+	schemas := NewSchemas()
+
+	assert.Equal(t, &Schema{Ref: "#/components/schemas/testNode"}, schemas.Of(testNode{}))
+	assert.Equal(t, &Schema{Type: "array", Items: &Schema{Ref: "#/components/schemas/testNode"}, Nullable: true}, schemas.Of([]testNode{}))
+
+	node := schemas.Components()["testNode"]
+	assert.Equal(t, []string{"name", "children"}, node.Required)
+	assert.Equal(t, &Schema{Type: "string", MinLength: intOf(1), MaxLength: intOf(32)}, node.Properties["name"])
+	assert.Equal(t, []interface{}{"leaf", "branch"}, node.Properties["kind"].Enum)
+	assert.Equal(t, &Schema{Type: "number", Format: "double", Minimum: float(0), ExclusiveMinimum: true}, node.Properties["weight"])
+	assert.Equal(t, &Schema{Type: "array", MaxItems: intOf(5), Items: &Schema{Type: "string", Format: "email"}, Nullable: true}, node.Properties["tags"])
+	assert.Equal(t, &Schema{Type: "integer", Format: "int64", Maximum: float(9)}, node.Properties["labels"].AdditionalProperties)
+	assert.Equal(t, &Schema{AllOf: []*Schema{{Ref: "#/components/schemas/testNode"}}, Nullable: true}, node.Properties["parent"])
+	assert.Contains(t, node.Properties, "extra")
+	assert.Equal(t, &Schema{Type: "string"}, node.Properties["token"])
+	assert.NotContains(t, node.Properties, "Secret")
+	assert.NotContains(t, node.Properties, "Internal")
+}
+
+func TestSchemasRequest(t *testing.T) {
+	schemas := NewSchemas()
+	schemas.Request(testNode{})
+
+	node := schemas.Components()["testNode"]
+	assert.True(t, node.Closed)
+	// maps take any key, so they stay open
+	assert.False(t, node.Properties["labels"].Closed)
+
+	data, err := json.Marshal(node)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"additionalProperties":false`)
+	data, err = json.Marshal(node.Properties["labels"])
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"additionalProperties":{"type":"integer"`)
+}
+
+func TestSchemasDefine(t *testing.T) {
+	type amount int64
+	schemas := NewSchemas()
+	schemas.Define(amount(0), &Schema{Type: "string", Pattern: "^[0-9]+$"})
+
+	assert.Equal(t, &Schema{Type: "string", Pattern: "^[0-9]+$"}, schemas.Of(amount(0)))
+	assert.Equal(t, &Schema{Type: "string", Pattern: "^[0-9]+$", Nullable: true}, schemas.Of(new(amount)))
+	// the defined schema is copied, so marking a use nullable leaves it alone
+	assert.False(t, schemas.Of(amount(0)).Nullable)
+}
+
+func TestPath(t *testing.T) {
+	assert.Equal(t, "/api/products", Path("/api/products/"))
+	assert.Equal(t, "/api/products/{productId}/variants/{variantId}", Path("/api/products/:productId/variants/:variantId"))
+	assert.Equal(t, "/", Path("/"))
+}
+
+func intOf(value int) *int {
+	return &value
+}
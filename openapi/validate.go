@@ -0,0 +1,387 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// ValidationError is a value that does not match its schema, at a path like body.barcodes[0].code
+type ValidationError struct {
+	Path    string
+	Message string
+}
+
+func (err ValidationError) Error() string {
+	return err.Path + ": " + err.Message
+}
+
+// patterns caches the compiled pattern keywords of the documents validated so far
+var patterns sync.Map
+
+// Find returns the operation serving a request and the values of its path parameters. Literal
+// segments win over parameters, so /api/products/lookup is not read as /api/products/{productId}.
+func (document *Document) Find(method string, path string) (*Operation, map[string]string) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	var found *Operation
+	var foundParams map[string]string
+	best := -1
+	for template, item := range document.Paths {
+		operation := item.Operation(method)
+		if operation == nil {
+			continue
+		}
+		templateSegments := strings.Split(strings.Trim(template, "/"), "/")
+		if len(templateSegments) != len(segments) {
+			continue
+		}
+
+		literals := 0
+		params := make(map[string]string)
+		for i, segment := range templateSegments {
+			if name, ok := strings.CutPrefix(segment, "{"); ok {
+				params[strings.TrimSuffix(name, "}")] = segments[i]
+			} else if segment == segments[i] {
+				literals++
+			} else {
+				literals = -1
+				break
+			}
+		}
+		if literals > best {
+			found, foundParams, best = operation, params, literals
+		}
+	}
+	return found, foundParams
+}
+
+// Parameters returns the parameters of an operation with their $refs resolved
+func (document *Document) Parameters(operation *Operation) []*Parameter {
+	parameters := make([]*Parameter, 0, len(operation.Parameters))
+	for _, parameter := range operation.Parameters {
+		if name, ok := strings.CutPrefix(parameter.Ref, Ref("parameters", "")); ok {
+			parameter = document.Components.Parameters[name]
+		}
+		if parameter != nil {
+			parameters = append(parameters, parameter)
+		}
+	}
+	return parameters
+}
+
+// RequestSchema returns the schema of a request body of the given media type
+func (document *Document) RequestSchema(operation *Operation, mediaType string) (*Schema, bool) {
+	if operation.RequestBody == nil {
+		return nil, false
+	}
+	content, ok := operation.RequestBody.Content[mediaType]
+	if !ok {
+		return nil, false
+	}
+	return content.Schema, true
+}
+
+// Response returns the documented response of a status, with its $ref resolved
+func (document *Document) Response(operation *Operation, status int) (*Response, bool) {
+	response, ok := operation.Responses[strconv.Itoa(status)]
+	if !ok {
+		response, ok = operation.Responses["default"]
+	}
+	if !ok {
+		return nil, false
+	}
+	if name, ok := strings.CutPrefix(response.Ref, Ref("responses", "")); ok {
+		response, ok = document.Components.Responses[name]
+		return response, ok
+	}
+	return response, true
+}
+
+// ValidateParameter checks the raw value of a path, query or header parameter; present tells an
+// absent parameter from an empty one
+func (document *Document) ValidateParameter(parameter *Parameter, raw string, present bool) []ValidationError {
+	path := parameter.In + "." + parameter.Name
+	if !present {
+		if parameter.Required {
+			return []ValidationError{{Path: path, Message: "is required"}}
+		}
+		return nil
+	}
+	if parameter.Schema == nil {
+		return nil
+	}
+
+	schema := document.resolve(parameter.Schema)
+	var value interface{} = raw
+	switch schema.Type {
+	case "integer", "number":
+		if _, err := strconv.ParseFloat(raw, 64); err != nil {
+			return []ValidationError{{Path: path, Message: "must be " + article(schema.Type)}}
+		}
+		value = json.Number(raw)
+	case "boolean":
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return []ValidationError{{Path: path, Message: "must be a boolean"}}
+		}
+		value = parsed
+	}
+	return document.Validate(path, schema, value)
+}
+
+// ValidateJSON decodes a JSON document and checks it against schema
+func (document *Document) ValidateJSON(path string, schema *Schema, data []byte) []ValidationError {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return []ValidationError{{Path: path, Message: "is not valid JSON: " + err.Error()}}
+	}
+	return document.Validate(path, schema, value)
+}
+
+// Validate checks a value decoded from JSON with UseNumber against schema
+func (document *Document) Validate(path string, schema *Schema, value interface{}) []ValidationError {
+	if schema == nil {
+		return nil
+	}
+	schema = document.resolve(schema)
+
+	var errs []ValidationError
+	fail := func(format string, args ...interface{}) []ValidationError {
+		return append(errs, ValidationError{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if value == nil {
+		if schema.Nullable || schema.Type == "" && len(schema.AllOf) == 0 && len(schema.OneOf) == 0 {
+			return nil
+		}
+		if schema.Type != "" {
+			return fail("must not be null")
+		}
+	}
+
+	for _, part := range schema.AllOf {
+		errs = append(errs, document.Validate(path, part, value)...)
+	}
+	if len(schema.OneOf) > 0 {
+		matches := 0
+		for _, part := range schema.OneOf {
+			if len(document.Validate(path, part, value)) == 0 {
+				matches++
+			}
+		}
+		if matches != 1 {
+			errs = fail("must match exactly one schema of oneOf, matches %d", matches)
+		}
+	}
+	if value == nil {
+		return errs
+	}
+
+	if len(schema.Enum) > 0 && !inEnum(schema.Enum, value) {
+		errs = fail("must be one of %v", schema.Enum)
+	}
+
+	switch schema.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return fail("must be an object")
+		}
+		return append(errs, document.validateObject(path, schema, object)...)
+	case "array":
+		array, ok := value.([]interface{})
+		if !ok {
+			return fail("must be an array")
+		}
+		return append(errs, document.validateArray(path, schema, array)...)
+	case "string":
+		text, ok := value.(string)
+		if !ok {
+			return fail("must be a string")
+		}
+		return append(errs, validateString(path, schema, text)...)
+	case "integer", "number":
+		number, ok := value.(json.Number)
+		if !ok {
+			return fail("must be %s", article(schema.Type))
+		}
+		return append(errs, validateNumber(path, schema, number)...)
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fail("must be a boolean")
+		}
+	}
+	return errs
+}
+
+func (document *Document) validateObject(path string, schema *Schema, object map[string]interface{}) []ValidationError {
+	var errs []ValidationError
+	for _, name := range schema.Required {
+		if _, ok := object[name]; !ok {
+			errs = append(errs, ValidationError{Path: path + "." + name, Message: "is required"})
+		}
+	}
+	if schema.MinProperties != nil && len(object) < *schema.MinProperties {
+		errs = append(errs, ValidationError{Path: path, Message: fmt.Sprintf("must have at least %d properties", *schema.MinProperties)})
+	}
+	if schema.MaxProperties != nil && len(object) > *schema.MaxProperties {
+		errs = append(errs, ValidationError{Path: path, Message: fmt.Sprintf("must have at most %d properties", *schema.MaxProperties)})
+	}
+
+	// sorted, so the same body always reports its errors in the same order
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if property, ok := schema.Properties[name]; ok {
+			errs = append(errs, document.Validate(path+"."+name, property, object[name])...)
+		} else if schema.AdditionalProperties != nil {
+			errs = append(errs, document.Validate(path+"."+name, schema.AdditionalProperties, object[name])...)
+		} else if schema.Closed {
+			errs = append(errs, ValidationError{Path: path + "." + name, Message: "is not a known property"})
+		}
+	}
+	return errs
+}
+
+func (document *Document) validateArray(path string, schema *Schema, array []interface{}) []ValidationError {
+	var errs []ValidationError
+	if schema.MinItems != nil && len(array) < *schema.MinItems {
+		errs = append(errs, ValidationError{Path: path, Message: fmt.Sprintf("must have at least %d items", *schema.MinItems)})
+	}
+	if schema.MaxItems != nil && len(array) > *schema.MaxItems {
+		errs = append(errs, ValidationError{Path: path, Message: fmt.Sprintf("must have at most %d items", *schema.MaxItems)})
+	}
+	if schema.UniqueItems {
+		for i := range array {
+			for j := 0; j < i; j++ {
+				if reflect.DeepEqual(array[i], array[j]) {
+					errs = append(errs, ValidationError{Path: fmt.Sprintf("%s[%d]", path, i), Message: fmt.Sprintf("duplicates item %d", j)})
+				}
+			}
+		}
+	}
+	for i, item := range array {
+		errs = append(errs, document.Validate(fmt.Sprintf("%s[%d]", path, i), schema.Items, item)...)
+	}
+	return errs
+}
+
+func validateString(path string, schema *Schema, text string) []ValidationError {
+	var errs []ValidationError
+	length := utf8.RuneCountInString(text)
+	if schema.MinLength != nil && length < *schema.MinLength {
+		errs = append(errs, ValidationError{Path: path, Message: fmt.Sprintf("must be at least %d characters", *schema.MinLength)})
+	}
+	if schema.MaxLength != nil && length > *schema.MaxLength {
+		errs = append(errs, ValidationError{Path: path, Message: fmt.Sprintf("must be at most %d characters", *schema.MaxLength)})
+	}
+	if schema.Pattern != "" && !compile(schema.Pattern).MatchString(text) {
+		errs = append(errs, ValidationError{Path: path, Message: "must match " + schema.Pattern})
+	}
+
+	valid := true
+	switch schema.Format {
+	case "email":
+		_, err := mail.ParseAddress(text)
+		valid = err == nil
+	case "uri":
+		parsed, err := url.Parse(text)
+		valid = err == nil && parsed.Scheme != "" && parsed.Host != ""
+	case "uuid":
+		valid = compile("^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$").MatchString(text)
+	case "date-time":
+		_, err := time.Parse(time.RFC3339Nano, text)
+		valid = err == nil
+	}
+	if !valid {
+		errs = append(errs, ValidationError{Path: path, Message: "must be a valid " + schema.Format})
+	}
+	return errs
+}
+
+func validateNumber(path string, schema *Schema, number json.Number) []ValidationError {
+	if schema.Type == "integer" {
+		if _, err := strconv.ParseInt(number.String(), 10, 64); err != nil {
+			if _, err := strconv.ParseUint(number.String(), 10, 64); err != nil || schema.Format == "int32" {
+				return []ValidationError{{Path: path, Message: "must be an integer"}}
+			}
+		}
+	}
+	value, err := number.Float64()
+	if err != nil {
+		return []ValidationError{{Path: path, Message: "must be a number"}}
+	}
+
+	var errs []ValidationError
+	if schema.Format == "int32" && (value < -1<<31 || value > 1<<31-1) {
+		errs = append(errs, ValidationError{Path: path, Message: "must fit in 32 bits"})
+	}
+	if schema.Minimum != nil && (value < *schema.Minimum || schema.ExclusiveMinimum && value == *schema.Minimum) {
+		errs = append(errs, ValidationError{Path: path, Message: bound("greater", *schema.Minimum, schema.ExclusiveMinimum)})
+	}
+	if schema.Maximum != nil && (value > *schema.Maximum || schema.ExclusiveMaximum && value == *schema.Maximum) {
+		errs = append(errs, ValidationError{Path: path, Message: bound("less", *schema.Maximum, schema.ExclusiveMaximum)})
+	}
+	return errs
+}
+
+// article prefixes a JSON type with a or an, e.g. an integer
+func article(jsonType string) string {
+	if jsonType == "integer" || jsonType == "object" || jsonType == "array" {
+		return "an " + jsonType
+	}
+	return "a " + jsonType
+}
+
+func bound(comparison string, limit float64, exclusive bool) string {
+	if exclusive {
+		return fmt.Sprintf("must be %s than %v", comparison, limit)
+	}
+	return fmt.Sprintf("must be %s than or equal to %v", comparison, limit)
+}
+
+// inEnum compares JSON values, numbers by value since the schema holds them as Go numbers
+func inEnum(enum []interface{}, value interface{}) bool {
+	for _, allowed := range enum {
+		if fmt.Sprint(allowed) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolve follows a $ref to its component; an unknown one validates nothing
+func (document *Document) resolve(schema *Schema) *Schema {
+	for schema.Ref != "" {
+		component, ok := document.Components.Schemas[strings.TrimPrefix(schema.Ref, Ref("schemas", ""))]
+		if !ok {
+			return &Schema{}
+		}
+		schema = component
+	}
+	return schema
+}
+
+func compile(pattern string) *regexp.Regexp {
+	if compiled, ok := patterns.Load(pattern); ok {
+		return compiled.(*regexp.Regexp)
+	}
+	compiled := regexp.MustCompile(pattern)
+	patterns.Store(pattern, compiled)
+	return compiled
+}
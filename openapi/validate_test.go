@@ -0,0 +1,123 @@
+package openapi
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDocumentFind(t *testing.T) {
+	document := &Document{Paths: map[string]*PathItem{
+		"/api/products":                      {Get: &Operation{Summary: "list"}},
+		"/api/products/lookup":               {Get: &Operation{Summary: "lookup"}},
+		"/api/products/{productId}":          {Get: &Operation{Summary: "get"}, Delete: &Operation{Summary: "delete"}},
+		"/api/products/{productId}/variants": {Get: &Operation{Summary: "variants"}},
+	}}
+
+	tests := []struct {
+		method          string
+		path            string
+		expectedSummary string
+		expectedParams  map[string]string
+	}{
+		{method: "GET", path: "/api/products/", expectedSummary: "list", expectedParams: map[string]string{}},
+		{method: "GET", path: "/api/products/lookup", expectedSummary: "lookup", expectedParams: map[string]string{}},
+		{method: "GET", path: "/api/products/42", expectedSummary: "get", expectedParams: map[string]string{"productId": "42"}},
+		{method: "DELETE", path: "/api/products/42", expectedSummary: "delete", expectedParams: map[string]string{"productId": "42"}},
+		{method: "GET", path: "/api/products/42/variants", expectedSummary: "variants", expectedParams: map[string]string{"productId": "42"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			operation, params := document.Find(tt.method, tt.path)
+			if assert.NotNil(t, operation) {
+				assert.Equal(t, tt.expectedSummary, operation.Summary)
+			}
+			assert.Equal(t, tt.expectedParams, params)
+		})
+	}
+
+	operation, _ := document.Find("PUT", "/api/products/42")
+	assert.Nil(t, operation)
+}
+
+func TestDocumentValidate(t *testing.T) {
+	document := &Document{Components: Components{Schemas: map[string]*Schema{
+		"Price": {
+			Type: "object",
+			Properties: map[string]*Schema{
+				"amount":   {Type: "string", Pattern: `^[0-9]+(\.[0-9]+)?$`},
+				"currency": {Type: "string", MinLength: intOf(3), MaxLength: intOf(3)},
+			},
+			Required: []string{"currency"},
+		},
+	}}}
+	schema := &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"price":    {AllOf: []*Schema{{Ref: "#/components/schemas/Price"}}, Nullable: true},
+			"quantity": {Type: "integer", Format: "int32", Minimum: float(0), Maximum: float(100), ExclusiveMaximum: true},
+			"tags":     {Type: "array", Items: &Schema{Type: "string"}, UniqueItems: true, Nullable: true},
+			"email":    {Type: "string", Format: "email"},
+			"url":      {Type: "string", Format: "uri"},
+			"at":       {Type: "string", Format: "date-time"},
+			"any":      {},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedErrors []string
+	}{
+		{name: "Valid", body: `{"price":{"amount":"12.50","currency":"USD"},"quantity":3,"tags":["a","b"],"email":"a@example.com","url":"https://example.com/hook","at":"2024-05-01T10:00:00Z","any":[1,"x"]}`},
+		{name: "Nulls where nullable", body: `{"price":null,"tags":null,"any":null}`},
+		{name: "Null where not", body: `{"quantity":null}`, expectedErrors: []string{"body.quantity: must not be null"}},
+		{name: "Nested component", body: `{"price":{"amount":"12,50"}}`, expectedErrors: []string{
+			"body.price.currency: is required",
+			`body.price.amount: must match ^[0-9]+(\.[0-9]+)?$`,
+		}},
+		{name: "Number bounds", body: `{"quantity":100}`, expectedErrors: []string{"body.quantity: must be less than 100"}},
+		{name: "Not an integer", body: `{"quantity":1.5}`, expectedErrors: []string{"body.quantity: must be an integer"}},
+		{name: "Duplicate items", body: `{"tags":["a","a"]}`, expectedErrors: []string{"body.tags[1]: duplicates item 0"}},
+		{name: "Formats", body: `{"email":"nobody","url":"/relative","at":"yesterday"}`, expectedErrors: []string{
+			"body.at: must be a valid date-time",
+			"body.email: must be a valid email",
+			"body.url: must be a valid uri",
+		}},
+		{name: "Not an object", body: `[]`, expectedErrors: []string{"body: must be an object"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var messages []string
+			for _, err := range document.ValidateJSON("body", schema, []byte(tt.body)) {
+				messages = append(messages, err.Error())
+			}
+			assert.Equal(t, tt.expectedErrors, messages)
+		})
+	}
+}
+
+func TestDocumentValidateParameter(t *testing.T) {
+	document := &Document{}
+	limit := &Parameter{Name: "limit", In: "query", Schema: &Schema{Type: "integer", Format: "int64", Minimum: float(1)}}
+	dryRun := &Parameter{Name: "dry_run", In: "query", Schema: &Schema{Type: "boolean"}}
+	code := &Parameter{Name: "code", In: "query", Required: true, Schema: &Schema{Type: "string"}}
+
+	assert.Empty(t, document.ValidateParameter(limit, "10", true))
+	assert.Empty(t, document.ValidateParameter(limit, "", false))
+	assert.Equal(t, []ValidationError{{Path: "query.limit", Message: "must be an integer"}}, document.ValidateParameter(limit, "ten", true))
+	assert.Equal(t, []ValidationError{{Path: "query.limit", Message: "must be greater than or equal to 1"}}, document.ValidateParameter(limit, "0", true))
+	assert.Empty(t, document.ValidateParameter(dryRun, "1", true))
+	assert.Equal(t, []ValidationError{{Path: "query.dry_run", Message: "must be a boolean"}}, document.ValidateParameter(dryRun, "maybe", true))
+	assert.Equal(t, []ValidationError{{Path: "query.code", Message: "is required"}}, document.ValidateParameter(code, "", false))
+}
+
+func TestDocumentValidateEnumNumbers(t *testing.T) {
+	document := &Document{}
+	schema := &Schema{Type: "integer", Enum: []interface{}{int64(1), int64(2)}}
+
+	assert.Empty(t, document.Validate("body", schema, json.Number("2")))
+	assert.Len(t, document.Validate("body", schema, json.Number("3")), 1)
+}
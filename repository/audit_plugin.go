@@ -0,0 +1,290 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/aronipurwanto/go-restful-api/helper"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"gorm.io/gorm"
+	"reflect"
+	"strconv"
+)
+
+// ErrAuditLogAppendOnly is returned when anything tries to change or remove a written audit log row
+var ErrAuditLogAppendOnly = errors.New("audit log is append-only")
+
+// AuditedTables maps every audited table to the entity name used in the audit log
+var AuditedTables = map[string]string{
+	"categories":       "category",
+	"customers":        "customer",
+	"employees":        "employee",
+	"products":         "product",
+	"product_prices":   "product_price",
+	"product_variants": "product_variant",
+}
+
+const (
+	auditLogTable     = "audit_logs"
+	auditSnapshotsKey = "audit:snapshots"
+	// auditRequestIdLength is the size of audit_logs.request_id
+	auditRequestIdLength = 64
+)
+
+type auditSnapshot = map[string]interface{}
+
+type auditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// AuditPlugin writes an audit log row for every create, update and delete GORM runs on an audited table,
+// in the same transaction, so services and repositories need no audit code of their own
+type AuditPlugin struct{}
+
+func NewAuditPlugin() gorm.Plugin {
+	return &AuditPlugin{}
+}
+
+func (plugin *AuditPlugin) Name() string {
+	return "audit"
+}
+
+func (plugin *AuditPlugin) Initialize(db *gorm.DB) error {
+	callback := db.Callback()
+	if err := callback.Create().After("gorm:create").Before("gorm:commit_or_rollback_transaction").
+		Register("audit:after_create", plugin.afterCreate); err != nil {
+		return err
+	}
+	if err := callback.Update().After("gorm:begin_transaction").Before("gorm:update").
+		Register("audit:before_update", plugin.before); err != nil {
+		return err
+	}
+	if err := callback.Update().After("gorm:update").Before("gorm:commit_or_rollback_transaction").
+		Register("audit:after_update", plugin.afterUpdate); err != nil {
+		return err
+	}
+	if err := callback.Delete().After("gorm:begin_transaction").Before("gorm:delete").
+		Register("audit:before_delete", plugin.before); err != nil {
+		return err
+	}
+	return callback.Delete().After("gorm:delete").Before("gorm:commit_or_rollback_transaction").
+		Register("audit:after_delete", plugin.afterDelete)
+}
+
+// before rejects writes to the audit log itself and snapshots the rows an update or delete is about to touch
+func (plugin *AuditPlugin) before(db *gorm.DB) {
+	if db.Error != nil {
+		return
+	}
+	if db.Statement.Table == auditLogTable {
+		_ = db.AddError(ErrAuditLogAppendOnly)
+		return
+	}
+	if _, ok := AuditedTables[db.Statement.Table]; !ok {
+		return
+	}
+
+	query := plugin.session(db).Unscoped().Table(db.Statement.Table)
+	if where, ok := db.Statement.Clauses["WHERE"]; ok {
+		query = query.Clauses(where.Expression)
+	} else if !plugin.hasPrimaryKey(db) {
+		return
+	}
+	if id, ok := plugin.primaryKey(db); ok {
+		query = query.Where("id = ?", id)
+	}
+
+	var snapshots []auditSnapshot
+	if err := query.Find(&snapshots).Error; err != nil {
+		_ = db.AddError(err)
+		return
+	}
+	db.InstanceSet(auditSnapshotsKey, snapshots)
+}
+
+func (plugin *AuditPlugin) afterCreate(db *gorm.DB) {
+	if !plugin.audited(db) {
+		return
+	}
+
+	var ids []interface{}
+	value := reflect.Indirect(db.Statement.ReflectValue)
+	switch value.Kind() {
+	case reflect.Struct:
+		if id, ok := plugin.primaryKeyOf(db, value); ok {
+			ids = append(ids, id)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if id, ok := plugin.primaryKeyOf(db, reflect.Indirect(value.Index(i))); ok {
+				ids = append(ids, id)
+			}
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+
+	created, err := plugin.reload(db, ids)
+	if err != nil {
+		_ = db.AddError(err)
+		return
+	}
+	for _, after := range created {
+		plugin.write(db, "create", nil, after)
+	}
+}
+
+func (plugin *AuditPlugin) afterUpdate(db *gorm.DB) {
+	if !plugin.audited(db) {
+		return
+	}
+	befores := plugin.snapshots(db)
+	if len(befores) == 0 {
+		return
+	}
+
+	ids := make([]interface{}, 0, len(befores))
+	for _, before := range befores {
+		ids = append(ids, before["id"])
+	}
+	afters, err := plugin.reload(db, ids)
+	if err != nil {
+		_ = db.AddError(err)
+		return
+	}
+
+	for _, before := range befores {
+		after, ok := afters[fmt.Sprint(before["id"])]
+		if !ok {
+			continue
+		}
+		action := "update"
+		if before["deleted_at"] != nil && after["deleted_at"] == nil {
+			action = "restore"
+		}
+		plugin.write(db, action, before, after)
+	}
+}
+
+func (plugin *AuditPlugin) afterDelete(db *gorm.DB) {
+	if !plugin.audited(db) || db.Statement.RowsAffected == 0 {
+		return
+	}
+
+	action := "delete"
+	if db.Statement.Unscoped {
+		action = "purge"
+	}
+	for _, before := range plugin.snapshots(db) {
+		// a soft delete leaves rows that were already deleted untouched
+		if action == "delete" && before["deleted_at"] != nil {
+			continue
+		}
+		plugin.write(db, action, before, nil)
+	}
+}
+
+// write inserts one audit log row holding the columns that differ between before and after
+func (plugin *AuditPlugin) write(db *gorm.DB, action string, before auditSnapshot, after auditSnapshot) {
+	changes := make(map[string]auditChange)
+	for column, value := range after {
+		if previous, ok := before[column]; !ok || !reflect.DeepEqual(previous, value) {
+			changes[column] = auditChange{Before: before[column], After: value}
+		}
+	}
+	for column, value := range before {
+		if _, ok := after[column]; !ok {
+			changes[column] = auditChange{Before: value}
+		}
+	}
+	if action == "update" && len(changes) == 0 {
+		return
+	}
+
+	source := after
+	if source == nil {
+		source = before
+	}
+	entityId, err := strconv.ParseUint(fmt.Sprint(source["id"]), 10, 64)
+	if err != nil {
+		_ = db.AddError(fmt.Errorf("audit: invalid id %v: %w", source["id"], err))
+		return
+	}
+	diff, err := json.Marshal(changes)
+	if err != nil {
+		_ = db.AddError(err)
+		return
+	}
+
+	ctx := db.Statement.Context
+	requestId := helper.RequestIdFromContext(ctx)
+	if len(requestId) > auditRequestIdLength {
+		requestId = requestId[:auditRequestIdLength]
+	}
+	_ = db.AddError(plugin.session(db).Create(&domain.AuditLog{
+		Entity:    AuditedTables[db.Statement.Table],
+		EntityId:  entityId,
+		Action:    action,
+		ActorId:   helper.EmployeeIdFromContext(ctx),
+		RequestId: requestId,
+		Changes:   diff,
+	}).Error)
+}
+
+// audited reports whether the statement succeeded on an audited table
+func (plugin *AuditPlugin) audited(db *gorm.DB) bool {
+	if db.Error != nil {
+		return false
+	}
+	_, ok := AuditedTables[db.Statement.Table]
+	return ok
+}
+
+// session starts a fresh statement on the connection of db, so audit queries join its transaction
+func (plugin *AuditPlugin) session(db *gorm.DB) *gorm.DB {
+	return db.Session(&gorm.Session{NewDB: true, SkipHooks: true, Context: db.Statement.Context})
+}
+
+// reload reads the current rows with the given ids, keyed by id
+func (plugin *AuditPlugin) reload(db *gorm.DB, ids []interface{}) (map[string]auditSnapshot, error) {
+	var rows []auditSnapshot
+	if err := plugin.session(db).Unscoped().Table(db.Statement.Table).Where("id IN ?", ids).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	byId := make(map[string]auditSnapshot, len(rows))
+	for _, row := range rows {
+		byId[fmt.Sprint(row["id"])] = row
+	}
+	return byId, nil
+}
+
+func (plugin *AuditPlugin) snapshots(db *gorm.DB) []auditSnapshot {
+	snapshots, _ := db.InstanceGet(auditSnapshotsKey)
+	rows, _ := snapshots.([]auditSnapshot)
+	return rows
+}
+
+func (plugin *AuditPlugin) hasPrimaryKey(db *gorm.DB) bool {
+	_, ok := plugin.primaryKey(db)
+	return ok
+}
+
+// primaryKey returns the id of the model a statement was given, if it has one
+func (plugin *AuditPlugin) primaryKey(db *gorm.DB) (interface{}, bool) {
+	value := reflect.Indirect(db.Statement.ReflectValue)
+	if value.Kind() != reflect.Struct {
+		return nil, false
+	}
+	return plugin.primaryKeyOf(db, value)
+}
+
+func (plugin *AuditPlugin) primaryKeyOf(db *gorm.DB, value reflect.Value) (interface{}, bool) {
+	if db.Statement.Schema == nil || db.Statement.Schema.PrioritizedPrimaryField == nil || !value.IsValid() {
+		return nil, false
+	}
+	id, zero := db.Statement.Schema.PrioritizedPrimaryField.ValueOf(db.Statement.Context, value)
+	return id, !zero
+}
@@ -0,0 +1,11 @@
+package repository
+
+import (
+	"context"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+)
+
+type AuditRepository interface {
+	FindAll(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditLog, error)
+	FindSince(ctx context.Context, afterId uint64, limit int) ([]domain.AuditLog, error)
+}
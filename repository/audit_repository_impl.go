@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"gorm.io/gorm"
+)
+
+// AuditRepositoryImpl only reads; audit log rows are written by AuditPlugin
+type AuditRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) AuditRepository {
+	return &AuditRepositoryImpl{db: db}
+}
+
+// FindAll - Get the matching audit log rows, newest first
+func (repository *AuditRepositoryImpl) FindAll(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditLog, error) {
+	query := dbFromContext(ctx, repository.db)
+	if filter.Entity != "" {
+		query = query.Where("entity = ?", filter.Entity)
+	}
+	if filter.EntityId != nil {
+		query = query.Where("entity_id = ?", *filter.EntityId)
+	}
+	if filter.ActorId != nil {
+		query = query.Where("actor_id = ?", *filter.ActorId)
+	}
+
+	var logs []domain.AuditLog
+	err := query.Order("id DESC").Limit(filter.Limit).Find(&logs).Error
+	return logs, err
+}
+
+// FindSince - Get the audit log rows written after afterId, oldest first
+func (repository *AuditRepositoryImpl) FindSince(ctx context.Context, afterId uint64, limit int) ([]domain.AuditLog, error) {
+	var logs []domain.AuditLog
+	err := dbFromContext(ctx, repository.db).Where("id > ?", afterId).Order("id").Limit(limit).Find(&logs).Error
+	return logs, err
+}
@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"github.com/aronipurwanto/go-restful-api/helper"
+	"github.com/aronipurwanto/go-restful-api/metrics"
+	"golang.org/x/sync/singleflight"
+	"sync"
+	"time"
+)
+
+type cacheEntry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiresAt time.Time
+}
+
+// Cache is an in-process LRU whose entries expire after a TTL. Concurrent misses of a key share a
+// single load, and a load that raced with Invalidate or Clear is not stored, so a write is never
+// followed by the value from before it.
+type Cache[K comparable, V any] struct {
+	name       string
+	size       int
+	ttl        time.Duration
+	now        func() time.Time
+	mutex      sync.Mutex
+	entries    map[K]*list.Element
+	order      *list.List // most recently used first
+	generation uint64     // bumped by every invalidation
+	loads      singleflight.Group
+}
+
+// NewCache holds up to size entries for ttl each; name labels its hit and miss metrics. A size of
+// zero or less disables caching, every lookup then loads.
+func NewCache[K comparable, V any](name string, size int, ttl time.Duration) *Cache[K, V] {
+	return &Cache[K, V]{
+		name:    name,
+		size:    size,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[K]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// Get returns the cached value of key, or stores and returns what load returns. The load runs
+// without the caller's cancellation because other callers may be waiting for it.
+func (cache *Cache[K, V]) Get(ctx context.Context, key K, load func(ctx context.Context) (V, error)) (V, error) {
+	cache.mutex.Lock()
+	value, ok := cache.lookup(key)
+	generation := cache.generation
+	cache.mutex.Unlock()
+	if ok {
+		metrics.CacheHits.WithLabelValues(cache.name).Inc()
+		return value, nil
+	}
+	metrics.CacheMisses.WithLabelValues(cache.name).Inc()
+
+	loaded, err, _ := cache.loads.Do(fmt.Sprint(generation, "/", key), func() (interface{}, error) {
+		value, err := load(context.WithoutCancel(ctx))
+		if err == nil {
+			cache.store(key, value, generation)
+		}
+		return value, err
+	})
+	if err != nil {
+		var zero V
+		return zero, err
+	}
+	return loaded.(V), nil
+}
+
+// Invalidate forgets key
+func (cache *Cache[K, V]) Invalidate(key K) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	cache.generation++
+	if element, ok := cache.entries[key]; ok {
+		cache.remove(element)
+	}
+}
+
+// Clear forgets every key
+func (cache *Cache[K, V]) Clear() {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	cache.generation++
+	clear(cache.entries)
+	cache.order.Init()
+}
+
+// Len is the number of entries held, expired ones included until they are looked up or evicted
+func (cache *Cache[K, V]) Len() int {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	return cache.order.Len()
+}
+
+// lookup returns the entry of key unless it has expired; the mutex must be held
+func (cache *Cache[K, V]) lookup(key K) (V, bool) {
+	var zero V
+	element, ok := cache.entries[key]
+	if !ok {
+		return zero, false
+	}
+	entry := element.Value.(*cacheEntry[K, V])
+	if !cache.now().Before(entry.expiresAt) {
+		cache.remove(element)
+		return zero, false
+	}
+	cache.order.MoveToFront(element)
+	return entry.value, true
+}
+
+// store adds a loaded value unless the cache was invalidated since generation, evicting the least
+// recently used entry when full
+func (cache *Cache[K, V]) store(key K, value V, generation uint64) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	if cache.size <= 0 || generation != cache.generation {
+		return
+	}
+
+	entry := &cacheEntry[K, V]{key: key, value: value, expiresAt: cache.now().Add(cache.ttl)}
+	if element, ok := cache.entries[key]; ok {
+		element.Value = entry
+		cache.order.MoveToFront(element)
+		return
+	}
+	cache.entries[key] = cache.order.PushFront(entry)
+	if cache.order.Len() > cache.size {
+		cache.remove(cache.order.Back())
+	}
+}
+
+func (cache *Cache[K, V]) remove(element *list.Element) {
+	delete(cache.entries, element.Value.(*cacheEntry[K, V]).key)
+	cache.order.Remove(element)
+}
+
+// cacheable reports whether a read may be answered from a cache: reads inside a transaction must see
+// its own writes, and ?include_deleted=true reads see rows the caches never hold
+func cacheable(ctx context.Context) bool {
+	return !inTransaction(ctx) && !helper.IncludeDeletedFromContext(ctx)
+}
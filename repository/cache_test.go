@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingLoader returns key*10 and counts how often it ran
+func countingLoader(loads *int) func(key int) func(ctx context.Context) (int, error) {
+	return func(key int) func(ctx context.Context) (int, error) {
+		return func(ctx context.Context) (int, error) {
+			*loads++
+			return key * 10, nil
+		}
+	}
+}
+
+func TestCacheExpiresAndEvicts(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := NewCache[int, int]("test", 2, time.Minute)
+	cache.now = func() time.Time { return now }
+	loads := 0
+	load := countingLoader(&loads)
+
+	value, err := cache.Get(ctx, 1, load(1))
+	assert.NoError(t, err)
+	assert.Equal(t, 10, value)
+	value, _ = cache.Get(ctx, 1, load(1))
+	assert.Equal(t, 10, value)
+	assert.Equal(t, 1, loads)
+
+	// 1 was used more recently than 2, so adding 3 evicts 2
+	_, _ = cache.Get(ctx, 2, load(2))
+	_, _ = cache.Get(ctx, 1, load(1))
+	_, _ = cache.Get(ctx, 3, load(3))
+	assert.Equal(t, 3, loads)
+	assert.Equal(t, 2, cache.Len())
+	_, _ = cache.Get(ctx, 1, load(1))
+	assert.Equal(t, 3, loads)
+	_, _ = cache.Get(ctx, 2, load(2))
+	assert.Equal(t, 4, loads)
+
+	now = now.Add(time.Minute)
+	_, _ = cache.Get(ctx, 2, load(2))
+	assert.Equal(t, 5, loads)
+}
+
+func TestCacheInvalidate(t *testing.T) {
+	ctx := context.Background()
+	cache := NewCache[int, int]("test", 10, time.Minute)
+	loads := 0
+	load := countingLoader(&loads)
+
+	_, _ = cache.Get(ctx, 1, load(1))
+	_, _ = cache.Get(ctx, 2, load(2))
+	cache.Invalidate(1)
+	_, _ = cache.Get(ctx, 1, load(1))
+	_, _ = cache.Get(ctx, 2, load(2))
+	assert.Equal(t, 3, loads)
+
+	cache.Clear()
+	assert.Equal(t, 0, cache.Len())
+	_, _ = cache.Get(ctx, 2, load(2))
+	assert.Equal(t, 4, loads)
+}
+
+func TestCacheDoesNotStoreErrorsOrDisabled(t *testing.T) {
+	ctx := context.Background()
+	cache := NewCache[int, int]("test", 10, time.Minute)
+	_, err := cache.Get(ctx, 1, func(ctx context.Context) (int, error) { return 0, errors.New("connection refused") })
+	assert.EqualError(t, err, "connection refused")
+	assert.Equal(t, 0, cache.Len())
+
+	disabled := NewCache[int, int]("test", 0, time.Minute)
+	loads := 0
+	_, _ = disabled.Get(ctx, 1, countingLoader(&loads)(1))
+	_, _ = disabled.Get(ctx, 1, countingLoader(&loads)(1))
+	assert.Equal(t, 2, loads)
+}
+
+func TestCacheCollapsesConcurrentMisses(t *testing.T) {
+	cache := NewCache[int, int]("test", 10, time.Minute)
+	var loads atomic.Int32
+	release := make(chan struct{})
+	load := func(ctx context.Context) (int, error) {
+		loads.Add(1)
+		<-release
+		return 10, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := cache.Get(context.Background(), 1, load)
+			assert.NoError(t, err)
+			assert.Equal(t, 10, value)
+		}()
+	}
+	// give the callers time to pile up behind the first load
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(1), loads.Load())
+}
+
+func TestCacheDropsLoadRacingInvalidate(t *testing.T) {
+	ctx := context.Background()
+	cache := NewCache[int, int]("test", 10, time.Minute)
+
+	// the row is written while it is being read; the value read before the write must not be kept
+	value, err := cache.Get(ctx, 1, func(ctx context.Context) (int, error) {
+		cache.Invalidate(1)
+		return 10, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 10, value)
+	assert.Equal(t, 0, cache.Len())
+
+	value, _ = cache.Get(ctx, 1, func(ctx context.Context) (int, error) { return 11, nil })
+	assert.Equal(t, 11, value)
+	assert.Equal(t, 1, cache.Len())
+}
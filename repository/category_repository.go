@@ -3,12 +3,18 @@ package repository
 import (
 	"context"
 	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"time"
 )
 
 type CategoryRepository interface {
 	Save(ctx context.Context, category domain.Category) (domain.Category, error)
 	Update(ctx context.Context, category domain.Category) (domain.Category, error)
 	Delete(ctx context.Context, category domain.Category) error
+	Restore(ctx context.Context, categoryId uint64) error
+	Purge(ctx context.Context, before time.Time) (int64, error)
 	FindById(ctx context.Context, categoryId uint64) (domain.Category, error)
 	FindAll(ctx context.Context) ([]domain.Category, error)
+	FindSubtreeIds(ctx context.Context, categoryId uint64) ([]uint64, error)
+	LockAncestorIds(ctx context.Context, categoryId uint64) ([]uint64, error)
+	CountProducts(ctx context.Context) (map[uint64]int64, error)
 }
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"slices"
+	"time"
+)
+
+// categoriesCacheKey is the single entry of the category cache, holding the result of FindAll
+const categoriesCacheKey = "all"
+
+// CachedCategoryRepository answers FindAll from a Cache and clears it on every write
+type CachedCategoryRepository struct {
+	CategoryRepository
+	Cache *Cache[string, []domain.Category]
+}
+
+func NewCachedCategoryRepository(categoryRepository CategoryRepository, cache *Cache[string, []domain.Category]) CategoryRepository {
+	return &CachedCategoryRepository{
+		CategoryRepository: categoryRepository,
+		Cache:              cache,
+	}
+}
+
+func (repository *CachedCategoryRepository) Save(ctx context.Context, category domain.Category) (domain.Category, error) {
+	defer repository.invalidate(ctx)
+	return repository.CategoryRepository.Save(ctx, category)
+}
+
+func (repository *CachedCategoryRepository) Update(ctx context.Context, category domain.Category) (domain.Category, error) {
+	defer repository.invalidate(ctx)
+	return repository.CategoryRepository.Update(ctx, category)
+}
+
+func (repository *CachedCategoryRepository) Delete(ctx context.Context, category domain.Category) error {
+	defer repository.invalidate(ctx)
+	return repository.CategoryRepository.Delete(ctx, category)
+}
+
+func (repository *CachedCategoryRepository) Restore(ctx context.Context, categoryId uint64) error {
+	defer repository.invalidate(ctx)
+	return repository.CategoryRepository.Restore(ctx, categoryId)
+}
+
+func (repository *CachedCategoryRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	defer repository.invalidate(ctx)
+	return repository.CategoryRepository.Purge(ctx, before)
+}
+
+// FindAll - Get all categories, from the cache unless the read has to see the database
+func (repository *CachedCategoryRepository) FindAll(ctx context.Context) ([]domain.Category, error) {
+	if !cacheable(ctx) {
+		return repository.CategoryRepository.FindAll(ctx)
+	}
+	categories, err := repository.Cache.Get(ctx, categoriesCacheKey, repository.CategoryRepository.FindAll)
+	if err != nil {
+		return nil, err
+	}
+
+	// callers may change the categories they get, but not the cached ones
+	categories = slices.Clone(categories)
+	for i := range categories {
+		if parentId := categories[i].ParentId; parentId != nil {
+			copied := *parentId
+			categories[i].ParentId = &copied
+		}
+	}
+	return categories, nil
+}
+
+// invalidate clears the cache now, and again once the transaction the write joined has committed
+func (repository *CachedCategoryRepository) invalidate(ctx context.Context) {
+	repository.Cache.Clear()
+	if inTransaction(ctx) {
+		afterCommit(ctx, repository.Cache.Clear)
+	}
+}
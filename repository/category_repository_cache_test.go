@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func TestCachedCategoryRepository(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockCategoryRepository(ctrl)
+	repo := NewCachedCategoryRepository(mockRepo, NewCache[string, []domain.Category]("category", 1, time.Minute))
+	ctx := context.Background()
+	parentId := uint64(1)
+	categories := []domain.Category{{Id: 1, Name: "Drinks"}, {Id: 2, Name: "Coffee", ParentId: &parentId}}
+
+	mockRepo.EXPECT().FindAll(gomock.Any()).Return(categories, nil)
+	found, err := repo.FindAll(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, categories, found)
+
+	// a hit does not load, and changes callers make stay their own
+	found[0].Name = "changed"
+	*found[1].ParentId = 9
+	found, err = repo.FindAll(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "Drinks", found[0].Name)
+	assert.Equal(t, uint64(1), *found[1].ParentId)
+
+	// every write clears the list
+	mockRepo.EXPECT().Save(ctx, domain.Category{Name: "Tea", ParentId: &parentId}).Return(domain.Category{Id: 3, Name: "Tea", ParentId: &parentId}, nil)
+	_, err = repo.Save(ctx, domain.Category{Name: "Tea", ParentId: &parentId})
+	assert.NoError(t, err)
+	mockRepo.EXPECT().FindAll(gomock.Any()).Return(append(categories, domain.Category{Id: 3, Name: "Tea", ParentId: &parentId}), nil)
+	found, err = repo.FindAll(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, found, 3)
+
+	mockRepo.EXPECT().Restore(ctx, uint64(4)).Return(nil)
+	assert.NoError(t, repo.Restore(ctx, 4))
+	mockRepo.EXPECT().FindAll(gomock.Any()).Return(categories, nil)
+	found, err = repo.FindAll(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, found, 2)
+}
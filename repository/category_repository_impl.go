@@ -3,10 +3,20 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/aronipurwanto/go-restful-api/model/domain"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
 )
 
+// categorySubtreeSQL selects the id of a category and of all its descendants
+const categorySubtreeSQL = `WITH RECURSIVE subtree AS (
+	SELECT id FROM categories WHERE id = ?
+	UNION ALL
+	SELECT c.id FROM categories c INNER JOIN subtree s ON c.parent_id = s.id WHERE c.deleted_at IS NULL
+) SELECT id FROM subtree`
+
 type CategoryRepositoryImpl struct {
 	db *gorm.DB
 }
@@ -17,34 +27,52 @@ func NewCategoryRepository(db *gorm.DB) CategoryRepository {
 
 // Save category
 func (repository *CategoryRepositoryImpl) Save(ctx context.Context, category domain.Category) (domain.Category, error) {
-	if err := repository.db.WithContext(ctx).Create(&category).Error; err != nil {
+	category.Version = 1
+	if err := dbFromContext(ctx, repository.db).Create(&category).Error; err != nil {
 		return domain.Category{}, err
 	}
 	return category, nil
 }
 
-// Update category
+// Update category if it still has the version it was read with
 func (repository *CategoryRepositoryImpl) Update(ctx context.Context, category domain.Category) (domain.Category, error) {
-	if err := repository.db.WithContext(ctx).Save(&category).Error; err != nil {
+	if err := updateVersioned(dbFromContext(ctx, repository.db), &category, &category.Version); err != nil {
 		return domain.Category{}, err
 	}
 	return category, nil
 }
 
-// Delete category
+// Delete category if it still has the version it was read with
 func (repository *CategoryRepositoryImpl) Delete(ctx context.Context, category domain.Category) error {
-	if err := repository.db.WithContext(ctx).Delete(&category).Error; err != nil {
-		return err
+	return deleteVersioned(dbFromContext(ctx, repository.db), &category, category.Version)
+}
+
+// Restore - Undelete a soft-deleted category
+func (repository *CategoryRepositoryImpl) Restore(ctx context.Context, categoryId uint64) error {
+	err := restoreDeletedChild(dbFromContext(ctx, repository.db), &domain.Category{}, categoryId, "parent_id", &domain.Category{})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("deleted category is not found: %w", err)
+	} else if errors.Is(err, ErrParentDeleted) {
+		return fmt.Errorf("parent category is deleted: %w", err)
 	}
-	return nil
+	return err
+}
+
+// Purge - Permanently remove categories soft-deleted before the given time. Categories still
+// referenced by a subcategory or a product, deleted or not, are kept until those are purged.
+func (repository *CategoryRepositoryImpl) Purge(ctx context.Context, before time.Time) (int64, error) {
+	db := dbFromContext(ctx, repository.db)
+	return purgeDeleted(db.
+		Where("id NOT IN (?)", db.Raw("SELECT parent_id FROM (SELECT parent_id FROM categories WHERE parent_id IS NOT NULL) AS parents")).
+		Where("id NOT IN (?)", db.Raw("SELECT category_id FROM products")), &domain.Category{}, before)
 }
 
 // FindById - Get category by ID
 func (repository *CategoryRepositoryImpl) FindById(ctx context.Context, categoryId uint64) (domain.Category, error) {
 	var category domain.Category
-	err := repository.db.WithContext(ctx).First(&category, categoryId).Error
+	err := dbFromContext(ctx, repository.db).First(&category, categoryId).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
-		return category, errors.New("category is not found")
+		return category, fmt.Errorf("category is not found: %w", err)
 	}
 	return category, err
 }
@@ -52,6 +80,60 @@ func (repository *CategoryRepositoryImpl) FindById(ctx context.Context, category
 // FindAll - Get all categories
 func (repository *CategoryRepositoryImpl) FindAll(ctx context.Context) ([]domain.Category, error) {
 	var categories []domain.Category
-	err := repository.db.WithContext(ctx).Find(&categories).Error
+	err := dbFromContext(ctx, repository.db).Find(&categories).Error
 	return categories, err
 }
+
+// FindSubtreeIds - Get the ids of a category and all of its descendants
+func (repository *CategoryRepositoryImpl) FindSubtreeIds(ctx context.Context, categoryId uint64) ([]uint64, error) {
+	var ids []uint64
+	err := dbFromContext(ctx, repository.db).Raw(categorySubtreeSQL, categoryId).Scan(&ids).Error
+	return ids, err
+}
+
+// LockAncestorIds - Get the id of a category and of all its ancestors, walking up one row at a time
+// with SELECT ... FOR UPDATE so no concurrent move can change the chain until the transaction ends
+func (repository *CategoryRepositoryImpl) LockAncestorIds(ctx context.Context, categoryId uint64) ([]uint64, error) {
+	db := dbFromContext(ctx, repository.db).Clauses(clause.Locking{Strength: "UPDATE"})
+
+	var ids []uint64
+	seen := make(map[uint64]bool)
+	for id := &categoryId; id != nil && !seen[*id]; {
+		var category domain.Category
+		err := db.Select("id", "parent_id").First(&category, *id).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) && len(ids) > 0 {
+			// A deleted ancestor ends the chain, as it does for FindSubtreeIds
+			break
+		} else if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("category is not found: %w", err)
+		} else if err != nil {
+			return nil, lockConflict(err)
+		}
+		seen[category.Id] = true
+		ids = append(ids, category.Id)
+		id = category.ParentId
+	}
+	return ids, nil
+}
+
+// CountProducts - Get the number of products directly assigned to each category
+func (repository *CategoryRepositoryImpl) CountProducts(ctx context.Context) (map[uint64]int64, error) {
+	var rows []struct {
+		CategoryId uint64
+		Total      int64
+	}
+	err := dbFromContext(ctx, repository.db).
+		Model(&domain.Product{}).
+		Select("category_id, COUNT(*) AS total").
+		Group("category_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uint64]int64, len(rows))
+	for _, row := range rows {
+		counts[row.CategoryId] = row.Total
+	}
+	return counts, nil
+}
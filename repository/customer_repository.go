@@ -3,12 +3,16 @@ package repository
 import (
 	"context"
 	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"time"
 )
 
 type CustomerRepository interface {
 	Save(ctx context.Context, customer domain.Customer) (domain.Customer, error)
 	Update(ctx context.Context, customer domain.Customer) (domain.Customer, error)
 	Delete(ctx context.Context, customer domain.Customer) error
+	Restore(ctx context.Context, customerId uint64) error
+	Purge(ctx context.Context, before time.Time) (int64, error)
 	FindById(ctx context.Context, customerId uint64) (domain.Customer, error)
 	FindAll(ctx context.Context) ([]domain.Customer, error)
+	FindInBatches(ctx context.Context, batchSize int, fn func(customers []domain.Customer) error) error
 }
@@ -3,8 +3,10 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/aronipurwanto/go-restful-api/model/domain"
 	"gorm.io/gorm"
+	"time"
 )
 
 type CustomerRepositoryImpl struct {
@@ -17,34 +19,46 @@ func NewCustomerRepository(db *gorm.DB) CustomerRepository {
 
 // Save customer
 func (repository *CustomerRepositoryImpl) Save(ctx context.Context, customer domain.Customer) (domain.Customer, error) {
-	if err := repository.db.WithContext(ctx).Create(&customer).Error; err != nil {
+	customer.Version = 1
+	if err := dbFromContext(ctx, repository.db).Create(&customer).Error; err != nil {
 		return domain.Customer{}, err
 	}
 	return customer, nil
 }
 
-// Update customer
+// Update customer if it still has the version it was read with
 func (repository *CustomerRepositoryImpl) Update(ctx context.Context, customer domain.Customer) (domain.Customer, error) {
-	if err := repository.db.WithContext(ctx).Save(&customer).Error; err != nil {
+	if err := updateVersioned(dbFromContext(ctx, repository.db), &customer, &customer.Version); err != nil {
 		return domain.Customer{}, err
 	}
 	return customer, nil
 }
 
-// Delete customer
+// Delete customer if it still has the version it was read with
 func (repository *CustomerRepositoryImpl) Delete(ctx context.Context, customer domain.Customer) error {
-	if err := repository.db.WithContext(ctx).Delete(&customer).Error; err != nil {
-		return err
+	return deleteVersioned(dbFromContext(ctx, repository.db), &customer, customer.Version)
+}
+
+// Restore - Undelete a soft-deleted customer
+func (repository *CustomerRepositoryImpl) Restore(ctx context.Context, customerId uint64) error {
+	err := restoreDeleted(dbFromContext(ctx, repository.db), &domain.Customer{}, customerId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("deleted customer is not found: %w", err)
 	}
-	return nil
+	return err
+}
+
+// Purge - Permanently remove customers soft-deleted before the given time
+func (repository *CustomerRepositoryImpl) Purge(ctx context.Context, before time.Time) (int64, error) {
+	return purgeDeleted(dbFromContext(ctx, repository.db), &domain.Customer{}, before)
 }
 
 // FindById - Get customer by ID
 func (repository *CustomerRepositoryImpl) FindById(ctx context.Context, customerId uint64) (domain.Customer, error) {
 	var customer domain.Customer
-	err := repository.db.WithContext(ctx).First(&customer, customerId).Error
+	err := dbFromContext(ctx, repository.db).First(&customer, customerId).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
-		return customer, errors.New("customer is not found")
+		return customer, fmt.Errorf("customer is not found: %w", err)
 	}
 	return customer, err
 }
@@ -52,6 +66,14 @@ func (repository *CustomerRepositoryImpl) FindById(ctx context.Context, customer
 // FindAll - Get all customers
 func (repository *CustomerRepositoryImpl) FindAll(ctx context.Context) ([]domain.Customer, error) {
 	var customers []domain.Customer
-	err := repository.db.WithContext(ctx).Find(&customers).Error
+	err := dbFromContext(ctx, repository.db).Find(&customers).Error
 	return customers, err
 }
+
+// FindInBatches - Walk all customers in primary key order, batchSize at a time
+func (repository *CustomerRepositoryImpl) FindInBatches(ctx context.Context, batchSize int, fn func(customers []domain.Customer) error) error {
+	var customers []domain.Customer
+	return dbFromContext(ctx, repository.db).FindInBatches(&customers, batchSize, func(tx *gorm.DB, batch int) error {
+		return fn(customers)
+	}).Error
+}
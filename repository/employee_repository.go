@@ -3,12 +3,16 @@ package repository
 import (
 	"context"
 	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"time"
 )
 
 type EmployeeRepository interface {
 	Save(ctx context.Context, employee domain.Employee) (domain.Employee, error)
 	Update(ctx context.Context, employee domain.Employee) (domain.Employee, error)
 	Delete(ctx context.Context, employee domain.Employee) error
+	Restore(ctx context.Context, employeeId uint64) error
+	Purge(ctx context.Context, before time.Time) (int64, error)
 	FindById(ctx context.Context, employeeId uint64) (domain.Employee, error)
 	FindAll(ctx context.Context) ([]domain.Employee, error)
+	FindInBatches(ctx context.Context, batchSize int, fn func(employees []domain.Employee) error) error
 }
@@ -3,8 +3,10 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/aronipurwanto/go-restful-api/model/domain"
 	"gorm.io/gorm"
+	"time"
 )
 
 type EmployeeRepositoryImpl struct {
@@ -17,34 +19,46 @@ func NewEmployeeRepository(db *gorm.DB) EmployeeRepository {
 
 // Save employee
 func (repository *EmployeeRepositoryImpl) Save(ctx context.Context, employee domain.Employee) (domain.Employee, error) {
-	if err := repository.db.WithContext(ctx).Create(&employee).Error; err != nil {
+	employee.Version = 1
+	if err := dbFromContext(ctx, repository.db).Create(&employee).Error; err != nil {
 		return domain.Employee{}, err
 	}
 	return employee, nil
 }
 
-// Update employee
+// Update employee if it still has the version it was read with
 func (repository *EmployeeRepositoryImpl) Update(ctx context.Context, employee domain.Employee) (domain.Employee, error) {
-	if err := repository.db.WithContext(ctx).Save(&employee).Error; err != nil {
+	if err := updateVersioned(dbFromContext(ctx, repository.db), &employee, &employee.Version); err != nil {
 		return domain.Employee{}, err
 	}
 	return employee, nil
 }
 
-// Delete employee
+// Delete employee if it still has the version it was read with
 func (repository *EmployeeRepositoryImpl) Delete(ctx context.Context, employee domain.Employee) error {
-	if err := repository.db.WithContext(ctx).Delete(&employee).Error; err != nil {
-		return err
+	return deleteVersioned(dbFromContext(ctx, repository.db), &employee, employee.Version)
+}
+
+// Restore - Undelete a soft-deleted employee
+func (repository *EmployeeRepositoryImpl) Restore(ctx context.Context, employeeId uint64) error {
+	err := restoreDeleted(dbFromContext(ctx, repository.db), &domain.Employee{}, employeeId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("deleted employee is not found: %w", err)
 	}
-	return nil
+	return err
+}
+
+// Purge - Permanently remove employees soft-deleted before the given time
+func (repository *EmployeeRepositoryImpl) Purge(ctx context.Context, before time.Time) (int64, error) {
+	return purgeDeleted(dbFromContext(ctx, repository.db), &domain.Employee{}, before)
 }
 
 // FindById - Get employee by ID
 func (repository *EmployeeRepositoryImpl) FindById(ctx context.Context, employeeId uint64) (domain.Employee, error) {
 	var employee domain.Employee
-	err := repository.db.WithContext(ctx).First(&employee, employeeId).Error
+	err := dbFromContext(ctx, repository.db).First(&employee, employeeId).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
-		return employee, errors.New("employee is not found")
+		return employee, fmt.Errorf("employee is not found: %w", err)
 	}
 	return employee, err
 }
@@ -52,6 +66,14 @@ func (repository *EmployeeRepositoryImpl) FindById(ctx context.Context, employee
 // FindAll - Get all employees
 func (repository *EmployeeRepositoryImpl) FindAll(ctx context.Context) ([]domain.Employee, error) {
 	var employees []domain.Employee
-	err := repository.db.WithContext(ctx).Find(&employees).Error
+	err := dbFromContext(ctx, repository.db).Find(&employees).Error
 	return employees, err
 }
+
+// FindInBatches - Walk all employees in primary key order, batchSize at a time
+func (repository *EmployeeRepositoryImpl) FindInBatches(ctx context.Context, batchSize int, fn func(employees []domain.Employee) error) error {
+	var employees []domain.Employee
+	return dbFromContext(ctx, repository.db).FindInBatches(&employees, batchSize, func(tx *gorm.DB, batch int) error {
+		return fn(employees)
+	}).Error
+}
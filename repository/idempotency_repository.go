@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"time"
+)
+
+// ErrIdempotencyClaimLost is returned to a request whose claim on a key expired and was taken over
+var ErrIdempotencyClaimLost = errors.New("idempotency key is no longer claimed by this request")
+
+type IdempotencyRepository interface {
+	Claim(ctx context.Context, key domain.IdempotencyKey, now time.Time) (bool, error)
+	FindByKey(ctx context.Context, key string) (domain.IdempotencyKey, error)
+	Renew(ctx context.Context, key string, token string, expiresAt time.Time) error
+	Complete(ctx context.Context, key domain.IdempotencyKey) error
+	Release(ctx context.Context, key string, token string) error
+	Purge(ctx context.Context, now time.Time) (int64, error)
+}
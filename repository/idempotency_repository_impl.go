@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type IdempotencyRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewIdempotencyRepository(db *gorm.DB) IdempotencyRepository {
+	return &IdempotencyRepositoryImpl{db: db}
+}
+
+// Claim inserts key unless a live row with the same key exists; false means another request holds it.
+// The primary key makes the insert atomic, so of two concurrent requests only one claims the key.
+func (repository *IdempotencyRepositoryImpl) Claim(ctx context.Context, key domain.IdempotencyKey, now time.Time) (bool, error) {
+	db := dbFromContext(ctx, repository.db)
+	if err := db.Where("idempotency_key = ? AND expires_at <= ?", key.Key, now).Delete(&domain.IdempotencyKey{}).Error; err != nil {
+		return false, err
+	}
+
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&key)
+	return result.RowsAffected == 1, result.Error
+}
+
+// FindByKey - Get the stored state of a key
+func (repository *IdempotencyRepositoryImpl) FindByKey(ctx context.Context, key string) (domain.IdempotencyKey, error) {
+	var idempotencyKey domain.IdempotencyKey
+	err := dbFromContext(ctx, repository.db).Where("idempotency_key = ?", key).First(&idempotencyKey).Error
+	return idempotencyKey, err
+}
+
+// Renew - Extend the lease of a claim still held with token
+func (repository *IdempotencyRepositoryImpl) Renew(ctx context.Context, key string, token string, expiresAt time.Time) error {
+	result := repository.claimed(ctx, key, token).Update("expires_at", expiresAt)
+	return claimResult(result)
+}
+
+// Complete - Store the response of a key still claimed with key.Token
+func (repository *IdempotencyRepositoryImpl) Complete(ctx context.Context, key domain.IdempotencyKey) error {
+	result := repository.claimed(ctx, key.Key, key.Token).
+		Updates(map[string]interface{}{
+			"status":           key.Status,
+			"response_status":  key.ResponseStatus,
+			"response_headers": key.ResponseHeaders,
+			"response_body":    key.ResponseBody,
+			"expires_at":       key.ExpiresAt,
+		})
+	return claimResult(result)
+}
+
+// Release - Remove a claim whose request failed, so a retry can run it again
+func (repository *IdempotencyRepositoryImpl) Release(ctx context.Context, key string, token string) error {
+	result := repository.claimed(ctx, key, token).Delete(&domain.IdempotencyKey{})
+	return claimResult(result)
+}
+
+// claimed selects key while it is in progress under token, so a request whose lease ran out
+// cannot overwrite or remove the claim of the request that took the key over
+func (repository *IdempotencyRepositoryImpl) claimed(ctx context.Context, key string, token string) *gorm.DB {
+	return dbFromContext(ctx, repository.db).Model(&domain.IdempotencyKey{}).
+		Where("idempotency_key = ? AND token = ? AND status = ?", key, token, domain.IdempotencyInProgress)
+}
+
+func claimResult(result *gorm.DB) error {
+	if result.Error == nil && result.RowsAffected == 0 {
+		return ErrIdempotencyClaimLost
+	}
+	return result.Error
+}
+
+// Purge - Remove expired keys
+func (repository *IdempotencyRepositoryImpl) Purge(ctx context.Context, now time.Time) (int64, error) {
+	result := dbFromContext(ctx, repository.db).Where("expires_at <= ?", now).Delete(&domain.IdempotencyKey{})
+	return result.RowsAffected, result.Error
+}
@@ -12,6 +12,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	domain "github.com/aronipurwanto/go-restful-api/model/domain"
 	gomock "go.uber.org/mock/gomock"
@@ -41,6 +42,21 @@ func (m *MockCategoryRepository) EXPECT() *MockCategoryRepositoryMockRecorder {
 	return m.recorder
 }
 
+// CountProducts mocks base method.
+func (m *MockCategoryRepository) CountProducts(ctx context.Context) (map[uint64]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountProducts", ctx)
+	ret0, _ := ret[0].(map[uint64]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountProducts indicates an expected call of CountProducts.
+func (mr *MockCategoryRepositoryMockRecorder) CountProducts(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountProducts", reflect.TypeOf((*MockCategoryRepository)(nil).CountProducts), ctx)
+}
+
 // Delete mocks base method.
 func (m *MockCategoryRepository) Delete(ctx context.Context, category domain.Category) error {
 	m.ctrl.T.Helper()
@@ -85,6 +101,65 @@ func (mr *MockCategoryRepositoryMockRecorder) FindById(ctx, categoryId any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockCategoryRepository)(nil).FindById), ctx, categoryId)
 }
 
+// FindSubtreeIds mocks base method.
+func (m *MockCategoryRepository) FindSubtreeIds(ctx context.Context, categoryId uint64) ([]uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindSubtreeIds", ctx, categoryId)
+	ret0, _ := ret[0].([]uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindSubtreeIds indicates an expected call of FindSubtreeIds.
+func (mr *MockCategoryRepositoryMockRecorder) FindSubtreeIds(ctx, categoryId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSubtreeIds", reflect.TypeOf((*MockCategoryRepository)(nil).FindSubtreeIds), ctx, categoryId)
+}
+
+// LockAncestorIds mocks base method.
+func (m *MockCategoryRepository) LockAncestorIds(ctx context.Context, categoryId uint64) ([]uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockAncestorIds", ctx, categoryId)
+	ret0, _ := ret[0].([]uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockAncestorIds indicates an expected call of LockAncestorIds.
+func (mr *MockCategoryRepositoryMockRecorder) LockAncestorIds(ctx, categoryId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockAncestorIds", reflect.TypeOf((*MockCategoryRepository)(nil).LockAncestorIds), ctx, categoryId)
+}
+
+// Purge mocks base method.
+func (m *MockCategoryRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockCategoryRepositoryMockRecorder) Purge(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockCategoryRepository)(nil).Purge), ctx, before)
+}
+
+// Restore mocks base method.
+func (m *MockCategoryRepository) Restore(ctx context.Context, categoryId uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, categoryId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockCategoryRepositoryMockRecorder) Restore(ctx, categoryId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockCategoryRepository)(nil).Restore), ctx, categoryId)
+}
+
 // Save mocks base method.
 func (m *MockCategoryRepository) Save(ctx context.Context, category domain.Category) (domain.Category, error) {
 	m.ctrl.T.Helper()
@@ -12,6 +12,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	domain "github.com/aronipurwanto/go-restful-api/model/domain"
 	gomock "go.uber.org/mock/gomock"
@@ -85,6 +86,49 @@ func (mr *MockEmployeeRepositoryMockRecorder) FindById(ctx, employeeId any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockEmployeeRepository)(nil).FindById), ctx, employeeId)
 }
 
+// FindInBatches mocks base method.
+func (m *MockEmployeeRepository) FindInBatches(ctx context.Context, batchSize int, fn func([]domain.Employee) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindInBatches", ctx, batchSize, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FindInBatches indicates an expected call of FindInBatches.
+func (mr *MockEmployeeRepositoryMockRecorder) FindInBatches(ctx, batchSize, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindInBatches", reflect.TypeOf((*MockEmployeeRepository)(nil).FindInBatches), ctx, batchSize, fn)
+}
+
+// Purge mocks base method.
+func (m *MockEmployeeRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockEmployeeRepositoryMockRecorder) Purge(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockEmployeeRepository)(nil).Purge), ctx, before)
+}
+
+// Restore mocks base method.
+func (m *MockEmployeeRepository) Restore(ctx context.Context, employeeId uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, employeeId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockEmployeeRepositoryMockRecorder) Restore(ctx, employeeId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockEmployeeRepository)(nil).Restore), ctx, employeeId)
+}
+
 // Save mocks base method.
 func (m *MockEmployeeRepository) Save(ctx context.Context, employee domain.Employee) (domain.Employee, error) {
 	m.ctrl.T.Helper()
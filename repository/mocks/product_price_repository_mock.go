@@ -0,0 +1,132 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/product_price_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=repository/product_price_repository.go -destination=repository/mocks/product_price_repository_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/aronipurwanto/go-restful-api/model/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockProductPriceRepository is a mock of ProductPriceRepository interface.
+type MockProductPriceRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockProductPriceRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockProductPriceRepositoryMockRecorder is the mock recorder for MockProductPriceRepository.
+type MockProductPriceRepositoryMockRecorder struct {
+	mock *MockProductPriceRepository
+}
+
+// NewMockProductPriceRepository creates a new mock instance.
+func NewMockProductPriceRepository(ctrl *gomock.Controller) *MockProductPriceRepository {
+	mock := &MockProductPriceRepository{ctrl: ctrl}
+	mock.recorder = &MockProductPriceRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProductPriceRepository) EXPECT() *MockProductPriceRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Apply mocks base method.
+func (m *MockProductPriceRepository) Apply(ctx context.Context, price domain.ProductPrice, appliedAt time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Apply", ctx, price, appliedAt)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Apply indicates an expected call of Apply.
+func (mr *MockProductPriceRepositoryMockRecorder) Apply(ctx, price, appliedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Apply", reflect.TypeOf((*MockProductPriceRepository)(nil).Apply), ctx, price, appliedAt)
+}
+
+// Delete mocks base method.
+func (m *MockProductPriceRepository) Delete(ctx context.Context, price domain.ProductPrice) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, price)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockProductPriceRepositoryMockRecorder) Delete(ctx, price any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockProductPriceRepository)(nil).Delete), ctx, price)
+}
+
+// FindById mocks base method.
+func (m *MockProductPriceRepository) FindById(ctx context.Context, priceId uint64) (domain.ProductPrice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindById", ctx, priceId)
+	ret0, _ := ret[0].(domain.ProductPrice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindById indicates an expected call of FindById.
+func (mr *MockProductPriceRepositoryMockRecorder) FindById(ctx, priceId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockProductPriceRepository)(nil).FindById), ctx, priceId)
+}
+
+// FindByProductId mocks base method.
+func (m *MockProductPriceRepository) FindByProductId(ctx context.Context, productId uint64) ([]domain.ProductPrice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByProductId", ctx, productId)
+	ret0, _ := ret[0].([]domain.ProductPrice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByProductId indicates an expected call of FindByProductId.
+func (mr *MockProductPriceRepositoryMockRecorder) FindByProductId(ctx, productId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByProductId", reflect.TypeOf((*MockProductPriceRepository)(nil).FindByProductId), ctx, productId)
+}
+
+// FindDue mocks base method.
+func (m *MockProductPriceRepository) FindDue(ctx context.Context, now time.Time) ([]domain.ProductPrice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDue", ctx, now)
+	ret0, _ := ret[0].([]domain.ProductPrice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDue indicates an expected call of FindDue.
+func (mr *MockProductPriceRepositoryMockRecorder) FindDue(ctx, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDue", reflect.TypeOf((*MockProductPriceRepository)(nil).FindDue), ctx, now)
+}
+
+// Save mocks base method.
+func (m *MockProductPriceRepository) Save(ctx context.Context, price domain.ProductPrice) (domain.ProductPrice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, price)
+	ret0, _ := ret[0].(domain.ProductPrice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockProductPriceRepositoryMockRecorder) Save(ctx, price any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockProductPriceRepository)(nil).Save), ctx, price)
+}
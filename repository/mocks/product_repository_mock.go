@@ -12,6 +12,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	domain "github.com/aronipurwanto/go-restful-api/model/domain"
 	gomock "go.uber.org/mock/gomock"
@@ -70,6 +71,36 @@ func (mr *MockProductRepositoryMockRecorder) FindAll(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockProductRepository)(nil).FindAll), ctx)
 }
 
+// FindByBarcode mocks base method.
+func (m *MockProductRepository) FindByBarcode(ctx context.Context, code string) (domain.ProductBarcode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByBarcode", ctx, code)
+	ret0, _ := ret[0].(domain.ProductBarcode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByBarcode indicates an expected call of FindByBarcode.
+func (mr *MockProductRepositoryMockRecorder) FindByBarcode(ctx, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByBarcode", reflect.TypeOf((*MockProductRepository)(nil).FindByBarcode), ctx, code)
+}
+
+// FindByCategoryTree mocks base method.
+func (m *MockProductRepository) FindByCategoryTree(ctx context.Context, categoryId uint64) ([]domain.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByCategoryTree", ctx, categoryId)
+	ret0, _ := ret[0].([]domain.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByCategoryTree indicates an expected call of FindByCategoryTree.
+func (mr *MockProductRepositoryMockRecorder) FindByCategoryTree(ctx, categoryId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByCategoryTree", reflect.TypeOf((*MockProductRepository)(nil).FindByCategoryTree), ctx, categoryId)
+}
+
 // FindById mocks base method.
 func (m *MockProductRepository) FindById(ctx context.Context, productId uint64) (domain.Product, error) {
 	m.ctrl.T.Helper()
@@ -85,6 +116,79 @@ func (mr *MockProductRepositoryMockRecorder) FindById(ctx, productId any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockProductRepository)(nil).FindById), ctx, productId)
 }
 
+// FindBySKUs mocks base method.
+func (m *MockProductRepository) FindBySKUs(ctx context.Context, skus []string) ([]domain.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindBySKUs", ctx, skus)
+	ret0, _ := ret[0].([]domain.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindBySKUs indicates an expected call of FindBySKUs.
+func (mr *MockProductRepositoryMockRecorder) FindBySKUs(ctx, skus any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindBySKUs", reflect.TypeOf((*MockProductRepository)(nil).FindBySKUs), ctx, skus)
+}
+
+// FindInBatches mocks base method.
+func (m *MockProductRepository) FindInBatches(ctx context.Context, filter domain.ProductFilter, batchSize int, fn func([]domain.Product) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindInBatches", ctx, filter, batchSize, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FindInBatches indicates an expected call of FindInBatches.
+func (mr *MockProductRepositoryMockRecorder) FindInBatches(ctx, filter, batchSize, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindInBatches", reflect.TypeOf((*MockProductRepository)(nil).FindInBatches), ctx, filter, batchSize, fn)
+}
+
+// Import mocks base method.
+func (m *MockProductRepository) Import(ctx context.Context, products []domain.Product) ([]domain.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", ctx, products)
+	ret0, _ := ret[0].([]domain.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockProductRepositoryMockRecorder) Import(ctx, products any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockProductRepository)(nil).Import), ctx, products)
+}
+
+// Purge mocks base method.
+func (m *MockProductRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockProductRepositoryMockRecorder) Purge(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockProductRepository)(nil).Purge), ctx, before)
+}
+
+// Restore mocks base method.
+func (m *MockProductRepository) Restore(ctx context.Context, productId uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, productId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockProductRepositoryMockRecorder) Restore(ctx, productId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockProductRepository)(nil).Restore), ctx, productId)
+}
+
 // Save mocks base method.
 func (m *MockProductRepository) Save(ctx context.Context, product domain.Product) (domain.Product, error) {
 	m.ctrl.T.Helper()
@@ -100,6 +204,20 @@ func (mr *MockProductRepositoryMockRecorder) Save(ctx, product any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockProductRepository)(nil).Save), ctx, product)
 }
 
+// Touch mocks base method.
+func (m *MockProductRepository) Touch(ctx context.Context, productId uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Touch", ctx, productId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Touch indicates an expected call of Touch.
+func (mr *MockProductRepositoryMockRecorder) Touch(ctx, productId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Touch", reflect.TypeOf((*MockProductRepository)(nil).Touch), ctx, productId)
+}
+
 // Update mocks base method.
 func (m *MockProductRepository) Update(ctx context.Context, product domain.Product) (domain.Product, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,131 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/product_variant_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=repository/product_variant_repository.go -destination=repository/mocks/product_variant_repository_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/aronipurwanto/go-restful-api/model/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockProductVariantRepository is a mock of ProductVariantRepository interface.
+type MockProductVariantRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockProductVariantRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockProductVariantRepositoryMockRecorder is the mock recorder for MockProductVariantRepository.
+type MockProductVariantRepositoryMockRecorder struct {
+	mock *MockProductVariantRepository
+}
+
+// NewMockProductVariantRepository creates a new mock instance.
+func NewMockProductVariantRepository(ctrl *gomock.Controller) *MockProductVariantRepository {
+	mock := &MockProductVariantRepository{ctrl: ctrl}
+	mock.recorder = &MockProductVariantRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProductVariantRepository) EXPECT() *MockProductVariantRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockProductVariantRepository) Delete(ctx context.Context, variant domain.ProductVariant) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, variant)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockProductVariantRepositoryMockRecorder) Delete(ctx, variant any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockProductVariantRepository)(nil).Delete), ctx, variant)
+}
+
+// FindById mocks base method.
+func (m *MockProductVariantRepository) FindById(ctx context.Context, variantId uint64) (domain.ProductVariant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindById", ctx, variantId)
+	ret0, _ := ret[0].(domain.ProductVariant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindById indicates an expected call of FindById.
+func (mr *MockProductVariantRepositoryMockRecorder) FindById(ctx, variantId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockProductVariantRepository)(nil).FindById), ctx, variantId)
+}
+
+// FindByProductId mocks base method.
+func (m *MockProductVariantRepository) FindByProductId(ctx context.Context, productId uint64) ([]domain.ProductVariant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByProductId", ctx, productId)
+	ret0, _ := ret[0].([]domain.ProductVariant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByProductId indicates an expected call of FindByProductId.
+func (mr *MockProductVariantRepositoryMockRecorder) FindByProductId(ctx, productId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByProductId", reflect.TypeOf((*MockProductVariantRepository)(nil).FindByProductId), ctx, productId)
+}
+
+// ReplaceOptions mocks base method.
+func (m *MockProductVariantRepository) ReplaceOptions(ctx context.Context, productId uint64, options []domain.ProductOption, variants []domain.ProductVariant) ([]domain.ProductVariant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceOptions", ctx, productId, options, variants)
+	ret0, _ := ret[0].([]domain.ProductVariant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReplaceOptions indicates an expected call of ReplaceOptions.
+func (mr *MockProductVariantRepositoryMockRecorder) ReplaceOptions(ctx, productId, options, variants any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceOptions", reflect.TypeOf((*MockProductVariantRepository)(nil).ReplaceOptions), ctx, productId, options, variants)
+}
+
+// Save mocks base method.
+func (m *MockProductVariantRepository) Save(ctx context.Context, variant domain.ProductVariant) (domain.ProductVariant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, variant)
+	ret0, _ := ret[0].(domain.ProductVariant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockProductVariantRepositoryMockRecorder) Save(ctx, variant any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockProductVariantRepository)(nil).Save), ctx, variant)
+}
+
+// Update mocks base method.
+func (m *MockProductVariantRepository) Update(ctx context.Context, variant domain.ProductVariant) (domain.ProductVariant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, variant)
+	ret0, _ := ret[0].(domain.ProductVariant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockProductVariantRepositoryMockRecorder) Update(ctx, variant any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockProductVariantRepository)(nil).Update), ctx, variant)
+}
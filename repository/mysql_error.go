@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-sql-driver/mysql"
+)
+
+// MySQL error numbers of a unique index violation, and of a transaction that lost a lock wait
+// or was picked as a deadlock victim
+const (
+	mysqlDuplicateEntry  = 1062
+	mysqlLockWaitTimeout = 1205
+	mysqlDeadlock        = 1213
+)
+
+// duplicateEntry reports a unique index violation as sentinel, keeping the MySQL message that
+// names the entry and index; any other error is returned as it is
+func duplicateEntry(err error, sentinel error) error {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry {
+		return fmt.Errorf("%w: %s", sentinel, mysqlErr.Message)
+	}
+	return err
+}
+
+// lockConflict reports a transaction that lost a row lock to a concurrent one as ErrVersionConflict
+func lockConflict(err error) error {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && (mysqlErr.Number == mysqlDeadlock || mysqlErr.Number == mysqlLockWaitTimeout) {
+		return fmt.Errorf("%w: %s", ErrVersionConflict, mysqlErr.Message)
+	}
+	return err
+}
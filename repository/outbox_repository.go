@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"time"
+)
+
+type OutboxRepository interface {
+	Save(ctx context.Context, events []domain.OutboxEvent) error
+	FindUndispatched(ctx context.Context, limit int) ([]domain.OutboxEvent, error)
+	MarkDispatched(ctx context.Context, eventId uint64, dispatchedAt time.Time) error
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"gorm.io/gorm"
+	"time"
+)
+
+type OutboxRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &OutboxRepositoryImpl{db: db}
+}
+
+// Save events; called with the ctx of a Transactor so they commit or roll back with the change they describe
+func (repository *OutboxRepositoryImpl) Save(ctx context.Context, events []domain.OutboxEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return dbFromContext(ctx, repository.db).Create(&events).Error
+}
+
+// FindUndispatched - Get the oldest events not yet fanned out to webhooks
+func (repository *OutboxRepositoryImpl) FindUndispatched(ctx context.Context, limit int) ([]domain.OutboxEvent, error) {
+	var events []domain.OutboxEvent
+	err := dbFromContext(ctx, repository.db).
+		Where("dispatched_at IS NULL").
+		Order("id").
+		Limit(limit).
+		Find(&events).Error
+	return events, err
+}
+
+// MarkDispatched - Record that deliveries were created for an event
+func (repository *OutboxRepositoryImpl) MarkDispatched(ctx context.Context, eventId uint64, dispatchedAt time.Time) error {
+	return dbFromContext(ctx, repository.db).Model(&domain.OutboxEvent{}).
+		Where("id = ?", eventId).
+		Update("dispatched_at", dispatchedAt).Error
+}
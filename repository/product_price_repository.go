@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"time"
+)
+
+type ProductPriceRepository interface {
+	Save(ctx context.Context, price domain.ProductPrice) (domain.ProductPrice, error)
+	Delete(ctx context.Context, price domain.ProductPrice) error
+	FindById(ctx context.Context, priceId uint64) (domain.ProductPrice, error)
+	FindByProductId(ctx context.Context, productId uint64) ([]domain.ProductPrice, error)
+	FindDue(ctx context.Context, now time.Time) ([]domain.ProductPrice, error)
+	Apply(ctx context.Context, price domain.ProductPrice, appliedAt time.Time) (bool, error)
+}
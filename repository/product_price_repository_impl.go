@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"gorm.io/gorm"
+	"time"
+)
+
+type ProductPriceRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewProductPriceRepository(db *gorm.DB) ProductPriceRepository {
+	return &ProductPriceRepositoryImpl{db: db}
+}
+
+// Save price change
+func (repository *ProductPriceRepositoryImpl) Save(ctx context.Context, price domain.ProductPrice) (domain.ProductPrice, error) {
+	if err := dbFromContext(ctx, repository.db).Create(&price).Error; err != nil {
+		return domain.ProductPrice{}, err
+	}
+	return price, nil
+}
+
+// Delete price change
+func (repository *ProductPriceRepositoryImpl) Delete(ctx context.Context, price domain.ProductPrice) error {
+	if err := dbFromContext(ctx, repository.db).Delete(&price).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindById - Get price change by ID
+func (repository *ProductPriceRepositoryImpl) FindById(ctx context.Context, priceId uint64) (domain.ProductPrice, error) {
+	var price domain.ProductPrice
+	err := dbFromContext(ctx, repository.db).First(&price, priceId).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return price, fmt.Errorf("price is not found: %w", err)
+	}
+	return price, err
+}
+
+// FindByProductId - Get applied and pending price changes of a product, newest first
+func (repository *ProductPriceRepositoryImpl) FindByProductId(ctx context.Context, productId uint64) ([]domain.ProductPrice, error) {
+	var prices []domain.ProductPrice
+	err := dbFromContext(ctx, repository.db).
+		Where("product_id = ?", productId).
+		Order("effective_from DESC, id DESC").
+		Find(&prices).Error
+	return prices, err
+}
+
+// FindDue - Get pending price changes whose effective time has come, oldest first
+func (repository *ProductPriceRepositoryImpl) FindDue(ctx context.Context, now time.Time) ([]domain.ProductPrice, error) {
+	var prices []domain.ProductPrice
+	err := dbFromContext(ctx, repository.db).
+		Where("applied_at IS NULL AND effective_from <= ?", now).
+		Order("effective_from, id").
+		Find(&prices).Error
+	return prices, err
+}
+
+// Apply - Set the product price from a pending change. Returns false when the change
+// was already applied, e.g. by another instance of the scheduler.
+func (repository *ProductPriceRepositoryImpl) Apply(ctx context.Context, price domain.ProductPrice, appliedAt time.Time) (bool, error) {
+	applied := false
+	err := dbFromContext(ctx, repository.db).Transaction(func(tx *gorm.DB) error {
+		var product domain.Product
+		if err := tx.Select("id", "product_price_amount", "product_price_currency").First(&product, price.ProductId).Error; err != nil {
+			return err
+		}
+
+		claimed := map[string]interface{}{"applied_at": appliedAt}
+		if product.Price.Currency == price.Price.Currency {
+			claimed["previous_price"] = product.Price.Amount
+		}
+		claim := tx.Model(&domain.ProductPrice{}).
+			Where("id = ? AND applied_at IS NULL", price.Id).
+			Updates(claimed)
+		if claim.Error != nil {
+			return claim.Error
+		}
+		if claim.RowsAffected == 0 {
+			return nil
+		}
+
+		if err := tx.Model(&domain.Product{}).Where("id = ?", price.ProductId).Updates(map[string]interface{}{
+			"product_price_amount":   price.Price.Amount,
+			"product_price_currency": price.Price.Currency,
+			"version":                gorm.Expr("version + 1"),
+		}).Error; err != nil {
+			return err
+		}
+		applied = true
+		return nil
+	})
+	return applied, err
+}
@@ -3,12 +3,21 @@ package repository
 import (
 	"context"
 	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"time"
 )
 
 type ProductRepository interface {
 	Save(ctx context.Context, product domain.Product) (domain.Product, error)
 	Update(ctx context.Context, product domain.Product) (domain.Product, error)
 	Delete(ctx context.Context, product domain.Product) error
+	Restore(ctx context.Context, productId uint64) error
+	Touch(ctx context.Context, productId uint64) error
+	Purge(ctx context.Context, before time.Time) (int64, error)
 	FindById(ctx context.Context, productId uint64) (domain.Product, error)
 	FindAll(ctx context.Context) ([]domain.Product, error)
+	FindByCategoryTree(ctx context.Context, categoryId uint64) ([]domain.Product, error)
+	FindByBarcode(ctx context.Context, code string) (domain.ProductBarcode, error)
+	FindBySKUs(ctx context.Context, skus []string) ([]domain.Product, error)
+	Import(ctx context.Context, products []domain.Product) ([]domain.Product, error)
+	FindInBatches(ctx context.Context, filter domain.ProductFilter, batchSize int, fn func(products []domain.Product) error) error
 }
@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"maps"
+	"slices"
+	"time"
+)
+
+// CachedProductRepository answers FindById from a Cache and invalidates the product on every write
+type CachedProductRepository struct {
+	ProductRepository
+	Cache *Cache[uint64, domain.Product]
+}
+
+func NewCachedProductRepository(productRepository ProductRepository, cache *Cache[uint64, domain.Product]) ProductRepository {
+	return &CachedProductRepository{
+		ProductRepository: productRepository,
+		Cache:             cache,
+	}
+}
+
+func (repository *CachedProductRepository) Save(ctx context.Context, product domain.Product) (domain.Product, error) {
+	savedProduct, err := repository.ProductRepository.Save(ctx, product)
+	invalidateProduct(ctx, repository.Cache, savedProduct.ProductID)
+	return savedProduct, err
+}
+
+func (repository *CachedProductRepository) Update(ctx context.Context, product domain.Product) (domain.Product, error) {
+	defer invalidateProduct(ctx, repository.Cache, product.ProductID)
+	return repository.ProductRepository.Update(ctx, product)
+}
+
+func (repository *CachedProductRepository) Delete(ctx context.Context, product domain.Product) error {
+	defer invalidateProduct(ctx, repository.Cache, product.ProductID)
+	return repository.ProductRepository.Delete(ctx, product)
+}
+
+func (repository *CachedProductRepository) Restore(ctx context.Context, productId uint64) error {
+	defer invalidateProduct(ctx, repository.Cache, productId)
+	return repository.ProductRepository.Restore(ctx, productId)
+}
+
+func (repository *CachedProductRepository) Touch(ctx context.Context, productId uint64) error {
+	defer invalidateProduct(ctx, repository.Cache, productId)
+	return repository.ProductRepository.Touch(ctx, productId)
+}
+
+func (repository *CachedProductRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	defer repository.Cache.Clear()
+	return repository.ProductRepository.Purge(ctx, before)
+}
+
+// FindById - Get product by ID, from the cache unless the read has to see the database
+func (repository *CachedProductRepository) FindById(ctx context.Context, productId uint64) (domain.Product, error) {
+	if !cacheable(ctx) {
+		return repository.ProductRepository.FindById(ctx, productId)
+	}
+	product, err := repository.Cache.Get(ctx, productId, func(ctx context.Context) (domain.Product, error) {
+		return repository.ProductRepository.FindById(ctx, productId)
+	})
+	if err != nil {
+		return domain.Product{}, err
+	}
+	return cloneProduct(product), nil
+}
+
+func (repository *CachedProductRepository) Import(ctx context.Context, products []domain.Product) ([]domain.Product, error) {
+	defer func() {
+		for _, product := range products {
+			invalidateProduct(ctx, repository.Cache, product.ProductID)
+		}
+	}()
+	return repository.ProductRepository.Import(ctx, products)
+}
+
+// CachedProductVariantRepository invalidates the cached product whose variants and options it writes
+type CachedProductVariantRepository struct {
+	ProductVariantRepository
+	Cache *Cache[uint64, domain.Product]
+}
+
+func NewCachedProductVariantRepository(productVariantRepository ProductVariantRepository, cache *Cache[uint64, domain.Product]) ProductVariantRepository {
+	return &CachedProductVariantRepository{
+		ProductVariantRepository: productVariantRepository,
+		Cache:                    cache,
+	}
+}
+
+func (repository *CachedProductVariantRepository) Save(ctx context.Context, variant domain.ProductVariant) (domain.ProductVariant, error) {
+	defer invalidateProduct(ctx, repository.Cache, variant.ProductId)
+	return repository.ProductVariantRepository.Save(ctx, variant)
+}
+
+func (repository *CachedProductVariantRepository) Update(ctx context.Context, variant domain.ProductVariant) (domain.ProductVariant, error) {
+	defer invalidateProduct(ctx, repository.Cache, variant.ProductId)
+	return repository.ProductVariantRepository.Update(ctx, variant)
+}
+
+func (repository *CachedProductVariantRepository) Delete(ctx context.Context, variant domain.ProductVariant) error {
+	defer invalidateProduct(ctx, repository.Cache, variant.ProductId)
+	return repository.ProductVariantRepository.Delete(ctx, variant)
+}
+
+func (repository *CachedProductVariantRepository) ReplaceOptions(ctx context.Context, productId uint64, options []domain.ProductOption, variants []domain.ProductVariant) ([]domain.ProductVariant, error) {
+	defer invalidateProduct(ctx, repository.Cache, productId)
+	return repository.ProductVariantRepository.ReplaceOptions(ctx, productId, options, variants)
+}
+
+// CachedProductPriceRepository invalidates the cached product a scheduled price is applied to
+type CachedProductPriceRepository struct {
+	ProductPriceRepository
+	Cache *Cache[uint64, domain.Product]
+}
+
+func NewCachedProductPriceRepository(productPriceRepository ProductPriceRepository, cache *Cache[uint64, domain.Product]) ProductPriceRepository {
+	return &CachedProductPriceRepository{
+		ProductPriceRepository: productPriceRepository,
+		Cache:                  cache,
+	}
+}
+
+func (repository *CachedProductPriceRepository) Apply(ctx context.Context, price domain.ProductPrice, appliedAt time.Time) (bool, error) {
+	defer invalidateProduct(ctx, repository.Cache, price.ProductId)
+	return repository.ProductPriceRepository.Apply(ctx, price, appliedAt)
+}
+
+// invalidateProduct forgets a product now, and again once the transaction the write joined has
+// committed, so a read in between cannot cache the row from before the commit
+func invalidateProduct(ctx context.Context, cache *Cache[uint64, domain.Product], productId uint64) {
+	if productId == 0 {
+		return
+	}
+	cache.Invalidate(productId)
+	if inTransaction(ctx) {
+		afterCommit(ctx, func() { cache.Invalidate(productId) })
+	}
+}
+
+// cloneProduct copies the slices and maps of a cached product, so callers changing it do not change the cache
+func cloneProduct(product domain.Product) domain.Product {
+	product.Barcodes = slices.Clone(product.Barcodes)
+	product.Options = slices.Clone(product.Options)
+	for i := range product.Options {
+		product.Options[i].Values = slices.Clone(product.Options[i].Values)
+	}
+	product.Variants = slices.Clone(product.Variants)
+	for i := range product.Variants {
+		product.Variants[i].Options = maps.Clone(product.Variants[i].Options)
+		if price := product.Variants[i].PriceOverride; price != nil {
+			copied := *price
+			product.Variants[i].PriceOverride = &copied
+		}
+	}
+	return product
+}
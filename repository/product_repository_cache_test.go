@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/aronipurwanto/go-restful-api/helper"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+	"testing"
+	"time"
+)
+
+func TestCachedProductRepository(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockProductRepository(ctrl)
+	cache := NewCache[uint64, domain.Product]("product", 10, time.Minute)
+	repo := NewCachedProductRepository(mockRepo, cache)
+	ctx := context.Background()
+	product := domain.Product{ProductID: 1, Name: "Coffee", Version: 1, Barcodes: []domain.ProductBarcode{{Code: "8991234567890"}}}
+
+	// a miss loads once, a hit does not load
+	mockRepo.EXPECT().FindById(gomock.Any(), uint64(1)).Return(product, nil)
+	found, err := repo.FindById(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, product, found)
+
+	// changing what a caller got does not change the cache
+	found.Barcodes[0].Code = "changed"
+	found, err = repo.FindById(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "8991234567890", found.Barcodes[0].Code)
+
+	// not found is not cached
+	mockRepo.EXPECT().FindById(gomock.Any(), uint64(2)).Return(domain.Product{}, gorm.ErrRecordNotFound).Times(2)
+	_, err = repo.FindById(ctx, 2)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	_, err = repo.FindById(ctx, 2)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	// an update, even a failed one, invalidates
+	updated := product
+	updated.Name = "Espresso"
+	mockRepo.EXPECT().Update(ctx, updated).Return(domain.Product{}, errors.New("version conflict"))
+	_, err = repo.Update(ctx, updated)
+	assert.Error(t, err)
+	updated.Version = 2
+	mockRepo.EXPECT().FindById(gomock.Any(), uint64(1)).Return(updated, nil)
+	found, err = repo.FindById(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "Espresso", found.Name)
+
+	// ?include_deleted=true reads bypass the cache
+	includeDeleted := context.WithValue(ctx, helper.IncludeDeletedKey, true)
+	mockRepo.EXPECT().FindById(includeDeleted, uint64(1)).Return(updated, nil)
+	_, err = repo.FindById(includeDeleted, 1)
+	assert.NoError(t, err)
+
+	mockRepo.EXPECT().Delete(ctx, updated).Return(nil)
+	assert.NoError(t, repo.Delete(ctx, updated))
+	assert.Equal(t, 0, cache.Len())
+}
+
+func TestCachedProductRepositoryInTransaction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockProductRepository(ctrl)
+	mockVariantRepo := mocks.NewMockProductVariantRepository(ctrl)
+	mockPriceRepo := mocks.NewMockProductPriceRepository(ctrl)
+	cache := NewCache[uint64, domain.Product]("product", 10, time.Minute)
+	repo := NewCachedProductRepository(mockRepo, cache)
+	variantRepo := NewCachedProductVariantRepository(mockVariantRepo, cache)
+	priceRepo := NewCachedProductPriceRepository(mockPriceRepo, cache)
+
+	// the state Transactor.Transaction sets up for fn
+	var hooks []func()
+	ctx := context.WithValue(context.Background(), commitHooksKey{}, &hooks)
+	tx := context.WithValue(ctx, txKey{}, &gorm.DB{})
+
+	// reads inside the transaction go to the database
+	mockRepo.EXPECT().FindById(tx, uint64(1)).Return(domain.Product{ProductID: 1}, nil)
+	_, err := repo.FindById(tx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, cache.Len())
+
+	// writes of variants and applied prices invalidate their product, again after the commit
+	mockVariantRepo.EXPECT().Update(tx, domain.ProductVariant{Id: 4, ProductId: 1}).Return(domain.ProductVariant{Id: 4, ProductId: 1}, nil)
+	_, err = variantRepo.Update(tx, domain.ProductVariant{Id: 4, ProductId: 1})
+	assert.NoError(t, err)
+	mockPriceRepo.EXPECT().Apply(tx, domain.ProductPrice{ProductId: 2}, gomock.Any()).Return(true, nil)
+	_, err = priceRepo.Apply(tx, domain.ProductPrice{ProductId: 2}, time.Now())
+	assert.NoError(t, err)
+	assert.Len(t, hooks, 2)
+
+	// a read between the write and the commit caches the row from before the commit ...
+	mockRepo.EXPECT().FindById(gomock.Any(), uint64(1)).Return(domain.Product{ProductID: 1, Version: 1}, nil)
+	_, err = repo.FindById(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, cache.Len())
+
+	// ... which the commit invalidates
+	for _, hook := range hooks {
+		hook()
+	}
+	assert.Equal(t, 0, cache.Len())
+}
@@ -3,10 +3,21 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
+	"github.com/aronipurwanto/go-restful-api/helper"
 	"github.com/aronipurwanto/go-restful-api/model/domain"
 	"gorm.io/gorm"
+	"time"
 )
 
+// ErrDuplicateBarcode is returned when a barcode is already registered to a product;
+// barcode_code is the only unique column written with a product
+var ErrDuplicateBarcode = errors.New("barcode is already used")
+
+// ErrDeletedProductBarcode is returned when a barcode is still held by a soft-deleted product;
+// its barcodes stay in the unique index until the product is restored or purged
+var ErrDeletedProductBarcode = errors.New("barcode belongs to a deleted product")
+
 type ProductRepositoryImpl struct {
 	db *gorm.DB
 }
@@ -17,34 +28,162 @@ func NewProductRepository(db *gorm.DB) ProductRepository {
 
 // Save product
 func (repository *ProductRepositoryImpl) Save(ctx context.Context, product domain.Product) (domain.Product, error) {
-	if err := repository.db.WithContext(ctx).Create(&product).Error; err != nil {
-		return domain.Product{}, err
+	product.Version = 1
+	db := dbFromContext(ctx, repository.db)
+	if err := db.Create(&product).Error; err != nil {
+		return domain.Product{}, barcodeConflict(db, err, product.Barcodes)
 	}
 	return product, nil
 }
 
-// Update product
+// Update product if it still has the version it was read with, replacing its barcodes
+// with the given ones and recording a price change
 func (repository *ProductRepositoryImpl) Update(ctx context.Context, product domain.Product) (domain.Product, error) {
-	if err := repository.db.WithContext(ctx).Save(&product).Error; err != nil {
+	err := dbFromContext(ctx, repository.db).Transaction(func(tx *gorm.DB) error {
+		return updateProduct(ctx, tx, &product)
+	})
+	if err != nil {
 		return domain.Product{}, err
 	}
 	return product, nil
 }
 
-// Delete product
-func (repository *ProductRepositoryImpl) Delete(ctx context.Context, product domain.Product) error {
-	if err := repository.db.WithContext(ctx).Delete(&product).Error; err != nil {
+// updateProduct saves product inside tx, records a price change and replaces its barcodes;
+// it fails with ErrVersionConflict when product.Version is no longer current
+func updateProduct(ctx context.Context, tx *gorm.DB, product *domain.Product) error {
+	var current domain.Product
+	if err := tx.Select("id", "product_price_amount", "product_price_currency").First(&current, product.ProductID).Error; err != nil {
 		return err
 	}
+
+	if err := updateVersioned(tx, product, &product.Version); err != nil {
+		return err
+	}
+
+	// Keep the price history in the same transaction as the price itself
+	if current.Price != product.Price {
+		now := time.Now()
+		priceChange := domain.ProductPrice{
+			ProductId:     product.ProductID,
+			Price:         product.Price,
+			EffectiveFrom: now,
+			AppliedAt:     &now,
+			EmployeeId:    helper.EmployeeIdFromContext(ctx),
+		}
+		// The previous amount is only meaningful when the currency stays the same
+		if current.Price.Currency == product.Price.Currency {
+			priceChange.PreviousPrice = &current.Price.Amount
+		}
+		if err := tx.Create(&priceChange).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Where("product_id = ?", product.ProductID).Delete(&domain.ProductBarcode{}).Error; err != nil {
+		return err
+	}
+	if len(product.Barcodes) == 0 {
+		return nil
+	}
+
+	for i := range product.Barcodes {
+		product.Barcodes[i].Id = 0
+		product.Barcodes[i].ProductId = product.ProductID
+	}
+	if err := tx.Omit("Product").Create(&product.Barcodes).Error; err != nil {
+		return barcodeConflict(tx, err, product.Barcodes)
+	}
 	return nil
 }
 
+// barcodeConflict reports a unique index violation on barcodes as ErrDuplicateBarcode, or as
+// ErrDeletedProductBarcode when one of the codes is held by a soft-deleted product
+func barcodeConflict(db *gorm.DB, err error, barcodes []domain.ProductBarcode) error {
+	err = duplicateEntry(err, ErrDuplicateBarcode)
+	if !errors.Is(err, ErrDuplicateBarcode) || len(barcodes) == 0 {
+		return err
+	}
+
+	codes := make([]string, len(barcodes))
+	for i, barcode := range barcodes {
+		codes[i] = barcode.Code
+	}
+	var owner domain.ProductBarcode
+	result := db.Joins("JOIN products ON products.id = product_barcodes.product_id").
+		Where("product_barcodes.barcode_code IN ? AND products.deleted_at IS NOT NULL", codes).
+		Limit(1).Find(&owner)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return err
+	}
+	return fmt.Errorf("%w: barcode %s belongs to deleted product %d", ErrDeletedProductBarcode, owner.Code, owner.ProductId)
+}
+
+// Delete product if it still has the version it was read with, cancelling its pending price changes
+func (repository *ProductRepositoryImpl) Delete(ctx context.Context, product domain.Product) error {
+	return dbFromContext(ctx, repository.db).Transaction(func(tx *gorm.DB) error {
+		if err := deleteVersioned(tx, &product, product.Version); err != nil {
+			return err
+		}
+		return tx.Where("product_id = ? AND applied_at IS NULL", product.ProductID).Delete(&domain.ProductPrice{}).Error
+	})
+}
+
+// Touch bumps the version of a product whose variants or options changed, so its ETag changes with them
+func (repository *ProductRepositoryImpl) Touch(ctx context.Context, productId uint64) error {
+	result := dbFromContext(ctx, repository.db).Model(&domain.Product{}).Where("id = ?", productId).
+		Update("version", gorm.Expr("version + 1"))
+	if result.Error == nil && result.RowsAffected == 0 {
+		return fmt.Errorf("product is not found: %w", gorm.ErrRecordNotFound)
+	}
+	return result.Error
+}
+
+// Restore - Undelete a soft-deleted product; its barcodes, options and variants are never soft-deleted
+// and so come back with it
+func (repository *ProductRepositoryImpl) Restore(ctx context.Context, productId uint64) error {
+	err := restoreDeletedChild(dbFromContext(ctx, repository.db), &domain.Product{}, productId, "category_id", &domain.Category{})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("deleted product is not found: %w", err)
+	} else if errors.Is(err, ErrParentDeleted) {
+		return fmt.Errorf("category of the product is deleted: %w", err)
+	}
+	return err
+}
+
+// Purge - Permanently remove products soft-deleted before the given time, with the rows that belong to them
+func (repository *ProductRepositoryImpl) Purge(ctx context.Context, before time.Time) (int64, error) {
+	var purged int64
+	err := dbFromContext(ctx, repository.db).Transaction(func(tx *gorm.DB) error {
+		expired := tx.Unscoped().Model(&domain.Product{}).Select("id").Where("deleted_at < ?", before)
+		for _, child := range []interface{}{&domain.ProductBarcode{}, &domain.ProductVariant{}, &domain.ProductOption{}, &domain.ProductPrice{}} {
+			if err := tx.Where("product_id IN (?)", expired).Delete(child).Error; err != nil {
+				return err
+			}
+		}
+
+		var err error
+		purged, err = purgeDeleted(tx, &domain.Product{}, before)
+		return err
+	})
+	return purged, err
+}
+
+// withAssociations preloads barcodes, options (in display order) and variants
+func (repository *ProductRepositoryImpl) withAssociations(ctx context.Context) *gorm.DB {
+	return dbFromContext(ctx, repository.db).
+		Preload("Barcodes").
+		Preload("Options", func(db *gorm.DB) *gorm.DB {
+			return db.Order("option_position")
+		}).
+		Preload("Variants")
+}
+
 // FindById - Get product by ID
 func (repository *ProductRepositoryImpl) FindById(ctx context.Context, productId uint64) (domain.Product, error) {
 	var product domain.Product
-	err := repository.db.WithContext(ctx).First(&product, productId).Error
+	err := repository.withAssociations(ctx).First(&product, productId).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
-		return product, errors.New("product is not found")
+		return product, fmt.Errorf("product is not found: %w", err)
 	}
 	return product, err
 }
@@ -52,6 +191,74 @@ func (repository *ProductRepositoryImpl) FindById(ctx context.Context, productId
 // FindAll - Get all products
 func (repository *ProductRepositoryImpl) FindAll(ctx context.Context) ([]domain.Product, error) {
 	var products []domain.Product
-	err := repository.db.WithContext(ctx).Find(&products).Error
+	err := repository.withAssociations(ctx).Find(&products).Error
 	return products, err
 }
+
+// FindByCategoryTree - Get all products of a category and of its descendant categories
+func (repository *ProductRepositoryImpl) FindByCategoryTree(ctx context.Context, categoryId uint64) ([]domain.Product, error) {
+	var products []domain.Product
+	err := repository.withAssociations(ctx).
+		Where("category_id IN (?)", repository.db.Raw(categorySubtreeSQL, categoryId)).
+		Find(&products).Error
+	return products, err
+}
+
+// FindByBarcode - Get a barcode together with its product in a single joined query; barcodes of deleted products are not found
+func (repository *ProductRepositoryImpl) FindByBarcode(ctx context.Context, code string) (domain.ProductBarcode, error) {
+	var barcode domain.ProductBarcode
+	err := dbFromContext(ctx, repository.db).
+		InnerJoins("Product").
+		Where("product_barcodes.barcode_code = ?", code).
+		First(&barcode).Error
+	return barcode, err
+}
+
+// FindBySKUs - Get all products whose SKU is one of skus, with their barcodes
+func (repository *ProductRepositoryImpl) FindBySKUs(ctx context.Context, skus []string) ([]domain.Product, error) {
+	var products []domain.Product
+	if len(skus) == 0 {
+		return products, nil
+	}
+	err := dbFromContext(ctx, repository.db).
+		Preload("Barcodes").
+		Where("product_sku IN ?", skus).
+		Find(&products).Error
+	return products, err
+}
+
+// Import creates products without an ID and updates the others, all in one transaction
+func (repository *ProductRepositoryImpl) Import(ctx context.Context, products []domain.Product) ([]domain.Product, error) {
+	err := dbFromContext(ctx, repository.db).Transaction(func(tx *gorm.DB) error {
+		for i := range products {
+			if products[i].ProductID == 0 {
+				products[i].Version = 1
+				if err := tx.Create(&products[i]).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			if err := updateProduct(ctx, tx, &products[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+// FindInBatches - Walk the matching products in primary key order, batchSize at a time
+func (repository *ProductRepositoryImpl) FindInBatches(ctx context.Context, filter domain.ProductFilter, batchSize int, fn func(products []domain.Product) error) error {
+	query := dbFromContext(ctx, repository.db).Preload("Category").Preload("Barcodes")
+	if filter.CategoryId != nil {
+		query = query.Where("category_id IN (?)", repository.db.Raw(categorySubtreeSQL, *filter.CategoryId))
+	}
+
+	var products []domain.Product
+	return query.FindInBatches(&products, batchSize, func(tx *gorm.DB, batch int) error {
+		return fn(products)
+	}).Error
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/money"
 	"github.com/aronipurwanto/go-restful-api/repository/mocks"
+	"github.com/go-sql-driver/mysql"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 	"testing"
@@ -27,13 +29,13 @@ func TestProductRepository(t *testing.T) {
 		{
 			name: "Save Success",
 			mock: func() {
-				product := domain.Product{ProductID: 1, Name: "Name", Description: "Test", Price: 1, StockQty: 1, CategoryId: 1, SKU: "test", TaxRate: 1.0, Category: domain.Category{Id: 1, Name: "Electronics"}}
+				product := domain.Product{ProductID: 1, Name: "Name", Description: "Test", Price: money.FromInt(1, "IDR"), StockQty: 1, CategoryId: 1, SKU: "test", TaxRate: money.AmountFromInt(1), Category: domain.Category{Id: 1, Name: "Electronics"}}
 				repo.EXPECT().Save(ctx, product).Return(product, nil)
 			},
 			method: func() (interface{}, error) {
-				return repo.Save(ctx, domain.Product{ProductID: 1, Name: "Name", Description: "Test", Price: 1, StockQty: 1, CategoryId: 1, SKU: "test", TaxRate: 1.0, Category: domain.Category{Id: 1, Name: "Electronics"}})
+				return repo.Save(ctx, domain.Product{ProductID: 1, Name: "Name", Description: "Test", Price: money.FromInt(1, "IDR"), StockQty: 1, CategoryId: 1, SKU: "test", TaxRate: money.AmountFromInt(1), Category: domain.Category{Id: 1, Name: "Electronics"}})
 			},
-			expect:    domain.Product{ProductID: 1, Name: "Name", Description: "Test", Price: 1, StockQty: 1, CategoryId: 1, SKU: "test", TaxRate: 1.0, Category: domain.Category{Id: 1, Name: "Electronics"}},
+			expect:    domain.Product{ProductID: 1, Name: "Name", Description: "Test", Price: money.FromInt(1, "IDR"), StockQty: 1, CategoryId: 1, SKU: "test", TaxRate: money.AmountFromInt(1), Category: domain.Category{Id: 1, Name: "Electronics"}},
 			expectErr: false,
 		},
 		{
@@ -50,24 +52,24 @@ func TestProductRepository(t *testing.T) {
 		{
 			name: "Update Success",
 			mock: func() {
-				product := domain.Product{ProductID: 1, Name: "Updated Name", Description: "Test", Price: 1, StockQty: 1, CategoryId: 1, SKU: "test", TaxRate: 1.0, Category: domain.Category{Id: 1, Name: "Electronics"}}
+				product := domain.Product{ProductID: 1, Name: "Updated Name", Description: "Test", Price: money.FromInt(1, "IDR"), StockQty: 1, CategoryId: 1, SKU: "test", TaxRate: money.AmountFromInt(1), Category: domain.Category{Id: 1, Name: "Electronics"}}
 				repo.EXPECT().Update(ctx, product).Return(product, nil)
 			},
 			method: func() (interface{}, error) {
-				return repo.Update(ctx, domain.Product{ProductID: 1, Name: "Updated Name", Description: "Test", Price: 1, StockQty: 1, CategoryId: 1, SKU: "test", TaxRate: 1.0, Category: domain.Category{Id: 1, Name: "Electronics"}})
+				return repo.Update(ctx, domain.Product{ProductID: 1, Name: "Updated Name", Description: "Test", Price: money.FromInt(1, "IDR"), StockQty: 1, CategoryId: 1, SKU: "test", TaxRate: money.AmountFromInt(1), Category: domain.Category{Id: 1, Name: "Electronics"}})
 			},
-			expect:    domain.Product{ProductID: 1, Name: "Updated Name", Description: "Test", Price: 1, StockQty: 1, CategoryId: 1, SKU: "test", TaxRate: 1.0, Category: domain.Category{Id: 1, Name: "Electronics"}},
+			expect:    domain.Product{ProductID: 1, Name: "Updated Name", Description: "Test", Price: money.FromInt(1, "IDR"), StockQty: 1, CategoryId: 1, SKU: "test", TaxRate: money.AmountFromInt(1), Category: domain.Category{Id: 1, Name: "Electronics"}},
 			expectErr: false,
 		},
 		{
 			name: "FindById Success",
 			mock: func() {
-				repo.EXPECT().FindById(ctx, uint64(1)).Return(domain.Product{ProductID: 1, Name: "Name", Description: "Test", Price: 1, StockQty: 1, CategoryId: 1, SKU: "test", TaxRate: 1.0, Category: domain.Category{Id: 1, Name: "Electronics"}}, nil)
+				repo.EXPECT().FindById(ctx, uint64(1)).Return(domain.Product{ProductID: 1, Name: "Name", Description: "Test", Price: money.FromInt(1, "IDR"), StockQty: 1, CategoryId: 1, SKU: "test", TaxRate: money.AmountFromInt(1), Category: domain.Category{Id: 1, Name: "Electronics"}}, nil)
 			},
 			method: func() (interface{}, error) {
 				return repo.FindById(ctx, 1)
 			},
-			expect:    domain.Product{ProductID: 1, Name: "Name", Description: "Test", Price: 1, StockQty: 1, CategoryId: 1, SKU: "test", TaxRate: 1.0, Category: domain.Category{Id: 1, Name: "Electronics"}},
+			expect:    domain.Product{ProductID: 1, Name: "Name", Description: "Test", Price: money.FromInt(1, "IDR"), StockQty: 1, CategoryId: 1, SKU: "test", TaxRate: money.AmountFromInt(1), Category: domain.Category{Id: 1, Name: "Electronics"}},
 			expectErr: false,
 		},
 		{
@@ -84,12 +86,12 @@ func TestProductRepository(t *testing.T) {
 		{
 			name: "FindAll Success",
 			mock: func() {
-				repo.EXPECT().FindAll(ctx).Return([]domain.Product{{ProductID: 1, Name: "Name", Description: "Test", Price: 1, StockQty: 1, CategoryId: 1, SKU: "test", TaxRate: 1.0, Category: domain.Category{Id: 1, Name: "Electronics"}}}, nil)
+				repo.EXPECT().FindAll(ctx).Return([]domain.Product{{ProductID: 1, Name: "Name", Description: "Test", Price: money.FromInt(1, "IDR"), StockQty: 1, CategoryId: 1, SKU: "test", TaxRate: money.AmountFromInt(1), Category: domain.Category{Id: 1, Name: "Electronics"}}}, nil)
 			},
 			method: func() (interface{}, error) {
 				return repo.FindAll(ctx)
 			},
-			expect:    []domain.Product{{ProductID: 1, Name: "Name", Description: "Test", Price: 1, StockQty: 1, CategoryId: 1, SKU: "test", TaxRate: 1.0, Category: domain.Category{Id: 1, Name: "Electronics"}}},
+			expect:    []domain.Product{{ProductID: 1, Name: "Name", Description: "Test", Price: money.FromInt(1, "IDR"), StockQty: 1, CategoryId: 1, SKU: "test", TaxRate: money.AmountFromInt(1), Category: domain.Category{Id: 1, Name: "Electronics"}}},
 			expectErr: false,
 		},
 		{
@@ -119,3 +121,12 @@ func TestProductRepository(t *testing.T) {
 		})
 	}
 }
+
+func TestDuplicateEntry(t *testing.T) {
+	duplicate := &mysql.MySQLError{Number: 1062, Message: "Duplicate entry '8991234567890' for key 'product_barcodes.barcode_code'"}
+	assert.ErrorIs(t, duplicateEntry(duplicate, ErrDuplicateBarcode), ErrDuplicateBarcode)
+
+	other := &mysql.MySQLError{Number: 1452, Message: "Cannot add or update a child row"}
+	assert.Equal(t, error(other), duplicateEntry(other, ErrDuplicateBarcode))
+	assert.NoError(t, duplicateEntry(nil, ErrDuplicateBarcode))
+}
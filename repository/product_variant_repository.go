@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+)
+
+type ProductVariantRepository interface {
+	Save(ctx context.Context, variant domain.ProductVariant) (domain.ProductVariant, error)
+	Update(ctx context.Context, variant domain.ProductVariant) (domain.ProductVariant, error)
+	Delete(ctx context.Context, variant domain.ProductVariant) error
+	FindById(ctx context.Context, variantId uint64) (domain.ProductVariant, error)
+	FindByProductId(ctx context.Context, productId uint64) ([]domain.ProductVariant, error)
+	ReplaceOptions(ctx context.Context, productId uint64, options []domain.ProductOption, variants []domain.ProductVariant) ([]domain.ProductVariant, error)
+}
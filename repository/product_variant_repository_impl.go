@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"gorm.io/gorm"
+)
+
+// ErrDuplicateVariant is returned when a variant SKU, or the option combination of a variant
+// within its product, is already used
+var ErrDuplicateVariant = errors.New("variant already exists")
+
+type ProductVariantRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewProductVariantRepository(db *gorm.DB) ProductVariantRepository {
+	return &ProductVariantRepositoryImpl{db: db}
+}
+
+// Save variant
+func (repository *ProductVariantRepositoryImpl) Save(ctx context.Context, variant domain.ProductVariant) (domain.ProductVariant, error) {
+	if err := dbFromContext(ctx, repository.db).Create(&variant).Error; err != nil {
+		return domain.ProductVariant{}, duplicateEntry(err, ErrDuplicateVariant)
+	}
+	return variant, nil
+}
+
+// Update variant
+func (repository *ProductVariantRepositoryImpl) Update(ctx context.Context, variant domain.ProductVariant) (domain.ProductVariant, error) {
+	if err := dbFromContext(ctx, repository.db).Save(&variant).Error; err != nil {
+		return domain.ProductVariant{}, duplicateEntry(err, ErrDuplicateVariant)
+	}
+	return variant, nil
+}
+
+// Delete variant
+func (repository *ProductVariantRepositoryImpl) Delete(ctx context.Context, variant domain.ProductVariant) error {
+	if err := dbFromContext(ctx, repository.db).Delete(&variant).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindById - Get variant by ID
+func (repository *ProductVariantRepositoryImpl) FindById(ctx context.Context, variantId uint64) (domain.ProductVariant, error) {
+	var variant domain.ProductVariant
+	err := dbFromContext(ctx, repository.db).First(&variant, variantId).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return variant, fmt.Errorf("variant is not found: %w", err)
+	}
+	return variant, err
+}
+
+// FindByProductId - Get all variants of a product
+func (repository *ProductVariantRepositoryImpl) FindByProductId(ctx context.Context, productId uint64) ([]domain.ProductVariant, error) {
+	var variants []domain.ProductVariant
+	err := dbFromContext(ctx, repository.db).Where("product_id = ?", productId).Order("id").Find(&variants).Error
+	return variants, err
+}
+
+// ReplaceOptions swaps the option dimensions of a product and keeps only the given variants.
+// Variants with an Id are kept as they are, variants without one are created.
+func (repository *ProductVariantRepositoryImpl) ReplaceOptions(ctx context.Context, productId uint64, options []domain.ProductOption, variants []domain.ProductVariant) ([]domain.ProductVariant, error) {
+	err := dbFromContext(ctx, repository.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("product_id = ?", productId).Delete(&domain.ProductOption{}).Error; err != nil {
+			return err
+		}
+		for i := range options {
+			options[i].Id = 0
+			options[i].ProductId = productId
+		}
+		if len(options) > 0 {
+			if err := tx.Create(&options).Error; err != nil {
+				return err
+			}
+		}
+
+		var keepIds []uint64
+		var created []domain.ProductVariant
+		for _, variant := range variants {
+			if variant.Id != 0 {
+				keepIds = append(keepIds, variant.Id)
+			} else {
+				variant.ProductId = productId
+				created = append(created, variant)
+			}
+		}
+
+		removed := tx.Where("product_id = ?", productId)
+		if len(keepIds) > 0 {
+			removed = removed.Where("id NOT IN ?", keepIds)
+		}
+		if err := removed.Delete(&domain.ProductVariant{}).Error; err != nil {
+			return err
+		}
+
+		if len(created) > 0 {
+			return duplicateEntry(tx.Create(&created).Error, ErrDuplicateVariant)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return repository.FindByProductId(ctx, productId)
+}
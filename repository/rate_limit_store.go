@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"time"
+)
+
+// RateLimitStore keeps the token buckets of the rate limiter. The in-memory store limits each
+// instance on its own; a store on a shared backend would limit clients across instances.
+type RateLimitStore interface {
+	Take(ctx context.Context, key string, limit domain.RateLimit, now time.Time) (domain.RateLimitResult, error)
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"math"
+	"sync"
+	"time"
+)
+
+// rateLimitSweepInterval is how often buckets that have refilled completely are forgotten
+const rateLimitSweepInterval = time.Minute
+
+type rateLimitBucket struct {
+	tokens  float64
+	updated time.Time
+	full    time.Time // when the bucket has refilled completely
+}
+
+type MemoryRateLimitStore struct {
+	mutex     sync.Mutex
+	buckets   map[string]*rateLimitBucket
+	lastSweep time.Time
+}
+
+func NewMemoryRateLimitStore() RateLimitStore {
+	return &MemoryRateLimitStore{
+		buckets: make(map[string]*rateLimitBucket),
+	}
+}
+
+// Take a token from the bucket of key; a bucket seen for the first time starts full
+func (store *MemoryRateLimitStore) Take(ctx context.Context, key string, limit domain.RateLimit, now time.Time) (domain.RateLimitResult, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	store.sweep(now)
+
+	capacity := float64(limit.Limit)
+	perToken := limit.Period / time.Duration(limit.Limit)
+
+	bucket, ok := store.buckets[key]
+	if !ok {
+		bucket = &rateLimitBucket{tokens: capacity, updated: now}
+		store.buckets[key] = bucket
+	}
+	if elapsed := now.Sub(bucket.updated); elapsed > 0 {
+		bucket.tokens = math.Min(capacity, bucket.tokens+float64(elapsed)/float64(perToken))
+		bucket.updated = now
+	}
+
+	result := domain.RateLimitResult{}
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration((1 - bucket.tokens) * float64(perToken))
+	}
+	bucket.full = now.Add(time.Duration((capacity - bucket.tokens) * float64(perToken)))
+
+	result.Remaining = int(bucket.tokens)
+	result.Reset = bucket.full.Sub(now)
+	return result, nil
+}
+
+// sweep forgets the buckets that are full again, which is the state a new bucket starts in
+func (store *MemoryRateLimitStore) sweep(now time.Time) {
+	if now.Sub(store.lastSweep) < rateLimitSweepInterval {
+		return
+	}
+	store.lastSweep = now
+	for key, bucket := range store.buckets {
+		if !now.Before(bucket.full) {
+			delete(store.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestMemoryRateLimitStore(t *testing.T) {
+	store := NewMemoryRateLimitStore()
+	ctx := context.Background()
+	limit := domain.RateLimit{Limit: 2, Period: 10 * time.Second}
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	steps := []struct {
+		name     string
+		key      string
+		at       time.Duration
+		expected domain.RateLimitResult
+	}{
+		{
+			name:     "New bucket starts full",
+			key:      "a",
+			expected: domain.RateLimitResult{Allowed: true, Remaining: 1, Reset: 5 * time.Second},
+		},
+		{
+			name:     "Last token",
+			key:      "a",
+			expected: domain.RateLimitResult{Allowed: true, Remaining: 0, Reset: 10 * time.Second},
+		},
+		{
+			name:     "Empty bucket",
+			key:      "a",
+			at:       time.Second,
+			expected: domain.RateLimitResult{Allowed: false, Remaining: 0, Reset: 9 * time.Second, RetryAfter: 4 * time.Second},
+		},
+		{
+			name:     "Other clients have their own bucket",
+			key:      "b",
+			at:       time.Second,
+			expected: domain.RateLimitResult{Allowed: true, Remaining: 1, Reset: 5 * time.Second},
+		},
+		{
+			name:     "Refilled token",
+			key:      "a",
+			at:       5 * time.Second,
+			expected: domain.RateLimitResult{Allowed: true, Remaining: 0, Reset: 10 * time.Second},
+		},
+		{
+			name:     "Refill stops at the limit",
+			key:      "a",
+			at:       time.Hour,
+			expected: domain.RateLimitResult{Allowed: true, Remaining: 1, Reset: 5 * time.Second},
+		},
+	}
+
+	for _, step := range steps {
+		t.Run(step.name, func(t *testing.T) {
+			result, err := store.Take(ctx, step.key, limit, start.Add(step.at))
+			assert.NoError(t, err)
+			assert.Equal(t, step.expected, result)
+		})
+	}
+}
+
+func TestMemoryRateLimitStoreForgetsFullBuckets(t *testing.T) {
+	store := NewMemoryRateLimitStore().(*MemoryRateLimitStore)
+	ctx := context.Background()
+	limit := domain.RateLimit{Limit: 1, Period: time.Second}
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := store.Take(ctx, "a", limit, start)
+	assert.NoError(t, err)
+	_, err = store.Take(ctx, "b", limit, start.Add(time.Minute))
+	assert.NoError(t, err)
+
+	assert.NotContains(t, store.buckets, "a")
+	assert.Contains(t, store.buckets, "b")
+}
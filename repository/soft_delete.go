@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"time"
+)
+
+// ErrParentDeleted is returned when restoring a row that hangs off a row still soft-deleted
+var ErrParentDeleted = errors.New("parent is deleted")
+
+// restoreDeleted brings a soft-deleted row back and bumps its version, so clients holding
+// the ETag from before the delete cannot overwrite it; gorm.ErrRecordNotFound when no such row is deleted
+func restoreDeleted(db *gorm.DB, model interface{}, id uint64) error {
+	result := db.Unscoped().Model(model).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Updates(map[string]interface{}{"deleted_at": nil, "version": gorm.Expr("version + 1")})
+	if result.Error == nil && result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return result.Error
+}
+
+// restoreDeletedChild restores a soft-deleted row like restoreDeleted, but only while the row of parent
+// that parentColumn points at is live, so a restored row never hangs off a deleted one. A NULL
+// parentColumn is a root and always restored.
+func restoreDeletedChild(db *gorm.DB, model interface{}, id uint64, parentColumn string, parent interface{}) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var parentIds []*uint64
+		err := tx.Unscoped().Model(model).Where("id = ? AND deleted_at IS NOT NULL", id).Pluck(parentColumn, &parentIds).Error
+		if err != nil {
+			return err
+		}
+		if len(parentIds) == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if parentId := parentIds[0]; parentId != nil {
+			var live int64
+			err := tx.Unscoped().Model(parent).Where("id = ? AND deleted_at IS NULL", *parentId).Count(&live).Error
+			if err != nil {
+				return err
+			}
+			if live == 0 {
+				return ErrParentDeleted
+			}
+		}
+		return restoreDeleted(tx, model, id)
+	})
+}
+
+// purgeDeleted permanently removes the rows of model soft-deleted before the given time
+func purgeDeleted(db *gorm.DB, model interface{}, before time.Time) (int64, error) {
+	result := db.Unscoped().Where("deleted_at < ?", before).Delete(model)
+	return result.RowsAffected, result.Error
+}
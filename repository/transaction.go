@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"github.com/aronipurwanto/go-restful-api/helper"
+	"gorm.io/gorm"
+)
+
+type txKey struct{}
+
+// commitHooksKey holds the functions afterCommit deferred until the outermost transaction commits
+type commitHooksKey struct{}
+
+// Transactor runs a group of repository calls in a single database transaction
+type Transactor interface {
+	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type TransactorImpl struct {
+	db *gorm.DB
+}
+
+func NewTransactor(db *gorm.DB) Transactor {
+	return &TransactorImpl{db: db}
+}
+
+// Transaction commits when fn returns nil and rolls back otherwise; repositories
+// called with the ctx given to fn join the transaction
+func (transactor *TransactorImpl) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	hooks, nested := ctx.Value(commitHooksKey{}).(*[]func())
+	if !nested {
+		hooks = &[]func(){}
+		ctx = context.WithValue(ctx, commitHooksKey{}, hooks)
+	}
+
+	err := dbFromContext(ctx, transactor.db).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+	if err == nil && !nested {
+		for _, hook := range *hooks {
+			hook()
+		}
+	}
+	return err
+}
+
+// afterCommit runs fn once the transaction ctx carries has committed, or right away outside a transaction
+func afterCommit(ctx context.Context, fn func()) {
+	if hooks, ok := ctx.Value(commitHooksKey{}).(*[]func()); ok {
+		*hooks = append(*hooks, fn)
+		return
+	}
+	fn()
+}
+
+// inTransaction reports whether ctx carries a transaction started by Transactor
+func inTransaction(ctx context.Context) bool {
+	_, ok := ctx.Value(txKey{}).(*gorm.DB)
+	return ok
+}
+
+// dbFromContext returns the transaction started by Transactor if ctx carries one, or db otherwise.
+// Soft-deleted rows are only visible when the request asked for them with ?include_deleted=true.
+func dbFromContext(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		db = tx
+	}
+	db = db.WithContext(ctx)
+	if helper.IncludeDeletedFromContext(ctx) {
+		return db.Unscoped()
+	}
+	return db
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrVersionConflict is returned when a row was changed or removed after it was read
+var ErrVersionConflict = errors.New("row was modified by another request")
+
+// updateVersioned writes every column of model, but only while its row still holds
+// the version it was read with; version is incremented on success
+func updateVersioned(db *gorm.DB, model interface{}, version *uint64) error {
+	expected := *version
+	*version = expected + 1
+
+	result := db.Model(model).Select("*").Omit(clause.Associations).Where("version = ?", expected).Updates(model)
+	if result.Error == nil && result.RowsAffected == 0 {
+		result.Error = ErrVersionConflict
+	}
+	if result.Error != nil {
+		*version = expected
+	}
+	return lockConflict(result.Error)
+}
+
+// deleteVersioned removes model only while its row still holds version
+func deleteVersioned(db *gorm.DB, model interface{}, version uint64) error {
+	result := db.Where("version = ?", version).Delete(model)
+	if result.Error == nil && result.RowsAffected == 0 {
+		return ErrVersionConflict
+	}
+	return result.Error
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"time"
+)
+
+type WebhookRepository interface {
+	Save(ctx context.Context, webhook domain.Webhook) (domain.Webhook, error)
+	Update(ctx context.Context, webhook domain.Webhook) (domain.Webhook, error)
+	Delete(ctx context.Context, webhook domain.Webhook) error
+	FindById(ctx context.Context, webhookId uint64) (domain.Webhook, error)
+	FindAll(ctx context.Context) ([]domain.Webhook, error)
+	FindActive(ctx context.Context) ([]domain.Webhook, error)
+	SaveDeliveries(ctx context.Context, deliveries []domain.WebhookDelivery) error
+	UpdateDelivery(ctx context.Context, delivery domain.WebhookDelivery) error
+	FindDueDeliveries(ctx context.Context, now time.Time, limit int) ([]domain.WebhookDelivery, error)
+	FindDeliveries(ctx context.Context, webhookId uint64, limit int) ([]domain.WebhookDelivery, error)
+	FindDeliveryById(ctx context.Context, deliveryId uint64) (domain.WebhookDelivery, error)
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type WebhookRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &WebhookRepositoryImpl{db: db}
+}
+
+// Save webhook
+func (repository *WebhookRepositoryImpl) Save(ctx context.Context, webhook domain.Webhook) (domain.Webhook, error) {
+	if err := dbFromContext(ctx, repository.db).Create(&webhook).Error; err != nil {
+		return domain.Webhook{}, err
+	}
+	return webhook, nil
+}
+
+// Update webhook
+func (repository *WebhookRepositoryImpl) Update(ctx context.Context, webhook domain.Webhook) (domain.Webhook, error) {
+	if err := dbFromContext(ctx, repository.db).Select("*").Omit("created_at").Updates(&webhook).Error; err != nil {
+		return domain.Webhook{}, err
+	}
+	return webhook, nil
+}
+
+// Delete webhook together with its delivery log
+func (repository *WebhookRepositoryImpl) Delete(ctx context.Context, webhook domain.Webhook) error {
+	return dbFromContext(ctx, repository.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("webhook_id = ?", webhook.Id).Delete(&domain.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&webhook).Error
+	})
+}
+
+// FindById - Get webhook by ID
+func (repository *WebhookRepositoryImpl) FindById(ctx context.Context, webhookId uint64) (domain.Webhook, error) {
+	var webhook domain.Webhook
+	err := dbFromContext(ctx, repository.db).First(&webhook, webhookId).Error
+	return webhook, err
+}
+
+// FindAll - Get all webhooks
+func (repository *WebhookRepositoryImpl) FindAll(ctx context.Context) ([]domain.Webhook, error) {
+	var webhooks []domain.Webhook
+	err := dbFromContext(ctx, repository.db).Order("id").Find(&webhooks).Error
+	return webhooks, err
+}
+
+// FindActive - Get the webhooks that currently receive events
+func (repository *WebhookRepositoryImpl) FindActive(ctx context.Context) ([]domain.Webhook, error) {
+	var webhooks []domain.Webhook
+	err := dbFromContext(ctx, repository.db).Where("active = ?", true).Order("id").Find(&webhooks).Error
+	return webhooks, err
+}
+
+// SaveDeliveries - Queue deliveries of an event
+func (repository *WebhookRepositoryImpl) SaveDeliveries(ctx context.Context, deliveries []domain.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return dbFromContext(ctx, repository.db).Omit(clause.Associations).Create(&deliveries).Error
+}
+
+// UpdateDelivery - Record the outcome of a delivery attempt
+func (repository *WebhookRepositoryImpl) UpdateDelivery(ctx context.Context, delivery domain.WebhookDelivery) error {
+	return dbFromContext(ctx, repository.db).Select("*").Omit("created_at", clause.Associations).Updates(&delivery).Error
+}
+
+// FindDueDeliveries - Get pending deliveries of active webhooks whose next attempt is due, oldest first
+func (repository *WebhookRepositoryImpl) FindDueDeliveries(ctx context.Context, now time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	var deliveries []domain.WebhookDelivery
+	err := dbFromContext(ctx, repository.db).
+		InnerJoins("Webhook", repository.db.Where(&domain.Webhook{Active: true})).
+		Preload("Event").
+		Where("webhook_deliveries.status = ? AND webhook_deliveries.next_attempt_at <= ?", domain.DeliveryPending, now).
+		Order("webhook_deliveries.next_attempt_at").
+		Limit(limit).
+		Find(&deliveries).Error
+	return deliveries, err
+}
+
+// FindDeliveries - Get the delivery log of a webhook, newest first
+func (repository *WebhookRepositoryImpl) FindDeliveries(ctx context.Context, webhookId uint64, limit int) ([]domain.WebhookDelivery, error) {
+	var deliveries []domain.WebhookDelivery
+	err := dbFromContext(ctx, repository.db).
+		Preload("Event").
+		Where("webhook_id = ?", webhookId).
+		Order("id DESC").
+		Limit(limit).
+		Find(&deliveries).Error
+	return deliveries, err
+}
+
+// FindDeliveryById - Get delivery by ID
+func (repository *WebhookRepositoryImpl) FindDeliveryById(ctx context.Context, deliveryId uint64) (domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+	err := dbFromContext(ctx, repository.db).Preload("Event").First(&delivery, deliveryId).Error
+	return delivery, err
+}
@@ -0,0 +1,10 @@
+package service
+
+import (
+	"context"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+)
+
+type AuditService interface {
+	FindAll(ctx context.Context, request web.AuditRequest) ([]web.AuditLogResponse, error)
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/helper"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/repository"
+	"github.com/go-playground/validator/v10"
+)
+
+const defaultAuditLimit = 100
+
+type AuditServiceImpl struct {
+	AuditRepository repository.AuditRepository
+	Validate        *validator.Validate
+}
+
+func NewAuditService(auditRepository repository.AuditRepository, validate *validator.Validate) AuditService {
+	return &AuditServiceImpl{
+		AuditRepository: auditRepository,
+		Validate:        validate,
+	}
+}
+
+// Find the newest audit log entries, optionally of one entity type, row or actor
+func (service *AuditServiceImpl) FindAll(ctx context.Context, request web.AuditRequest) ([]web.AuditLogResponse, error) {
+	ctx, span := startSpan(ctx, "AuditService.FindAll")
+	defer span.End()
+
+	if err := service.Validate.Struct(request); err != nil {
+		return nil, exception.NewBadRequestError(err.Error())
+	}
+
+	filter := domain.AuditFilter{
+		Entity:   request.Entity,
+		EntityId: request.Id,
+		ActorId:  request.ActorId,
+		Limit:    request.Limit,
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultAuditLimit
+	}
+
+	logs, err := service.AuditRepository.FindAll(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	span.SetAttributes(countAttribute(len(logs)))
+	return helper.ToAuditLogResponses(logs), nil
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/repository/mocks"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func TestFindAllAudit(t *testing.T) {
+	productId := uint64(42)
+	actorId := uint64(7)
+	at := time.Date(2025, 3, 1, 9, 30, 0, 0, time.UTC)
+	changes := json.RawMessage(`{"product_price_amount":{"before":"85000.0000","after":"90000.0000"}}`)
+
+	tests := []struct {
+		name      string
+		input     web.AuditRequest
+		mock      func(mockAuditRepo *mocks.MockAuditRepository)
+		expects   []web.AuditLogResponse
+		expectErr error
+	}{
+		{
+			name:  "Entity And Id With Default Limit",
+			input: web.AuditRequest{Entity: "product", Id: &productId},
+			mock: func(mockAuditRepo *mocks.MockAuditRepository) {
+				mockAuditRepo.EXPECT().FindAll(gomock.Any(), domain.AuditFilter{Entity: "product", EntityId: &productId, Limit: 100}).
+					Return([]domain.AuditLog{{Id: 9, Entity: "product", EntityId: 42, Action: "update", ActorId: &actorId, RequestId: "req-1", Changes: changes, CreatedAt: at}}, nil)
+			},
+			expects: []web.AuditLogResponse{{Id: 9, Entity: "product", EntityId: 42, Action: "update", ActorId: &actorId, RequestId: "req-1", Changes: changes, CreatedAt: at}},
+		},
+		{
+			name:  "Actor With Limit",
+			input: web.AuditRequest{ActorId: &actorId, Limit: 10},
+			mock: func(mockAuditRepo *mocks.MockAuditRepository) {
+				mockAuditRepo.EXPECT().FindAll(gomock.Any(), domain.AuditFilter{ActorId: &actorId, Limit: 10}).Return(nil, nil)
+			},
+			expects: nil,
+		},
+		{
+			name:      "Unknown Entity",
+			input:     web.AuditRequest{Entity: "invoice"},
+			mock:      func(mockAuditRepo *mocks.MockAuditRepository) {},
+			expectErr: exception.BadRequestError{},
+		},
+		{
+			name:      "Id Without Entity",
+			input:     web.AuditRequest{Id: &productId},
+			mock:      func(mockAuditRepo *mocks.MockAuditRepository) {},
+			expectErr: exception.BadRequestError{},
+		},
+		{
+			name:  "Database Error",
+			input: web.AuditRequest{Entity: "customer"},
+			mock: func(mockAuditRepo *mocks.MockAuditRepository) {
+				mockAuditRepo.EXPECT().FindAll(gomock.Any(), gomock.Any()).Return(nil, errors.New("database error"))
+			},
+			expectErr: errors.New("database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockAuditRepo := mocks.NewMockAuditRepository(ctrl)
+			tt.mock(mockAuditRepo)
+
+			service := NewAuditService(mockAuditRepo, validator.New())
+			result, err := service.FindAll(context.Background(), tt.input)
+
+			if tt.expectErr != nil {
+				assert.Error(t, err)
+				assert.IsType(t, tt.expectErr, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expects, result)
+			}
+		})
+	}
+}
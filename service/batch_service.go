@@ -0,0 +1,13 @@
+package service
+
+import (
+	"context"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+)
+
+type BatchService interface {
+	Products(ctx context.Context, request web.BatchRequest) (web.BatchResponse, error)
+	Customers(ctx context.Context, request web.BatchRequest) (web.BatchResponse, error)
+	Employees(ctx context.Context, request web.BatchRequest) (web.BatchResponse, error)
+	Categories(ctx context.Context, request web.BatchRequest) (web.BatchResponse, error)
+}
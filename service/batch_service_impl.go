@@ -0,0 +1,265 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/repository"
+	"github.com/go-playground/validator/v10"
+	"net/http"
+)
+
+// errBatchAborted stops an atomic batch after its first failed operation
+var errBatchAborted = errors.New("batch aborted")
+
+// batchHandlers apply single operations through a resource's own service
+type batchHandlers struct {
+	create func(ctx context.Context, data json.RawMessage) (interface{}, error)
+	update func(ctx context.Context, id uint64, version uint64, data json.RawMessage) (interface{}, error)
+	delete func(ctx context.Context, id uint64, version uint64) error
+}
+
+type BatchServiceImpl struct {
+	ProductService  ProductService
+	CustomerService CustomerService
+	EmployeeService EmployeeService
+	CategoryService CategoryService
+	Transactor      repository.Transactor
+	Validate        *validator.Validate
+}
+
+func NewBatchService(productService ProductService, customerService CustomerService, employeeService EmployeeService, categoryService CategoryService, transactor repository.Transactor, validate *validator.Validate) BatchService {
+	return &BatchServiceImpl{
+		ProductService:  productService,
+		CustomerService: customerService,
+		EmployeeService: employeeService,
+		CategoryService: categoryService,
+		Transactor:      transactor,
+		Validate:        validate,
+	}
+}
+
+// Products applies a batch of product operations
+func (service *BatchServiceImpl) Products(ctx context.Context, request web.BatchRequest) (web.BatchResponse, error) {
+	ctx, span := startSpan(ctx, "BatchService.Products")
+	defer span.End()
+
+	return service.run(ctx, request, batchHandlers{
+		create: func(ctx context.Context, data json.RawMessage) (interface{}, error) {
+			var createRequest web.ProductCreateRequest
+			if err := decodeBatchData(data, &createRequest); err != nil {
+				return nil, err
+			}
+			return service.ProductService.Create(ctx, createRequest)
+		},
+		update: func(ctx context.Context, id uint64, version uint64, data json.RawMessage) (interface{}, error) {
+			var updateRequest web.ProductUpdateRequest
+			if err := decodeBatchData(data, &updateRequest); err != nil {
+				return nil, err
+			}
+			updateRequest.Id = id
+			if version != 0 {
+				updateRequest.Version = version
+			}
+			return service.ProductService.Update(ctx, updateRequest)
+		},
+		delete: service.ProductService.Delete,
+	})
+}
+
+// Customers applies a batch of customer operations
+func (service *BatchServiceImpl) Customers(ctx context.Context, request web.BatchRequest) (web.BatchResponse, error) {
+	ctx, span := startSpan(ctx, "BatchService.Customers")
+	defer span.End()
+
+	return service.run(ctx, request, batchHandlers{
+		create: func(ctx context.Context, data json.RawMessage) (interface{}, error) {
+			var createRequest web.CustomerCreateRequest
+			if err := decodeBatchData(data, &createRequest); err != nil {
+				return nil, err
+			}
+			return service.CustomerService.Create(ctx, createRequest)
+		},
+		update: func(ctx context.Context, id uint64, version uint64, data json.RawMessage) (interface{}, error) {
+			var updateRequest web.CustomerUpdateRequest
+			if err := decodeBatchData(data, &updateRequest); err != nil {
+				return nil, err
+			}
+			updateRequest.Id = id
+			if version != 0 {
+				updateRequest.Version = version
+			}
+			return service.CustomerService.Update(ctx, updateRequest)
+		},
+		delete: service.CustomerService.Delete,
+	})
+}
+
+// Employees applies a batch of employee operations
+func (service *BatchServiceImpl) Employees(ctx context.Context, request web.BatchRequest) (web.BatchResponse, error) {
+	ctx, span := startSpan(ctx, "BatchService.Employees")
+	defer span.End()
+
+	return service.run(ctx, request, batchHandlers{
+		create: func(ctx context.Context, data json.RawMessage) (interface{}, error) {
+			var createRequest web.EmployeeCreateRequest
+			if err := decodeBatchData(data, &createRequest); err != nil {
+				return nil, err
+			}
+			return service.EmployeeService.Create(ctx, createRequest)
+		},
+		update: func(ctx context.Context, id uint64, version uint64, data json.RawMessage) (interface{}, error) {
+			var updateRequest web.EmployeeUpdateRequest
+			if err := decodeBatchData(data, &updateRequest); err != nil {
+				return nil, err
+			}
+			updateRequest.Id = id
+			if version != 0 {
+				updateRequest.Version = version
+			}
+			return service.EmployeeService.Update(ctx, updateRequest)
+		},
+		delete: service.EmployeeService.Delete,
+	})
+}
+
+// Categories applies a batch of category operations
+func (service *BatchServiceImpl) Categories(ctx context.Context, request web.BatchRequest) (web.BatchResponse, error) {
+	ctx, span := startSpan(ctx, "BatchService.Categories")
+	defer span.End()
+
+	return service.run(ctx, request, batchHandlers{
+		create: func(ctx context.Context, data json.RawMessage) (interface{}, error) {
+			var createRequest web.CategoryCreateRequest
+			if err := decodeBatchData(data, &createRequest); err != nil {
+				return nil, err
+			}
+			return service.CategoryService.Create(ctx, createRequest)
+		},
+		update: func(ctx context.Context, id uint64, version uint64, data json.RawMessage) (interface{}, error) {
+			var updateRequest web.CategoryUpdateRequest
+			if err := decodeBatchData(data, &updateRequest); err != nil {
+				return nil, err
+			}
+			updateRequest.Id = id
+			if version != 0 {
+				updateRequest.Version = version
+			}
+			return service.CategoryService.Update(ctx, updateRequest)
+		},
+		delete: service.CategoryService.Delete,
+	})
+}
+
+// run applies every operation on its own, or all of them in one transaction when the batch is atomic
+func (service *BatchServiceImpl) run(ctx context.Context, request web.BatchRequest, handlers batchHandlers) (web.BatchResponse, error) {
+	if err := service.Validate.Struct(request); err != nil {
+		return web.BatchResponse{}, exception.NewBadRequestError(err.Error())
+	}
+
+	response := web.BatchResponse{Atomic: request.Atomic, Results: make([]web.BatchResultResponse, 0, len(request.Operations))}
+	if !request.Atomic {
+		for i, operation := range request.Operations {
+			response.Results = append(response.Results, applyBatchOperation(ctx, i, operation, handlers))
+		}
+		return countBatchResults(response), nil
+	}
+
+	err := service.Transactor.Transaction(ctx, func(ctx context.Context) error {
+		for i, operation := range request.Operations {
+			result := applyBatchOperation(ctx, i, operation, handlers)
+			response.Results = append(response.Results, result)
+			if result.Error != "" {
+				return errBatchAborted
+			}
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errBatchAborted) {
+		return web.BatchResponse{}, err
+	}
+
+	if err != nil {
+		// Everything before the failed operation was rolled back, everything after it never ran
+		failedAt := len(response.Results) - 1
+		for i := 0; i < failedAt; i++ {
+			response.Results[i].Status = "rolled_back"
+			response.Results[i].Data = nil
+		}
+		for i := failedAt + 1; i < len(request.Operations); i++ {
+			response.Results = append(response.Results, web.BatchResultResponse{
+				Index:  i,
+				Op:     request.Operations[i].Op,
+				Id:     request.Operations[i].Id,
+				Code:   http.StatusFailedDependency,
+				Status: "skipped",
+			})
+		}
+	}
+	return countBatchResults(response), nil
+}
+
+func applyBatchOperation(ctx context.Context, index int, operation web.BatchOperation, handlers batchHandlers) web.BatchResultResponse {
+	result := web.BatchResultResponse{Index: index, Op: operation.Op, Id: operation.Id}
+
+	var data interface{}
+	var err error
+	switch operation.Op {
+	case "create":
+		data, err = handlers.create(ctx, operation.Data)
+		result.Code, result.Status = http.StatusCreated, "created"
+	case "update":
+		data, err = handlers.update(ctx, operation.Id, operation.Version, operation.Data)
+		result.Code, result.Status = http.StatusOK, "updated"
+	case "delete":
+		err = handlers.delete(ctx, operation.Id, operation.Version)
+		result.Code, result.Status = http.StatusOK, "deleted"
+	}
+
+	if err != nil {
+		result.Code, result.Status, result.Error = batchErrorCode(err), "failed", err.Error()
+		return result
+	}
+	result.Data = data
+	return result
+}
+
+// batchErrorCode is the status a single-item endpoint would have answered with
+func batchErrorCode(err error) int {
+	var validationErrors validator.ValidationErrors
+	switch {
+	case errors.As(err, &validationErrors):
+		return http.StatusBadRequest
+	case errors.As(err, new(exception.BadRequestError)):
+		return http.StatusBadRequest
+	case errors.As(err, new(exception.NotFoundError)):
+		return http.StatusNotFound
+	case errors.As(err, new(exception.PreconditionFailedError)):
+		return http.StatusPreconditionFailed
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func decodeBatchData(data json.RawMessage, target interface{}) error {
+	if len(data) == 0 {
+		return exception.NewBadRequestError("data is required")
+	}
+	if err := json.Unmarshal(data, target); err != nil {
+		return exception.NewBadRequestError("Invalid data: " + err.Error())
+	}
+	return nil
+}
+
+func countBatchResults(response web.BatchResponse) web.BatchResponse {
+	for _, result := range response.Results {
+		if result.Status == "failed" || result.Status == "skipped" || result.Status == "rolled_back" {
+			response.Failed++
+		} else {
+			response.Succeeded++
+		}
+	}
+	return response
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/repository/mocks"
+	servicemocks "github.com/aronipurwanto/go-restful-api/service/mocks"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"testing"
+)
+
+func TestBatchCustomers(t *testing.T) {
+	budi := json.RawMessage(`{"name":"Budi"}`)
+
+	tests := []struct {
+		name    string
+		mock    func(mockCustomerService *servicemocks.MockCustomerService, mockTransactor *mocks.MockTransactor)
+		input   web.BatchRequest
+		expects web.BatchResponse
+		err     error
+	}{
+		{
+			name: "Best Effort",
+			mock: func(mockCustomerService *servicemocks.MockCustomerService, mockTransactor *mocks.MockTransactor) {
+				mockCustomerService.EXPECT().Create(gomock.Any(), web.CustomerCreateRequest{Name: "Budi"}).Return(web.CustomerResponse{Id: 9, Name: "Budi"}, nil)
+				mockCustomerService.EXPECT().Update(gomock.Any(), web.CustomerUpdateRequest{Id: 4, Name: "Budi"}).Return(web.CustomerResponse{}, exception.NewNotFoundError("Customer not found"))
+				mockCustomerService.EXPECT().Delete(gomock.Any(), uint64(5), uint64(0)).Return(nil)
+			},
+			input: web.BatchRequest{Operations: []web.BatchOperation{
+				{Op: "create", Data: budi},
+				{Op: "update", Id: 4, Data: budi},
+				{Op: "delete", Id: 5},
+			}},
+			expects: web.BatchResponse{Succeeded: 2, Failed: 1, Results: []web.BatchResultResponse{
+				{Index: 0, Op: "create", Code: http.StatusCreated, Status: "created", Data: web.CustomerResponse{Id: 9, Name: "Budi"}},
+				{Index: 1, Op: "update", Id: 4, Code: http.StatusNotFound, Status: "failed", Error: "Customer not found"},
+				{Index: 2, Op: "delete", Id: 5, Code: http.StatusOK, Status: "deleted"},
+			}},
+		},
+		{
+			name: "Atomic Rolls Back On Failure",
+			mock: func(mockCustomerService *servicemocks.MockCustomerService, mockTransactor *mocks.MockTransactor) {
+				mockTransactor.EXPECT().Transaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				mockCustomerService.EXPECT().Delete(gomock.Any(), uint64(5), uint64(0)).Return(nil)
+			},
+			input: web.BatchRequest{Atomic: true, Operations: []web.BatchOperation{
+				{Op: "delete", Id: 5},
+				{Op: "create", Data: json.RawMessage(`{"name":`)},
+				{Op: "delete", Id: 6},
+			}},
+			expects: web.BatchResponse{Atomic: true, Failed: 3, Results: []web.BatchResultResponse{
+				{Index: 0, Op: "delete", Id: 5, Code: http.StatusOK, Status: "rolled_back"},
+				{Index: 1, Op: "create", Code: http.StatusBadRequest, Status: "failed", Error: "Invalid data: unexpected end of JSON input"},
+				{Index: 2, Op: "delete", Id: 6, Code: http.StatusFailedDependency, Status: "skipped"},
+			}},
+		},
+		{
+			name: "Atomic Success",
+			mock: func(mockCustomerService *servicemocks.MockCustomerService, mockTransactor *mocks.MockTransactor) {
+				mockTransactor.EXPECT().Transaction(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+				mockCustomerService.EXPECT().Delete(gomock.Any(), uint64(5), uint64(0)).Return(nil)
+			},
+			input: web.BatchRequest{Atomic: true, Operations: []web.BatchOperation{{Op: "delete", Id: 5}}},
+			expects: web.BatchResponse{Atomic: true, Succeeded: 1, Results: []web.BatchResultResponse{
+				{Index: 0, Op: "delete", Id: 5, Code: http.StatusOK, Status: "deleted"},
+			}},
+		},
+		{
+			name:    "Update Without Id",
+			mock:    func(mockCustomerService *servicemocks.MockCustomerService, mockTransactor *mocks.MockTransactor) {},
+			input:   web.BatchRequest{Operations: []web.BatchOperation{{Op: "update", Data: budi}}},
+			expects: web.BatchResponse{},
+			err:     exception.NewBadRequestError("Key: 'BatchRequest.Operations[0].Id' Error:Field validation for 'Id' failed on the 'required_unless' tag"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockCustomerService := servicemocks.NewMockCustomerService(ctrl)
+			mockTransactor := mocks.NewMockTransactor(ctrl)
+			tt.mock(mockCustomerService, mockTransactor)
+
+			service := NewBatchService(nil, mockCustomerService, nil, nil, mockTransactor, validator.New())
+			result, err := service.Customers(context.Background(), tt.input)
+			assert.Equal(t, tt.expects, result)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
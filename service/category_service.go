@@ -8,7 +8,11 @@ import (
 type CategoryService interface {
 	Create(ctx context.Context, request web.CategoryCreateRequest) (web.CategoryResponse, error)
 	Update(ctx context.Context, request web.CategoryUpdateRequest) (web.CategoryResponse, error)
-	Delete(ctx context.Context, categoryId uint64) error
+	Patch(ctx context.Context, request web.PatchRequest) (web.CategoryResponse, error)
+	Delete(ctx context.Context, categoryId uint64, version uint64) error
+	Restore(ctx context.Context, categoryId uint64) (web.CategoryResponse, error)
 	FindById(ctx context.Context, categoryId uint64) (web.CategoryResponse, error)
 	FindAll(ctx context.Context) ([]web.CategoryResponse, error)
+	FindTree(ctx context.Context, rootId *uint64, withCounts bool) ([]web.CategoryResponse, error)
+	Move(ctx context.Context, request web.CategoryMoveRequest) (web.CategoryResponse, error)
 }
@@ -10,27 +10,39 @@ import (
 	"github.com/aronipurwanto/go-restful-api/repository"
 	"github.com/go-playground/validator/v10"
 	"gorm.io/gorm"
+	"slices"
 )
 
 type CategoryServiceImpl struct {
 	CategoryRepository repository.CategoryRepository
+	Transactor         repository.Transactor
 	Validate           *validator.Validate
 }
 
-func NewCategoryService(categoryRepository repository.CategoryRepository, validate *validator.Validate) CategoryService {
+func NewCategoryService(categoryRepository repository.CategoryRepository, transactor repository.Transactor, validate *validator.Validate) CategoryService {
 	return &CategoryServiceImpl{
 		CategoryRepository: categoryRepository,
+		Transactor:         transactor,
 		Validate:           validate,
 	}
 }
 
 // Create Category
 func (service *CategoryServiceImpl) Create(ctx context.Context, request web.CategoryCreateRequest) (web.CategoryResponse, error) {
+	ctx, span := startSpan(ctx, "CategoryService.Create")
+	defer span.End()
+
 	if err := service.Validate.Struct(request); err != nil {
 		return web.CategoryResponse{}, err
 	}
 
-	category := domain.Category{Name: request.Name}
+	if request.ParentId != nil {
+		if _, err := service.findCategory(ctx, *request.ParentId, "Parent category not found"); err != nil {
+			return web.CategoryResponse{}, err
+		}
+	}
+
+	category := domain.Category{Name: request.Name, ParentId: request.ParentId}
 	savedCategory, err := service.CategoryRepository.Save(ctx, category)
 	if err != nil {
 		return web.CategoryResponse{}, err
@@ -41,6 +53,9 @@ func (service *CategoryServiceImpl) Create(ctx context.Context, request web.Cate
 
 // Update Category
 func (service *CategoryServiceImpl) Update(ctx context.Context, request web.CategoryUpdateRequest) (web.CategoryResponse, error) {
+	ctx, span := startSpan(ctx, "CategoryService.Update", idAttribute("category", request.Id))
+	defer span.End()
+
 	if err := service.Validate.Struct(request); err != nil {
 		return web.CategoryResponse{}, err
 	}
@@ -51,18 +66,47 @@ func (service *CategoryServiceImpl) Update(ctx context.Context, request web.Cate
 	} else if err != nil {
 		return web.CategoryResponse{}, err
 	}
+	if err := checkVersion(ctx, "Category", request.Version, category.Version); err != nil {
+		return web.CategoryResponse{}, err
+	}
 
 	category.Name = request.Name
 	updatedCategory, err := service.CategoryRepository.Update(ctx, category)
 	if err != nil {
-		return web.CategoryResponse{}, err
+		return web.CategoryResponse{}, versionConflict("Category", err)
 	}
 
 	return helper.ToCategoryResponse(updatedCategory), nil
 }
 
+// Patch Category with a merge patch or JSON patch applied to its current state
+func (service *CategoryServiceImpl) Patch(ctx context.Context, request web.PatchRequest) (web.CategoryResponse, error) {
+	ctx, span := startSpan(ctx, "CategoryService.Patch", idAttribute("category", request.Id))
+	defer span.End()
+
+	category, err := service.CategoryRepository.FindById(ctx, request.Id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return web.CategoryResponse{}, exception.NewNotFoundError("Category not found")
+	} else if err != nil {
+		return web.CategoryResponse{}, err
+	}
+	if err := checkVersion(ctx, "Category", request.Version, category.Version); err != nil {
+		return web.CategoryResponse{}, err
+	}
+
+	var updateRequest web.CategoryUpdateRequest
+	if err := applyPatch(request, helper.ToCategoryUpdateRequest(category), category.Version, &updateRequest); err != nil {
+		return web.CategoryResponse{}, err
+	}
+
+	return service.Update(ctx, updateRequest)
+}
+
 // Delete Category
-func (service *CategoryServiceImpl) Delete(ctx context.Context, categoryId uint64) error {
+func (service *CategoryServiceImpl) Delete(ctx context.Context, categoryId uint64, version uint64) error {
+	ctx, span := startSpan(ctx, "CategoryService.Delete", idAttribute("category", categoryId))
+	defer span.End()
+
 	category, err := service.CategoryRepository.FindById(ctx, categoryId)
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return exception.NewNotFoundError("Category not found")
@@ -70,11 +114,53 @@ func (service *CategoryServiceImpl) Delete(ctx context.Context, categoryId uint6
 		return err
 	}
 
-	return service.CategoryRepository.Delete(ctx, category)
+	if err := checkVersion(ctx, "Category", version, category.Version); err != nil {
+		return err
+	}
+
+	// a soft-deleted category would otherwise leave its subcategories hanging in the tree
+	subtreeIds, err := service.CategoryRepository.FindSubtreeIds(ctx, categoryId)
+	if err != nil {
+		return err
+	}
+	if len(subtreeIds) > 1 {
+		return exception.NewBadRequestError("Category still has subcategories")
+	}
+
+	// and its products would point at a category no listing shows
+	counts, err := service.CategoryRepository.CountProducts(ctx)
+	if err != nil {
+		return err
+	}
+	if counts[categoryId] > 0 {
+		return exception.NewBadRequestError("Category still has products")
+	}
+
+	return versionConflict("Category", service.CategoryRepository.Delete(ctx, category))
+}
+
+// Restore a soft-deleted Category
+func (service *CategoryServiceImpl) Restore(ctx context.Context, categoryId uint64) (web.CategoryResponse, error) {
+	ctx, span := startSpan(ctx, "CategoryService.Restore", idAttribute("category", categoryId))
+	defer span.End()
+
+	err := service.CategoryRepository.Restore(ctx, categoryId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return web.CategoryResponse{}, exception.NewNotFoundError("Deleted category not found")
+	} else if errors.Is(err, repository.ErrParentDeleted) {
+		return web.CategoryResponse{}, exception.NewBadRequestError("Parent category is deleted, restore it first")
+	} else if err != nil {
+		return web.CategoryResponse{}, err
+	}
+
+	return service.FindById(ctx, categoryId)
 }
 
 // Find Category By ID
 func (service *CategoryServiceImpl) FindById(ctx context.Context, categoryId uint64) (web.CategoryResponse, error) {
+	ctx, span := startSpan(ctx, "CategoryService.FindById", idAttribute("category", categoryId))
+	defer span.End()
+
 	category, err := service.CategoryRepository.FindById(ctx, categoryId)
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return web.CategoryResponse{}, exception.NewNotFoundError("Category not found")
@@ -87,10 +173,141 @@ func (service *CategoryServiceImpl) FindById(ctx context.Context, categoryId uin
 
 // Find All Categories
 func (service *CategoryServiceImpl) FindAll(ctx context.Context) ([]web.CategoryResponse, error) {
+	ctx, span := startSpan(ctx, "CategoryService.FindAll")
+	defer span.End()
+
 	categories, err := service.CategoryRepository.FindAll(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	span.SetAttributes(countAttribute(len(categories)))
 	return helper.ToCategoryResponses(categories), nil
 }
+
+// Find Category Tree, either the whole forest or the subtree below rootId
+func (service *CategoryServiceImpl) FindTree(ctx context.Context, rootId *uint64, withCounts bool) ([]web.CategoryResponse, error) {
+	ctx, span := startSpan(ctx, "CategoryService.FindTree")
+	defer span.End()
+
+	if rootId != nil {
+		if _, err := service.findCategory(ctx, *rootId, "Category not found"); err != nil {
+			return nil, err
+		}
+	}
+
+	categories, err := service.CategoryRepository.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var counts map[uint64]int64
+	if withCounts {
+		counts, err = service.CategoryRepository.CountProducts(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	childrenOf := make(map[uint64][]domain.Category)
+	known := make(map[uint64]bool, len(categories))
+	for _, category := range categories {
+		known[category.Id] = true
+	}
+
+	var roots []domain.Category
+	for _, category := range categories {
+		switch {
+		case rootId != nil && category.Id == *rootId:
+			roots = append(roots, category)
+		case category.ParentId != nil && known[*category.ParentId]:
+			childrenOf[*category.ParentId] = append(childrenOf[*category.ParentId], category)
+		case rootId == nil:
+			roots = append(roots, category)
+		}
+	}
+
+	var tree []web.CategoryResponse
+	for _, root := range roots {
+		tree = append(tree, buildCategoryTree(root, childrenOf, counts, withCounts))
+	}
+	span.SetAttributes(countAttribute(len(tree)))
+	return tree, nil
+}
+
+// Move Category together with its subtree under a new parent, or to the root when ParentId is nil
+func (service *CategoryServiceImpl) Move(ctx context.Context, request web.CategoryMoveRequest) (web.CategoryResponse, error) {
+	ctx, span := startSpan(ctx, "CategoryService.Move", idAttribute("category", request.Id))
+	defer span.End()
+
+	if err := service.Validate.Struct(request); err != nil {
+		return web.CategoryResponse{}, err
+	}
+
+	// The cycle check and the update share one transaction, and the new parent's ancestor rows stay
+	// locked until it ends, so two opposite moves cannot both pass the check
+	var movedCategory domain.Category
+	err := service.Transactor.Transaction(ctx, func(ctx context.Context) error {
+		category, err := service.findCategory(ctx, request.Id, "Category not found")
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(ctx, "Category", request.Version, category.Version); err != nil {
+			return err
+		}
+
+		if request.ParentId != nil {
+			ancestorIds, err := service.CategoryRepository.LockAncestorIds(ctx, *request.ParentId)
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return exception.NewNotFoundError("Parent category not found")
+			} else if err != nil {
+				return err
+			}
+
+			// Moving a category below itself or one of its descendants would create a cycle
+			if slices.Contains(ancestorIds, category.Id) {
+				return exception.NewBadRequestError("Category cannot be moved below itself or its descendants")
+			}
+		}
+
+		category.ParentId = request.ParentId
+		movedCategory, err = service.CategoryRepository.Update(ctx, category)
+		return versionConflict("Category", err)
+	})
+	if err != nil {
+		return web.CategoryResponse{}, err
+	}
+
+	return helper.ToCategoryResponse(movedCategory), nil
+}
+
+func (service *CategoryServiceImpl) findCategory(ctx context.Context, categoryId uint64, notFoundMessage string) (domain.Category, error) {
+	category, err := service.CategoryRepository.FindById(ctx, categoryId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.Category{}, exception.NewNotFoundError(notFoundMessage)
+	} else if err != nil {
+		return domain.Category{}, err
+	}
+	return category, nil
+}
+
+func buildCategoryTree(category domain.Category, childrenOf map[uint64][]domain.Category, counts map[uint64]int64, withCounts bool) web.CategoryResponse {
+	response := helper.ToCategoryResponse(category)
+
+	var subtreeCount int64
+	for _, child := range childrenOf[category.Id] {
+		childResponse := buildCategoryTree(child, childrenOf, counts, withCounts)
+		if withCounts {
+			subtreeCount += *childResponse.SubtreeProductCount
+		}
+		response.Children = append(response.Children, childResponse)
+	}
+
+	if withCounts {
+		productCount := counts[category.Id]
+		subtreeCount += productCount
+		response.ProductCount = &productCount
+		response.SubtreeProductCount = &subtreeCount
+	}
+	return response
+}
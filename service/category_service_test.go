@@ -3,12 +3,16 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"github.com/aronipurwanto/go-restful-api/exception"
 	"github.com/aronipurwanto/go-restful-api/model/domain"
 	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/repository"
 	"github.com/aronipurwanto/go-restful-api/repository/mocks"
 	"github.com/go-playground/validator/v10"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
 	"testing"
 )
 
@@ -18,7 +22,7 @@ func TestCreateCategory(t *testing.T) {
 
 	mockRepo := mocks.NewMockCategoryRepository(ctrl)
 	mockValidator := validator.New()
-	categoryService := NewCategoryService(mockRepo, mockValidator)
+	categoryService := NewCategoryService(mockRepo, passthroughTransactor(ctrl), mockValidator)
 
 	tests := []struct {
 		name      string
@@ -73,7 +77,7 @@ func TestDeleteCategory(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockCategoryRepository(ctrl)
-	categoryService := NewCategoryService(mockRepo, validator.New())
+	categoryService := NewCategoryService(mockRepo, passthroughTransactor(ctrl), validator.New())
 
 	tests := []struct {
 		name       string
@@ -86,10 +90,31 @@ func TestDeleteCategory(t *testing.T) {
 			categoryId: 1,
 			mock: func() {
 				mockRepo.EXPECT().FindById(gomock.Any(), uint64(1)).Return(domain.Category{Id: 1, Name: "Electronics"}, nil)
+				mockRepo.EXPECT().FindSubtreeIds(gomock.Any(), uint64(1)).Return([]uint64{1}, nil)
+				mockRepo.EXPECT().CountProducts(gomock.Any()).Return(map[uint64]int64{2: 4}, nil)
 				mockRepo.EXPECT().Delete(gomock.Any(), gomock.Any()).Return(nil)
 			},
 			expectErr: false,
 		},
+		{
+			name:       "has products",
+			categoryId: 3,
+			mock: func() {
+				mockRepo.EXPECT().FindById(gomock.Any(), uint64(3)).Return(domain.Category{Id: 3, Name: "Drinks"}, nil)
+				mockRepo.EXPECT().FindSubtreeIds(gomock.Any(), uint64(3)).Return([]uint64{3}, nil)
+				mockRepo.EXPECT().CountProducts(gomock.Any()).Return(map[uint64]int64{3: 1}, nil)
+			},
+			expectErr: true,
+		},
+		{
+			name:       "has subcategories",
+			categoryId: 2,
+			mock: func() {
+				mockRepo.EXPECT().FindById(gomock.Any(), uint64(2)).Return(domain.Category{Id: 2, Name: "Electronics"}, nil)
+				mockRepo.EXPECT().FindSubtreeIds(gomock.Any(), uint64(2)).Return([]uint64{2, 3}, nil)
+			},
+			expectErr: true,
+		},
 		{
 			name:       "not found",
 			categoryId: 99,
@@ -103,7 +128,7 @@ func TestDeleteCategory(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
-			err := categoryService.Delete(context.Background(), tt.categoryId)
+			err := categoryService.Delete(context.Background(), tt.categoryId, 0)
 			if tt.expectErr {
 				assert.Error(t, err)
 			} else {
@@ -113,6 +138,55 @@ func TestDeleteCategory(t *testing.T) {
 	}
 }
 
+func TestRestoreCategory(t *testing.T) {
+	tests := []struct {
+		name    string
+		mock    func(mockCategoryRepo *mocks.MockCategoryRepository)
+		expects error
+	}{
+		{
+			name: "Success",
+			mock: func(mockCategoryRepo *mocks.MockCategoryRepository) {
+				mockCategoryRepo.EXPECT().Restore(gomock.Any(), uint64(2)).Return(nil)
+				mockCategoryRepo.EXPECT().FindById(gomock.Any(), uint64(2)).Return(domain.Category{Id: 2, Name: "Tea", Version: 3}, nil)
+			},
+		},
+		{
+			name: "Not Deleted",
+			mock: func(mockCategoryRepo *mocks.MockCategoryRepository) {
+				mockCategoryRepo.EXPECT().Restore(gomock.Any(), uint64(2)).Return(fmt.Errorf("deleted category is not found: %w", gorm.ErrRecordNotFound))
+			},
+			expects: exception.NewNotFoundError("Deleted category not found"),
+		},
+		{
+			name: "Parent Deleted",
+			mock: func(mockCategoryRepo *mocks.MockCategoryRepository) {
+				mockCategoryRepo.EXPECT().Restore(gomock.Any(), uint64(2)).Return(fmt.Errorf("parent category is deleted: %w", repository.ErrParentDeleted))
+			},
+			expects: exception.NewBadRequestError("Parent category is deleted, restore it first"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockCategoryRepo := mocks.NewMockCategoryRepository(ctrl)
+			tt.mock(mockCategoryRepo)
+
+			service := NewCategoryService(mockCategoryRepo, passthroughTransactor(ctrl), validator.New())
+			response, err := service.Restore(context.Background(), 2)
+
+			if tt.expects != nil {
+				assert.Equal(t, tt.expects, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, uint64(3), response.Version)
+			}
+		})
+	}
+}
+
 func TestUpdateCategory(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -168,7 +242,7 @@ func TestUpdateCategory(t *testing.T) {
 			mockCategoryRepo := mocks.NewMockCategoryRepository(ctrl)
 			tt.mock(mockCategoryRepo)
 
-			service := NewCategoryService(mockCategoryRepo, validator.New())
+			service := NewCategoryService(mockCategoryRepo, passthroughTransactor(ctrl), validator.New())
 			_, err := service.Update(context.Background(), tt.input)
 
 			if tt.expects != nil {
@@ -213,7 +287,7 @@ func TestFindAllCategories(t *testing.T) {
 			mockCategoryRepo := mocks.NewMockCategoryRepository(ctrl)
 			tt.mock(mockCategoryRepo)
 
-			service := NewCategoryService(mockCategoryRepo, validator.New())
+			service := NewCategoryService(mockCategoryRepo, passthroughTransactor(ctrl), validator.New())
 			result, err := service.FindAll(context.Background())
 			assert.Equal(t, tt.expects, result)
 			assert.Equal(t, tt.err, err)
@@ -256,10 +330,173 @@ func TestFindByIdCategory(t *testing.T) {
 			mockCategoryRepo := mocks.NewMockCategoryRepository(ctrl)
 			tt.mock(mockCategoryRepo)
 
-			service := NewCategoryService(mockCategoryRepo, validator.New())
+			service := NewCategoryService(mockCategoryRepo, passthroughTransactor(ctrl), validator.New())
 			result, err := service.FindById(context.Background(), tt.input)
 			assert.Equal(t, tt.expects, result)
 			assert.Equal(t, tt.err, err)
 		})
 	}
 }
+
+func TestFindTreeCategories(t *testing.T) {
+	parentOne := uint64(1)
+	parentTwo := uint64(2)
+	categories := []domain.Category{
+		{Id: 1, Name: "Food"},
+		{Id: 2, Name: "Drinks", ParentId: &parentOne},
+		{Id: 3, Name: "Juice", ParentId: &parentTwo},
+		{Id: 4, Name: "Fashion"},
+	}
+	count := func(n int64) *int64 { return &n }
+
+	tests := []struct {
+		name       string
+		mock       func(mockCategoryRepo *mocks.MockCategoryRepository)
+		rootId     *uint64
+		withCounts bool
+		expects    []web.CategoryResponse
+		err        error
+	}{
+		{
+			name: "Whole Tree",
+			mock: func(mockCategoryRepo *mocks.MockCategoryRepository) {
+				mockCategoryRepo.EXPECT().FindAll(gomock.Any()).Return(categories, nil)
+			},
+			expects: []web.CategoryResponse{
+				{Id: 1, Name: "Food", Children: []web.CategoryResponse{
+					{Id: 2, Name: "Drinks", ParentId: &parentOne, Children: []web.CategoryResponse{
+						{Id: 3, Name: "Juice", ParentId: &parentTwo},
+					}},
+				}},
+				{Id: 4, Name: "Fashion"},
+			},
+			err: nil,
+		},
+		{
+			name: "Subtree With Counts",
+			mock: func(mockCategoryRepo *mocks.MockCategoryRepository) {
+				mockCategoryRepo.EXPECT().FindById(gomock.Any(), uint64(2)).Return(categories[1], nil)
+				mockCategoryRepo.EXPECT().FindAll(gomock.Any()).Return(categories, nil)
+				mockCategoryRepo.EXPECT().CountProducts(gomock.Any()).Return(map[uint64]int64{1: 5, 2: 2, 3: 4}, nil)
+			},
+			rootId:     &parentTwo,
+			withCounts: true,
+			expects: []web.CategoryResponse{
+				{Id: 2, Name: "Drinks", ParentId: &parentOne, ProductCount: count(2), SubtreeProductCount: count(6), Children: []web.CategoryResponse{
+					{Id: 3, Name: "Juice", ParentId: &parentTwo, ProductCount: count(4), SubtreeProductCount: count(4)},
+				}},
+			},
+			err: nil,
+		},
+		{
+			name: "Root Not Found",
+			mock: func(mockCategoryRepo *mocks.MockCategoryRepository) {
+				mockCategoryRepo.EXPECT().FindById(gomock.Any(), uint64(2)).Return(domain.Category{}, gorm.ErrRecordNotFound)
+			},
+			rootId:  &parentTwo,
+			expects: nil,
+			err:     exception.NewNotFoundError("Category not found"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockCategoryRepo := mocks.NewMockCategoryRepository(ctrl)
+			tt.mock(mockCategoryRepo)
+
+			service := NewCategoryService(mockCategoryRepo, passthroughTransactor(ctrl), validator.New())
+			result, err := service.FindTree(context.Background(), tt.rootId, tt.withCounts)
+			assert.Equal(t, tt.expects, result)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestMoveCategory(t *testing.T) {
+	newParent := uint64(3)
+
+	tests := []struct {
+		name    string
+		mock    func(mockCategoryRepo *mocks.MockCategoryRepository)
+		input   web.CategoryMoveRequest
+		expects web.CategoryResponse
+		err     error
+	}{
+		{
+			name: "Success",
+			mock: func(mockCategoryRepo *mocks.MockCategoryRepository) {
+				mockCategoryRepo.EXPECT().FindById(gomock.Any(), uint64(1)).Return(domain.Category{Id: 1, Name: "Drinks"}, nil)
+				mockCategoryRepo.EXPECT().LockAncestorIds(gomock.Any(), uint64(3)).Return([]uint64{3, 5}, nil)
+				mockCategoryRepo.EXPECT().Update(gomock.Any(), domain.Category{Id: 1, Name: "Drinks", ParentId: &newParent}).Return(domain.Category{Id: 1, Name: "Drinks", ParentId: &newParent}, nil)
+			},
+			input:   web.CategoryMoveRequest{Id: 1, ParentId: &newParent},
+			expects: web.CategoryResponse{Id: 1, Name: "Drinks", ParentId: &newParent},
+			err:     nil,
+		},
+		{
+			name: "Move To Root",
+			mock: func(mockCategoryRepo *mocks.MockCategoryRepository) {
+				mockCategoryRepo.EXPECT().FindById(gomock.Any(), uint64(1)).Return(domain.Category{Id: 1, Name: "Drinks", ParentId: &newParent}, nil)
+				mockCategoryRepo.EXPECT().Update(gomock.Any(), domain.Category{Id: 1, Name: "Drinks"}).Return(domain.Category{Id: 1, Name: "Drinks"}, nil)
+			},
+			input:   web.CategoryMoveRequest{Id: 1},
+			expects: web.CategoryResponse{Id: 1, Name: "Drinks"},
+			err:     nil,
+		},
+		{
+			name: "Cycle Rejected",
+			mock: func(mockCategoryRepo *mocks.MockCategoryRepository) {
+				mockCategoryRepo.EXPECT().FindById(gomock.Any(), uint64(1)).Return(domain.Category{Id: 1, Name: "Drinks"}, nil)
+				mockCategoryRepo.EXPECT().LockAncestorIds(gomock.Any(), uint64(3)).Return([]uint64{3, 2, 1}, nil)
+			},
+			input:   web.CategoryMoveRequest{Id: 1, ParentId: &newParent},
+			expects: web.CategoryResponse{},
+			err:     exception.NewBadRequestError("Category cannot be moved below itself or its descendants"),
+		},
+		{
+			name: "Parent Not Found",
+			mock: func(mockCategoryRepo *mocks.MockCategoryRepository) {
+				mockCategoryRepo.EXPECT().FindById(gomock.Any(), uint64(1)).Return(domain.Category{Id: 1, Name: "Drinks"}, nil)
+				mockCategoryRepo.EXPECT().LockAncestorIds(gomock.Any(), uint64(3)).Return(nil, fmt.Errorf("category is not found: %w", gorm.ErrRecordNotFound))
+			},
+			input:   web.CategoryMoveRequest{Id: 1, ParentId: &newParent},
+			expects: web.CategoryResponse{},
+			err:     exception.NewNotFoundError("Parent category not found"),
+		},
+		{
+			name: "Version Moved",
+			mock: func(mockCategoryRepo *mocks.MockCategoryRepository) {
+				mockCategoryRepo.EXPECT().FindById(gomock.Any(), uint64(1)).Return(domain.Category{Id: 1, Name: "Drinks", Version: 2}, nil)
+			},
+			input:   web.CategoryMoveRequest{Id: 1, ParentId: &newParent, Version: 1},
+			expects: web.CategoryResponse{},
+			err:     exception.NewPreconditionFailedError("Category has been modified by another request"),
+		},
+		{
+			name: "Lost Race",
+			mock: func(mockCategoryRepo *mocks.MockCategoryRepository) {
+				mockCategoryRepo.EXPECT().FindById(gomock.Any(), uint64(1)).Return(domain.Category{Id: 1, Name: "Drinks", Version: 2}, nil)
+				mockCategoryRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(domain.Category{}, repository.ErrVersionConflict)
+			},
+			input:   web.CategoryMoveRequest{Id: 1, Version: 2},
+			expects: web.CategoryResponse{},
+			err:     exception.NewPreconditionFailedError("Category has been modified by another request"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockCategoryRepo := mocks.NewMockCategoryRepository(ctrl)
+			tt.mock(mockCategoryRepo)
+
+			service := NewCategoryService(mockCategoryRepo, passthroughTransactor(ctrl), validator.New())
+			result, err := service.Move(context.Background(), tt.input)
+			assert.Equal(t, tt.expects, result)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
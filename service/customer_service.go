@@ -8,7 +8,9 @@ import (
 type CustomerService interface {
 	Create(ctx context.Context, request web.CustomerCreateRequest) (web.CustomerResponse, error)
 	Update(ctx context.Context, request web.CustomerUpdateRequest) (web.CustomerResponse, error)
-	Delete(ctx context.Context, customerId uint64) error
+	Patch(ctx context.Context, request web.PatchRequest) (web.CustomerResponse, error)
+	Delete(ctx context.Context, customerId uint64, version uint64) error
+	Restore(ctx context.Context, customerId uint64) (web.CustomerResponse, error)
 	FindById(ctx context.Context, customerId uint64) (web.CustomerResponse, error)
 	FindAll(ctx context.Context) ([]web.CustomerResponse, error)
 }
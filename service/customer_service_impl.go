@@ -14,23 +14,36 @@ import (
 
 type CustomerServiceImpl struct {
 	CustomerRepository repository.CustomerRepository
+	OutboxRepository   repository.OutboxRepository
+	Transactor         repository.Transactor
 	Validate           *validator.Validate
 }
 
-func NewCustomerService(customerRepository repository.CustomerRepository, validate *validator.Validate) CustomerService {
+func NewCustomerService(customerRepository repository.CustomerRepository, outboxRepository repository.OutboxRepository, transactor repository.Transactor, validate *validator.Validate) CustomerService {
 	return &CustomerServiceImpl{
 		CustomerRepository: customerRepository,
+		OutboxRepository:   outboxRepository,
+		Transactor:         transactor,
 		Validate:           validate,
 	}
 }
 
 // Create Customer
 func (service *CustomerServiceImpl) Create(ctx context.Context, request web.CustomerCreateRequest) (web.CustomerResponse, error) {
+	ctx, span := startSpan(ctx, "CustomerService.Create")
+	defer span.End()
+
 	if err := service.Validate.Struct(request); err != nil {
 		return web.CustomerResponse{}, err
 	}
 
-	customer := domain.Customer{Name: request.Name}
+	customer := domain.Customer{
+		Name:       request.Name,
+		Email:      request.Email,
+		Phone:      request.Phone,
+		Address:    request.Address,
+		LoyaltyPts: request.LoyaltyPts,
+	}
 	savedCustomer, err := service.CustomerRepository.Save(ctx, customer)
 	if err != nil {
 		return web.CustomerResponse{}, err
@@ -41,6 +54,9 @@ func (service *CustomerServiceImpl) Create(ctx context.Context, request web.Cust
 
 // Update Customer
 func (service *CustomerServiceImpl) Update(ctx context.Context, request web.CustomerUpdateRequest) (web.CustomerResponse, error) {
+	ctx, span := startSpan(ctx, "CustomerService.Update", idAttribute("customer", request.Id))
+	defer span.End()
+
 	if err := service.Validate.Struct(request); err != nil {
 		return web.CustomerResponse{}, err
 	}
@@ -51,18 +67,64 @@ func (service *CustomerServiceImpl) Update(ctx context.Context, request web.Cust
 	} else if err != nil {
 		return web.CustomerResponse{}, err
 	}
+	if err := checkVersion(ctx, "Customer", request.Version, customer.Version); err != nil {
+		return web.CustomerResponse{}, err
+	}
 
 	customer.Name = request.Name
-	updatedCustomer, err := service.CustomerRepository.Update(ctx, customer)
+	customer.Email = request.Email
+	customer.Phone = request.Phone
+	customer.Address = request.Address
+	customer.LoyaltyPts = request.LoyaltyPts
+	var updatedCustomer domain.Customer
+	err = service.Transactor.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		updatedCustomer, err = service.CustomerRepository.Update(ctx, customer)
+		if err != nil {
+			return err
+		}
+
+		events, err := customerEvents(updatedCustomer)
+		if err != nil {
+			return err
+		}
+		return service.OutboxRepository.Save(ctx, events)
+	})
 	if err != nil {
-		return web.CustomerResponse{}, err
+		return web.CustomerResponse{}, versionConflict("Customer", err)
 	}
 
 	return helper.ToCustomerResponse(updatedCustomer), nil
 }
 
+// Patch Customer with a merge patch or JSON patch applied to its current state
+func (service *CustomerServiceImpl) Patch(ctx context.Context, request web.PatchRequest) (web.CustomerResponse, error) {
+	ctx, span := startSpan(ctx, "CustomerService.Patch", idAttribute("customer", request.Id))
+	defer span.End()
+
+	customer, err := service.CustomerRepository.FindById(ctx, request.Id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return web.CustomerResponse{}, exception.NewNotFoundError("Customer not found")
+	} else if err != nil {
+		return web.CustomerResponse{}, err
+	}
+	if err := checkVersion(ctx, "Customer", request.Version, customer.Version); err != nil {
+		return web.CustomerResponse{}, err
+	}
+
+	var updateRequest web.CustomerUpdateRequest
+	if err := applyPatch(request, helper.ToCustomerUpdateRequest(customer), customer.Version, &updateRequest); err != nil {
+		return web.CustomerResponse{}, err
+	}
+
+	return service.Update(ctx, updateRequest)
+}
+
 // Delete Customer
-func (service *CustomerServiceImpl) Delete(ctx context.Context, customerId uint64) error {
+func (service *CustomerServiceImpl) Delete(ctx context.Context, customerId uint64, version uint64) error {
+	ctx, span := startSpan(ctx, "CustomerService.Delete", idAttribute("customer", customerId))
+	defer span.End()
+
 	customer, err := service.CustomerRepository.FindById(ctx, customerId)
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return exception.NewNotFoundError("Customer not found")
@@ -70,11 +132,33 @@ func (service *CustomerServiceImpl) Delete(ctx context.Context, customerId uint6
 		return err
 	}
 
-	return service.CustomerRepository.Delete(ctx, customer)
+	if err := checkVersion(ctx, "Customer", version, customer.Version); err != nil {
+		return err
+	}
+
+	return versionConflict("Customer", service.CustomerRepository.Delete(ctx, customer))
+}
+
+// Restore a soft-deleted Customer
+func (service *CustomerServiceImpl) Restore(ctx context.Context, customerId uint64) (web.CustomerResponse, error) {
+	ctx, span := startSpan(ctx, "CustomerService.Restore", idAttribute("customer", customerId))
+	defer span.End()
+
+	err := service.CustomerRepository.Restore(ctx, customerId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return web.CustomerResponse{}, exception.NewNotFoundError("Deleted customer not found")
+	} else if err != nil {
+		return web.CustomerResponse{}, err
+	}
+
+	return service.FindById(ctx, customerId)
 }
 
 // Find Customer By ID
 func (service *CustomerServiceImpl) FindById(ctx context.Context, customerId uint64) (web.CustomerResponse, error) {
+	ctx, span := startSpan(ctx, "CustomerService.FindById", idAttribute("customer", customerId))
+	defer span.End()
+
 	customer, err := service.CustomerRepository.FindById(ctx, customerId)
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return web.CustomerResponse{}, exception.NewNotFoundError("Customer not found")
@@ -87,10 +171,14 @@ func (service *CustomerServiceImpl) FindById(ctx context.Context, customerId uin
 
 // Find All Customers
 func (service *CustomerServiceImpl) FindAll(ctx context.Context) ([]web.CustomerResponse, error) {
+	ctx, span := startSpan(ctx, "CustomerService.FindAll")
+	defer span.End()
+
 	customers, err := service.CustomerRepository.FindAll(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	span.SetAttributes(countAttribute(len(customers)))
 	return helper.ToCustomerResponses(customers), nil
 }
@@ -18,7 +18,7 @@ func TestCreateCustomer(t *testing.T) {
 
 	mockRepo := mocks.NewMockCustomerRepository(ctrl)
 	mockValidator := validator.New()
-	customerService := NewCustomerService(mockRepo, mockValidator)
+	customerService := NewCustomerService(mockRepo, discardOutbox(ctrl), passthroughTransactor(ctrl), mockValidator)
 
 	tests := []struct {
 		name      string
@@ -73,7 +73,7 @@ func TestDeleteCustomer(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockCustomerRepository(ctrl)
-	customerService := NewCustomerService(mockRepo, validator.New())
+	customerService := NewCustomerService(mockRepo, discardOutbox(ctrl), passthroughTransactor(ctrl), validator.New())
 
 	tests := []struct {
 		name       string
@@ -103,7 +103,7 @@ func TestDeleteCustomer(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
-			err := customerService.Delete(context.Background(), tt.customerId)
+			err := customerService.Delete(context.Background(), tt.customerId, 0)
 			if tt.expectErr {
 				assert.Error(t, err)
 			} else {
@@ -168,7 +168,7 @@ func TestUpdateCustomer(t *testing.T) {
 			mockCustomerRepo := mocks.NewMockCustomerRepository(ctrl)
 			tt.mock(mockCustomerRepo)
 
-			service := NewCustomerService(mockCustomerRepo, validator.New())
+			service := NewCustomerService(mockCustomerRepo, discardOutbox(ctrl), passthroughTransactor(ctrl), validator.New())
 			_, err := service.Update(context.Background(), tt.input)
 
 			if tt.expects != nil {
@@ -213,7 +213,7 @@ func TestFindAllCustomers(t *testing.T) {
 			mockCustomerRepo := mocks.NewMockCustomerRepository(ctrl)
 			tt.mock(mockCustomerRepo)
 
-			service := NewCustomerService(mockCustomerRepo, validator.New())
+			service := NewCustomerService(mockCustomerRepo, discardOutbox(ctrl), passthroughTransactor(ctrl), validator.New())
 			result, err := service.FindAll(context.Background())
 			assert.Equal(t, tt.expects, result)
 			assert.Equal(t, tt.err, err)
@@ -256,7 +256,7 @@ func TestFindByIdCustomer(t *testing.T) {
 			mockCustomerRepo := mocks.NewMockCustomerRepository(ctrl)
 			tt.mock(mockCustomerRepo)
 
-			service := NewCustomerService(mockCustomerRepo, validator.New())
+			service := NewCustomerService(mockCustomerRepo, discardOutbox(ctrl), passthroughTransactor(ctrl), validator.New())
 			result, err := service.FindById(context.Background(), tt.input)
 			assert.Equal(t, tt.expects, result)
 			assert.Equal(t, tt.err, err)
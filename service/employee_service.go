@@ -8,7 +8,9 @@ import (
 type EmployeeService interface {
 	Create(ctx context.Context, request web.EmployeeCreateRequest) (web.EmployeeResponse, error)
 	Update(ctx context.Context, request web.EmployeeUpdateRequest) (web.EmployeeResponse, error)
-	Delete(ctx context.Context, employeeId uint64) error
+	Patch(ctx context.Context, request web.PatchRequest) (web.EmployeeResponse, error)
+	Delete(ctx context.Context, employeeId uint64, version uint64) error
+	Restore(ctx context.Context, employeeId uint64) (web.EmployeeResponse, error)
 	FindById(ctx context.Context, employeeId uint64) (web.EmployeeResponse, error)
 	FindAll(ctx context.Context) ([]web.EmployeeResponse, error)
 }
@@ -26,11 +26,19 @@ func NewEmployeeService(employeeRepository repository.EmployeeRepository, valida
 
 // Create Employee
 func (service *EmployeeServiceImpl) Create(ctx context.Context, request web.EmployeeCreateRequest) (web.EmployeeResponse, error) {
+	ctx, span := startSpan(ctx, "EmployeeService.Create")
+	defer span.End()
+
 	if err := service.Validate.Struct(request); err != nil {
 		return web.EmployeeResponse{}, err
 	}
 
-	employee := domain.Employee{Name: request.Name}
+	employee := domain.Employee{
+		Name:      request.Name,
+		Email:     request.Email,
+		Phone:     request.Phone,
+		DateHired: request.DateHired,
+	}
 	savedEmployee, err := service.EmployeeRepository.Save(ctx, employee)
 	if err != nil {
 		return web.EmployeeResponse{}, err
@@ -41,6 +49,9 @@ func (service *EmployeeServiceImpl) Create(ctx context.Context, request web.Empl
 
 // Update Employee
 func (service *EmployeeServiceImpl) Update(ctx context.Context, request web.EmployeeUpdateRequest) (web.EmployeeResponse, error) {
+	ctx, span := startSpan(ctx, "EmployeeService.Update", idAttribute("employee", request.Id))
+	defer span.End()
+
 	if err := service.Validate.Struct(request); err != nil {
 		return web.EmployeeResponse{}, err
 	}
@@ -51,18 +62,50 @@ func (service *EmployeeServiceImpl) Update(ctx context.Context, request web.Empl
 	} else if err != nil {
 		return web.EmployeeResponse{}, err
 	}
+	if err := checkVersion(ctx, "Employee", request.Version, employee.Version); err != nil {
+		return web.EmployeeResponse{}, err
+	}
 
 	employee.Name = request.Name
+	employee.Email = request.Email
+	employee.Phone = request.Phone
+	employee.DateHired = request.DateHired
 	updatedEmployee, err := service.EmployeeRepository.Update(ctx, employee)
 	if err != nil {
-		return web.EmployeeResponse{}, err
+		return web.EmployeeResponse{}, versionConflict("Employee", err)
 	}
 
 	return helper.ToEmployeeResponse(updatedEmployee), nil
 }
 
+// Patch Employee with a merge patch or JSON patch applied to its current state
+func (service *EmployeeServiceImpl) Patch(ctx context.Context, request web.PatchRequest) (web.EmployeeResponse, error) {
+	ctx, span := startSpan(ctx, "EmployeeService.Patch", idAttribute("employee", request.Id))
+	defer span.End()
+
+	employee, err := service.EmployeeRepository.FindById(ctx, request.Id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return web.EmployeeResponse{}, exception.NewNotFoundError("Employee not found")
+	} else if err != nil {
+		return web.EmployeeResponse{}, err
+	}
+	if err := checkVersion(ctx, "Employee", request.Version, employee.Version); err != nil {
+		return web.EmployeeResponse{}, err
+	}
+
+	var updateRequest web.EmployeeUpdateRequest
+	if err := applyPatch(request, helper.ToEmployeeUpdateRequest(employee), employee.Version, &updateRequest); err != nil {
+		return web.EmployeeResponse{}, err
+	}
+
+	return service.Update(ctx, updateRequest)
+}
+
 // Delete Employee
-func (service *EmployeeServiceImpl) Delete(ctx context.Context, employeeId uint64) error {
+func (service *EmployeeServiceImpl) Delete(ctx context.Context, employeeId uint64, version uint64) error {
+	ctx, span := startSpan(ctx, "EmployeeService.Delete", idAttribute("employee", employeeId))
+	defer span.End()
+
 	employee, err := service.EmployeeRepository.FindById(ctx, employeeId)
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return exception.NewNotFoundError("Employee not found")
@@ -70,11 +113,33 @@ func (service *EmployeeServiceImpl) Delete(ctx context.Context, employeeId uint6
 		return err
 	}
 
-	return service.EmployeeRepository.Delete(ctx, employee)
+	if err := checkVersion(ctx, "Employee", version, employee.Version); err != nil {
+		return err
+	}
+
+	return versionConflict("Employee", service.EmployeeRepository.Delete(ctx, employee))
+}
+
+// Restore a soft-deleted Employee
+func (service *EmployeeServiceImpl) Restore(ctx context.Context, employeeId uint64) (web.EmployeeResponse, error) {
+	ctx, span := startSpan(ctx, "EmployeeService.Restore", idAttribute("employee", employeeId))
+	defer span.End()
+
+	err := service.EmployeeRepository.Restore(ctx, employeeId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return web.EmployeeResponse{}, exception.NewNotFoundError("Deleted employee not found")
+	} else if err != nil {
+		return web.EmployeeResponse{}, err
+	}
+
+	return service.FindById(ctx, employeeId)
 }
 
 // Find Employee By ID
 func (service *EmployeeServiceImpl) FindById(ctx context.Context, employeeId uint64) (web.EmployeeResponse, error) {
+	ctx, span := startSpan(ctx, "EmployeeService.FindById", idAttribute("employee", employeeId))
+	defer span.End()
+
 	employee, err := service.EmployeeRepository.FindById(ctx, employeeId)
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return web.EmployeeResponse{}, exception.NewNotFoundError("Employee not found")
@@ -87,10 +152,14 @@ func (service *EmployeeServiceImpl) FindById(ctx context.Context, employeeId uin
 
 // Find All Employees
 func (service *EmployeeServiceImpl) FindAll(ctx context.Context) ([]web.EmployeeResponse, error) {
+	ctx, span := startSpan(ctx, "EmployeeService.FindAll")
+	defer span.End()
+
 	employees, err := service.EmployeeRepository.FindAll(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	span.SetAttributes(countAttribute(len(employees)))
 	return helper.ToEmployeeResponses(employees), nil
 }
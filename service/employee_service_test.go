@@ -103,7 +103,7 @@ func TestDeleteEmployee(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
-			err := employeeService.Delete(context.Background(), tt.employeeId)
+			err := employeeService.Delete(context.Background(), tt.employeeId, 0)
 			if tt.expectErr {
 				assert.Error(t, err)
 			} else {
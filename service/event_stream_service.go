@@ -0,0 +1,12 @@
+package service
+
+import (
+	"context"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+)
+
+type EventStreamService interface {
+	Subscribe(topics []string, lastEventId uint64) (web.EventSubscription, error)
+	Poll(ctx context.Context) (int, error)
+	Close()
+}
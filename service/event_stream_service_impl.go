@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/repository"
+	"sync"
+)
+
+const (
+	// eventStreamPollSize is the number of audit log rows read per query while catching up
+	eventStreamPollSize = 500
+	// eventSubscriberBacklog is how many events a subscriber may lag behind before it is dropped
+	eventSubscriberBacklog = 64
+)
+
+// EventTopics are the audit log entities published on the event stream
+var EventTopics = []string{"category", "customer", "employee", "product"}
+
+type eventSubscriber struct {
+	topics map[string]bool // nil for every topic
+	events chan web.ChangeEvent
+}
+
+func (subscriber *eventSubscriber) wants(event web.ChangeEvent) bool {
+	return subscriber.topics == nil || subscriber.topics[event.Topic]
+}
+
+// EventStreamServiceImpl tails the audit log, which only ever holds committed changes, and fans its
+// rows out to the subscribers of their topic. The newest events are kept in a ring buffer so that
+// reconnecting subscribers can resume from their Last-Event-ID.
+type EventStreamServiceImpl struct {
+	AuditRepository repository.AuditRepository
+
+	mutex       sync.Mutex
+	ring        []web.ChangeEvent
+	next        int    // index of the ring slot written next, which holds the oldest event once full
+	floor       uint64 // events with an id up to floor are no longer all in the ring
+	cursor      uint64 // id of the newest audit log row read
+	seeded      bool
+	closed      bool
+	subscribers map[*eventSubscriber]struct{}
+}
+
+func NewEventStreamService(auditRepository repository.AuditRepository, bufferSize int) EventStreamService {
+	return &EventStreamServiceImpl{
+		AuditRepository: auditRepository,
+		ring:            make([]web.ChangeEvent, 0, bufferSize),
+		subscribers:     make(map[*eventSubscriber]struct{}),
+	}
+}
+
+// Subscribe to the changes of topics, all of them when empty, resuming after lastEventId unless it is 0
+func (service *EventStreamServiceImpl) Subscribe(topics []string, lastEventId uint64) (web.EventSubscription, error) {
+	subscriber := &eventSubscriber{events: make(chan web.ChangeEvent, eventSubscriberBacklog)}
+	for _, topic := range topics {
+		if !isEventTopic(topic) {
+			return web.EventSubscription{}, exception.NewBadRequestError("Unknown topic " + topic)
+		}
+		if subscriber.topics == nil {
+			subscriber.topics = make(map[string]bool)
+		}
+		subscriber.topics[topic] = true
+	}
+
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+	if service.closed {
+		return web.EventSubscription{}, exception.NewServiceUnavailableError("Event stream is shutting down")
+	}
+
+	subscription := web.EventSubscription{Events: subscriber.events}
+	if lastEventId != 0 {
+		if lastEventId < service.floor {
+			subscription.Reset = true
+		} else {
+			for _, event := range service.buffered() {
+				if event.Id > lastEventId && subscriber.wants(event) {
+					subscription.Missed = append(subscription.Missed, event)
+				}
+			}
+		}
+	}
+
+	service.subscribers[subscriber] = struct{}{}
+	subscription.Close = func() {
+		service.mutex.Lock()
+		defer service.mutex.Unlock()
+		service.drop(subscriber)
+	}
+	return subscription, nil
+}
+
+// Poll publishes the audit log rows written since the last poll and returns how many were published.
+// The first poll only fills the ring buffer with the newest rows, which were written before anyone subscribed.
+// Rows are read in id order, so a transaction that commits after a later id was read goes unannounced;
+// the stream is a hint to reload, not a replication log.
+func (service *EventStreamServiceImpl) Poll(ctx context.Context) (int, error) {
+	ctx, span := startSpan(ctx, "EventStreamService.Poll")
+	defer span.End()
+
+	if !service.seeded {
+		return 0, service.seed(ctx)
+	}
+
+	published := 0
+	for {
+		logs, err := service.AuditRepository.FindSince(ctx, service.cursor, eventStreamPollSize)
+		if err != nil {
+			return published, err
+		}
+
+		service.mutex.Lock()
+		for _, log := range logs {
+			service.cursor = log.Id
+			if event, ok := toChangeEvent(log); ok {
+				service.push(event)
+				service.publish(event)
+				published++
+			}
+		}
+		service.mutex.Unlock()
+
+		if len(logs) < eventStreamPollSize {
+			return published, nil
+		}
+	}
+}
+
+// seed fills the ring buffer with the newest audit log rows so subscribers can resume across restarts
+func (service *EventStreamServiceImpl) seed(ctx context.Context) error {
+	size := cap(service.ring)
+	logs, err := service.AuditRepository.FindAll(ctx, domain.AuditFilter{Limit: size})
+	if err != nil {
+		return err
+	}
+
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+	if len(logs) == size && size > 0 {
+		service.floor = logs[len(logs)-1].Id - 1
+	}
+	for i := len(logs) - 1; i >= 0; i-- {
+		if event, ok := toChangeEvent(logs[i]); ok {
+			service.push(event)
+		}
+	}
+	if len(logs) > 0 {
+		service.cursor = logs[0].Id
+	}
+	service.seeded = true
+	return nil
+}
+
+// push stores event in the ring buffer, evicting the oldest one when it is full
+func (service *EventStreamServiceImpl) push(event web.ChangeEvent) {
+	if cap(service.ring) == 0 {
+		service.floor = event.Id
+		return
+	}
+	if len(service.ring) < cap(service.ring) {
+		service.ring = append(service.ring, event)
+		return
+	}
+	service.floor = service.ring[service.next].Id
+	service.ring[service.next] = event
+	service.next = (service.next + 1) % len(service.ring)
+}
+
+// buffered returns the ring buffer oldest first
+func (service *EventStreamServiceImpl) buffered() []web.ChangeEvent {
+	return append(append([]web.ChangeEvent{}, service.ring[service.next:]...), service.ring[:service.next]...)
+}
+
+// publish hands event to every interested subscriber, dropping those whose backlog is full
+func (service *EventStreamServiceImpl) publish(event web.ChangeEvent) {
+	for subscriber := range service.subscribers {
+		if !subscriber.wants(event) {
+			continue
+		}
+		select {
+		case subscriber.events <- event:
+		default:
+			service.drop(subscriber)
+		}
+	}
+}
+
+// Close ends every subscription and refuses new ones, so open streams let the server shut down
+func (service *EventStreamServiceImpl) Close() {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	service.closed = true
+	for subscriber := range service.subscribers {
+		service.drop(subscriber)
+	}
+}
+
+func (service *EventStreamServiceImpl) drop(subscriber *eventSubscriber) {
+	if _, ok := service.subscribers[subscriber]; ok {
+		delete(service.subscribers, subscriber)
+		close(subscriber.events)
+	}
+}
+
+func toChangeEvent(log domain.AuditLog) (web.ChangeEvent, bool) {
+	if !isEventTopic(log.Entity) {
+		return web.ChangeEvent{}, false
+	}
+	return web.ChangeEvent{
+		Id:         log.Id,
+		Topic:      log.Entity,
+		Action:     log.Action,
+		EntityId:   log.EntityId,
+		OccurredAt: log.CreatedAt,
+	}, true
+}
+
+func isEventTopic(topic string) bool {
+	for _, eventTopic := range EventTopics {
+		if topic == eventTopic {
+			return true
+		}
+	}
+	return false
+}
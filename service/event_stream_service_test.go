@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+func auditLogs(entity string, ids ...uint64) []domain.AuditLog {
+	var logs []domain.AuditLog
+	for _, id := range ids {
+		logs = append(logs, domain.AuditLog{Id: id, Entity: entity, EntityId: id * 10, Action: "update"})
+	}
+	return logs
+}
+
+func eventIds(events []web.ChangeEvent) []uint64 {
+	var ids []uint64
+	for _, event := range events {
+		ids = append(ids, event.Id)
+	}
+	return ids
+}
+
+func TestEventStreamPublishesNewAuditLogs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mocks.NewMockAuditRepository(ctrl)
+
+	// newest first, as FindAll returns them; product prices are not a stream topic
+	mockRepo.EXPECT().FindAll(gomock.Any(), domain.AuditFilter{Limit: 3}).
+		Return(append(auditLogs("product", 5), append(auditLogs("product_price", 4), auditLogs("customer", 3)...)...), nil)
+	mockRepo.EXPECT().FindSince(gomock.Any(), uint64(5), eventStreamPollSize).
+		Return(append(auditLogs("category", 6), auditLogs("product", 7)...), nil)
+
+	service := NewEventStreamService(mockRepo, 3)
+	_, err := service.Poll(context.Background())
+	assert.NoError(t, err)
+
+	products, err := service.Subscribe([]string{"product"}, 0)
+	assert.NoError(t, err)
+	all, err := service.Subscribe(nil, 0)
+	assert.NoError(t, err)
+
+	published, err := service.Poll(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, published)
+
+	assert.Equal(t, web.ChangeEvent{Id: 7, Topic: "product", Action: "update", EntityId: 70}, <-products.Events)
+	assert.Len(t, products.Events, 0)
+	assert.Equal(t, uint64(6), (<-all.Events).Id)
+	assert.Equal(t, uint64(7), (<-all.Events).Id)
+
+	// the ring holds 3 events; 3 was evicted by 7, so a client that only saw 3 has to reload
+	resumed, err := service.Subscribe(nil, 3)
+	assert.NoError(t, err)
+	assert.False(t, resumed.Reset)
+	assert.Equal(t, []uint64{5, 6, 7}, eventIds(resumed.Missed))
+
+	resumed, err = service.Subscribe([]string{"category"}, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{6}, eventIds(resumed.Missed))
+
+	resumed, err = service.Subscribe(nil, 2)
+	assert.NoError(t, err)
+	assert.True(t, resumed.Reset)
+	assert.Empty(t, resumed.Missed)
+}
+
+func TestEventStreamDropsSlowSubscribers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mocks.NewMockAuditRepository(ctrl)
+
+	var ids []uint64
+	for id := uint64(1); id <= eventSubscriberBacklog+1; id++ {
+		ids = append(ids, id)
+	}
+	mockRepo.EXPECT().FindAll(gomock.Any(), gomock.Any()).Return(nil, nil)
+	mockRepo.EXPECT().FindSince(gomock.Any(), uint64(0), eventStreamPollSize).Return(auditLogs("employee", ids...), nil)
+
+	service := NewEventStreamService(mockRepo, 10)
+	_, err := service.Poll(context.Background())
+	assert.NoError(t, err)
+	subscription, err := service.Subscribe([]string{"employee"}, 0)
+	assert.NoError(t, err)
+	_, err = service.Poll(context.Background())
+	assert.NoError(t, err)
+
+	received := 0
+	for range subscription.Events {
+		received++
+	}
+	assert.Equal(t, eventSubscriberBacklog, received)
+	subscription.Close()
+}
+
+func TestEventStreamRejectsUnknownTopics(t *testing.T) {
+	service := NewEventStreamService(nil, 10)
+	_, err := service.Subscribe([]string{"product", "invoice"}, 0)
+	assert.Equal(t, exception.NewBadRequestError("Unknown topic invoice"), err)
+}
+
+func TestEventStreamCloseEndsSubscriptions(t *testing.T) {
+	service := NewEventStreamService(nil, 10)
+	subscription, err := service.Subscribe(nil, 0)
+	assert.NoError(t, err)
+
+	service.Close()
+	_, open := <-subscription.Events
+	assert.False(t, open)
+	subscription.Close()
+
+	_, err = service.Subscribe(nil, 0)
+	assert.Equal(t, exception.NewServiceUnavailableError("Event stream is shutting down"), err)
+}
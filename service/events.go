@@ -0,0 +1,109 @@
+package service
+
+import (
+	"encoding/json"
+	"github.com/aronipurwanto/go-restful-api/helper"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+)
+
+// productEvents returns the domain events of writing a product; before is nil when it was created
+func productEvents(before *domain.Product, after domain.Product) ([]domain.OutboxEvent, error) {
+	if before == nil {
+		event, err := newEvent(domain.ProductCreatedEvent, "product", after.ProductID, helper.ToProductResponse(after))
+		if err != nil {
+			return nil, err
+		}
+		return []domain.OutboxEvent{event}, nil
+	}
+
+	var events []domain.OutboxEvent
+	if before.Price != after.Price {
+		event, err := newEvent(domain.PriceChangedEvent, "product", after.ProductID, web.PriceChangedEvent{
+			ProductId:     after.ProductID,
+			PreviousPrice: before.Price,
+			Price:         after.Price,
+		})
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	if before.StockQty != after.StockQty {
+		event, err := newEvent(domain.StockChangedEvent, "product", after.ProductID, web.StockChangedEvent{
+			ProductId:        after.ProductID,
+			PreviousStockQty: before.StockQty,
+			StockQty:         after.StockQty,
+		})
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// variantEvents returns the StockChanged events of writing variants of a product: before holds the
+// variants as they were, after as they were written; variants missing from after lost their stock
+func variantEvents(before []domain.ProductVariant, after []domain.ProductVariant) ([]domain.OutboxEvent, error) {
+	written := make(map[uint64]bool, len(after))
+	var events []domain.OutboxEvent
+	stockChanged := func(variant domain.ProductVariant, previousStockQty int, stockQty int) error {
+		if previousStockQty == stockQty {
+			return nil
+		}
+		variantId := variant.Id
+		event, err := newEvent(domain.StockChangedEvent, "product", variant.ProductId, web.StockChangedEvent{
+			ProductId:        variant.ProductId,
+			VariantId:        &variantId,
+			PreviousStockQty: previousStockQty,
+			StockQty:         stockQty,
+		})
+		if err != nil {
+			return err
+		}
+		events = append(events, event)
+		return nil
+	}
+
+	previous := make(map[uint64]int, len(before))
+	for _, variant := range before {
+		previous[variant.Id] = variant.StockQty
+	}
+	for _, variant := range after {
+		written[variant.Id] = true
+		if err := stockChanged(variant, previous[variant.Id], variant.StockQty); err != nil {
+			return nil, err
+		}
+	}
+	for _, variant := range before {
+		if !written[variant.Id] {
+			if err := stockChanged(variant, variant.StockQty, 0); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return events, nil
+}
+
+// customerEvents returns the CustomerUpdated event of an updated customer
+func customerEvents(customer domain.Customer) ([]domain.OutboxEvent, error) {
+	event, err := newEvent(domain.CustomerUpdatedEvent, "customer", customer.CustomerID, helper.ToCustomerResponse(customer))
+	if err != nil {
+		return nil, err
+	}
+	return []domain.OutboxEvent{event}, nil
+}
+
+func newEvent(eventType string, aggregateType string, aggregateId uint64, data interface{}) (domain.OutboxEvent, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return domain.OutboxEvent{}, err
+	}
+	return domain.OutboxEvent{
+		Type:          eventType,
+		AggregateType: aggregateType,
+		AggregateId:   aggregateId,
+		Payload:       payload,
+	}, nil
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/money"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+// passthroughTransactor runs transaction bodies directly, as if every transaction committed
+func passthroughTransactor(ctrl *gomock.Controller) *mocks.MockTransactor {
+	mockTransactor := mocks.NewMockTransactor(ctrl)
+	mockTransactor.EXPECT().Transaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		}).AnyTimes()
+	return mockTransactor
+}
+
+// discardOutbox accepts and drops every event
+func discardOutbox(ctrl *gomock.Controller) *mocks.MockOutboxRepository {
+	mockOutbox := mocks.NewMockOutboxRepository(ctrl)
+	mockOutbox.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	return mockOutbox
+}
+
+func TestProductEvents(t *testing.T) {
+	before := domain.Product{ProductID: 7, Name: "Tea", Price: money.FromInt(10, "IDR"), StockQty: 5}
+
+	tests := []struct {
+		name   string
+		before *domain.Product
+		after  func(product domain.Product) domain.Product
+		expect []string
+	}{
+		{
+			name:   "Created",
+			before: nil,
+			after:  func(product domain.Product) domain.Product { return product },
+			expect: []string{domain.ProductCreatedEvent},
+		},
+		{
+			name:   "Price And Stock Changed",
+			before: &before,
+			after: func(product domain.Product) domain.Product {
+				product.Price = money.FromInt(12, "IDR")
+				product.StockQty = 3
+				return product
+			},
+			expect: []string{domain.PriceChangedEvent, domain.StockChangedEvent},
+		},
+		{
+			name:   "Name Changed Only",
+			before: &before,
+			after: func(product domain.Product) domain.Product {
+				product.Name = "Green Tea"
+				return product
+			},
+			expect: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := productEvents(tt.before, tt.after(before))
+			assert.NoError(t, err)
+
+			var types []string
+			for _, event := range events {
+				assert.Equal(t, "product", event.AggregateType)
+				assert.Equal(t, uint64(7), event.AggregateId)
+				types = append(types, event.Type)
+			}
+			assert.Equal(t, tt.expect, types)
+		})
+	}
+}
+
+func TestProductEventsPayload(t *testing.T) {
+	before := domain.Product{ProductID: 7, Price: money.FromInt(10, "IDR"), StockQty: 5}
+	after := before
+	after.Price = money.FromInt(12, "IDR")
+
+	events, err := productEvents(&before, after)
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+
+	var payload web.PriceChangedEvent
+	assert.NoError(t, json.Unmarshal(events[0].Payload, &payload))
+	assert.Equal(t, web.PriceChangedEvent{ProductId: 7, PreviousPrice: money.FromInt(10, "IDR"), Price: money.FromInt(12, "IDR")}, payload)
+}
+
+func TestVariantEvents(t *testing.T) {
+	small := domain.ProductVariant{Id: 3, ProductId: 7, StockQty: 5}
+	large := domain.ProductVariant{Id: 4, ProductId: 7, StockQty: 2}
+
+	tests := []struct {
+		name   string
+		before []domain.ProductVariant
+		after  []domain.ProductVariant
+		expect []web.StockChangedEvent
+	}{
+		{
+			name:   "Created With Stock",
+			after:  []domain.ProductVariant{small},
+			expect: []web.StockChangedEvent{{ProductId: 7, VariantId: &small.Id, PreviousStockQty: 0, StockQty: 5}},
+		},
+		{
+			name:  "Created Without Stock",
+			after: []domain.ProductVariant{{Id: 5, ProductId: 7}},
+		},
+		{
+			name:   "Stock Updated",
+			before: []domain.ProductVariant{small},
+			after:  []domain.ProductVariant{{Id: 3, ProductId: 7, StockQty: 8}},
+			expect: []web.StockChangedEvent{{ProductId: 7, VariantId: &small.Id, PreviousStockQty: 5, StockQty: 8}},
+		},
+		{
+			name:   "Regenerated Without A Combination",
+			before: []domain.ProductVariant{small, large},
+			after:  []domain.ProductVariant{small, {Id: 6, ProductId: 7}},
+			expect: []web.StockChangedEvent{{ProductId: 7, VariantId: &large.Id, PreviousStockQty: 2, StockQty: 0}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := variantEvents(tt.before, tt.after)
+			assert.NoError(t, err)
+
+			var payloads []web.StockChangedEvent
+			for _, event := range events {
+				assert.Equal(t, domain.StockChangedEvent, event.Type)
+				assert.Equal(t, "product", event.AggregateType)
+				assert.Equal(t, uint64(7), event.AggregateId)
+
+				var payload web.StockChangedEvent
+				assert.NoError(t, json.Unmarshal(event.Payload, &payload))
+				payloads = append(payloads, payload)
+			}
+			assert.Equal(t, tt.expect, payloads)
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package service
+
+import (
+	"context"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"io"
+)
+
+type ExportService interface {
+	Prepare(request web.ExportRequest) (web.ExportFileResponse, error)
+	Export(ctx context.Context, request web.ExportRequest, w io.Writer) error
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/helper"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/repository"
+	"github.com/go-playground/validator/v10"
+	"io"
+	"strings"
+	"time"
+)
+
+// exportBatchSize is the number of rows read from the database at a time
+const exportBatchSize = 500
+
+// exportColumn is a named column of an export and how to read it from a row
+type exportColumn[T any] struct {
+	Name  string
+	Value func(T) interface{}
+}
+
+// productExportColumns match the import columns, so an export can be edited and imported again
+var productExportColumns = []exportColumn[domain.Product]{
+	{"id", func(p domain.Product) interface{} { return p.ProductID }},
+	{"name", func(p domain.Product) interface{} { return p.Name }},
+	{"description", func(p domain.Product) interface{} { return p.Description }},
+	{"price", func(p domain.Product) interface{} { return p.Price.Decimal() }},
+	{"currency", func(p domain.Product) interface{} { return p.Price.Currency }},
+	{"stock_qty", func(p domain.Product) interface{} { return p.StockQty }},
+	{"category", func(p domain.Product) interface{} { return p.Category.Name }},
+	{"sku", func(p domain.Product) interface{} { return p.SKU }},
+	{"tax_rate", func(p domain.Product) interface{} { return p.TaxRate.Decimal() }},
+	{"barcodes", func(p domain.Product) interface{} {
+		codes := make([]string, len(p.Barcodes))
+		for i, barcode := range p.Barcodes {
+			codes[i] = fmt.Sprintf("%s:%d", barcode.Code, barcode.PackQty)
+		}
+		return strings.Join(codes, "|")
+	}},
+}
+
+var customerExportColumns = []exportColumn[domain.Customer]{
+	{"id", func(c domain.Customer) interface{} { return c.CustomerID }},
+	{"name", func(c domain.Customer) interface{} { return c.Name }},
+	{"email", func(c domain.Customer) interface{} { return c.Email }},
+	{"phone", func(c domain.Customer) interface{} { return c.Phone }},
+	{"address", func(c domain.Customer) interface{} { return c.Address }},
+	{"loyalty_points", func(c domain.Customer) interface{} { return c.LoyaltyPts }},
+}
+
+var employeeExportColumns = []exportColumn[domain.Employee]{
+	{"id", func(e domain.Employee) interface{} { return e.EmployeeID }},
+	{"name", func(e domain.Employee) interface{} { return e.Name }},
+	{"role", func(e domain.Employee) interface{} { return e.Role }},
+	{"email", func(e domain.Employee) interface{} { return e.Email }},
+	{"phone", func(e domain.Employee) interface{} { return e.Phone }},
+	{"date_hired", func(e domain.Employee) interface{} { return e.DateHired }},
+}
+
+type ExportServiceImpl struct {
+	ProductRepository  repository.ProductRepository
+	CustomerRepository repository.CustomerRepository
+	EmployeeRepository repository.EmployeeRepository
+	Validate           *validator.Validate
+}
+
+func NewExportService(productRepository repository.ProductRepository, customerRepository repository.CustomerRepository, employeeRepository repository.EmployeeRepository, validate *validator.Validate) ExportService {
+	return &ExportServiceImpl{
+		ProductRepository:  productRepository,
+		CustomerRepository: customerRepository,
+		EmployeeRepository: employeeRepository,
+		Validate:           validate,
+	}
+}
+
+// Prepare checks an export request before anything is streamed and names the file
+func (service *ExportServiceImpl) Prepare(request web.ExportRequest) (web.ExportFileResponse, error) {
+	if err := service.Validate.Struct(request); err != nil {
+		return web.ExportFileResponse{}, exception.NewBadRequestError(err.Error())
+	}
+	if request.CategoryId != nil && request.Resource != "products" {
+		return web.ExportFileResponse{}, exception.NewBadRequestError("category_id can only filter products")
+	}
+
+	var err error
+	switch request.Resource {
+	case "products":
+		_, err = selectExportColumns(productExportColumns, request.Columns)
+	case "customers":
+		_, err = selectExportColumns(customerExportColumns, request.Columns)
+	case "employees":
+		_, err = selectExportColumns(employeeExportColumns, request.Columns)
+	}
+	if err != nil {
+		return web.ExportFileResponse{}, err
+	}
+
+	return web.ExportFileResponse{
+		Filename:    fmt.Sprintf("%s-%s.%s", request.Resource, time.Now().Format("20060102-150405"), request.Format),
+		ContentType: helper.SpreadsheetContentType(request.Format),
+	}, nil
+}
+
+// Export writes every matching row to w, reading them from the repository in batches
+func (service *ExportServiceImpl) Export(ctx context.Context, request web.ExportRequest, w io.Writer) error {
+	ctx, span := startSpan(ctx, "ExportService.Export")
+	defer span.End()
+
+	switch request.Resource {
+	case "products":
+		columns, err := selectExportColumns(productExportColumns, request.Columns)
+		if err != nil {
+			return err
+		}
+		filter := domain.ProductFilter{CategoryId: request.CategoryId}
+		return writeExport(w, request.Format, columns, func(fn func([]domain.Product) error) error {
+			return service.ProductRepository.FindInBatches(ctx, filter, exportBatchSize, fn)
+		})
+	case "customers":
+		columns, err := selectExportColumns(customerExportColumns, request.Columns)
+		if err != nil {
+			return err
+		}
+		return writeExport(w, request.Format, columns, func(fn func([]domain.Customer) error) error {
+			return service.CustomerRepository.FindInBatches(ctx, exportBatchSize, fn)
+		})
+	case "employees":
+		columns, err := selectExportColumns(employeeExportColumns, request.Columns)
+		if err != nil {
+			return err
+		}
+		return writeExport(w, request.Format, columns, func(fn func([]domain.Employee) error) error {
+			return service.EmployeeRepository.FindInBatches(ctx, exportBatchSize, fn)
+		})
+	default:
+		return exception.NewBadRequestError("Unknown resource " + request.Resource)
+	}
+}
+
+// selectExportColumns returns the requested columns in the requested order, or all of them
+func selectExportColumns[T any](available []exportColumn[T], names []string) ([]exportColumn[T], error) {
+	if len(names) == 0 {
+		return available, nil
+	}
+
+	selected := make([]exportColumn[T], 0, len(names))
+	for _, name := range names {
+		found := false
+		for _, column := range available {
+			if column.Name == name {
+				selected = append(selected, column)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, exception.NewBadRequestError("Unknown column " + name)
+		}
+	}
+	return selected, nil
+}
+
+// writeExport writes the header, then each batch handed over by stream as it arrives
+func writeExport[T any](w io.Writer, format string, columns []exportColumn[T], stream func(fn func([]T) error) error) error {
+	names := make([]string, len(columns))
+	for i, column := range columns {
+		names[i] = column.Name
+	}
+
+	writer, err := helper.NewSpreadsheetWriter(w, format, names)
+	if err != nil {
+		return err
+	}
+
+	err = stream(func(items []T) error {
+		for _, item := range items {
+			values := make([]interface{}, len(columns))
+			for i, column := range columns {
+				values[i] = column.Value(item)
+			}
+			if err := writer.WriteRow(values); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		writer.Close()
+		return err
+	}
+	return writer.Close()
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/helper"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/money"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/repository/mocks"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+func TestPrepareExport(t *testing.T) {
+	categoryId := uint64(1)
+
+	tests := []struct {
+		name        string
+		input       web.ExportRequest
+		contentType string
+		err         error
+	}{
+		{
+			name:        "Success",
+			input:       web.ExportRequest{Resource: "products", Format: "jsonl", Columns: []string{"sku", "price"}, CategoryId: &categoryId},
+			contentType: "application/jsonl",
+		},
+		{
+			name:  "Unknown Column",
+			input: web.ExportRequest{Resource: "customers", Format: "csv", Columns: []string{"password"}},
+			err:   exception.NewBadRequestError("Unknown column password"),
+		},
+		{
+			name:  "Category Filter On Employees",
+			input: web.ExportRequest{Resource: "employees", Format: "csv", CategoryId: &categoryId},
+			err:   exception.NewBadRequestError("category_id can only filter products"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewExportService(nil, nil, nil, validator.New())
+			result, err := service.Prepare(tt.input)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.contentType, result.ContentType)
+		})
+	}
+
+	t.Run("Unsupported Format", func(t *testing.T) {
+		service := NewExportService(nil, nil, nil, validator.New())
+		_, err := service.Prepare(web.ExportRequest{Resource: "products", Format: "pdf"})
+		assert.IsType(t, exception.BadRequestError{}, err)
+	})
+}
+
+func TestExport(t *testing.T) {
+	categoryId := uint64(3)
+	coffee := domain.Product{ProductID: 1, Name: "Coffee", Price: money.FromInt(85000, "IDR"), StockQty: 10, SKU: "SKU-1", TaxRate: money.AmountFromInt(11),
+		Category: domain.Category{Id: 3, Name: "Drinks"}, Barcodes: []domain.ProductBarcode{{Code: "8991234567891", PackQty: 6}}}
+	tea := domain.Product{ProductID: 2, Name: "Tea, green", Price: money.MustParse("15000.5", "IDR"), StockQty: 5, SKU: "SKU-2", TaxRate: money.AmountFromInt(11),
+		Category: domain.Category{Id: 3, Name: "Drinks"}}
+
+	tests := []struct {
+		name    string
+		mock    func(mockProductRepo *mocks.MockProductRepository, mockCustomerRepo *mocks.MockCustomerRepository, mockEmployeeRepo *mocks.MockEmployeeRepository)
+		input   web.ExportRequest
+		expects string
+		err     error
+	}{
+		{
+			name: "Products CSV In Batches",
+			mock: func(mockProductRepo *mocks.MockProductRepository, mockCustomerRepo *mocks.MockCustomerRepository, mockEmployeeRepo *mocks.MockEmployeeRepository) {
+				mockProductRepo.EXPECT().FindInBatches(gomock.Any(), domain.ProductFilter{CategoryId: &categoryId}, exportBatchSize, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, filter domain.ProductFilter, batchSize int, fn func([]domain.Product) error) error {
+						if err := fn([]domain.Product{coffee}); err != nil {
+							return err
+						}
+						return fn([]domain.Product{tea})
+					})
+			},
+			input: web.ExportRequest{Resource: "products", Format: "csv", CategoryId: &categoryId},
+			expects: "id,name,description,price,currency,stock_qty,category,sku,tax_rate,barcodes\n" +
+				"1,Coffee,,85000.00,IDR,10,Drinks,SKU-1,11,8991234567891:6\n" +
+				"2,\"Tea, green\",,15000.50,IDR,5,Drinks,SKU-2,11,\n",
+		},
+		{
+			name: "Customers JSON Lines With Columns",
+			mock: func(mockProductRepo *mocks.MockProductRepository, mockCustomerRepo *mocks.MockCustomerRepository, mockEmployeeRepo *mocks.MockEmployeeRepository) {
+				mockCustomerRepo.EXPECT().FindInBatches(gomock.Any(), exportBatchSize, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, batchSize int, fn func([]domain.Customer) error) error {
+						return fn([]domain.Customer{{CustomerID: 7, Name: "Budi", Email: "budi@example.com", LoyaltyPts: 40}})
+					})
+			},
+			input:   web.ExportRequest{Resource: "customers", Format: "jsonl", Columns: []string{"loyalty_points", "name"}},
+			expects: "{\"loyalty_points\":40,\"name\":\"Budi\"}\n",
+		},
+		{
+			name: "Customers CSV Quotes Formulas",
+			mock: func(mockProductRepo *mocks.MockProductRepository, mockCustomerRepo *mocks.MockCustomerRepository, mockEmployeeRepo *mocks.MockEmployeeRepository) {
+				mockCustomerRepo.EXPECT().FindInBatches(gomock.Any(), exportBatchSize, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, batchSize int, fn func([]domain.Customer) error) error {
+						return fn([]domain.Customer{{CustomerID: 7, Name: `=HYPERLINK("http://evil.example")`, Phone: "+62811", Address: "@SUM(A1)", LoyaltyPts: -5}})
+					})
+			},
+			input:   web.ExportRequest{Resource: "customers", Format: "csv", Columns: []string{"name", "phone", "address", "loyalty_points"}},
+			expects: "name,phone,address,loyalty_points\n\"'=HYPERLINK(\"\"http://evil.example\"\")\",'+62811,'@SUM(A1),-5\n",
+		},
+		{
+			name: "Repository Error",
+			mock: func(mockProductRepo *mocks.MockProductRepository, mockCustomerRepo *mocks.MockCustomerRepository, mockEmployeeRepo *mocks.MockEmployeeRepository) {
+				mockEmployeeRepo.EXPECT().FindInBatches(gomock.Any(), exportBatchSize, gomock.Any()).Return(errors.New("connection lost"))
+			},
+			input:   web.ExportRequest{Resource: "employees", Format: "csv"},
+			expects: "id,name,role,email,phone,date_hired\n",
+			err:     errors.New("connection lost"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockProductRepo := mocks.NewMockProductRepository(ctrl)
+			mockCustomerRepo := mocks.NewMockCustomerRepository(ctrl)
+			mockEmployeeRepo := mocks.NewMockEmployeeRepository(ctrl)
+			tt.mock(mockProductRepo, mockCustomerRepo, mockEmployeeRepo)
+
+			var output bytes.Buffer
+			service := NewExportService(mockProductRepo, mockCustomerRepo, mockEmployeeRepo, validator.New())
+			err := service.Export(context.Background(), tt.input, &output)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.expects, output.String())
+		})
+	}
+
+	t.Run("Employees XLSX", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		mockEmployeeRepo := mocks.NewMockEmployeeRepository(ctrl)
+		mockEmployeeRepo.EXPECT().FindInBatches(gomock.Any(), exportBatchSize, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, batchSize int, fn func([]domain.Employee) error) error {
+				return fn([]domain.Employee{{EmployeeID: 2, Name: "=1+1", Role: "Cashier"}})
+			})
+
+		var output bytes.Buffer
+		service := NewExportService(nil, nil, mockEmployeeRepo, validator.New())
+		err := service.Export(context.Background(), web.ExportRequest{Resource: "employees", Format: "xlsx", Columns: []string{"id", "name", "role"}}, &output)
+		assert.NoError(t, err)
+
+		rows, err := helper.ReadSpreadsheet(&output, "xlsx")
+		assert.NoError(t, err)
+		assert.Equal(t, []helper.SpreadsheetRow{
+			{Line: 1, Cells: []string{"id", "name", "role"}},
+			{Line: 2, Cells: []string{"2", "=1+1", "Cashier"}},
+		}, rows)
+	})
+}
@@ -0,0 +1,10 @@
+package service
+
+import (
+	"context"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+)
+
+type HealthService interface {
+	Check(ctx context.Context) web.HealthResponse
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"sync"
+	"time"
+)
+
+// HealthCheck is a dependency the service cannot work without
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+type HealthServiceImpl struct {
+	Checks  []HealthCheck
+	Timeout time.Duration
+}
+
+func NewHealthService(checks []HealthCheck, timeout time.Duration) HealthService {
+	return &HealthServiceImpl{
+		Checks:  checks,
+		Timeout: timeout,
+	}
+}
+
+// Check runs every dependency check concurrently, each bounded by Timeout; the service is up
+// only when all of them pass
+func (service *HealthServiceImpl) Check(ctx context.Context) web.HealthResponse {
+	ctx, cancel := context.WithTimeout(ctx, service.Timeout)
+	defer cancel()
+
+	response := web.HealthResponse{Status: web.HealthUp, Checks: make([]web.HealthCheckResponse, len(service.Checks))}
+	var wg sync.WaitGroup
+	for i, check := range service.Checks {
+		wg.Add(1)
+		go func(i int, check HealthCheck) {
+			defer wg.Done()
+			response.Checks[i] = service.run(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	for _, check := range response.Checks {
+		if check.Status != web.HealthUp {
+			response.Status = web.HealthDown
+		}
+	}
+	return response
+}
+
+// run a single check; a check that ignores ctx is reported down once the timeout passes
+func (service *HealthServiceImpl) run(ctx context.Context, check HealthCheck) web.HealthCheckResponse {
+	start := time.Now()
+	result := make(chan error, 1)
+	go func() {
+		result <- check.Check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-result:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	response := web.HealthCheckResponse{
+		Name:      check.Name,
+		Status:    web.HealthUp,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		response.Status = web.HealthDown
+		response.Error = err.Error()
+	}
+	return response
+}
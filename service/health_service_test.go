@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestHealthServiceCheck(t *testing.T) {
+	up := func(ctx context.Context) error { return nil }
+	down := func(ctx context.Context) error { return errors.New("connection refused") }
+	hanging := func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	}
+
+	tests := []struct {
+		name           string
+		checks         []HealthCheck
+		expectedStatus string
+		expectedChecks []string
+	}{
+		{
+			name:           "All checks pass",
+			checks:         []HealthCheck{{Name: "database", Check: up}, {Name: "migrations", Check: up}},
+			expectedStatus: web.HealthUp,
+			expectedChecks: []string{web.HealthUp, web.HealthUp},
+		},
+		{
+			name:           "A check fails",
+			checks:         []HealthCheck{{Name: "database", Check: down}, {Name: "migrations", Check: up}},
+			expectedStatus: web.HealthDown,
+			expectedChecks: []string{web.HealthDown, web.HealthUp},
+		},
+		{
+			name:           "A check times out",
+			checks:         []HealthCheck{{Name: "database", Check: hanging}},
+			expectedStatus: web.HealthDown,
+			expectedChecks: []string{web.HealthDown},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			healthService := NewHealthService(tt.checks, 50*time.Millisecond)
+
+			start := time.Now()
+			response := healthService.Check(context.Background())
+			assert.Less(t, time.Since(start), 500*time.Millisecond)
+
+			assert.Equal(t, tt.expectedStatus, response.Status)
+			assert.Len(t, response.Checks, len(tt.expectedChecks))
+			for i, status := range tt.expectedChecks {
+				assert.Equal(t, tt.checks[i].Name, response.Checks[i].Name)
+				assert.Equal(t, status, response.Checks[i].Status)
+				assert.Equal(t, status == web.HealthDown, response.Checks[i].Error != "")
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service/batch_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service/batch_service.go -destination=service/mocks/batch_service_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	web "github.com/aronipurwanto/go-restful-api/model/web"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBatchService is a mock of BatchService interface.
+type MockBatchService struct {
+	ctrl     *gomock.Controller
+	recorder *MockBatchServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockBatchServiceMockRecorder is the mock recorder for MockBatchService.
+type MockBatchServiceMockRecorder struct {
+	mock *MockBatchService
+}
+
+// NewMockBatchService creates a new mock instance.
+func NewMockBatchService(ctrl *gomock.Controller) *MockBatchService {
+	mock := &MockBatchService{ctrl: ctrl}
+	mock.recorder = &MockBatchServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBatchService) EXPECT() *MockBatchServiceMockRecorder {
+	return m.recorder
+}
+
+// Categories mocks base method.
+func (m *MockBatchService) Categories(ctx context.Context, request web.BatchRequest) (web.BatchResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Categories", ctx, request)
+	ret0, _ := ret[0].(web.BatchResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Categories indicates an expected call of Categories.
+func (mr *MockBatchServiceMockRecorder) Categories(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Categories", reflect.TypeOf((*MockBatchService)(nil).Categories), ctx, request)
+}
+
+// Customers mocks base method.
+func (m *MockBatchService) Customers(ctx context.Context, request web.BatchRequest) (web.BatchResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Customers", ctx, request)
+	ret0, _ := ret[0].(web.BatchResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Customers indicates an expected call of Customers.
+func (mr *MockBatchServiceMockRecorder) Customers(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Customers", reflect.TypeOf((*MockBatchService)(nil).Customers), ctx, request)
+}
+
+// Employees mocks base method.
+func (m *MockBatchService) Employees(ctx context.Context, request web.BatchRequest) (web.BatchResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Employees", ctx, request)
+	ret0, _ := ret[0].(web.BatchResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Employees indicates an expected call of Employees.
+func (mr *MockBatchServiceMockRecorder) Employees(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Employees", reflect.TypeOf((*MockBatchService)(nil).Employees), ctx, request)
+}
+
+// Products mocks base method.
+func (m *MockBatchService) Products(ctx context.Context, request web.BatchRequest) (web.BatchResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Products", ctx, request)
+	ret0, _ := ret[0].(web.BatchResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Products indicates an expected call of Products.
+func (mr *MockBatchServiceMockRecorder) Products(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Products", reflect.TypeOf((*MockBatchService)(nil).Products), ctx, request)
+}
@@ -57,17 +57,17 @@ func (mr *MockCategoryServiceMockRecorder) Create(ctx, request any) *gomock.Call
 }
 
 // Delete mocks base method.
-func (m *MockCategoryService) Delete(ctx context.Context, categoryId uint64) error {
+func (m *MockCategoryService) Delete(ctx context.Context, categoryId, version uint64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", ctx, categoryId)
+	ret := m.ctrl.Call(m, "Delete", ctx, categoryId, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockCategoryServiceMockRecorder) Delete(ctx, categoryId any) *gomock.Call {
+func (mr *MockCategoryServiceMockRecorder) Delete(ctx, categoryId, version any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCategoryService)(nil).Delete), ctx, categoryId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCategoryService)(nil).Delete), ctx, categoryId, version)
 }
 
 // FindAll mocks base method.
@@ -100,6 +100,66 @@ func (mr *MockCategoryServiceMockRecorder) FindById(ctx, categoryId any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockCategoryService)(nil).FindById), ctx, categoryId)
 }
 
+// FindTree mocks base method.
+func (m *MockCategoryService) FindTree(ctx context.Context, rootId *uint64, withCounts bool) ([]web.CategoryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindTree", ctx, rootId, withCounts)
+	ret0, _ := ret[0].([]web.CategoryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindTree indicates an expected call of FindTree.
+func (mr *MockCategoryServiceMockRecorder) FindTree(ctx, rootId, withCounts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTree", reflect.TypeOf((*MockCategoryService)(nil).FindTree), ctx, rootId, withCounts)
+}
+
+// Move mocks base method.
+func (m *MockCategoryService) Move(ctx context.Context, request web.CategoryMoveRequest) (web.CategoryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Move", ctx, request)
+	ret0, _ := ret[0].(web.CategoryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Move indicates an expected call of Move.
+func (mr *MockCategoryServiceMockRecorder) Move(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Move", reflect.TypeOf((*MockCategoryService)(nil).Move), ctx, request)
+}
+
+// Patch mocks base method.
+func (m *MockCategoryService) Patch(ctx context.Context, request web.PatchRequest) (web.CategoryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Patch", ctx, request)
+	ret0, _ := ret[0].(web.CategoryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Patch indicates an expected call of Patch.
+func (mr *MockCategoryServiceMockRecorder) Patch(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Patch", reflect.TypeOf((*MockCategoryService)(nil).Patch), ctx, request)
+}
+
+// Restore mocks base method.
+func (m *MockCategoryService) Restore(ctx context.Context, categoryId uint64) (web.CategoryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, categoryId)
+	ret0, _ := ret[0].(web.CategoryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockCategoryServiceMockRecorder) Restore(ctx, categoryId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockCategoryService)(nil).Restore), ctx, categoryId)
+}
+
 // Update mocks base method.
 func (m *MockCategoryService) Update(ctx context.Context, request web.CategoryUpdateRequest) (web.CategoryResponse, error) {
 	m.ctrl.T.Helper()
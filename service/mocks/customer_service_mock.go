@@ -57,17 +57,17 @@ func (mr *MockCustomerServiceMockRecorder) Create(ctx, request any) *gomock.Call
 }
 
 // Delete mocks base method.
-func (m *MockCustomerService) Delete(ctx context.Context, customerId uint64) error {
+func (m *MockCustomerService) Delete(ctx context.Context, customerId, version uint64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", ctx, customerId)
+	ret := m.ctrl.Call(m, "Delete", ctx, customerId, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockCustomerServiceMockRecorder) Delete(ctx, customerId any) *gomock.Call {
+func (mr *MockCustomerServiceMockRecorder) Delete(ctx, customerId, version any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCustomerService)(nil).Delete), ctx, customerId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCustomerService)(nil).Delete), ctx, customerId, version)
 }
 
 // FindAll mocks base method.
@@ -100,6 +100,36 @@ func (mr *MockCustomerServiceMockRecorder) FindById(ctx, customerId any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockCustomerService)(nil).FindById), ctx, customerId)
 }
 
+// Patch mocks base method.
+func (m *MockCustomerService) Patch(ctx context.Context, request web.PatchRequest) (web.CustomerResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Patch", ctx, request)
+	ret0, _ := ret[0].(web.CustomerResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Patch indicates an expected call of Patch.
+func (mr *MockCustomerServiceMockRecorder) Patch(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Patch", reflect.TypeOf((*MockCustomerService)(nil).Patch), ctx, request)
+}
+
+// Restore mocks base method.
+func (m *MockCustomerService) Restore(ctx context.Context, customerId uint64) (web.CustomerResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, customerId)
+	ret0, _ := ret[0].(web.CustomerResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockCustomerServiceMockRecorder) Restore(ctx, customerId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockCustomerService)(nil).Restore), ctx, customerId)
+}
+
 // Update mocks base method.
 func (m *MockCustomerService) Update(ctx context.Context, request web.CustomerUpdateRequest) (web.CustomerResponse, error) {
 	m.ctrl.T.Helper()
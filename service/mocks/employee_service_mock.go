@@ -57,17 +57,17 @@ func (mr *MockEmployeeServiceMockRecorder) Create(ctx, request any) *gomock.Call
 }
 
 // Delete mocks base method.
-func (m *MockEmployeeService) Delete(ctx context.Context, employeeId uint64) error {
+func (m *MockEmployeeService) Delete(ctx context.Context, employeeId, version uint64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", ctx, employeeId)
+	ret := m.ctrl.Call(m, "Delete", ctx, employeeId, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockEmployeeServiceMockRecorder) Delete(ctx, employeeId any) *gomock.Call {
+func (mr *MockEmployeeServiceMockRecorder) Delete(ctx, employeeId, version any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockEmployeeService)(nil).Delete), ctx, employeeId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockEmployeeService)(nil).Delete), ctx, employeeId, version)
 }
 
 // FindAll mocks base method.
@@ -100,6 +100,36 @@ func (mr *MockEmployeeServiceMockRecorder) FindById(ctx, employeeId any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockEmployeeService)(nil).FindById), ctx, employeeId)
 }
 
+// Patch mocks base method.
+func (m *MockEmployeeService) Patch(ctx context.Context, request web.PatchRequest) (web.EmployeeResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Patch", ctx, request)
+	ret0, _ := ret[0].(web.EmployeeResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Patch indicates an expected call of Patch.
+func (mr *MockEmployeeServiceMockRecorder) Patch(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Patch", reflect.TypeOf((*MockEmployeeService)(nil).Patch), ctx, request)
+}
+
+// Restore mocks base method.
+func (m *MockEmployeeService) Restore(ctx context.Context, employeeId uint64) (web.EmployeeResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, employeeId)
+	ret0, _ := ret[0].(web.EmployeeResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockEmployeeServiceMockRecorder) Restore(ctx, employeeId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockEmployeeService)(nil).Restore), ctx, employeeId)
+}
+
 // Update mocks base method.
 func (m *MockEmployeeService) Update(ctx context.Context, request web.EmployeeUpdateRequest) (web.EmployeeResponse, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,84 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service/event_stream_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service/event_stream_service.go -destination=service/mocks/event_stream_service_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	web "github.com/aronipurwanto/go-restful-api/model/web"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockEventStreamService is a mock of EventStreamService interface.
+type MockEventStreamService struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventStreamServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockEventStreamServiceMockRecorder is the mock recorder for MockEventStreamService.
+type MockEventStreamServiceMockRecorder struct {
+	mock *MockEventStreamService
+}
+
+// NewMockEventStreamService creates a new mock instance.
+func NewMockEventStreamService(ctrl *gomock.Controller) *MockEventStreamService {
+	mock := &MockEventStreamService{ctrl: ctrl}
+	mock.recorder = &MockEventStreamServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventStreamService) EXPECT() *MockEventStreamServiceMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockEventStreamService) Close() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Close")
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockEventStreamServiceMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockEventStreamService)(nil).Close))
+}
+
+// Poll mocks base method.
+func (m *MockEventStreamService) Poll(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Poll", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Poll indicates an expected call of Poll.
+func (mr *MockEventStreamServiceMockRecorder) Poll(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Poll", reflect.TypeOf((*MockEventStreamService)(nil).Poll), ctx)
+}
+
+// Subscribe mocks base method.
+func (m *MockEventStreamService) Subscribe(topics []string, lastEventId uint64) (web.EventSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", topics, lastEventId)
+	ret0, _ := ret[0].(web.EventSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockEventStreamServiceMockRecorder) Subscribe(topics, lastEventId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockEventStreamService)(nil).Subscribe), topics, lastEventId)
+}
@@ -0,0 +1,102 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service/product_price_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service/product_price_service.go -destination=service/mocks/product_price_service_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	web "github.com/aronipurwanto/go-restful-api/model/web"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockProductPriceService is a mock of ProductPriceService interface.
+type MockProductPriceService struct {
+	ctrl     *gomock.Controller
+	recorder *MockProductPriceServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockProductPriceServiceMockRecorder is the mock recorder for MockProductPriceService.
+type MockProductPriceServiceMockRecorder struct {
+	mock *MockProductPriceService
+}
+
+// NewMockProductPriceService creates a new mock instance.
+func NewMockProductPriceService(ctrl *gomock.Controller) *MockProductPriceService {
+	mock := &MockProductPriceService{ctrl: ctrl}
+	mock.recorder = &MockProductPriceServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProductPriceService) EXPECT() *MockProductPriceServiceMockRecorder {
+	return m.recorder
+}
+
+// ApplyDue mocks base method.
+func (m *MockProductPriceService) ApplyDue(ctx context.Context, now time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyDue", ctx, now)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplyDue indicates an expected call of ApplyDue.
+func (mr *MockProductPriceServiceMockRecorder) ApplyDue(ctx, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyDue", reflect.TypeOf((*MockProductPriceService)(nil).ApplyDue), ctx, now)
+}
+
+// Cancel mocks base method.
+func (m *MockProductPriceService) Cancel(ctx context.Context, productId, priceId uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", ctx, productId, priceId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockProductPriceServiceMockRecorder) Cancel(ctx, productId, priceId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockProductPriceService)(nil).Cancel), ctx, productId, priceId)
+}
+
+// FindAll mocks base method.
+func (m *MockProductPriceService) FindAll(ctx context.Context, productId uint64) (web.ProductPriceHistoryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", ctx, productId)
+	ret0, _ := ret[0].(web.ProductPriceHistoryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockProductPriceServiceMockRecorder) FindAll(ctx, productId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockProductPriceService)(nil).FindAll), ctx, productId)
+}
+
+// Schedule mocks base method.
+func (m *MockProductPriceService) Schedule(ctx context.Context, request web.ProductPriceScheduleRequest) (web.ProductPriceResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Schedule", ctx, request)
+	ret0, _ := ret[0].(web.ProductPriceResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Schedule indicates an expected call of Schedule.
+func (mr *MockProductPriceServiceMockRecorder) Schedule(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Schedule", reflect.TypeOf((*MockProductPriceService)(nil).Schedule), ctx, request)
+}
@@ -57,17 +57,17 @@ func (mr *MockProductServiceMockRecorder) Create(ctx, request any) *gomock.Call
 }
 
 // Delete mocks base method.
-func (m *MockProductService) Delete(ctx context.Context, productId uint64) error {
+func (m *MockProductService) Delete(ctx context.Context, productId, version uint64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", ctx, productId)
+	ret := m.ctrl.Call(m, "Delete", ctx, productId, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockProductServiceMockRecorder) Delete(ctx, productId any) *gomock.Call {
+func (mr *MockProductServiceMockRecorder) Delete(ctx, productId, version any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockProductService)(nil).Delete), ctx, productId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockProductService)(nil).Delete), ctx, productId, version)
 }
 
 // FindAll mocks base method.
@@ -85,6 +85,21 @@ func (mr *MockProductServiceMockRecorder) FindAll(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockProductService)(nil).FindAll), ctx)
 }
 
+// FindByCategory mocks base method.
+func (m *MockProductService) FindByCategory(ctx context.Context, categoryId uint64) ([]web.ProductResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByCategory", ctx, categoryId)
+	ret0, _ := ret[0].([]web.ProductResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByCategory indicates an expected call of FindByCategory.
+func (mr *MockProductServiceMockRecorder) FindByCategory(ctx, categoryId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByCategory", reflect.TypeOf((*MockProductService)(nil).FindByCategory), ctx, categoryId)
+}
+
 // FindById mocks base method.
 func (m *MockProductService) FindById(ctx context.Context, productId uint64) (web.ProductResponse, error) {
 	m.ctrl.T.Helper()
@@ -100,6 +115,51 @@ func (mr *MockProductServiceMockRecorder) FindById(ctx, productId any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindById", reflect.TypeOf((*MockProductService)(nil).FindById), ctx, productId)
 }
 
+// Lookup mocks base method.
+func (m *MockProductService) Lookup(ctx context.Context, code string) (web.ProductLookupResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lookup", ctx, code)
+	ret0, _ := ret[0].(web.ProductLookupResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Lookup indicates an expected call of Lookup.
+func (mr *MockProductServiceMockRecorder) Lookup(ctx, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lookup", reflect.TypeOf((*MockProductService)(nil).Lookup), ctx, code)
+}
+
+// Patch mocks base method.
+func (m *MockProductService) Patch(ctx context.Context, request web.PatchRequest) (web.ProductResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Patch", ctx, request)
+	ret0, _ := ret[0].(web.ProductResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Patch indicates an expected call of Patch.
+func (mr *MockProductServiceMockRecorder) Patch(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Patch", reflect.TypeOf((*MockProductService)(nil).Patch), ctx, request)
+}
+
+// Restore mocks base method.
+func (m *MockProductService) Restore(ctx context.Context, productId uint64) (web.ProductResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, productId)
+	ret0, _ := ret[0].(web.ProductResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockProductServiceMockRecorder) Restore(ctx, productId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockProductService)(nil).Restore), ctx, productId)
+}
+
 // Update mocks base method.
 func (m *MockProductService) Update(ctx context.Context, request web.ProductUpdateRequest) (web.ProductResponse, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,116 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service/product_variant_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service/product_variant_service.go -destination=service/mocks/product_variant_service_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	web "github.com/aronipurwanto/go-restful-api/model/web"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockProductVariantService is a mock of ProductVariantService interface.
+type MockProductVariantService struct {
+	ctrl     *gomock.Controller
+	recorder *MockProductVariantServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockProductVariantServiceMockRecorder is the mock recorder for MockProductVariantService.
+type MockProductVariantServiceMockRecorder struct {
+	mock *MockProductVariantService
+}
+
+// NewMockProductVariantService creates a new mock instance.
+func NewMockProductVariantService(ctrl *gomock.Controller) *MockProductVariantService {
+	mock := &MockProductVariantService{ctrl: ctrl}
+	mock.recorder = &MockProductVariantServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProductVariantService) EXPECT() *MockProductVariantServiceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockProductVariantService) Create(ctx context.Context, request web.ProductVariantCreateRequest) (web.ProductVariantResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, request)
+	ret0, _ := ret[0].(web.ProductVariantResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockProductVariantServiceMockRecorder) Create(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockProductVariantService)(nil).Create), ctx, request)
+}
+
+// Delete mocks base method.
+func (m *MockProductVariantService) Delete(ctx context.Context, productId, variantId uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, productId, variantId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockProductVariantServiceMockRecorder) Delete(ctx, productId, variantId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockProductVariantService)(nil).Delete), ctx, productId, variantId)
+}
+
+// FindAll mocks base method.
+func (m *MockProductVariantService) FindAll(ctx context.Context, productId uint64) ([]web.ProductVariantResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", ctx, productId)
+	ret0, _ := ret[0].([]web.ProductVariantResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockProductVariantServiceMockRecorder) FindAll(ctx, productId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockProductVariantService)(nil).FindAll), ctx, productId)
+}
+
+// Generate mocks base method.
+func (m *MockProductVariantService) Generate(ctx context.Context, request web.ProductVariantGenerateRequest) ([]web.ProductVariantResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Generate", ctx, request)
+	ret0, _ := ret[0].([]web.ProductVariantResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Generate indicates an expected call of Generate.
+func (mr *MockProductVariantServiceMockRecorder) Generate(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Generate", reflect.TypeOf((*MockProductVariantService)(nil).Generate), ctx, request)
+}
+
+// Update mocks base method.
+func (m *MockProductVariantService) Update(ctx context.Context, request web.ProductVariantUpdateRequest) (web.ProductVariantResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, request)
+	ret0, _ := ret[0].(web.ProductVariantResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockProductVariantServiceMockRecorder) Update(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockProductVariantService)(nil).Update), ctx, request)
+}
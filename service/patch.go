@@ -0,0 +1,50 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/helper"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+)
+
+// applyPatch patches the update request built from the stored entity and decodes the result into target,
+// so the merged document goes through the same validation as a full update.
+// The id and version are pinned afterwards: the patched document cannot retarget another row
+// or skip the version it was based on.
+func applyPatch(request web.PatchRequest, current interface{}, version uint64, target interface{}) error {
+	document, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+
+	var patched []byte
+	switch request.ContentType {
+	case helper.JSONPatchContentType:
+		patched, err = helper.ApplyJSONPatch(document, request.Patch)
+	case helper.MergePatchContentType, "application/json":
+		patched, err = helper.MergePatch(document, request.Patch)
+	default:
+		return exception.NewBadRequestError("Unsupported patch content type " + request.ContentType)
+	}
+	if err != nil {
+		return exception.NewBadRequestError("Invalid patch: " + err.Error())
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(patched, &fields); err != nil {
+		return exception.NewBadRequestError("Invalid patch: " + err.Error())
+	}
+	fields["id"], _ = json.Marshal(request.Id)
+	fields["version"], _ = json.Marshal(version)
+	if patched, err = json.Marshal(fields); err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(target); err != nil {
+		return exception.NewBadRequestError("Invalid patch: " + err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,11 @@
+package service
+
+import (
+	"context"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"io"
+)
+
+type ProductImportService interface {
+	Import(ctx context.Context, file io.Reader, format string, dryRun bool) (web.ProductImportResponse, error)
+}
@@ -0,0 +1,326 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/helper"
+	"github.com/aronipurwanto/go-restful-api/metrics"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/money"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/repository"
+	"github.com/go-playground/validator/v10"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// productImportChunkSize is the number of rows written per transaction
+const productImportChunkSize = 100
+
+// productImportColumns are the header names every import file must contain
+var productImportColumns = []string{"name", "price", "currency", "stock_qty", "category", "sku", "tax_rate"}
+
+type ProductImportServiceImpl struct {
+	ProductRepository  repository.ProductRepository
+	CategoryRepository repository.CategoryRepository
+	OutboxRepository   repository.OutboxRepository
+	Transactor         repository.Transactor
+	Validate           *validator.Validate
+}
+
+func NewProductImportService(productRepository repository.ProductRepository, categoryRepository repository.CategoryRepository, outboxRepository repository.OutboxRepository, transactor repository.Transactor, validate *validator.Validate) ProductImportService {
+	return &ProductImportServiceImpl{
+		ProductRepository:  productRepository,
+		CategoryRepository: categoryRepository,
+		OutboxRepository:   outboxRepository,
+		Transactor:         transactor,
+		Validate:           validate,
+	}
+}
+
+// importRow is a parsed row waiting to be written
+type importRow struct {
+	Line     int
+	Product  domain.Product
+	Previous *domain.Product // the stored product the row updates, nil when it creates one
+}
+
+// Import Products from a CSV or XLSX file, creating new SKUs and updating known ones
+func (service *ProductImportServiceImpl) Import(ctx context.Context, file io.Reader, format string, dryRun bool) (web.ProductImportResponse, error) {
+	ctx, span := startSpan(ctx, "ProductImportService.Import")
+	defer span.End()
+
+	rows, err := helper.ReadSpreadsheet(file, format)
+	if err != nil {
+		return web.ProductImportResponse{}, exception.NewBadRequestError("Unreadable file: " + err.Error())
+	}
+	if len(rows) == 0 {
+		return web.ProductImportResponse{}, exception.NewBadRequestError("File is empty")
+	}
+
+	columns, err := importColumns(rows[0].Cells)
+	if err != nil {
+		return web.ProductImportResponse{}, err
+	}
+
+	categoryIds, err := service.categoryIdsByName(ctx)
+	if err != nil {
+		return web.ProductImportResponse{}, err
+	}
+
+	var failed []domain.ProductError
+	var parsed []importRow
+	lineBySKU := make(map[string]int)
+	for _, row := range rows[1:] {
+		product, err := service.parseImportRow(columns, row.Cells, categoryIds)
+		if err == nil {
+			if line, ok := lineBySKU[product.SKU]; ok {
+				err = fmt.Errorf("sku %s is already used on line %d", product.SKU, line)
+			}
+		}
+		if err != nil {
+			failed = append(failed, domain.ProductError{Line: row.Line, Product: product, Error: err})
+			continue
+		}
+
+		lineBySKU[product.SKU] = row.Line
+		parsed = append(parsed, importRow{Line: row.Line, Product: product})
+	}
+
+	pending, matchErrors, err := service.matchExisting(ctx, parsed, columns)
+	if err != nil {
+		return web.ProductImportResponse{}, err
+	}
+	failed = append(failed, matchErrors...)
+
+	response := web.ProductImportResponse{DryRun: dryRun, Total: len(rows) - 1}
+	for start := 0; start < len(pending); start += productImportChunkSize {
+		chunk := pending[start:min(start+productImportChunkSize, len(pending))]
+
+		if !dryRun {
+			// A failed chunk is rolled back as a whole, so each of its rows is reported
+			if err := service.importChunk(ctx, chunk); err != nil {
+				for _, row := range chunk {
+					failed = append(failed, domain.ProductError{Line: row.Line, Product: row.Product, Error: err})
+				}
+				continue
+			}
+		}
+
+		for _, row := range chunk {
+			if row.Product.ProductID == 0 {
+				response.Created++
+			} else {
+				response.Updated++
+			}
+		}
+	}
+	if !dryRun {
+		metrics.ProductsCreated.Add(float64(response.Created))
+	}
+
+	sort.SliceStable(failed, func(i, j int) bool {
+		return failed[i].Line < failed[j].Line
+	})
+	response.Failed = len(failed)
+	response.Errors = helper.ToProductImportErrorResponses(failed)
+	span.SetAttributes(countAttribute(response.Created + response.Updated))
+	return response, nil
+}
+
+// importChunk writes the products of a chunk and their domain events in one transaction
+func (service *ProductImportServiceImpl) importChunk(ctx context.Context, chunk []importRow) error {
+	products := make([]domain.Product, len(chunk))
+	for i, row := range chunk {
+		products[i] = row.Product
+	}
+
+	return service.Transactor.Transaction(ctx, func(ctx context.Context) error {
+		imported, err := service.ProductRepository.Import(ctx, products)
+		if err != nil {
+			return err
+		}
+
+		var events []domain.OutboxEvent
+		for i, product := range imported {
+			rowEvents, err := productEvents(chunk[i].Previous, product)
+			if err != nil {
+				return err
+			}
+			events = append(events, rowEvents...)
+		}
+		return service.OutboxRepository.Save(ctx, events)
+	})
+}
+
+// matchExisting gives rows the ID of the product sharing their SKU, so they are updated instead of created
+func (service *ProductImportServiceImpl) matchExisting(ctx context.Context, rows []importRow, columns map[string]int) ([]importRow, []domain.ProductError, error) {
+	skus := make([]string, len(rows))
+	for i, row := range rows {
+		skus[i] = row.Product.SKU
+	}
+	existing, err := service.ProductRepository.FindBySKUs(ctx, skus)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	productsBySKU := make(map[string][]domain.Product)
+	for _, product := range existing {
+		productsBySKU[product.SKU] = append(productsBySKU[product.SKU], product)
+	}
+
+	_, hasBarcodes := columns["barcodes"]
+	_, hasDescription := columns["description"]
+	var pending []importRow
+	var failed []domain.ProductError
+	for _, row := range rows {
+		matches := productsBySKU[row.Product.SKU]
+		if len(matches) > 1 {
+			failed = append(failed, domain.ProductError{
+				Line:    row.Line,
+				Product: row.Product,
+				Error:   fmt.Errorf("sku %s matches %d existing products", row.Product.SKU, len(matches)),
+			})
+			continue
+		}
+		if len(matches) == 1 {
+			previous := matches[0]
+			row.Previous = &previous
+			row.Product.ProductID = matches[0].ProductID
+			row.Product.Version = matches[0].Version
+			// Without a barcodes or description column the file says nothing about them, so keep what is stored
+			if !hasBarcodes {
+				row.Product.Barcodes = matches[0].Barcodes
+			}
+			if !hasDescription {
+				row.Product.Description = matches[0].Description
+			}
+		}
+		pending = append(pending, row)
+	}
+	return pending, failed, nil
+}
+
+// parseImportRow applies the ProductCreateRequest rules to one row; the returned product
+// carries whatever could be read even when an error is returned
+func (service *ProductImportServiceImpl) parseImportRow(columns map[string]int, cells []string, categoryIds map[string][]uint64) (domain.Product, error) {
+	cell := func(name string) string {
+		index, ok := columns[name]
+		if !ok || index >= len(cells) {
+			return ""
+		}
+		return strings.TrimSpace(cells[index])
+	}
+
+	product := domain.Product{Name: cell("name"), Description: cell("description"), SKU: cell("sku")}
+
+	price, err := money.Parse(cell("price"), cell("currency"))
+	if err != nil {
+		return product, fmt.Errorf("invalid price %q: %w", cell("price"), err)
+	}
+	stockQty, err := strconv.Atoi(cell("stock_qty"))
+	if err != nil {
+		return product, fmt.Errorf("invalid stock_qty %q", cell("stock_qty"))
+	}
+	taxRate, err := money.ParseAmount(cell("tax_rate"))
+	if err != nil {
+		return product, fmt.Errorf("invalid tax_rate %q: %w", cell("tax_rate"), err)
+	}
+
+	categoryName := cell("category")
+	ids := categoryIds[strings.ToLower(categoryName)]
+	if len(ids) == 0 {
+		return product, fmt.Errorf("category %q not found", categoryName)
+	}
+	if len(ids) > 1 {
+		return product, fmt.Errorf("category %q matches %d categories", categoryName, len(ids))
+	}
+
+	barcodeRequests, err := parseImportBarcodes(cell("barcodes"))
+	if err != nil {
+		return product, err
+	}
+
+	request := web.ProductCreateRequest{
+		Name:        product.Name,
+		Description: product.Description,
+		Price:       price,
+		StockQty:    stockQty,
+		CategoryID:  int(ids[0]),
+		SKU:         product.SKU,
+		TaxRate:     taxRate,
+		Barcodes:    barcodeRequests,
+	}
+	if err := service.Validate.Struct(request); err != nil {
+		return product, err
+	}
+	if request.Price.IsNegative() {
+		return product, exception.NewBadRequestError("price must not be negative")
+	}
+	barcodes, err := toProductBarcodes(request.Barcodes)
+	if err != nil {
+		return product, err
+	}
+
+	product.Price = request.Price
+	product.StockQty = request.StockQty
+	product.CategoryId = ids[0]
+	product.TaxRate = request.TaxRate
+	product.Barcodes = barcodes
+	return product, nil
+}
+
+// categoryIdsByName indexes category IDs by lower-cased name; names are not unique across the tree
+func (service *ProductImportServiceImpl) categoryIdsByName(ctx context.Context) (map[string][]uint64, error) {
+	categories, err := service.CategoryRepository.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	categoryIds := make(map[string][]uint64)
+	for _, category := range categories {
+		name := strings.ToLower(strings.TrimSpace(category.Name))
+		categoryIds[name] = append(categoryIds[name], category.Id)
+	}
+	return categoryIds, nil
+}
+
+// importColumns maps lower-cased header names to their column index
+func importColumns(header []string) (map[string]int, error) {
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	for _, name := range productImportColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, exception.NewBadRequestError("Missing column " + name)
+		}
+	}
+	return columns, nil
+}
+
+// parseImportBarcodes reads a "code[:pack_qty]|code[:pack_qty]" cell
+func parseImportBarcodes(value string) ([]web.ProductBarcodeRequest, error) {
+	var requests []web.ProductBarcodeRequest
+	if value == "" {
+		return requests, nil
+	}
+
+	for _, entry := range strings.Split(value, "|") {
+		code, packQty, found := strings.Cut(strings.TrimSpace(entry), ":")
+		request := web.ProductBarcodeRequest{Code: code, PackQty: 1}
+		if found {
+			qty, err := strconv.Atoi(packQty)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pack quantity in barcode %q", entry)
+			}
+			request.PackQty = qty
+		}
+		requests = append(requests, request)
+	}
+	return requests, nil
+}
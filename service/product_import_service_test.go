@@ -0,0 +1,140 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/money"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/repository/mocks"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/mock/gomock"
+	"io"
+	"strings"
+	"testing"
+)
+
+const productImportCSV = `name,description,price,currency,stock_qty,category,sku,tax_rate,barcodes
+Coffee,Arabica beans,85000,IDR,10,drinks,SKU-1,11,8991234567891:6
+Tea,,15000.50,IDR,5,Drinks,SKU-2,11,
+Cake,,20000,IDR,5,Bakery,SKU-3,11,
+"Milk
+Tea",,18000,IDR,5,Drinks,SKU-1,11,
+`
+
+func productImportXLSX(t *testing.T) io.Reader {
+	file := excelize.NewFile()
+	defer file.Close()
+	assert.NoError(t, file.SetSheetRow("Sheet1", "A1", &[]interface{}{"name", "price", "currency", "stock_qty", "category", "sku", "tax_rate"}))
+	assert.NoError(t, file.SetSheetRow("Sheet1", "A3", &[]interface{}{"Coffee", 85000, "IDR", 10, "Drinks", "SKU-1", 11}))
+	buffer, err := file.WriteToBuffer()
+	assert.NoError(t, err)
+	return buffer
+}
+
+func TestImportProducts(t *testing.T) {
+	categories := []domain.Category{{Id: 1, Name: "Drinks"}}
+	coffee := domain.Product{Name: "Coffee", Description: "Arabica beans", Price: money.FromInt(85000, "IDR"), StockQty: 10, CategoryId: 1, SKU: "SKU-1", TaxRate: money.AmountFromInt(11),
+		Barcodes: []domain.ProductBarcode{{Code: "8991234567891", PackQty: 6}}}
+	tea := domain.Product{ProductID: 4, Name: "Tea", Price: money.MustParse("15000.50", "IDR"), StockQty: 5, CategoryId: 1, SKU: "SKU-2", TaxRate: money.AmountFromInt(11)}
+	csvErrors := []web.ProductImportErrorResponse{
+		{Line: 4, SKU: "SKU-3", Name: "Cake", Error: `category "Bakery" not found`},
+		{Line: 5, SKU: "SKU-1", Name: "Milk\nTea", Error: "sku SKU-1 is already used on line 2"},
+	}
+
+	tests := []struct {
+		name    string
+		mock    func(mockProductRepo *mocks.MockProductRepository, mockCategoryRepo *mocks.MockCategoryRepository)
+		file    func(t *testing.T) io.Reader
+		format  string
+		dryRun  bool
+		expects web.ProductImportResponse
+		err     error
+	}{
+		{
+			name: "Creates And Updates By SKU",
+			mock: func(mockProductRepo *mocks.MockProductRepository, mockCategoryRepo *mocks.MockCategoryRepository) {
+				mockCategoryRepo.EXPECT().FindAll(gomock.Any()).Return(categories, nil)
+				mockProductRepo.EXPECT().FindBySKUs(gomock.Any(), []string{"SKU-1", "SKU-2"}).Return([]domain.Product{{ProductID: 4, SKU: "SKU-2"}}, nil)
+				mockProductRepo.EXPECT().Import(gomock.Any(), []domain.Product{coffee, tea}).Return([]domain.Product{coffee, tea}, nil)
+			},
+			file:    func(t *testing.T) io.Reader { return strings.NewReader(productImportCSV) },
+			format:  "csv",
+			expects: web.ProductImportResponse{Total: 4, Created: 1, Updated: 1, Failed: 2, Errors: csvErrors},
+		},
+		{
+			name: "Dry Run",
+			mock: func(mockProductRepo *mocks.MockProductRepository, mockCategoryRepo *mocks.MockCategoryRepository) {
+				mockCategoryRepo.EXPECT().FindAll(gomock.Any()).Return(categories, nil)
+				mockProductRepo.EXPECT().FindBySKUs(gomock.Any(), gomock.Any()).Return(nil, nil)
+			},
+			file:    func(t *testing.T) io.Reader { return strings.NewReader(productImportCSV) },
+			format:  "csv",
+			dryRun:  true,
+			expects: web.ProductImportResponse{DryRun: true, Total: 4, Created: 2, Failed: 2, Errors: csvErrors},
+		},
+		{
+			name: "Failed Chunk",
+			mock: func(mockProductRepo *mocks.MockProductRepository, mockCategoryRepo *mocks.MockCategoryRepository) {
+				mockCategoryRepo.EXPECT().FindAll(gomock.Any()).Return(categories, nil)
+				mockProductRepo.EXPECT().FindBySKUs(gomock.Any(), []string{"SKU-1"}).Return(nil, nil)
+				mockProductRepo.EXPECT().Import(gomock.Any(), gomock.Any()).Return(nil, errors.New("deadlock"))
+			},
+			file:   productImportXLSX,
+			format: "xlsx",
+			expects: web.ProductImportResponse{Total: 1, Failed: 1, Errors: []web.ProductImportErrorResponse{
+				{Line: 3, SKU: "SKU-1", Name: "Coffee", Error: "deadlock"},
+			}},
+		},
+		{
+			name: "Keeps Stored Columns The File Leaves Out",
+			mock: func(mockProductRepo *mocks.MockProductRepository, mockCategoryRepo *mocks.MockCategoryRepository) {
+				stored := domain.Product{ProductID: 7, Name: "Coffee", Description: "Arabica beans", SKU: "SKU-1", Version: 2,
+					Barcodes: []domain.ProductBarcode{{Code: "8991234567891", PackQty: 6}}}
+				updated := domain.Product{ProductID: 7, Name: "Coffee", Description: "Arabica beans", Price: money.FromInt(85000, "IDR"), StockQty: 10, CategoryId: 1, SKU: "SKU-1", TaxRate: money.AmountFromInt(11), Version: 2,
+					Barcodes: []domain.ProductBarcode{{Code: "8991234567891", PackQty: 6}}}
+				mockCategoryRepo.EXPECT().FindAll(gomock.Any()).Return(categories, nil)
+				mockProductRepo.EXPECT().FindBySKUs(gomock.Any(), []string{"SKU-1"}).Return([]domain.Product{stored}, nil)
+				mockProductRepo.EXPECT().Import(gomock.Any(), []domain.Product{updated}).Return([]domain.Product{updated}, nil)
+			},
+			file:    productImportXLSX,
+			format:  "xlsx",
+			expects: web.ProductImportResponse{Total: 1, Updated: 1, Errors: []web.ProductImportErrorResponse{}},
+		},
+		{
+			name:    "Missing Column",
+			mock:    func(mockProductRepo *mocks.MockProductRepository, mockCategoryRepo *mocks.MockCategoryRepository) {},
+			file:    func(t *testing.T) io.Reader { return strings.NewReader("name,price\nCoffee,1\n") },
+			format:  "csv",
+			expects: web.ProductImportResponse{},
+			err:     exception.NewBadRequestError("Missing column currency"),
+		},
+		{
+			name:    "Unsupported Format",
+			mock:    func(mockProductRepo *mocks.MockProductRepository, mockCategoryRepo *mocks.MockCategoryRepository) {},
+			file:    func(t *testing.T) io.Reader { return bytes.NewReader(nil) },
+			format:  "ods",
+			expects: web.ProductImportResponse{},
+			err:     exception.NewBadRequestError(`Unreadable file: unsupported format "ods", expected csv or xlsx`),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockProductRepo := mocks.NewMockProductRepository(ctrl)
+			mockCategoryRepo := mocks.NewMockCategoryRepository(ctrl)
+			tt.mock(mockProductRepo, mockCategoryRepo)
+
+			service := NewProductImportService(mockProductRepo, mockCategoryRepo, discardOutbox(ctrl), passthroughTransactor(ctrl), validator.New())
+			result, err := service.Import(context.Background(), tt.file(t), tt.format, tt.dryRun)
+			assert.Equal(t, tt.expects, result)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package service
+
+import (
+	"context"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"time"
+)
+
+type ProductPriceService interface {
+	Schedule(ctx context.Context, request web.ProductPriceScheduleRequest) (web.ProductPriceResponse, error)
+	Cancel(ctx context.Context, productId uint64, priceId uint64) error
+	FindAll(ctx context.Context, productId uint64) (web.ProductPriceHistoryResponse, error)
+	ApplyDue(ctx context.Context, now time.Time) (int, error)
+}
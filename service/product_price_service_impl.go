@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/helper"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/repository"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+	"time"
+)
+
+type ProductPriceServiceImpl struct {
+	ProductPriceRepository repository.ProductPriceRepository
+	ProductRepository      repository.ProductRepository
+	OutboxRepository       repository.OutboxRepository
+	Transactor             repository.Transactor
+	Validate               *validator.Validate
+}
+
+func NewProductPriceService(productPriceRepository repository.ProductPriceRepository, productRepository repository.ProductRepository, outboxRepository repository.OutboxRepository, transactor repository.Transactor, validate *validator.Validate) ProductPriceService {
+	return &ProductPriceServiceImpl{
+		ProductPriceRepository: productPriceRepository,
+		ProductRepository:      productRepository,
+		OutboxRepository:       outboxRepository,
+		Transactor:             transactor,
+		Validate:               validate,
+	}
+}
+
+// Schedule a future Price change
+func (service *ProductPriceServiceImpl) Schedule(ctx context.Context, request web.ProductPriceScheduleRequest) (web.ProductPriceResponse, error) {
+	ctx, span := startSpan(ctx, "ProductPriceService.Schedule", idAttribute("product", request.ProductId))
+	defer span.End()
+
+	if err := service.Validate.Struct(request); err != nil {
+		return web.ProductPriceResponse{}, err
+	}
+
+	if !request.EffectiveFrom.After(time.Now()) {
+		return web.ProductPriceResponse{}, exception.NewBadRequestError("effective_from must be in the future")
+	}
+	if request.Price.IsNegative() {
+		return web.ProductPriceResponse{}, exception.NewBadRequestError("price must not be negative")
+	}
+
+	product, err := service.ProductRepository.FindById(ctx, request.ProductId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return web.ProductPriceResponse{}, exception.NewNotFoundError("Product not found")
+	} else if err != nil {
+		return web.ProductPriceResponse{}, err
+	}
+	if request.Price.Currency != product.Price.Currency {
+		return web.ProductPriceResponse{}, exception.NewBadRequestError("price must be in " + product.Price.Currency)
+	}
+
+	price := domain.ProductPrice{
+		ProductId:     request.ProductId,
+		Price:         request.Price,
+		EffectiveFrom: request.EffectiveFrom,
+		EmployeeId:    helper.EmployeeIdFromContext(ctx),
+	}
+	savedPrice, err := service.ProductPriceRepository.Save(ctx, price)
+	if err != nil {
+		return web.ProductPriceResponse{}, err
+	}
+
+	return helper.ToProductPriceResponse(savedPrice), nil
+}
+
+// Cancel a pending Price change
+func (service *ProductPriceServiceImpl) Cancel(ctx context.Context, productId uint64, priceId uint64) error {
+	ctx, span := startSpan(ctx, "ProductPriceService.Cancel", idAttribute("product", productId), idAttribute("price", priceId))
+	defer span.End()
+
+	price, err := service.ProductPriceRepository.FindById(ctx, priceId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return exception.NewNotFoundError("Price not found")
+	} else if err != nil {
+		return err
+	}
+
+	if price.ProductId != productId {
+		return exception.NewNotFoundError("Price not found")
+	}
+	if price.AppliedAt != nil {
+		return exception.NewBadRequestError("Price has already been applied")
+	}
+
+	return service.ProductPriceRepository.Delete(ctx, price)
+}
+
+// Find Price history and pending changes of a Product
+func (service *ProductPriceServiceImpl) FindAll(ctx context.Context, productId uint64) (web.ProductPriceHistoryResponse, error) {
+	ctx, span := startSpan(ctx, "ProductPriceService.FindAll", idAttribute("product", productId))
+	defer span.End()
+
+	_, err := service.ProductRepository.FindById(ctx, productId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return web.ProductPriceHistoryResponse{}, exception.NewNotFoundError("Product not found")
+	} else if err != nil {
+		return web.ProductPriceHistoryResponse{}, err
+	}
+
+	prices, err := service.ProductPriceRepository.FindByProductId(ctx, productId)
+	if err != nil {
+		return web.ProductPriceHistoryResponse{}, err
+	}
+
+	return helper.ToProductPriceHistoryResponse(prices), nil
+}
+
+// ApplyDue applies every pending Price change whose effective time has come and returns how many were applied
+func (service *ProductPriceServiceImpl) ApplyDue(ctx context.Context, now time.Time) (int, error) {
+	ctx, span := startSpan(ctx, "ProductPriceService.ApplyDue")
+	defer span.End()
+
+	prices, err := service.ProductPriceRepository.FindDue(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	appliedCount := 0
+	var errs []error
+	for _, price := range prices {
+		applied, err := service.apply(ctx, price, now)
+		if err != nil {
+			// A failing change must not hold back the ones due after it
+			errs = append(errs, fmt.Errorf("price change %d: %w", price.Id, err))
+			continue
+		}
+		if applied {
+			appliedCount++
+		}
+	}
+	span.SetAttributes(countAttribute(appliedCount))
+	return appliedCount, errors.Join(errs...)
+}
+
+// apply a Price change and publish the PriceChanged event in one transaction
+func (service *ProductPriceServiceImpl) apply(ctx context.Context, price domain.ProductPrice, now time.Time) (bool, error) {
+	applied := false
+	err := service.Transactor.Transaction(ctx, func(ctx context.Context) error {
+		product, err := service.ProductRepository.FindById(ctx, price.ProductId)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// The product was deleted after the change was scheduled, so it will never apply
+			return service.ProductPriceRepository.Delete(ctx, price)
+		} else if err != nil {
+			return err
+		}
+		applied, err = service.ProductPriceRepository.Apply(ctx, price, now)
+		if err != nil || !applied {
+			return err
+		}
+
+		updated := product
+		updated.Price = price.Price
+		events, err := productEvents(&product, updated)
+		if err != nil {
+			return err
+		}
+		return service.OutboxRepository.Save(ctx, events)
+	})
+	return applied, err
+}
@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/helper"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/money"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/repository/mocks"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+	"testing"
+	"time"
+)
+
+func TestScheduleProductPrice(t *testing.T) {
+	monday := time.Now().Add(72 * time.Hour).Truncate(time.Second)
+	employeeId := uint64(7)
+
+	tests := []struct {
+		name    string
+		mock    func(mockPriceRepo *mocks.MockProductPriceRepository, mockProductRepo *mocks.MockProductRepository)
+		input   web.ProductPriceScheduleRequest
+		expects web.ProductPriceResponse
+		err     error
+	}{
+		{
+			name: "Success",
+			mock: func(mockPriceRepo *mocks.MockProductPriceRepository, mockProductRepo *mocks.MockProductRepository) {
+				mockProductRepo.EXPECT().FindById(gomock.Any(), uint64(1)).Return(domain.Product{ProductID: 1, Price: money.FromInt(100, "IDR")}, nil)
+				mockPriceRepo.EXPECT().Save(gomock.Any(), domain.ProductPrice{ProductId: 1, Price: money.FromInt(80, "IDR"), EffectiveFrom: monday, EmployeeId: &employeeId}).
+					Return(domain.ProductPrice{Id: 3, ProductId: 1, Price: money.FromInt(80, "IDR"), EffectiveFrom: monday, EmployeeId: &employeeId}, nil)
+			},
+			input:   web.ProductPriceScheduleRequest{ProductId: 1, Price: money.FromInt(80, "IDR"), EffectiveFrom: monday},
+			expects: web.ProductPriceResponse{Id: 3, ProductId: 1, Price: money.FromInt(80, "IDR"), EffectiveFrom: monday, EmployeeId: &employeeId},
+			err:     nil,
+		},
+		{
+			name:    "Effective In The Past",
+			mock:    func(mockPriceRepo *mocks.MockProductPriceRepository, mockProductRepo *mocks.MockProductRepository) {},
+			input:   web.ProductPriceScheduleRequest{ProductId: 1, Price: money.FromInt(80, "IDR"), EffectiveFrom: time.Now().Add(-time.Hour)},
+			expects: web.ProductPriceResponse{},
+			err:     exception.NewBadRequestError("effective_from must be in the future"),
+		},
+		{
+			name: "Product Not Found",
+			mock: func(mockPriceRepo *mocks.MockProductPriceRepository, mockProductRepo *mocks.MockProductRepository) {
+				mockProductRepo.EXPECT().FindById(gomock.Any(), uint64(1)).Return(domain.Product{}, gorm.ErrRecordNotFound)
+			},
+			input:   web.ProductPriceScheduleRequest{ProductId: 1, Price: money.FromInt(80, "IDR"), EffectiveFrom: monday},
+			expects: web.ProductPriceResponse{},
+			err:     exception.NewNotFoundError("Product not found"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockPriceRepo := mocks.NewMockProductPriceRepository(ctrl)
+			mockProductRepo := mocks.NewMockProductRepository(ctrl)
+			tt.mock(mockPriceRepo, mockProductRepo)
+
+			ctx := context.WithValue(context.Background(), helper.EmployeeIdKey, employeeId)
+			service := NewProductPriceService(mockPriceRepo, mockProductRepo, discardOutbox(ctrl), passthroughTransactor(ctrl), validator.New())
+			result, err := service.Schedule(ctx, tt.input)
+			assert.Equal(t, tt.expects, result)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestCancelProductPrice(t *testing.T) {
+	appliedAt := time.Now()
+
+	tests := []struct {
+		name string
+		mock func(mockPriceRepo *mocks.MockProductPriceRepository)
+		err  error
+	}{
+		{
+			name: "Success",
+			mock: func(mockPriceRepo *mocks.MockProductPriceRepository) {
+				mockPriceRepo.EXPECT().FindById(gomock.Any(), uint64(3)).Return(domain.ProductPrice{Id: 3, ProductId: 1}, nil)
+				mockPriceRepo.EXPECT().Delete(gomock.Any(), domain.ProductPrice{Id: 3, ProductId: 1}).Return(nil)
+			},
+			err: nil,
+		},
+		{
+			name: "Already Applied",
+			mock: func(mockPriceRepo *mocks.MockProductPriceRepository) {
+				mockPriceRepo.EXPECT().FindById(gomock.Any(), uint64(3)).Return(domain.ProductPrice{Id: 3, ProductId: 1, AppliedAt: &appliedAt}, nil)
+			},
+			err: exception.NewBadRequestError("Price has already been applied"),
+		},
+		{
+			name: "Price Of Another Product",
+			mock: func(mockPriceRepo *mocks.MockProductPriceRepository) {
+				mockPriceRepo.EXPECT().FindById(gomock.Any(), uint64(3)).Return(domain.ProductPrice{Id: 3, ProductId: 2}, nil)
+			},
+			err: exception.NewNotFoundError("Price not found"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockPriceRepo := mocks.NewMockProductPriceRepository(ctrl)
+			tt.mock(mockPriceRepo)
+
+			service := NewProductPriceService(mockPriceRepo, mocks.NewMockProductRepository(ctrl), discardOutbox(ctrl), passthroughTransactor(ctrl), validator.New())
+			err := service.Cancel(context.Background(), 1, 3)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestFindAllProductPrices(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockPriceRepo := mocks.NewMockProductPriceRepository(ctrl)
+	mockProductRepo := mocks.NewMockProductRepository(ctrl)
+
+	appliedAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+	pendingFrom := time.Now().Add(time.Hour).Truncate(time.Second)
+	mockProductRepo.EXPECT().FindById(gomock.Any(), uint64(1)).Return(domain.Product{ProductID: 1}, nil)
+	mockPriceRepo.EXPECT().FindByProductId(gomock.Any(), uint64(1)).Return([]domain.ProductPrice{
+		{Id: 2, ProductId: 1, Price: money.FromInt(80, "IDR"), EffectiveFrom: pendingFrom},
+		{Id: 1, ProductId: 1, Price: money.FromInt(100, "IDR"), EffectiveFrom: appliedAt, AppliedAt: &appliedAt},
+	}, nil)
+
+	service := NewProductPriceService(mockPriceRepo, mockProductRepo, discardOutbox(ctrl), passthroughTransactor(ctrl), validator.New())
+	result, err := service.FindAll(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, web.ProductPriceHistoryResponse{
+		History: []web.ProductPriceResponse{{Id: 1, ProductId: 1, Price: money.FromInt(100, "IDR"), EffectiveFrom: appliedAt, AppliedAt: &appliedAt}},
+		Pending: []web.ProductPriceResponse{{Id: 2, ProductId: 1, Price: money.FromInt(80, "IDR"), EffectiveFrom: pendingFrom}},
+	}, result)
+}
+
+func TestApplyDueProductPrices(t *testing.T) {
+	now := time.Now()
+	due := []domain.ProductPrice{
+		{Id: 1, ProductId: 1, Price: money.FromInt(80, "IDR"), EffectiveFrom: now.Add(-time.Minute)},
+		{Id: 2, ProductId: 2, Price: money.FromInt(50, "IDR"), EffectiveFrom: now.Add(-time.Second)},
+	}
+
+	tests := []struct {
+		name    string
+		mock    func(mockPriceRepo *mocks.MockProductPriceRepository, mockProductRepo *mocks.MockProductRepository, mockOutbox *mocks.MockOutboxRepository)
+		expects int
+		err     error
+	}{
+		{
+			name: "Skips Changes Applied Elsewhere",
+			mock: func(mockPriceRepo *mocks.MockProductPriceRepository, mockProductRepo *mocks.MockProductRepository, mockOutbox *mocks.MockOutboxRepository) {
+				mockPriceRepo.EXPECT().FindDue(gomock.Any(), now).Return(due, nil)
+				mockProductRepo.EXPECT().FindById(gomock.Any(), uint64(1)).Return(domain.Product{ProductID: 1, Price: money.FromInt(100, "IDR")}, nil)
+				mockPriceRepo.EXPECT().Apply(gomock.Any(), due[0], now).Return(true, nil)
+				mockOutbox.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, events []domain.OutboxEvent) error {
+					assert.Len(t, events, 1)
+					assert.Equal(t, domain.PriceChangedEvent, events[0].Type)
+					assert.Equal(t, uint64(1), events[0].AggregateId)
+					return nil
+				})
+				mockProductRepo.EXPECT().FindById(gomock.Any(), uint64(2)).Return(domain.Product{ProductID: 2, Price: money.FromInt(60, "IDR")}, nil)
+				mockPriceRepo.EXPECT().Apply(gomock.Any(), due[1], now).Return(false, nil)
+			},
+			expects: 1,
+			err:     nil,
+		},
+		{
+			name: "Failing Change In The Middle",
+			mock: func(mockPriceRepo *mocks.MockProductPriceRepository, mockProductRepo *mocks.MockProductRepository, mockOutbox *mocks.MockOutboxRepository) {
+				third := domain.ProductPrice{Id: 3, ProductId: 3, Price: money.FromInt(30, "IDR"), EffectiveFrom: now}
+				mockPriceRepo.EXPECT().FindDue(gomock.Any(), now).Return(append(due[:2:2], third), nil)
+				mockProductRepo.EXPECT().FindById(gomock.Any(), uint64(1)).Return(domain.Product{ProductID: 1, Price: money.FromInt(100, "IDR")}, nil)
+				mockPriceRepo.EXPECT().Apply(gomock.Any(), due[0], now).Return(true, nil)
+				mockProductRepo.EXPECT().FindById(gomock.Any(), uint64(2)).Return(domain.Product{ProductID: 2, Price: money.FromInt(60, "IDR")}, nil)
+				mockPriceRepo.EXPECT().Apply(gomock.Any(), due[1], now).Return(false, errors.New("database error"))
+				mockProductRepo.EXPECT().FindById(gomock.Any(), uint64(3)).Return(domain.Product{ProductID: 3, Price: money.FromInt(40, "IDR")}, nil)
+				mockPriceRepo.EXPECT().Apply(gomock.Any(), third, now).Return(true, nil)
+				mockOutbox.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+			},
+			expects: 2,
+			err:     errors.New("price change 2: database error"),
+		},
+		{
+			name: "Cancels Changes Of Deleted Products",
+			mock: func(mockPriceRepo *mocks.MockProductPriceRepository, mockProductRepo *mocks.MockProductRepository, mockOutbox *mocks.MockOutboxRepository) {
+				mockPriceRepo.EXPECT().FindDue(gomock.Any(), now).Return(due, nil)
+				mockProductRepo.EXPECT().FindById(gomock.Any(), uint64(1)).Return(domain.Product{}, fmt.Errorf("product is not found: %w", gorm.ErrRecordNotFound))
+				mockPriceRepo.EXPECT().Delete(gomock.Any(), due[0]).Return(nil)
+				mockProductRepo.EXPECT().FindById(gomock.Any(), uint64(2)).Return(domain.Product{ProductID: 2, Price: money.FromInt(60, "IDR")}, nil)
+				mockPriceRepo.EXPECT().Apply(gomock.Any(), due[1], now).Return(true, nil)
+				mockOutbox.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			expects: 1,
+			err:     nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockPriceRepo := mocks.NewMockProductPriceRepository(ctrl)
+			mockProductRepo := mocks.NewMockProductRepository(ctrl)
+			mockOutbox := mocks.NewMockOutboxRepository(ctrl)
+			tt.mock(mockPriceRepo, mockProductRepo, mockOutbox)
+
+			service := NewProductPriceService(mockPriceRepo, mockProductRepo, mockOutbox, passthroughTransactor(ctrl), validator.New())
+			result, err := service.ApplyDue(context.Background(), now)
+			assert.Equal(t, tt.expects, result)
+			if tt.err != nil {
+				assert.EqualError(t, err, tt.err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
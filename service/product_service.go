@@ -8,7 +8,11 @@ import (
 type ProductService interface {
 	Create(ctx context.Context, request web.ProductCreateRequest) (web.ProductResponse, error)
 	Update(ctx context.Context, request web.ProductUpdateRequest) (web.ProductResponse, error)
-	Delete(ctx context.Context, productId uint64) error
+	Patch(ctx context.Context, request web.PatchRequest) (web.ProductResponse, error)
+	Delete(ctx context.Context, productId uint64, version uint64) error
+	Restore(ctx context.Context, productId uint64) (web.ProductResponse, error)
 	FindById(ctx context.Context, productId uint64) (web.ProductResponse, error)
 	FindAll(ctx context.Context) ([]web.ProductResponse, error)
+	FindByCategory(ctx context.Context, categoryId uint64) ([]web.ProductResponse, error)
+	Lookup(ctx context.Context, code string) (web.ProductLookupResponse, error)
 }
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"github.com/aronipurwanto/go-restful-api/exception"
 	"github.com/aronipurwanto/go-restful-api/helper"
+	"github.com/aronipurwanto/go-restful-api/metrics"
 	"github.com/aronipurwanto/go-restful-api/model/domain"
 	"github.com/aronipurwanto/go-restful-api/model/web"
 	"github.com/aronipurwanto/go-restful-api/repository"
@@ -14,36 +15,72 @@ import (
 
 type ProductServiceImpl struct {
 	ProductRepository repository.ProductRepository
+	OutboxRepository  repository.OutboxRepository
+	Transactor        repository.Transactor
 	Validate          *validator.Validate
 }
 
-func NewProductService(productRepository repository.ProductRepository, validate *validator.Validate) ProductService {
+func NewProductService(productRepository repository.ProductRepository, outboxRepository repository.OutboxRepository, transactor repository.Transactor, validate *validator.Validate) ProductService {
 	return &ProductServiceImpl{
 		ProductRepository: productRepository,
+		OutboxRepository:  outboxRepository,
+		Transactor:        transactor,
 		Validate:          validate,
 	}
 }
 
 // Create Product
 func (service *ProductServiceImpl) Create(ctx context.Context, request web.ProductCreateRequest) (web.ProductResponse, error) {
+	ctx, span := startSpan(ctx, "ProductService.Create")
+	defer span.End()
+
 	if err := service.Validate.Struct(request); err != nil {
 		return web.ProductResponse{}, err
 	}
+	if request.Price.IsNegative() {
+		return web.ProductResponse{}, exception.NewBadRequestError("price must not be negative")
+	}
 
-	product := domain.Product{Name: request.Name}
-	savedProduct, err := service.ProductRepository.Save(ctx, product)
+	barcodes, err := toProductBarcodes(request.Barcodes)
 	if err != nil {
 		return web.ProductResponse{}, err
 	}
 
+	product := domain.Product{
+		Name:        request.Name,
+		Description: request.Description,
+		Price:       request.Price,
+		StockQty:    request.StockQty,
+		CategoryId:  uint64(request.CategoryID),
+		SKU:         request.SKU,
+		TaxRate:     request.TaxRate,
+		Barcodes:    barcodes,
+	}
+	savedProduct, err := service.save(ctx, nil, product)
+	if err != nil {
+		return web.ProductResponse{}, err
+	}
+	metrics.ProductsCreated.Inc()
+
 	return helper.ToProductResponse(savedProduct), nil
 }
 
 // Update Product
 func (service *ProductServiceImpl) Update(ctx context.Context, request web.ProductUpdateRequest) (web.ProductResponse, error) {
+	ctx, span := startSpan(ctx, "ProductService.Update", idAttribute("product", request.Id))
+	defer span.End()
+
 	if err := service.Validate.Struct(request); err != nil {
 		return web.ProductResponse{}, err
 	}
+	if request.Price.IsNegative() {
+		return web.ProductResponse{}, exception.NewBadRequestError("price must not be negative")
+	}
+
+	barcodes, err := toProductBarcodes(request.Barcodes)
+	if err != nil {
+		return web.ProductResponse{}, err
+	}
 
 	product, err := service.ProductRepository.FindById(ctx, request.Id)
 	if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -51,18 +88,55 @@ func (service *ProductServiceImpl) Update(ctx context.Context, request web.Produ
 	} else if err != nil {
 		return web.ProductResponse{}, err
 	}
+	if err := checkVersion(ctx, "Product", request.Version, product.Version); err != nil {
+		return web.ProductResponse{}, err
+	}
 
+	previous := product
 	product.Name = request.Name
-	updatedProduct, err := service.ProductRepository.Update(ctx, product)
+	product.Description = request.Description
+	product.Price = request.Price
+	product.StockQty = request.StockQty
+	product.CategoryId = uint64(request.CategoryID)
+	product.SKU = request.SKU
+	product.TaxRate = request.TaxRate
+	product.Barcodes = barcodes
+	updatedProduct, err := service.save(ctx, &previous, product)
 	if err != nil {
-		return web.ProductResponse{}, err
+		return web.ProductResponse{}, versionConflict("Product", err)
 	}
 
 	return helper.ToProductResponse(updatedProduct), nil
 }
 
+// Patch Product with a merge patch or JSON patch applied to its current state
+func (service *ProductServiceImpl) Patch(ctx context.Context, request web.PatchRequest) (web.ProductResponse, error) {
+	ctx, span := startSpan(ctx, "ProductService.Patch", idAttribute("product", request.Id))
+	defer span.End()
+
+	product, err := service.ProductRepository.FindById(ctx, request.Id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return web.ProductResponse{}, exception.NewNotFoundError("Product not found")
+	} else if err != nil {
+		return web.ProductResponse{}, err
+	}
+	if err := checkVersion(ctx, "Product", request.Version, product.Version); err != nil {
+		return web.ProductResponse{}, err
+	}
+
+	var updateRequest web.ProductUpdateRequest
+	if err := applyPatch(request, helper.ToProductUpdateRequest(product), product.Version, &updateRequest); err != nil {
+		return web.ProductResponse{}, err
+	}
+
+	return service.Update(ctx, updateRequest)
+}
+
 // Delete Product
-func (service *ProductServiceImpl) Delete(ctx context.Context, productId uint64) error {
+func (service *ProductServiceImpl) Delete(ctx context.Context, productId uint64, version uint64) error {
+	ctx, span := startSpan(ctx, "ProductService.Delete", idAttribute("product", productId))
+	defer span.End()
+
 	product, err := service.ProductRepository.FindById(ctx, productId)
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return exception.NewNotFoundError("Product not found")
@@ -70,11 +144,35 @@ func (service *ProductServiceImpl) Delete(ctx context.Context, productId uint64)
 		return err
 	}
 
-	return service.ProductRepository.Delete(ctx, product)
+	if err := checkVersion(ctx, "Product", version, product.Version); err != nil {
+		return err
+	}
+
+	return versionConflict("Product", service.ProductRepository.Delete(ctx, product))
+}
+
+// Restore a soft-deleted Product
+func (service *ProductServiceImpl) Restore(ctx context.Context, productId uint64) (web.ProductResponse, error) {
+	ctx, span := startSpan(ctx, "ProductService.Restore", idAttribute("product", productId))
+	defer span.End()
+
+	err := service.ProductRepository.Restore(ctx, productId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return web.ProductResponse{}, exception.NewNotFoundError("Deleted product not found")
+	} else if errors.Is(err, repository.ErrParentDeleted) {
+		return web.ProductResponse{}, exception.NewBadRequestError("Category of the product is deleted, restore it first")
+	} else if err != nil {
+		return web.ProductResponse{}, err
+	}
+
+	return service.FindById(ctx, productId)
 }
 
 // Find Product By ID
 func (service *ProductServiceImpl) FindById(ctx context.Context, productId uint64) (web.ProductResponse, error) {
+	ctx, span := startSpan(ctx, "ProductService.FindById", idAttribute("product", productId))
+	defer span.End()
+
 	product, err := service.ProductRepository.FindById(ctx, productId)
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return web.ProductResponse{}, exception.NewNotFoundError("Product not found")
@@ -87,10 +185,93 @@ func (service *ProductServiceImpl) FindById(ctx context.Context, productId uint6
 
 // Find All Products
 func (service *ProductServiceImpl) FindAll(ctx context.Context) ([]web.ProductResponse, error) {
+	ctx, span := startSpan(ctx, "ProductService.FindAll")
+	defer span.End()
+
 	products, err := service.ProductRepository.FindAll(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	span.SetAttributes(countAttribute(len(products)))
+	return helper.ToProductResponses(products), nil
+}
+
+// Find Products of a Category including all of its descendant categories
+func (service *ProductServiceImpl) FindByCategory(ctx context.Context, categoryId uint64) ([]web.ProductResponse, error) {
+	ctx, span := startSpan(ctx, "ProductService.FindByCategory", idAttribute("category", categoryId))
+	defer span.End()
+
+	products, err := service.ProductRepository.FindByCategoryTree(ctx, categoryId)
+	if err != nil {
+		return nil, err
+	}
+
+	span.SetAttributes(countAttribute(len(products)))
 	return helper.ToProductResponses(products), nil
 }
+
+// Lookup Product By scanned barcode
+func (service *ProductServiceImpl) Lookup(ctx context.Context, code string) (web.ProductLookupResponse, error) {
+	ctx, span := startSpan(ctx, "ProductService.Lookup")
+	defer span.End()
+
+	if !helper.IsValidBarcode(code) {
+		return web.ProductLookupResponse{}, exception.NewBadRequestError("Invalid barcode " + code)
+	}
+
+	barcode, err := service.ProductRepository.FindByBarcode(ctx, helper.NormalizeBarcode(code))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return web.ProductLookupResponse{}, exception.NewNotFoundError("Product not found")
+	} else if err != nil {
+		return web.ProductLookupResponse{}, err
+	}
+
+	return helper.ToProductLookupResponse(barcode), nil
+}
+
+// save writes the product together with its domain events in one transaction; previous is nil for a new product
+func (service *ProductServiceImpl) save(ctx context.Context, previous *domain.Product, product domain.Product) (domain.Product, error) {
+	var saved domain.Product
+	err := service.Transactor.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		if previous == nil {
+			saved, err = service.ProductRepository.Save(ctx, product)
+		} else {
+			saved, err = service.ProductRepository.Update(ctx, product)
+		}
+		if err != nil {
+			return err
+		}
+
+		events, err := productEvents(previous, saved)
+		if err != nil {
+			return err
+		}
+		return service.OutboxRepository.Save(ctx, events)
+	})
+	if errors.Is(err, repository.ErrDeletedProductBarcode) {
+		return saved, exception.NewBadRequestError("Barcode belongs to a deleted product; restore or purge that product to reuse it")
+	} else if errors.Is(err, repository.ErrDuplicateBarcode) {
+		return saved, exception.NewBadRequestError("Barcode is already used by another product")
+	}
+	return saved, err
+}
+
+func toProductBarcodes(requests []web.ProductBarcodeRequest) ([]domain.ProductBarcode, error) {
+	var barcodes []domain.ProductBarcode
+	seen := make(map[string]bool)
+	for _, request := range requests {
+		if !helper.IsValidBarcode(request.Code) {
+			return nil, exception.NewBadRequestError("Invalid barcode " + request.Code)
+		}
+		code := helper.NormalizeBarcode(request.Code)
+		if seen[code] {
+			return nil, exception.NewBadRequestError("Duplicate barcode " + request.Code)
+		}
+		seen[code] = true
+
+		barcodes = append(barcodes, domain.ProductBarcode{Code: code, PackQty: request.PackQty})
+	}
+	return barcodes, nil
+}
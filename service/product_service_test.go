@@ -3,12 +3,18 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/helper"
 	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/money"
 	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/repository"
 	"github.com/aronipurwanto/go-restful-api/repository/mocks"
 	"github.com/go-playground/validator/v10"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
 	"testing"
 )
 
@@ -18,7 +24,7 @@ func TestCreateProduct(t *testing.T) {
 
 	mockRepo := mocks.NewMockProductRepository(ctrl)
 	mockValidator := validator.New()
-	productService := NewProductService(mockRepo, mockValidator)
+	productService := NewProductService(mockRepo, discardOutbox(ctrl), passthroughTransactor(ctrl), mockValidator)
 
 	tests := []struct {
 		name      string
@@ -29,11 +35,11 @@ func TestCreateProduct(t *testing.T) {
 	}{
 		{
 			name:  "success",
-			input: web.ProductCreateRequest{Name: "Test", Description: "Test", Price: 1, StockQty: 1, CategoryID: 1, SKU: "test", TaxRate: 1.0},
+			input: web.ProductCreateRequest{Name: "Test", Description: "Test", Price: money.FromInt(1, "IDR"), StockQty: 1, CategoryID: 1, SKU: "test", TaxRate: money.AmountFromInt(1)},
 			mock: func() {
-				mockRepo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(domain.Product{Name: "Test", Description: "Test", Price: 1, StockQty: 1, CategoryId: 1, SKU: "test", TaxRate: 1.0}, nil)
+				mockRepo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(domain.Product{Name: "Test", Description: "Test", Price: money.FromInt(1, "IDR"), StockQty: 1, CategoryId: 1, SKU: "test", TaxRate: money.AmountFromInt(1)}, nil)
 			},
-			expect:    web.ProductResponse{Name: "Test", Description: "Test", Price: 1, StockQty: 1, CategoryID: 1, SKU: "test", TaxRate: 1.0},
+			expect:    web.ProductResponse{Name: "Test", Description: "Test", Price: money.FromInt(1, "IDR"), StockQty: 1, CategoryID: 1, SKU: "test", TaxRate: money.AmountFromInt(1)},
 			expectErr: false,
 		},
 		{
@@ -43,9 +49,34 @@ func TestCreateProduct(t *testing.T) {
 			expect:    web.ProductResponse{},
 			expectErr: true,
 		},
+		{
+			name:      "invalid barcode check digit",
+			input:     web.ProductCreateRequest{Name: "Test", Description: "Test", Price: money.FromInt(1, "IDR"), StockQty: 1, CategoryID: 1, SKU: "test", TaxRate: money.AmountFromInt(1), Barcodes: []web.ProductBarcodeRequest{{Code: "4006381333932", PackQty: 1}}},
+			mock:      func() {},
+			expect:    web.ProductResponse{},
+			expectErr: true,
+		},
+		{
+			name:      "UPC-A and its EAN-13 form",
+			input:     web.ProductCreateRequest{Name: "Test", Description: "Test", Price: money.FromInt(1, "IDR"), StockQty: 1, CategoryID: 1, SKU: "test", TaxRate: money.AmountFromInt(1), Barcodes: []web.ProductBarcodeRequest{{Code: "036000291452", PackQty: 1}, {Code: "0036000291452", PackQty: 1}}},
+			mock:      func() {},
+			expect:    web.ProductResponse{},
+			expectErr: true,
+		},
+		{
+			name:  "UPC-A stored as EAN-13",
+			input: web.ProductCreateRequest{Name: "Test", Description: "Test", Price: money.FromInt(1, "IDR"), StockQty: 1, CategoryID: 1, SKU: "test", TaxRate: money.AmountFromInt(1), Barcodes: []web.ProductBarcodeRequest{{Code: "036000291452", PackQty: 1}}},
+			mock: func() {
+				mockRepo.EXPECT().Save(gomock.Any(), gomock.Cond(func(product domain.Product) bool {
+					return len(product.Barcodes) == 1 && product.Barcodes[0].Code == "0036000291452"
+				})).Return(domain.Product{Name: "Test"}, nil)
+			},
+			expect:    web.ProductResponse{Name: "Test"},
+			expectErr: false,
+		},
 		{
 			name:  "repository error",
-			input: web.ProductCreateRequest{Name: "Test", Description: "Test", Price: 1, StockQty: 1, CategoryID: 1, SKU: "test", TaxRate: 1.0},
+			input: web.ProductCreateRequest{Name: "Test", Description: "Test", Price: money.FromInt(1, "IDR"), StockQty: 1, CategoryID: 1, SKU: "test", TaxRate: money.AmountFromInt(1)},
 			mock: func() {
 				mockRepo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(domain.Product{}, errors.New("database error"))
 			},
@@ -68,12 +99,35 @@ func TestCreateProduct(t *testing.T) {
 	}
 }
 
+func TestCreateProductDuplicateBarcode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockProductRepository(ctrl)
+	productService := NewProductService(mockRepo, discardOutbox(ctrl), passthroughTransactor(ctrl), validator.New())
+	mockRepo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(domain.Product{}, fmt.Errorf("%w: Duplicate entry", repository.ErrDuplicateBarcode))
+
+	_, err := productService.Create(context.Background(), web.ProductCreateRequest{
+		Name: "Test", Description: "Test", Price: money.FromInt(1, "IDR"), StockQty: 1, CategoryID: 1, SKU: "test", TaxRate: money.AmountFromInt(1),
+		Barcodes: []web.ProductBarcodeRequest{{Code: "4006381333931", PackQty: 1}},
+	})
+	assert.Equal(t, exception.NewBadRequestError("Barcode is already used by another product"), err)
+
+	mockRepo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(domain.Product{}, fmt.Errorf("%w: barcode 4006381333931 belongs to deleted product 7", repository.ErrDeletedProductBarcode))
+
+	_, err = productService.Create(context.Background(), web.ProductCreateRequest{
+		Name: "Test", Description: "Test", Price: money.FromInt(1, "IDR"), StockQty: 1, CategoryID: 1, SKU: "test", TaxRate: money.AmountFromInt(1),
+		Barcodes: []web.ProductBarcodeRequest{{Code: "4006381333931", PackQty: 1}},
+	})
+	assert.Equal(t, exception.NewBadRequestError("Barcode belongs to a deleted product; restore or purge that product to reuse it"), err)
+}
+
 func TestDeleteProduct(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockProductRepository(ctrl)
-	productService := NewProductService(mockRepo, validator.New())
+	productService := NewProductService(mockRepo, discardOutbox(ctrl), passthroughTransactor(ctrl), validator.New())
 
 	tests := []struct {
 		name      string
@@ -85,7 +139,7 @@ func TestDeleteProduct(t *testing.T) {
 			name:      "success",
 			productId: 1,
 			mock: func() {
-				mockRepo.EXPECT().FindById(gomock.Any(), uint64(1)).Return(domain.Product{Name: "Test", Description: "Test", Price: 1, StockQty: 1, CategoryId: 1, SKU: "test", TaxRate: 1.0}, nil)
+				mockRepo.EXPECT().FindById(gomock.Any(), uint64(1)).Return(domain.Product{Name: "Test", Description: "Test", Price: money.FromInt(1, "IDR"), StockQty: 1, CategoryId: 1, SKU: "test", TaxRate: money.AmountFromInt(1)}, nil)
 				mockRepo.EXPECT().Delete(gomock.Any(), gomock.Any()).Return(nil)
 			},
 			expectErr: false,
@@ -103,7 +157,7 @@ func TestDeleteProduct(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
-			err := productService.Delete(context.Background(), tt.productId)
+			err := productService.Delete(context.Background(), tt.productId, 0)
 			if tt.expectErr {
 				assert.Error(t, err)
 			} else {
@@ -113,6 +167,22 @@ func TestDeleteProduct(t *testing.T) {
 	}
 }
 
+func TestDeleteProductIfMatchList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockProductRepository(ctrl)
+	productService := NewProductService(mockRepo, discardOutbox(ctrl), passthroughTransactor(ctrl), validator.New())
+	mockRepo.EXPECT().FindById(gomock.Any(), uint64(1)).Return(domain.Product{ProductID: 1, Version: 4}, nil).Times(2)
+	mockRepo.EXPECT().Delete(gomock.Any(), gomock.Any()).Return(nil)
+
+	stale := context.WithValue(context.Background(), helper.IfMatchKey, []uint64{2, 3})
+	assert.Equal(t, exception.NewPreconditionFailedError("Product has been modified by another request"), productService.Delete(stale, 1, 0))
+
+	current := context.WithValue(context.Background(), helper.IfMatchKey, []uint64{3, 4})
+	assert.NoError(t, productService.Delete(current, 1, 0))
+}
+
 func TestUpdateProduct(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -124,11 +194,11 @@ func TestUpdateProduct(t *testing.T) {
 			name: "Success",
 			mock: func(mockProductRepo *mocks.MockProductRepository) {
 				mockProductRepo.EXPECT().FindById(gomock.Any(), uint64(1)).
-					Return(domain.Product{Name: "Test", Description: "Test", Price: 1, StockQty: 1, CategoryId: 1, SKU: "test", TaxRate: 1.0}, nil)
+					Return(domain.Product{Name: "Test", Description: "Test", Price: money.FromInt(1, "IDR"), StockQty: 1, CategoryId: 1, SKU: "test", TaxRate: money.AmountFromInt(1)}, nil)
 				mockProductRepo.EXPECT().Update(gomock.Any(), gomock.Any()).
-					Return(domain.Product{Name: "Updated Test", Description: "Test", Price: 1, StockQty: 1, CategoryId: 1, SKU: "test", TaxRate: 1.0}, nil)
+					Return(domain.Product{Name: "Updated Test", Description: "Test", Price: money.FromInt(1, "IDR"), StockQty: 1, CategoryId: 1, SKU: "test", TaxRate: money.AmountFromInt(1)}, nil)
 			},
-			input:   web.ProductUpdateRequest{Id: 1, Name: "Updated Test", Description: "Test", Price: 1, StockQty: 1, CategoryID: 1, SKU: "test", TaxRate: 1.0},
+			input:   web.ProductUpdateRequest{Id: 1, Name: "Updated Test", Description: "Test", Price: money.FromInt(1, "IDR"), StockQty: 1, CategoryID: 1, SKU: "test", TaxRate: money.AmountFromInt(1)},
 			expects: nil,
 		},
 		{
@@ -137,7 +207,7 @@ func TestUpdateProduct(t *testing.T) {
 				mockProductRepo.EXPECT().FindById(gomock.Any(), uint64(1)).
 					Return(domain.Product{}, errors.New("not found"))
 			},
-			input:   web.ProductUpdateRequest{Id: 1, Name: "Test", Description: "Test", Price: 1, StockQty: 1, CategoryID: 1, SKU: "test", TaxRate: 1.0},
+			input:   web.ProductUpdateRequest{Id: 1, Name: "Test", Description: "Test", Price: money.FromInt(1, "IDR"), StockQty: 1, CategoryID: 1, SKU: "test", TaxRate: money.AmountFromInt(1)},
 			expects: errors.New("not found"),
 		},
 		{
@@ -145,20 +215,40 @@ func TestUpdateProduct(t *testing.T) {
 			mock: func(mockProductRepo *mocks.MockProductRepository) {
 				// Tidak perlu mock FindById karena validasi gagal sebelum ke repository
 			},
-			input:   web.ProductUpdateRequest{Id: 1, Name: "", Description: "Test", Price: 1, StockQty: 1, CategoryID: 1, SKU: "test", TaxRate: 1.0},
+			input:   web.ProductUpdateRequest{Id: 1, Name: "", Description: "Test", Price: money.FromInt(1, "IDR"), StockQty: 1, CategoryID: 1, SKU: "test", TaxRate: money.AmountFromInt(1)},
 			expects: errors.New("ProductUpdateRequest.Name"),
 		},
 		{
 			name: "Database Error on Update",
 			mock: func(mockProductRepo *mocks.MockProductRepository) {
 				mockProductRepo.EXPECT().FindById(gomock.Any(), uint64(1)).
-					Return(domain.Product{Name: "Test", Description: "Test", Price: 1, StockQty: 1, CategoryId: 1, SKU: "test", TaxRate: 1.0}, nil)
+					Return(domain.Product{Name: "Test", Description: "Test", Price: money.FromInt(1, "IDR"), StockQty: 1, CategoryId: 1, SKU: "test", TaxRate: money.AmountFromInt(1)}, nil)
 				mockProductRepo.EXPECT().Update(gomock.Any(), gomock.Any()).
 					Return(domain.Product{}, errors.New("database error"))
 			},
-			input:   web.ProductUpdateRequest{Id: 1, Name: "Updated Test", Description: "Test", Price: 1, StockQty: 1, CategoryID: 1, SKU: "test", TaxRate: 1.0},
+			input:   web.ProductUpdateRequest{Id: 1, Name: "Updated Test", Description: "Test", Price: money.FromInt(1, "IDR"), StockQty: 1, CategoryID: 1, SKU: "test", TaxRate: money.AmountFromInt(1)},
 			expects: errors.New("database error"),
 		},
+		{
+			name: "Stale If-Match Version",
+			mock: func(mockProductRepo *mocks.MockProductRepository) {
+				mockProductRepo.EXPECT().FindById(gomock.Any(), uint64(1)).
+					Return(domain.Product{ProductID: 1, Name: "Test", Price: money.FromInt(1, "IDR"), Version: 4}, nil)
+			},
+			input:   web.ProductUpdateRequest{Id: 1, Name: "Updated Test", Description: "Test", Price: money.FromInt(1, "IDR"), StockQty: 1, CategoryID: 1, SKU: "test", TaxRate: money.AmountFromInt(1), Version: 3},
+			expects: exception.NewPreconditionFailedError("Product has been modified by another request"),
+		},
+		{
+			name: "Concurrent Update Wins",
+			mock: func(mockProductRepo *mocks.MockProductRepository) {
+				mockProductRepo.EXPECT().FindById(gomock.Any(), uint64(1)).
+					Return(domain.Product{ProductID: 1, Name: "Test", Price: money.FromInt(1, "IDR"), Version: 4}, nil)
+				mockProductRepo.EXPECT().Update(gomock.Any(), gomock.Any()).
+					Return(domain.Product{}, repository.ErrVersionConflict)
+			},
+			input:   web.ProductUpdateRequest{Id: 1, Name: "Updated Test", Description: "Test", Price: money.FromInt(1, "IDR"), StockQty: 1, CategoryID: 1, SKU: "test", TaxRate: money.AmountFromInt(1), Version: 4},
+			expects: exception.NewPreconditionFailedError("Product has been modified by another request"),
+		},
 	}
 
 	for _, tt := range tests {
@@ -168,7 +258,7 @@ func TestUpdateProduct(t *testing.T) {
 			mockProductRepo := mocks.NewMockProductRepository(ctrl)
 			tt.mock(mockProductRepo)
 
-			service := NewProductService(mockProductRepo, validator.New())
+			service := NewProductService(mockProductRepo, discardOutbox(ctrl), passthroughTransactor(ctrl), validator.New())
 			_, err := service.Update(context.Background(), tt.input)
 
 			if tt.expects != nil {
@@ -181,6 +271,144 @@ func TestUpdateProduct(t *testing.T) {
 	}
 }
 
+func TestPatchProduct(t *testing.T) {
+	stored := domain.Product{ProductID: 1, Name: "Test", Description: "Test", Price: money.FromInt(1, "IDR"), StockQty: 1, CategoryId: 1, SKU: "test", TaxRate: money.AmountFromInt(1), Version: 2}
+
+	tests := []struct {
+		name    string
+		input   web.PatchRequest
+		updated func(product domain.Product) bool
+		expects error
+	}{
+		{
+			name:  "Merge Patch Keeps Absent Fields",
+			input: web.PatchRequest{Id: 1, ContentType: helper.MergePatchContentType, Patch: []byte(`{"name":"Patched"}`)},
+			updated: func(product domain.Product) bool {
+				return product.Name == "Patched" && product.Description == "Test" && product.SKU == "test" && product.Version == 2
+			},
+		},
+		{
+			name:  "Merge Patch Null Clears Field",
+			input: web.PatchRequest{Id: 1, ContentType: helper.MergePatchContentType, Patch: []byte(`{"description":null}`)},
+			updated: func(product domain.Product) bool {
+				return product.Name == "Test" && product.Description == ""
+			},
+		},
+		{
+			name:    "Merge Patch Null Required Field",
+			input:   web.PatchRequest{Id: 1, ContentType: helper.MergePatchContentType, Patch: []byte(`{"name":null}`)},
+			expects: errors.New("ProductUpdateRequest.Name"),
+		},
+		{
+			name:    "Merge Patch Unknown Field",
+			input:   web.PatchRequest{Id: 1, ContentType: helper.MergePatchContentType, Patch: []byte(`{"colour":"red"}`)},
+			expects: exception.NewBadRequestError(`Invalid patch: json: unknown field "colour"`),
+		},
+		{
+			name:  "Merge Patch Cannot Change Id",
+			input: web.PatchRequest{Id: 1, ContentType: helper.MergePatchContentType, Patch: []byte(`{"id":7,"stock_qty":5}`)},
+			updated: func(product domain.Product) bool {
+				return product.ProductID == 1 && product.StockQty == 5
+			},
+		},
+		{
+			name:  "JSON Patch",
+			input: web.PatchRequest{Id: 1, ContentType: helper.JSONPatchContentType, Patch: []byte(`[{"op":"test","path":"/sku","value":"test"},{"op":"replace","path":"/stock_qty","value":9},{"op":"add","path":"/barcodes","value":[{"code":"8991234567891","pack_qty":1}]}]`)},
+			updated: func(product domain.Product) bool {
+				return product.StockQty == 9 && len(product.Barcodes) == 1 && product.Barcodes[0].Code == "8991234567891"
+			},
+		},
+		{
+			name:    "JSON Patch Failed Test",
+			input:   web.PatchRequest{Id: 1, ContentType: helper.JSONPatchContentType, Patch: []byte(`[{"op":"test","path":"/sku","value":"other"},{"op":"replace","path":"/stock_qty","value":9}]`)},
+			expects: exception.NewBadRequestError("Invalid patch: operation 0 (test /sku): test failed"),
+		},
+		{
+			name:    "Stale If-Match Version",
+			input:   web.PatchRequest{Id: 1, ContentType: helper.MergePatchContentType, Patch: []byte(`{"name":"Patched"}`), Version: 1},
+			expects: exception.NewPreconditionFailedError("Product has been modified by another request"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockProductRepo := mocks.NewMockProductRepository(ctrl)
+			mockProductRepo.EXPECT().FindById(gomock.Any(), uint64(1)).Return(stored, nil).AnyTimes()
+			if tt.updated != nil {
+				mockProductRepo.EXPECT().Update(gomock.Any(), gomock.Cond(tt.updated)).
+					DoAndReturn(func(_ context.Context, product domain.Product) (domain.Product, error) {
+						product.Version++
+						return product, nil
+					})
+			}
+
+			service := NewProductService(mockProductRepo, discardOutbox(ctrl), passthroughTransactor(ctrl), validator.New())
+			response, err := service.Patch(context.Background(), tt.input)
+
+			if tt.expects != nil {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expects.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, uint64(3), response.Version)
+			}
+		})
+	}
+}
+
+func TestRestoreProduct(t *testing.T) {
+	tests := []struct {
+		name    string
+		mock    func(mockProductRepo *mocks.MockProductRepository)
+		expects error
+	}{
+		{
+			name: "Success",
+			mock: func(mockProductRepo *mocks.MockProductRepository) {
+				mockProductRepo.EXPECT().Restore(gomock.Any(), uint64(1)).Return(nil)
+				mockProductRepo.EXPECT().FindById(gomock.Any(), uint64(1)).
+					Return(domain.Product{ProductID: 1, Name: "Test", Price: money.FromInt(1, "IDR"), Version: 3}, nil)
+			},
+		},
+		{
+			name: "Not Deleted",
+			mock: func(mockProductRepo *mocks.MockProductRepository) {
+				mockProductRepo.EXPECT().Restore(gomock.Any(), uint64(1)).Return(fmt.Errorf("deleted product is not found: %w", gorm.ErrRecordNotFound))
+			},
+			expects: exception.NewNotFoundError("Deleted product not found"),
+		},
+		{
+			name: "Category Deleted",
+			mock: func(mockProductRepo *mocks.MockProductRepository) {
+				mockProductRepo.EXPECT().Restore(gomock.Any(), uint64(1)).Return(fmt.Errorf("category of the product is deleted: %w", repository.ErrParentDeleted))
+			},
+			expects: exception.NewBadRequestError("Category of the product is deleted, restore it first"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockProductRepo := mocks.NewMockProductRepository(ctrl)
+			tt.mock(mockProductRepo)
+
+			service := NewProductService(mockProductRepo, discardOutbox(ctrl), passthroughTransactor(ctrl), validator.New())
+			response, err := service.Restore(context.Background(), 1)
+
+			if tt.expects != nil {
+				assert.Equal(t, tt.expects, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, uint64(3), response.Version)
+				assert.Nil(t, response.DeletedAt)
+			}
+		})
+	}
+}
+
 func TestFindAllProducts(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -191,9 +419,9 @@ func TestFindAllProducts(t *testing.T) {
 		{
 			name: "Success",
 			mock: func(mockProductRepo *mocks.MockProductRepository) {
-				mockProductRepo.EXPECT().FindAll(gomock.Any()).Return([]domain.Product{{ProductID: 1, Name: "Test", Description: "Test", Price: 1, StockQty: 1, CategoryId: 1, SKU: "test", TaxRate: 1.0}}, nil)
+				mockProductRepo.EXPECT().FindAll(gomock.Any()).Return([]domain.Product{{ProductID: 1, Name: "Test", Description: "Test", Price: money.FromInt(1, "IDR"), StockQty: 1, CategoryId: 1, SKU: "test", TaxRate: money.AmountFromInt(1)}}, nil)
 			},
-			expects: []web.ProductResponse{{Id: 1, Name: "Test", Description: "Test", Price: 1, StockQty: 1, CategoryID: 1, SKU: "test", TaxRate: 1.0}},
+			expects: []web.ProductResponse{{Id: 1, Name: "Test", Description: "Test", Price: money.FromInt(1, "IDR"), StockQty: 1, CategoryID: 1, SKU: "test", TaxRate: money.AmountFromInt(1)}},
 			err:     nil,
 		},
 		{
@@ -213,7 +441,7 @@ func TestFindAllProducts(t *testing.T) {
 			mockProductRepo := mocks.NewMockProductRepository(ctrl)
 			tt.mock(mockProductRepo)
 
-			service := NewProductService(mockProductRepo, validator.New())
+			service := NewProductService(mockProductRepo, discardOutbox(ctrl), passthroughTransactor(ctrl), validator.New())
 			result, err := service.FindAll(context.Background())
 			assert.Equal(t, tt.expects, result)
 			assert.Equal(t, tt.err, err)
@@ -232,10 +460,10 @@ func TestFindByIdProduct(t *testing.T) {
 		{
 			name: "Success",
 			mock: func(mockProductRepo *mocks.MockProductRepository) {
-				mockProductRepo.EXPECT().FindById(gomock.Any(), uint64(1)).Return(domain.Product{ProductID: 1, Name: "Test", Description: "Test", Price: 1, StockQty: 1, CategoryId: 1, SKU: "test", TaxRate: 1.0}, nil)
+				mockProductRepo.EXPECT().FindById(gomock.Any(), uint64(1)).Return(domain.Product{ProductID: 1, Name: "Test", Description: "Test", Price: money.FromInt(1, "IDR"), StockQty: 1, CategoryId: 1, SKU: "test", TaxRate: money.AmountFromInt(1)}, nil)
 			},
 			input:   1,
-			expects: web.ProductResponse{Id: 1, Name: "Test", Description: "Test", Price: 1, StockQty: 1, CategoryID: 1, SKU: "test", TaxRate: 1.0},
+			expects: web.ProductResponse{Id: 1, Name: "Test", Description: "Test", Price: money.FromInt(1, "IDR"), StockQty: 1, CategoryID: 1, SKU: "test", TaxRate: money.AmountFromInt(1)},
 			err:     nil,
 		},
 		{
@@ -256,10 +484,115 @@ func TestFindByIdProduct(t *testing.T) {
 			mockProductRepo := mocks.NewMockProductRepository(ctrl)
 			tt.mock(mockProductRepo)
 
-			service := NewProductService(mockProductRepo, validator.New())
+			service := NewProductService(mockProductRepo, discardOutbox(ctrl), passthroughTransactor(ctrl), validator.New())
 			result, err := service.FindById(context.Background(), tt.input)
 			assert.Equal(t, tt.expects, result)
 			assert.Equal(t, tt.err, err)
 		})
 	}
 }
+
+func TestLookupProduct(t *testing.T) {
+	tests := []struct {
+		name    string
+		mock    func(mockProductRepo *mocks.MockProductRepository)
+		input   string
+		expects web.ProductLookupResponse
+		err     error
+	}{
+		{
+			name: "Success EAN-13 Case Pack",
+			mock: func(mockProductRepo *mocks.MockProductRepository) {
+				mockProductRepo.EXPECT().FindByBarcode(gomock.Any(), "4006381333931").Return(domain.ProductBarcode{Code: "4006381333931", PackQty: 12, ProductId: 1, Product: domain.Product{ProductID: 1, Name: "Test", Description: "Test", Price: money.FromInt(1, "IDR"), StockQty: 1, CategoryId: 1, SKU: "test", TaxRate: money.AmountFromInt(1)}}, nil)
+			},
+			input:   "4006381333931",
+			expects: web.ProductLookupResponse{Code: "4006381333931", PackQty: 12, Product: web.ProductResponse{Id: 1, Name: "Test", Description: "Test", Price: money.FromInt(1, "IDR"), StockQty: 1, CategoryID: 1, SKU: "test", TaxRate: money.AmountFromInt(1)}},
+			err:     nil,
+		},
+		{
+			name: "Success UPC-A as EAN-13",
+			mock: func(mockProductRepo *mocks.MockProductRepository) {
+				mockProductRepo.EXPECT().FindByBarcode(gomock.Any(), "0036000291452").Return(domain.ProductBarcode{Code: "0036000291452", PackQty: 1, ProductId: 1, Product: domain.Product{ProductID: 1, Name: "Test"}}, nil)
+			},
+			input:   "036000291452",
+			expects: web.ProductLookupResponse{Code: "0036000291452", PackQty: 1, Product: web.ProductResponse{Id: 1, Name: "Test"}},
+			err:     nil,
+		},
+		{
+			name: "Success EAN-8",
+			mock: func(mockProductRepo *mocks.MockProductRepository) {
+				mockProductRepo.EXPECT().FindByBarcode(gomock.Any(), "96385074").Return(domain.ProductBarcode{Code: "96385074", PackQty: 1, ProductId: 1, Product: domain.Product{ProductID: 1, Name: "Test"}}, nil)
+			},
+			input:   "96385074",
+			expects: web.ProductLookupResponse{Code: "96385074", PackQty: 1, Product: web.ProductResponse{Id: 1, Name: "Test"}},
+			err:     nil,
+		},
+		{
+			name:    "Invalid Check Digit",
+			mock:    func(mockProductRepo *mocks.MockProductRepository) {},
+			input:   "4006381333932",
+			expects: web.ProductLookupResponse{},
+			err:     exception.NewBadRequestError("Invalid barcode 4006381333932"),
+		},
+		{
+			name:    "Invalid Length",
+			mock:    func(mockProductRepo *mocks.MockProductRepository) {},
+			input:   "12345",
+			expects: web.ProductLookupResponse{},
+			err:     exception.NewBadRequestError("Invalid barcode 12345"),
+		},
+		{
+			name: "Not Found",
+			mock: func(mockProductRepo *mocks.MockProductRepository) {
+				mockProductRepo.EXPECT().FindByBarcode(gomock.Any(), "96385074").Return(domain.ProductBarcode{}, gorm.ErrRecordNotFound)
+			},
+			input:   "96385074",
+			expects: web.ProductLookupResponse{},
+			err:     exception.NewNotFoundError("Product not found"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockProductRepo := mocks.NewMockProductRepository(ctrl)
+			tt.mock(mockProductRepo)
+
+			service := NewProductService(mockProductRepo, discardOutbox(ctrl), passthroughTransactor(ctrl), validator.New())
+			result, err := service.Lookup(context.Background(), tt.input)
+			assert.Equal(t, tt.expects, result)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestCreateProductPublishesEventInTransaction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mocks.NewMockProductRepository(ctrl)
+	mockOutbox := mocks.NewMockOutboxRepository(ctrl)
+	mockTransactor := mocks.NewMockTransactor(ctrl)
+
+	type txMarker struct{}
+	mockTransactor.EXPECT().Transaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(context.WithValue(ctx, txMarker{}, true))
+		})
+	mockRepo.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, product domain.Product) (domain.Product, error) {
+		assert.Equal(t, true, ctx.Value(txMarker{}))
+		product.ProductID = 5
+		return product, nil
+	})
+	mockOutbox.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, events []domain.OutboxEvent) error {
+		assert.Equal(t, true, ctx.Value(txMarker{}))
+		assert.Len(t, events, 1)
+		assert.Equal(t, domain.ProductCreatedEvent, events[0].Type)
+		assert.Equal(t, uint64(5), events[0].AggregateId)
+		return nil
+	})
+
+	service := NewProductService(mockRepo, mockOutbox, mockTransactor, validator.New())
+	_, err := service.Create(context.Background(), web.ProductCreateRequest{Name: "Test", Description: "Test", Price: money.FromInt(1, "IDR"), StockQty: 1, CategoryID: 1, SKU: "test", TaxRate: money.AmountFromInt(1)})
+	assert.NoError(t, err)
+}
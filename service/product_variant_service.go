@@ -0,0 +1,14 @@
+package service
+
+import (
+	"context"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+)
+
+type ProductVariantService interface {
+	Create(ctx context.Context, request web.ProductVariantCreateRequest) (web.ProductVariantResponse, error)
+	Update(ctx context.Context, request web.ProductVariantUpdateRequest) (web.ProductVariantResponse, error)
+	Delete(ctx context.Context, productId uint64, variantId uint64) error
+	FindAll(ctx context.Context, productId uint64) ([]web.ProductVariantResponse, error)
+	Generate(ctx context.Context, request web.ProductVariantGenerateRequest) ([]web.ProductVariantResponse, error)
+}
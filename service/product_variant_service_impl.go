@@ -0,0 +1,371 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/helper"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/money"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/repository"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+	"strings"
+)
+
+// maxGeneratedVariants caps the cartesian product of option values
+const maxGeneratedVariants = 200
+
+// maxVariantSKULength is the size of the variant_sku column
+const maxVariantSKULength = 100
+
+type ProductVariantServiceImpl struct {
+	ProductVariantRepository repository.ProductVariantRepository
+	ProductRepository        repository.ProductRepository
+	OutboxRepository         repository.OutboxRepository
+	Transactor               repository.Transactor
+	Validate                 *validator.Validate
+}
+
+func NewProductVariantService(productVariantRepository repository.ProductVariantRepository, productRepository repository.ProductRepository, outboxRepository repository.OutboxRepository, transactor repository.Transactor, validate *validator.Validate) ProductVariantService {
+	return &ProductVariantServiceImpl{
+		ProductVariantRepository: productVariantRepository,
+		ProductRepository:        productRepository,
+		OutboxRepository:         outboxRepository,
+		Transactor:               transactor,
+		Validate:                 validate,
+	}
+}
+
+// Create Variant
+func (service *ProductVariantServiceImpl) Create(ctx context.Context, request web.ProductVariantCreateRequest) (web.ProductVariantResponse, error) {
+	ctx, span := startSpan(ctx, "ProductVariantService.Create", idAttribute("product", request.ProductId))
+	defer span.End()
+
+	if err := service.Validate.Struct(request); err != nil {
+		return web.ProductVariantResponse{}, err
+	}
+
+	product, err := service.findProduct(ctx, request.ProductId)
+	if err != nil {
+		return web.ProductVariantResponse{}, err
+	}
+
+	optionKey, err := variantOptionKey(product.Options, request.Options)
+	if err != nil {
+		return web.ProductVariantResponse{}, err
+	}
+
+	priceOverride, err := variantPriceOverride(product, request.PriceOverride)
+	if err != nil {
+		return web.ProductVariantResponse{}, err
+	}
+
+	variant := domain.ProductVariant{
+		ProductId:     product.ProductID,
+		OptionKey:     optionKey,
+		Options:       request.Options,
+		SKU:           request.SKU,
+		PriceOverride: priceOverride,
+		StockQty:      request.StockQty,
+	}
+	var savedVariant domain.ProductVariant
+	err = service.touchProduct(ctx, product.ProductID, func(ctx context.Context) (events []domain.OutboxEvent, err error) {
+		savedVariant, err = service.ProductVariantRepository.Save(ctx, variant)
+		if err != nil {
+			return nil, err
+		}
+		return variantEvents(nil, []domain.ProductVariant{savedVariant})
+	})
+	if err != nil {
+		return web.ProductVariantResponse{}, err
+	}
+
+	return helper.ToProductVariantResponse(product, savedVariant), nil
+}
+
+// Update Variant
+func (service *ProductVariantServiceImpl) Update(ctx context.Context, request web.ProductVariantUpdateRequest) (web.ProductVariantResponse, error) {
+	ctx, span := startSpan(ctx, "ProductVariantService.Update", idAttribute("product", request.ProductId), idAttribute("variant", request.Id))
+	defer span.End()
+
+	if err := service.Validate.Struct(request); err != nil {
+		return web.ProductVariantResponse{}, err
+	}
+
+	product, err := service.findProduct(ctx, request.ProductId)
+	if err != nil {
+		return web.ProductVariantResponse{}, err
+	}
+
+	variant, err := service.findVariant(ctx, request.ProductId, request.Id)
+	if err != nil {
+		return web.ProductVariantResponse{}, err
+	}
+
+	priceOverride, err := variantPriceOverride(product, request.PriceOverride)
+	if err != nil {
+		return web.ProductVariantResponse{}, err
+	}
+
+	previous := variant
+	variant.SKU = request.SKU
+	variant.PriceOverride = priceOverride
+	variant.StockQty = request.StockQty
+	var updatedVariant domain.ProductVariant
+	err = service.touchProduct(ctx, product.ProductID, func(ctx context.Context) (events []domain.OutboxEvent, err error) {
+		updatedVariant, err = service.ProductVariantRepository.Update(ctx, variant)
+		if err != nil {
+			return nil, err
+		}
+		return variantEvents([]domain.ProductVariant{previous}, []domain.ProductVariant{updatedVariant})
+	})
+	if err != nil {
+		return web.ProductVariantResponse{}, err
+	}
+
+	return helper.ToProductVariantResponse(product, updatedVariant), nil
+}
+
+// Delete Variant
+func (service *ProductVariantServiceImpl) Delete(ctx context.Context, productId uint64, variantId uint64) error {
+	ctx, span := startSpan(ctx, "ProductVariantService.Delete", idAttribute("product", productId), idAttribute("variant", variantId))
+	defer span.End()
+
+	variant, err := service.findVariant(ctx, productId, variantId)
+	if err != nil {
+		return err
+	}
+
+	return service.touchProduct(ctx, productId, func(ctx context.Context) ([]domain.OutboxEvent, error) {
+		return nil, service.ProductVariantRepository.Delete(ctx, variant)
+	})
+}
+
+// Find All Variants of a Product
+func (service *ProductVariantServiceImpl) FindAll(ctx context.Context, productId uint64) ([]web.ProductVariantResponse, error) {
+	ctx, span := startSpan(ctx, "ProductVariantService.FindAll", idAttribute("product", productId))
+	defer span.End()
+
+	product, err := service.findProduct(ctx, productId)
+	if err != nil {
+		return nil, err
+	}
+
+	variants, err := service.ProductVariantRepository.FindByProductId(ctx, productId)
+	if err != nil {
+		return nil, err
+	}
+
+	span.SetAttributes(countAttribute(len(variants)))
+	return helper.ToProductVariantResponses(product, variants), nil
+}
+
+// Generate Variants from option dimensions, keeping existing variants whose combination still exists
+func (service *ProductVariantServiceImpl) Generate(ctx context.Context, request web.ProductVariantGenerateRequest) ([]web.ProductVariantResponse, error) {
+	ctx, span := startSpan(ctx, "ProductVariantService.Generate", idAttribute("product", request.ProductId))
+	defer span.End()
+
+	if err := service.Validate.Struct(request); err != nil {
+		return nil, err
+	}
+
+	product, err := service.findProduct(ctx, request.ProductId)
+	if err != nil {
+		return nil, err
+	}
+
+	options := make([]domain.ProductOption, 0, len(request.Options))
+	combinations := 1
+	seen := make(map[string]bool)
+	for i, optionRequest := range request.Options {
+		if seen[optionRequest.Name] {
+			return nil, exception.NewBadRequestError("Duplicate option " + optionRequest.Name)
+		}
+		seen[optionRequest.Name] = true
+
+		combinations *= len(optionRequest.Values)
+		if combinations > maxGeneratedVariants {
+			return nil, exception.NewBadRequestError("Too many variant combinations")
+		}
+
+		options = append(options, domain.ProductOption{
+			Name:     optionRequest.Name,
+			Values:   optionRequest.Values,
+			Position: i,
+		})
+	}
+
+	existingVariants, err := service.ProductVariantRepository.FindByProductId(ctx, product.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	existingByKey := make(map[string]domain.ProductVariant)
+	for _, variant := range existingVariants {
+		existingByKey[variant.OptionKey] = variant
+	}
+
+	var variants []domain.ProductVariant
+	for _, values := range optionCombinations(options) {
+		optionKey, err := variantOptionKey(options, values)
+		if err != nil {
+			return nil, err
+		}
+
+		if variant, ok := existingByKey[optionKey]; ok {
+			variants = append(variants, variant)
+			continue
+		}
+
+		sku := variantSKU(product.SKU, options, values)
+		if len(sku) > maxVariantSKULength {
+			return nil, exception.NewBadRequestError(fmt.Sprintf("Generated variant SKU %s is longer than %d characters", sku, maxVariantSKULength))
+		}
+
+		variants = append(variants, domain.ProductVariant{
+			ProductId: product.ProductID,
+			OptionKey: optionKey,
+			Options:   values,
+			SKU:       sku,
+		})
+	}
+
+	var savedVariants []domain.ProductVariant
+	err = service.touchProduct(ctx, product.ProductID, func(ctx context.Context) (events []domain.OutboxEvent, err error) {
+		savedVariants, err = service.ProductVariantRepository.ReplaceOptions(ctx, product.ProductID, options, variants)
+		if err != nil {
+			return nil, err
+		}
+		return variantEvents(existingVariants, savedVariants)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	span.SetAttributes(countAttribute(len(savedVariants)))
+	return helper.ToProductVariantResponses(product, savedVariants), nil
+}
+
+// touchProduct runs a variant or option write, saves the domain events it returns and bumps the
+// parent product version in one transaction, so the product ETag changes whenever its variants do
+func (service *ProductVariantServiceImpl) touchProduct(ctx context.Context, productId uint64, write func(ctx context.Context) ([]domain.OutboxEvent, error)) error {
+	err := service.Transactor.Transaction(ctx, func(ctx context.Context) error {
+		events, err := write(ctx)
+		if err != nil {
+			return err
+		}
+		if err := service.ProductRepository.Touch(ctx, productId); err != nil {
+			return err
+		}
+		return service.OutboxRepository.Save(ctx, events)
+	})
+	if errors.Is(err, repository.ErrDuplicateVariant) {
+		return exception.NewBadRequestError("Variant SKU or option combination is already used")
+	}
+	return err
+}
+
+func (service *ProductVariantServiceImpl) findProduct(ctx context.Context, productId uint64) (domain.Product, error) {
+	product, err := service.ProductRepository.FindById(ctx, productId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.Product{}, exception.NewNotFoundError("Product not found")
+	} else if err != nil {
+		return domain.Product{}, err
+	}
+	return product, nil
+}
+
+func (service *ProductVariantServiceImpl) findVariant(ctx context.Context, productId uint64, variantId uint64) (domain.ProductVariant, error) {
+	variant, err := service.ProductVariantRepository.FindById(ctx, variantId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.ProductVariant{}, exception.NewNotFoundError("Variant not found")
+	} else if err != nil {
+		return domain.ProductVariant{}, err
+	}
+
+	// A variant is only reachable through its own parent product
+	if variant.ProductId != productId {
+		return domain.ProductVariant{}, exception.NewNotFoundError("Variant not found")
+	}
+	return variant, nil
+}
+
+// variantOptionKey builds a canonical key such as "Size=M;Color=Red" and checks
+// that every option dimension of the product has exactly one allowed value
+func variantOptionKey(options []domain.ProductOption, values map[string]string) (string, error) {
+	if len(options) == 0 {
+		return "", exception.NewBadRequestError("Product has no variant options")
+	}
+	if len(values) != len(options) {
+		return "", exception.NewBadRequestError("Variant must have a value for every product option")
+	}
+
+	parts := make([]string, 0, len(options))
+	for _, option := range options {
+		value, ok := values[option.Name]
+		if !ok {
+			return "", exception.NewBadRequestError("Missing value for option " + option.Name)
+		}
+
+		allowed := false
+		for _, optionValue := range option.Values {
+			if optionValue == value {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return "", exception.NewBadRequestError("Invalid value " + value + " for option " + option.Name)
+		}
+
+		parts = append(parts, option.Name+"="+value)
+	}
+	return strings.Join(parts, ";"), nil
+}
+
+// variantPriceOverride checks that an override is in the parent product currency and keeps only its amount
+func variantPriceOverride(product domain.Product, override *money.Money) (*money.Amount, error) {
+	if override == nil {
+		return nil, nil
+	}
+	if override.Currency != product.Price.Currency {
+		return nil, exception.NewBadRequestError("price_override must be in " + product.Price.Currency)
+	}
+	if override.IsNegative() {
+		return nil, exception.NewBadRequestError("price_override must not be negative")
+	}
+
+	amount := override.Amount
+	return &amount, nil
+}
+
+// optionCombinations returns the cartesian product of all option values
+func optionCombinations(options []domain.ProductOption) []map[string]string {
+	combinations := []map[string]string{{}}
+	for _, option := range options {
+		var next []map[string]string
+		for _, combination := range combinations {
+			for _, value := range option.Values {
+				values := make(map[string]string, len(combination)+1)
+				for name, existing := range combination {
+					values[name] = existing
+				}
+				values[option.Name] = value
+				next = append(next, values)
+			}
+		}
+		combinations = next
+	}
+	return combinations
+}
+
+// variantSKU derives a variant SKU such as "TSHIRT-M-RED" from the parent SKU
+func variantSKU(parentSKU string, options []domain.ProductOption, values map[string]string) string {
+	parts := []string{parentSKU}
+	for _, option := range options {
+		parts = append(parts, strings.ToUpper(strings.ReplaceAll(values[option.Name], " ", "")))
+	}
+	return strings.Join(parts, "-")
+}
@@ -0,0 +1,299 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/money"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/repository"
+	"github.com/aronipurwanto/go-restful-api/repository/mocks"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+	"strings"
+	"testing"
+)
+
+func variantTestProduct() domain.Product {
+	return domain.Product{
+		ProductID: 1, Name: "T-Shirt", Price: money.FromInt(100, "IDR"), SKU: "TSHIRT",
+		Options: []domain.ProductOption{
+			{Name: "Size", Values: []string{"S", "M"}, Position: 0},
+			{Name: "Color", Values: []string{"Red"}, Position: 1},
+		},
+	}
+}
+
+func TestGenerateProductVariants(t *testing.T) {
+	price := money.FromInt(120, "IDR")
+
+	tests := []struct {
+		name    string
+		mock    func(mockVariantRepo *mocks.MockProductVariantRepository, mockProductRepo *mocks.MockProductRepository)
+		input   web.ProductVariantGenerateRequest
+		expects []web.ProductVariantResponse
+		err     error
+	}{
+		{
+			name: "Success keeps existing combination",
+			mock: func(mockVariantRepo *mocks.MockProductVariantRepository, mockProductRepo *mocks.MockProductRepository) {
+				mockProductRepo.EXPECT().FindById(gomock.Any(), uint64(1)).Return(domain.Product{ProductID: 1, Price: money.FromInt(100, "IDR"), SKU: "TSHIRT"}, nil)
+				mockVariantRepo.EXPECT().FindByProductId(gomock.Any(), uint64(1)).Return([]domain.ProductVariant{
+					{Id: 7, ProductId: 1, OptionKey: "Size=S;Color=Red", Options: map[string]string{"Size": "S", "Color": "Red"}, SKU: "CUSTOM", PriceOverride: &price.Amount, StockQty: 3},
+					{Id: 8, ProductId: 1, OptionKey: "Size=XL;Color=Red", Options: map[string]string{"Size": "XL", "Color": "Red"}, SKU: "TSHIRT-XL-RED"},
+				}, nil)
+				mockVariantRepo.EXPECT().ReplaceOptions(gomock.Any(), uint64(1),
+					[]domain.ProductOption{
+						{Name: "Size", Values: []string{"S", "M"}, Position: 0},
+						{Name: "Color", Values: []string{"Red"}, Position: 1},
+					},
+					[]domain.ProductVariant{
+						{Id: 7, ProductId: 1, OptionKey: "Size=S;Color=Red", Options: map[string]string{"Size": "S", "Color": "Red"}, SKU: "CUSTOM", PriceOverride: &price.Amount, StockQty: 3},
+						{ProductId: 1, OptionKey: "Size=M;Color=Red", Options: map[string]string{"Size": "M", "Color": "Red"}, SKU: "TSHIRT-M-RED"},
+					}).
+					Return([]domain.ProductVariant{
+						{Id: 7, ProductId: 1, OptionKey: "Size=S;Color=Red", Options: map[string]string{"Size": "S", "Color": "Red"}, SKU: "CUSTOM", PriceOverride: &price.Amount, StockQty: 3},
+						{Id: 9, ProductId: 1, OptionKey: "Size=M;Color=Red", Options: map[string]string{"Size": "M", "Color": "Red"}, SKU: "TSHIRT-M-RED"},
+					}, nil)
+				mockProductRepo.EXPECT().Touch(gomock.Any(), uint64(1)).Return(nil)
+			},
+			input: web.ProductVariantGenerateRequest{ProductId: 1, Options: []web.ProductOptionRequest{
+				{Name: "Size", Values: []string{"S", "M"}},
+				{Name: "Color", Values: []string{"Red"}},
+			}},
+			expects: []web.ProductVariantResponse{
+				{Id: 7, ProductId: 1, SKU: "CUSTOM", Options: map[string]string{"Size": "S", "Color": "Red"}, Price: money.FromInt(120, "IDR"), PriceOverride: &price, StockQty: 3},
+				{Id: 9, ProductId: 1, SKU: "TSHIRT-M-RED", Options: map[string]string{"Size": "M", "Color": "Red"}, Price: money.FromInt(100, "IDR")},
+			},
+			err: nil,
+		},
+		{
+			name: "Duplicate Option",
+			mock: func(mockVariantRepo *mocks.MockProductVariantRepository, mockProductRepo *mocks.MockProductRepository) {
+				mockProductRepo.EXPECT().FindById(gomock.Any(), uint64(1)).Return(domain.Product{ProductID: 1}, nil)
+			},
+			input: web.ProductVariantGenerateRequest{ProductId: 1, Options: []web.ProductOptionRequest{
+				{Name: "Size", Values: []string{"S"}},
+				{Name: "Size", Values: []string{"M"}},
+			}},
+			expects: nil,
+			err:     exception.NewBadRequestError("Duplicate option Size"),
+		},
+		{
+			name: "Product Not Found",
+			mock: func(mockVariantRepo *mocks.MockProductVariantRepository, mockProductRepo *mocks.MockProductRepository) {
+				mockProductRepo.EXPECT().FindById(gomock.Any(), uint64(1)).Return(domain.Product{}, gorm.ErrRecordNotFound)
+			},
+			input: web.ProductVariantGenerateRequest{ProductId: 1, Options: []web.ProductOptionRequest{
+				{Name: "Size", Values: []string{"S"}},
+			}},
+			expects: nil,
+			err:     exception.NewNotFoundError("Product not found"),
+		},
+		{
+			name: "SKU Already Used",
+			mock: func(mockVariantRepo *mocks.MockProductVariantRepository, mockProductRepo *mocks.MockProductRepository) {
+				mockProductRepo.EXPECT().FindById(gomock.Any(), uint64(1)).Return(domain.Product{ProductID: 1, SKU: "TSHIRT"}, nil)
+				mockVariantRepo.EXPECT().FindByProductId(gomock.Any(), uint64(1)).Return(nil, nil)
+				mockVariantRepo.EXPECT().ReplaceOptions(gomock.Any(), uint64(1), gomock.Any(), gomock.Any()).
+					Return(nil, fmt.Errorf("%w: Duplicate entry 'TSHIRT-M' for key 'product_variants.variant_sku'", repository.ErrDuplicateVariant))
+			},
+			input: web.ProductVariantGenerateRequest{ProductId: 1, Options: []web.ProductOptionRequest{
+				{Name: "Size", Values: []string{"M"}},
+			}},
+			expects: nil,
+			err:     exception.NewBadRequestError("Variant SKU or option combination is already used"),
+		},
+		{
+			name: "Generated SKU Too Long",
+			mock: func(mockVariantRepo *mocks.MockProductVariantRepository, mockProductRepo *mocks.MockProductRepository) {
+				mockProductRepo.EXPECT().FindById(gomock.Any(), uint64(1)).Return(domain.Product{ProductID: 1, SKU: strings.Repeat("X", 98)}, nil)
+				mockVariantRepo.EXPECT().FindByProductId(gomock.Any(), uint64(1)).Return(nil, nil)
+			},
+			input: web.ProductVariantGenerateRequest{ProductId: 1, Options: []web.ProductOptionRequest{
+				{Name: "Size", Values: []string{"XL"}},
+			}},
+			expects: nil,
+			err:     exception.NewBadRequestError("Generated variant SKU " + strings.Repeat("X", 98) + "-XL is longer than 100 characters"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockVariantRepo := mocks.NewMockProductVariantRepository(ctrl)
+			mockProductRepo := mocks.NewMockProductRepository(ctrl)
+			tt.mock(mockVariantRepo, mockProductRepo)
+
+			service := NewProductVariantService(mockVariantRepo, mockProductRepo, discardOutbox(ctrl), passthroughTransactor(ctrl), validator.New())
+			result, err := service.Generate(context.Background(), tt.input)
+			assert.Equal(t, tt.expects, result)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestCreateProductVariant(t *testing.T) {
+	tests := []struct {
+		name    string
+		mock    func(mockVariantRepo *mocks.MockProductVariantRepository, mockProductRepo *mocks.MockProductRepository)
+		input   web.ProductVariantCreateRequest
+		expects web.ProductVariantResponse
+		err     error
+	}{
+		{
+			name: "Success",
+			mock: func(mockVariantRepo *mocks.MockProductVariantRepository, mockProductRepo *mocks.MockProductRepository) {
+				mockProductRepo.EXPECT().FindById(gomock.Any(), uint64(1)).Return(variantTestProduct(), nil)
+				mockVariantRepo.EXPECT().Save(gomock.Any(), domain.ProductVariant{ProductId: 1, OptionKey: "Size=M;Color=Red", Options: map[string]string{"Size": "M", "Color": "Red"}, SKU: "TS-M-R", StockQty: 5}).
+					Return(domain.ProductVariant{Id: 1, ProductId: 1, OptionKey: "Size=M;Color=Red", Options: map[string]string{"Size": "M", "Color": "Red"}, SKU: "TS-M-R", StockQty: 5}, nil)
+				mockProductRepo.EXPECT().Touch(gomock.Any(), uint64(1)).Return(nil)
+			},
+			input:   web.ProductVariantCreateRequest{ProductId: 1, Options: map[string]string{"Size": "M", "Color": "Red"}, SKU: "TS-M-R", StockQty: 5},
+			expects: web.ProductVariantResponse{Id: 1, ProductId: 1, SKU: "TS-M-R", Options: map[string]string{"Size": "M", "Color": "Red"}, Price: money.FromInt(100, "IDR"), StockQty: 5},
+			err:     nil,
+		},
+		{
+			name: "Value Not Allowed",
+			mock: func(mockVariantRepo *mocks.MockProductVariantRepository, mockProductRepo *mocks.MockProductRepository) {
+				mockProductRepo.EXPECT().FindById(gomock.Any(), uint64(1)).Return(variantTestProduct(), nil)
+			},
+			input:   web.ProductVariantCreateRequest{ProductId: 1, Options: map[string]string{"Size": "XL", "Color": "Red"}, SKU: "TS-XL-R"},
+			expects: web.ProductVariantResponse{},
+			err:     exception.NewBadRequestError("Invalid value XL for option Size"),
+		},
+		{
+			name: "Missing Option",
+			mock: func(mockVariantRepo *mocks.MockProductVariantRepository, mockProductRepo *mocks.MockProductRepository) {
+				mockProductRepo.EXPECT().FindById(gomock.Any(), uint64(1)).Return(variantTestProduct(), nil)
+			},
+			input:   web.ProductVariantCreateRequest{ProductId: 1, Options: map[string]string{"Size": "M"}, SKU: "TS-M"},
+			expects: web.ProductVariantResponse{},
+			err:     exception.NewBadRequestError("Variant must have a value for every product option"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockVariantRepo := mocks.NewMockProductVariantRepository(ctrl)
+			mockProductRepo := mocks.NewMockProductRepository(ctrl)
+			tt.mock(mockVariantRepo, mockProductRepo)
+
+			service := NewProductVariantService(mockVariantRepo, mockProductRepo, discardOutbox(ctrl), passthroughTransactor(ctrl), validator.New())
+			result, err := service.Create(context.Background(), tt.input)
+			assert.Equal(t, tt.expects, result)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestUpdateProductVariant(t *testing.T) {
+	price := money.FromInt(90, "IDR")
+
+	tests := []struct {
+		name    string
+		mock    func(mockVariantRepo *mocks.MockProductVariantRepository, mockProductRepo *mocks.MockProductRepository)
+		input   web.ProductVariantUpdateRequest
+		expects web.ProductVariantResponse
+		err     error
+	}{
+		{
+			name: "Success",
+			mock: func(mockVariantRepo *mocks.MockProductVariantRepository, mockProductRepo *mocks.MockProductRepository) {
+				mockProductRepo.EXPECT().FindById(gomock.Any(), uint64(1)).Return(variantTestProduct(), nil)
+				mockVariantRepo.EXPECT().FindById(gomock.Any(), uint64(2)).Return(domain.ProductVariant{Id: 2, ProductId: 1, SKU: "OLD"}, nil)
+				mockVariantRepo.EXPECT().Update(gomock.Any(), domain.ProductVariant{Id: 2, ProductId: 1, SKU: "NEW", PriceOverride: &price.Amount, StockQty: 4}).
+					Return(domain.ProductVariant{Id: 2, ProductId: 1, SKU: "NEW", PriceOverride: &price.Amount, StockQty: 4}, nil)
+				mockProductRepo.EXPECT().Touch(gomock.Any(), uint64(1)).Return(nil)
+			},
+			input:   web.ProductVariantUpdateRequest{Id: 2, ProductId: 1, SKU: "NEW", PriceOverride: &price, StockQty: 4},
+			expects: web.ProductVariantResponse{Id: 2, ProductId: 1, SKU: "NEW", Price: money.FromInt(90, "IDR"), PriceOverride: &price, StockQty: 4},
+			err:     nil,
+		},
+		{
+			name: "Override In Another Currency",
+			mock: func(mockVariantRepo *mocks.MockProductVariantRepository, mockProductRepo *mocks.MockProductRepository) {
+				mockProductRepo.EXPECT().FindById(gomock.Any(), uint64(1)).Return(variantTestProduct(), nil)
+				mockVariantRepo.EXPECT().FindById(gomock.Any(), uint64(2)).Return(domain.ProductVariant{Id: 2, ProductId: 1, SKU: "OLD"}, nil)
+			},
+			input:   web.ProductVariantUpdateRequest{Id: 2, ProductId: 1, SKU: "NEW", PriceOverride: &money.Money{Amount: money.AmountFromInt(5), Currency: "USD"}},
+			expects: web.ProductVariantResponse{},
+			err:     exception.NewBadRequestError("price_override must be in IDR"),
+		},
+		{
+			name: "Variant Of Another Product",
+			mock: func(mockVariantRepo *mocks.MockProductVariantRepository, mockProductRepo *mocks.MockProductRepository) {
+				mockProductRepo.EXPECT().FindById(gomock.Any(), uint64(1)).Return(variantTestProduct(), nil)
+				mockVariantRepo.EXPECT().FindById(gomock.Any(), uint64(2)).Return(domain.ProductVariant{Id: 2, ProductId: 5}, nil)
+			},
+			input:   web.ProductVariantUpdateRequest{Id: 2, ProductId: 1, SKU: "NEW"},
+			expects: web.ProductVariantResponse{},
+			err:     exception.NewNotFoundError("Variant not found"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockVariantRepo := mocks.NewMockProductVariantRepository(ctrl)
+			mockProductRepo := mocks.NewMockProductRepository(ctrl)
+			tt.mock(mockVariantRepo, mockProductRepo)
+
+			service := NewProductVariantService(mockVariantRepo, mockProductRepo, discardOutbox(ctrl), passthroughTransactor(ctrl), validator.New())
+			result, err := service.Update(context.Background(), tt.input)
+			assert.Equal(t, tt.expects, result)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestDeleteProductVariant(t *testing.T) {
+	tests := []struct {
+		name      string
+		mock      func(mockVariantRepo *mocks.MockProductVariantRepository, mockProductRepo *mocks.MockProductRepository)
+		expectErr bool
+	}{
+		{
+			name: "success",
+			mock: func(mockVariantRepo *mocks.MockProductVariantRepository, mockProductRepo *mocks.MockProductRepository) {
+				mockVariantRepo.EXPECT().FindById(gomock.Any(), uint64(2)).Return(domain.ProductVariant{Id: 2, ProductId: 1}, nil)
+				mockVariantRepo.EXPECT().Delete(gomock.Any(), domain.ProductVariant{Id: 2, ProductId: 1}).Return(nil)
+				mockProductRepo.EXPECT().Touch(gomock.Any(), uint64(1)).Return(nil)
+			},
+			expectErr: false,
+		},
+		{
+			name: "not found",
+			mock: func(mockVariantRepo *mocks.MockProductVariantRepository, mockProductRepo *mocks.MockProductRepository) {
+				mockVariantRepo.EXPECT().FindById(gomock.Any(), uint64(2)).Return(domain.ProductVariant{}, errors.New("not found"))
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockVariantRepo := mocks.NewMockProductVariantRepository(ctrl)
+			mockProductRepo := mocks.NewMockProductRepository(ctrl)
+			tt.mock(mockVariantRepo, mockProductRepo)
+
+			service := NewProductVariantService(mockVariantRepo, mockProductRepo, discardOutbox(ctrl), passthroughTransactor(ctrl), validator.New())
+			err := service.Delete(context.Background(), 1, 2)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
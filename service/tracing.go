@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+	"github.com/aronipurwanto/go-restful-api/helper"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/aronipurwanto/go-restful-api/service")
+
+// startSpan starts the span of a service method; it is a child of the request span carried in ctx
+func startSpan(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer.Start(helper.ContextWithRequestSpan(ctx), name, trace.WithAttributes(attributes...))
+}
+
+// idAttribute labels a span with the ID of the entity a method works on, e.g. product.id
+func idAttribute(entity string, id uint64) attribute.KeyValue {
+	return attribute.Int64(entity+".id", int64(id))
+}
+
+// countAttribute labels a span with the number of rows a method returned or changed
+func countAttribute(count int) attribute.KeyValue {
+	return attribute.Int("result.count", count)
+}
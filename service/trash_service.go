@@ -0,0 +1,10 @@
+package service
+
+import (
+	"context"
+	"time"
+)
+
+type TrashService interface {
+	Purge(ctx context.Context, now time.Time) (int64, error)
+}
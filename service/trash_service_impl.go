@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"github.com/aronipurwanto/go-restful-api/repository"
+	"time"
+)
+
+type TrashServiceImpl struct {
+	ProductRepository  repository.ProductRepository
+	CategoryRepository repository.CategoryRepository
+	CustomerRepository repository.CustomerRepository
+	EmployeeRepository repository.EmployeeRepository
+	Retention          time.Duration
+}
+
+func NewTrashService(productRepository repository.ProductRepository, categoryRepository repository.CategoryRepository, customerRepository repository.CustomerRepository, employeeRepository repository.EmployeeRepository, retention time.Duration) TrashService {
+	return &TrashServiceImpl{
+		ProductRepository:  productRepository,
+		CategoryRepository: categoryRepository,
+		CustomerRepository: customerRepository,
+		EmployeeRepository: employeeRepository,
+		Retention:          retention,
+	}
+}
+
+// Purge permanently removes every record that has been soft-deleted for longer than the retention
+// period and returns how many were removed. Products go first so their categories can follow.
+func (service *TrashServiceImpl) Purge(ctx context.Context, now time.Time) (int64, error) {
+	ctx, span := startSpan(ctx, "TrashService.Purge")
+	defer span.End()
+
+	before := now.Add(-service.Retention)
+	purges := []func(ctx context.Context, before time.Time) (int64, error){
+		service.ProductRepository.Purge,
+		service.CategoryRepository.Purge,
+		service.CustomerRepository.Purge,
+		service.EmployeeRepository.Purge,
+	}
+
+	var purged int64
+	for _, purge := range purges {
+		count, err := purge(ctx, before)
+		purged += count
+		if err != nil {
+			return purged, err
+		}
+	}
+	span.SetAttributes(countAttribute(int(purged)))
+	return purged, nil
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/aronipurwanto/go-restful-api/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func TestPurgeTrash(t *testing.T) {
+	now := time.Date(2025, 3, 31, 12, 0, 0, 0, time.UTC)
+	cutoff := now.Add(-30 * 24 * time.Hour)
+
+	tests := []struct {
+		name      string
+		mock      func(products *mocks.MockProductRepository, categories *mocks.MockCategoryRepository, customers *mocks.MockCustomerRepository, employees *mocks.MockEmployeeRepository)
+		expects   int64
+		expectErr bool
+	}{
+		{
+			name: "Products Before Categories",
+			mock: func(products *mocks.MockProductRepository, categories *mocks.MockCategoryRepository, customers *mocks.MockCustomerRepository, employees *mocks.MockEmployeeRepository) {
+				gomock.InOrder(
+					products.EXPECT().Purge(gomock.Any(), cutoff).Return(int64(3), nil),
+					categories.EXPECT().Purge(gomock.Any(), cutoff).Return(int64(1), nil),
+				)
+				customers.EXPECT().Purge(gomock.Any(), cutoff).Return(int64(0), nil)
+				employees.EXPECT().Purge(gomock.Any(), cutoff).Return(int64(2), nil)
+			},
+			expects: 6,
+		},
+		{
+			name: "Stops On Error",
+			mock: func(products *mocks.MockProductRepository, categories *mocks.MockCategoryRepository, customers *mocks.MockCustomerRepository, employees *mocks.MockEmployeeRepository) {
+				products.EXPECT().Purge(gomock.Any(), cutoff).Return(int64(3), nil)
+				categories.EXPECT().Purge(gomock.Any(), cutoff).Return(int64(0), errors.New("database error"))
+			},
+			expects:   3,
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			products := mocks.NewMockProductRepository(ctrl)
+			categories := mocks.NewMockCategoryRepository(ctrl)
+			customers := mocks.NewMockCustomerRepository(ctrl)
+			employees := mocks.NewMockEmployeeRepository(ctrl)
+			tt.mock(products, categories, customers, employees)
+
+			service := NewTrashService(products, categories, customers, employees, 30*24*time.Hour)
+			purged, err := service.Purge(context.Background(), now)
+
+			assert.Equal(t, tt.expects, purged)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/helper"
+	"github.com/aronipurwanto/go-restful-api/repository"
+	"slices"
+)
+
+// checkVersion rejects a write based on an outdated read; an expected version of 0 means unconditional
+// unless If-Match listed several versions, in which case current has to be one of them
+func checkVersion(ctx context.Context, entity string, expected uint64, current uint64) error {
+	matches := expected == 0 || expected == current
+	if versions := helper.IfMatchFromContext(ctx); versions != nil {
+		matches = slices.Contains(versions, current)
+	}
+	if !matches {
+		return exception.NewPreconditionFailedError(entity + " has been modified by another request")
+	}
+	return nil
+}
+
+// versionConflict reports a write that lost the race against another one the same way as checkVersion
+func versionConflict(entity string, err error) error {
+	if errors.Is(err, repository.ErrVersionConflict) {
+		return exception.NewPreconditionFailedError(entity + " has been modified by another request")
+	}
+	return err
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/aronipurwanto/go-restful-api/helper"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// webhookBatchSize is the number of events fanned out, and of deliveries attempted, per run
+	webhookBatchSize = 100
+	// webhookMaxBackoff caps the delay between two attempts of a delivery
+	webhookMaxBackoff = 6 * time.Hour
+	// webhookErrorLength is the size of the last_error column
+	webhookErrorLength = 1024
+)
+
+type WebhookDispatcherImpl struct {
+	OutboxRepository  repository.OutboxRepository
+	WebhookRepository repository.WebhookRepository
+	Transactor        repository.Transactor
+	Client            *http.Client
+	MaxAttempts       int
+	RetryBackoff      time.Duration
+}
+
+func NewWebhookDispatcher(outboxRepository repository.OutboxRepository, webhookRepository repository.WebhookRepository, transactor repository.Transactor, client *http.Client, maxAttempts int, retryBackoff time.Duration) WebhookDispatcher {
+	return &WebhookDispatcherImpl{
+		OutboxRepository:  outboxRepository,
+		WebhookRepository: webhookRepository,
+		Transactor:        transactor,
+		Client:            client,
+		MaxAttempts:       maxAttempts,
+		RetryBackoff:      retryBackoff,
+	}
+}
+
+// Dispatch queues a delivery of every new outbox event for each webhook subscribed to it, then
+// attempts every delivery that is due and returns how many succeeded. A failed delivery is retried
+// with exponential backoff and dead-lettered after MaxAttempts; receivers may see an event twice
+// and should deduplicate on its X-Event-Id.
+func (service *WebhookDispatcherImpl) Dispatch(ctx context.Context, now time.Time) (int, error) {
+	ctx, span := startSpan(ctx, "WebhookDispatcher.Dispatch")
+	defer span.End()
+
+	if err := service.fanOut(ctx, now); err != nil {
+		return 0, err
+	}
+	succeeded, err := service.deliver(ctx, now)
+	span.SetAttributes(countAttribute(succeeded))
+	return succeeded, err
+}
+
+// fanOut turns undispatched events into pending deliveries, one transaction per event
+func (service *WebhookDispatcherImpl) fanOut(ctx context.Context, now time.Time) error {
+	events, err := service.OutboxRepository.FindUndispatched(ctx, webhookBatchSize)
+	if err != nil || len(events) == 0 {
+		return err
+	}
+	webhooks, err := service.WebhookRepository.FindActive(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		var deliveries []domain.WebhookDelivery
+		for _, webhook := range webhooks {
+			if webhook.Subscribes(event.Type) {
+				deliveries = append(deliveries, domain.WebhookDelivery{
+					WebhookId:     webhook.Id,
+					EventId:       event.Id,
+					Status:        domain.DeliveryPending,
+					NextAttemptAt: now,
+				})
+			}
+		}
+
+		err := service.Transactor.Transaction(ctx, func(ctx context.Context) error {
+			if err := service.WebhookRepository.SaveDeliveries(ctx, deliveries); err != nil {
+				return err
+			}
+			return service.OutboxRepository.MarkDispatched(ctx, event.Id, now)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deliver attempts the due deliveries and records each outcome in the delivery log
+func (service *WebhookDispatcherImpl) deliver(ctx context.Context, now time.Time) (int, error) {
+	deliveries, err := service.WebhookRepository.FindDueDeliveries(ctx, now, webhookBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	succeeded := 0
+	for _, delivery := range deliveries {
+		statusCode, err := service.post(ctx, delivery, now)
+		if ctx.Err() != nil {
+			// shutting down; the attempt did not really happen
+			return succeeded, ctx.Err()
+		}
+
+		delivery.Attempts++
+		delivery.LastStatusCode = statusCode
+		if err == nil {
+			delivery.Status = domain.DeliverySucceeded
+			delivery.LastError = ""
+			delivery.DeliveredAt = &now
+			succeeded++
+		} else {
+			delivery.LastError = truncate(err.Error(), webhookErrorLength)
+			if delivery.Attempts >= service.MaxAttempts {
+				delivery.Status = domain.DeliveryDead
+			} else {
+				delivery.NextAttemptAt = now.Add(service.backoff(delivery.Attempts))
+			}
+		}
+
+		if err := service.WebhookRepository.UpdateDelivery(ctx, delivery); err != nil {
+			return succeeded, err
+		}
+	}
+	return succeeded, nil
+}
+
+// post sends one signed delivery and returns the status code the endpoint answered with
+func (service *WebhookDispatcherImpl) post(ctx context.Context, delivery domain.WebhookDelivery, now time.Time) (int, error) {
+	body, err := json.Marshal(helper.ToWebhookEvent(delivery.Event))
+	if err != nil {
+		return 0, err
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Webhook.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := now.Unix()
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(helper.WebhookEventIdHeader, strconv.FormatUint(delivery.EventId, 10))
+	request.Header.Set(helper.WebhookEventTypeHeader, delivery.Event.Type)
+	request.Header.Set(helper.WebhookDeliveryHeader, strconv.FormatUint(delivery.Id, 10))
+	request.Header.Set(helper.WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	request.Header.Set(helper.WebhookSignatureHeader, helper.SignWebhook(delivery.Webhook.Secret, timestamp, body))
+	// Receivers that trace can continue the dispatcher's trace from traceparent
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(request.Header))
+
+	response, err := service.Client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	// drain a little of the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 64<<10))
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return response.StatusCode, fmt.Errorf("endpoint answered %s", response.Status)
+	}
+	return response.StatusCode, nil
+}
+
+// backoff doubles the delay after every failed attempt, up to webhookMaxBackoff
+func (service *WebhookDispatcherImpl) backoff(attempts int) time.Duration {
+	delay := service.RetryBackoff
+	for i := 1; i < attempts && delay < webhookMaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, webhookMaxBackoff)
+}
+
+func truncate(value string, length int) string {
+	if len(value) <= length {
+		return value
+	}
+	return value[:length]
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/aronipurwanto/go-restful-api/helper"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/repository/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestDispatchFansOutToSubscribers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockOutbox := mocks.NewMockOutboxRepository(ctrl)
+	mockWebhooks := mocks.NewMockWebhookRepository(ctrl)
+
+	now := time.Now()
+	mockOutbox.EXPECT().FindUndispatched(gomock.Any(), webhookBatchSize).Return([]domain.OutboxEvent{
+		{Id: 10, Type: domain.PriceChangedEvent},
+		{Id: 11, Type: domain.CustomerUpdatedEvent},
+	}, nil)
+	mockWebhooks.EXPECT().FindActive(gomock.Any()).Return([]domain.Webhook{
+		{Id: 1, Events: "PriceChanged,StockChanged"},
+		{Id: 2},
+	}, nil)
+	mockWebhooks.EXPECT().SaveDeliveries(gomock.Any(), []domain.WebhookDelivery{
+		{WebhookId: 1, EventId: 10, Status: domain.DeliveryPending, NextAttemptAt: now},
+		{WebhookId: 2, EventId: 10, Status: domain.DeliveryPending, NextAttemptAt: now},
+	}).Return(nil)
+	mockOutbox.EXPECT().MarkDispatched(gomock.Any(), uint64(10), now).Return(nil)
+	mockWebhooks.EXPECT().SaveDeliveries(gomock.Any(), []domain.WebhookDelivery{
+		{WebhookId: 2, EventId: 11, Status: domain.DeliveryPending, NextAttemptAt: now},
+	}).Return(nil)
+	mockOutbox.EXPECT().MarkDispatched(gomock.Any(), uint64(11), now).Return(nil)
+	mockWebhooks.EXPECT().FindDueDeliveries(gomock.Any(), now, webhookBatchSize).Return(nil, nil)
+
+	dispatcher := NewWebhookDispatcher(mockOutbox, mockWebhooks, passthroughTransactor(ctrl), http.DefaultClient, 3, time.Second)
+	delivered, err := dispatcher.Dispatch(context.Background(), now)
+	assert.NoError(t, err)
+	assert.Zero(t, delivered)
+}
+
+func TestDispatchDeliversSignedEvents(t *testing.T) {
+	const secret = "0123456789abcdef"
+	now := time.Unix(1760000000, 0).UTC()
+	event := domain.OutboxEvent{Id: 10, Type: domain.StockChangedEvent, AggregateType: "product", AggregateId: 7, Payload: json.RawMessage(`{"product_id":7,"previous_stock_qty":5,"stock_qty":3}`), CreatedAt: now}
+
+	status := http.StatusOK
+	var received web.WebhookEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(helper.WebhookTimestampHeader), 10, 64)
+		assert.Equal(t, helper.SignWebhook(secret, timestamp, body), r.Header.Get(helper.WebhookSignatureHeader))
+		assert.Equal(t, "10", r.Header.Get(helper.WebhookEventIdHeader))
+		assert.Equal(t, domain.StockChangedEvent, r.Header.Get(helper.WebhookEventTypeHeader))
+		assert.NoError(t, json.Unmarshal(body, &received))
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		status    int
+		attempts  int
+		expect    func(t *testing.T, delivery domain.WebhookDelivery)
+		delivered int
+	}{
+		{
+			name:     "Succeeded",
+			status:   http.StatusNoContent,
+			attempts: 0,
+			expect: func(t *testing.T, delivery domain.WebhookDelivery) {
+				assert.Equal(t, domain.DeliverySucceeded, delivery.Status)
+				assert.Equal(t, 1, delivery.Attempts)
+				assert.Equal(t, http.StatusNoContent, delivery.LastStatusCode)
+				assert.Equal(t, &now, delivery.DeliveredAt)
+			},
+			delivered: 1,
+		},
+		{
+			name:     "Retried With Backoff",
+			status:   http.StatusInternalServerError,
+			attempts: 2,
+			expect: func(t *testing.T, delivery domain.WebhookDelivery) {
+				assert.Equal(t, domain.DeliveryPending, delivery.Status)
+				assert.Equal(t, 3, delivery.Attempts)
+				assert.Equal(t, http.StatusInternalServerError, delivery.LastStatusCode)
+				assert.Equal(t, "endpoint answered 500 Internal Server Error", delivery.LastError)
+				assert.Equal(t, now.Add(4*time.Second), delivery.NextAttemptAt)
+			},
+		},
+		{
+			name:     "Dead Lettered",
+			status:   http.StatusGone,
+			attempts: 4,
+			expect: func(t *testing.T, delivery domain.WebhookDelivery) {
+				assert.Equal(t, domain.DeliveryDead, delivery.Status)
+				assert.Equal(t, 5, delivery.Attempts)
+				assert.Nil(t, delivery.DeliveredAt)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockOutbox := mocks.NewMockOutboxRepository(ctrl)
+			mockWebhooks := mocks.NewMockWebhookRepository(ctrl)
+
+			status = tt.status
+			mockOutbox.EXPECT().FindUndispatched(gomock.Any(), webhookBatchSize).Return(nil, nil)
+			mockWebhooks.EXPECT().FindDueDeliveries(gomock.Any(), now, webhookBatchSize).Return([]domain.WebhookDelivery{{
+				Id:            3,
+				WebhookId:     1,
+				Webhook:       domain.Webhook{Id: 1, Url: server.URL, Secret: secret, Active: true},
+				EventId:       10,
+				Event:         event,
+				Status:        domain.DeliveryPending,
+				Attempts:      tt.attempts,
+				NextAttemptAt: now,
+			}}, nil)
+			mockWebhooks.EXPECT().UpdateDelivery(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, delivery domain.WebhookDelivery) error {
+				tt.expect(t, delivery)
+				return nil
+			})
+
+			dispatcher := NewWebhookDispatcher(mockOutbox, mockWebhooks, passthroughTransactor(ctrl), server.Client(), 5, time.Second)
+			delivered, err := dispatcher.Dispatch(context.Background(), now)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.delivered, delivered)
+			assert.Equal(t, web.WebhookEvent{Id: 10, Type: domain.StockChangedEvent, AggregateType: "product", AggregateId: 7, OccurredAt: now, Data: event.Payload}, received)
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package service
+
+import (
+	"context"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+)
+
+type WebhookService interface {
+	Create(ctx context.Context, request web.WebhookCreateRequest) (web.WebhookResponse, error)
+	Update(ctx context.Context, request web.WebhookUpdateRequest) (web.WebhookResponse, error)
+	Delete(ctx context.Context, webhookId uint64) error
+	FindById(ctx context.Context, webhookId uint64) (web.WebhookResponse, error)
+	FindAll(ctx context.Context) ([]web.WebhookResponse, error)
+	FindDeliveries(ctx context.Context, webhookId uint64) ([]web.WebhookDeliveryResponse, error)
+	Redeliver(ctx context.Context, webhookId uint64, deliveryId uint64) (web.WebhookDeliveryResponse, error)
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/helper"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/repository"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+	"strings"
+	"time"
+)
+
+// webhookDeliveryLogLimit is the number of most recent deliveries listed per webhook
+const webhookDeliveryLogLimit = 100
+
+type WebhookServiceImpl struct {
+	WebhookRepository repository.WebhookRepository
+	Validate          *validator.Validate
+}
+
+func NewWebhookService(webhookRepository repository.WebhookRepository, validate *validator.Validate) WebhookService {
+	return &WebhookServiceImpl{
+		WebhookRepository: webhookRepository,
+		Validate:          validate,
+	}
+}
+
+// Create Webhook; the response is the only one that carries its signing secret
+func (service *WebhookServiceImpl) Create(ctx context.Context, request web.WebhookCreateRequest) (web.WebhookResponse, error) {
+	ctx, span := startSpan(ctx, "WebhookService.Create")
+	defer span.End()
+
+	if err := service.Validate.Struct(request); err != nil {
+		return web.WebhookResponse{}, err
+	}
+
+	secret := request.Secret
+	if secret == "" {
+		generated, err := newWebhookSecret()
+		if err != nil {
+			return web.WebhookResponse{}, err
+		}
+		secret = generated
+	}
+
+	webhook := domain.Webhook{
+		Url:    request.Url,
+		Secret: secret,
+		Events: strings.Join(request.Events, ","),
+		Active: request.Active == nil || *request.Active,
+	}
+	savedWebhook, err := service.WebhookRepository.Save(ctx, webhook)
+	if err != nil {
+		return web.WebhookResponse{}, err
+	}
+
+	response := helper.ToWebhookResponse(savedWebhook)
+	response.Secret = savedWebhook.Secret
+	return response, nil
+}
+
+// Update Webhook; an empty secret keeps the current one and a missing active flag keeps its state
+func (service *WebhookServiceImpl) Update(ctx context.Context, request web.WebhookUpdateRequest) (web.WebhookResponse, error) {
+	ctx, span := startSpan(ctx, "WebhookService.Update", idAttribute("webhook", request.Id))
+	defer span.End()
+
+	if err := service.Validate.Struct(request); err != nil {
+		return web.WebhookResponse{}, err
+	}
+
+	webhook, err := service.findWebhook(ctx, request.Id)
+	if err != nil {
+		return web.WebhookResponse{}, err
+	}
+
+	webhook.Url = request.Url
+	webhook.Events = strings.Join(request.Events, ",")
+	if request.Secret != "" {
+		webhook.Secret = request.Secret
+	}
+	if request.Active != nil {
+		webhook.Active = *request.Active
+	}
+	updatedWebhook, err := service.WebhookRepository.Update(ctx, webhook)
+	if err != nil {
+		return web.WebhookResponse{}, err
+	}
+
+	return helper.ToWebhookResponse(updatedWebhook), nil
+}
+
+// Delete Webhook and its delivery log
+func (service *WebhookServiceImpl) Delete(ctx context.Context, webhookId uint64) error {
+	ctx, span := startSpan(ctx, "WebhookService.Delete", idAttribute("webhook", webhookId))
+	defer span.End()
+
+	webhook, err := service.findWebhook(ctx, webhookId)
+	if err != nil {
+		return err
+	}
+
+	return service.WebhookRepository.Delete(ctx, webhook)
+}
+
+// Find Webhook By ID
+func (service *WebhookServiceImpl) FindById(ctx context.Context, webhookId uint64) (web.WebhookResponse, error) {
+	ctx, span := startSpan(ctx, "WebhookService.FindById", idAttribute("webhook", webhookId))
+	defer span.End()
+
+	webhook, err := service.findWebhook(ctx, webhookId)
+	if err != nil {
+		return web.WebhookResponse{}, err
+	}
+
+	return helper.ToWebhookResponse(webhook), nil
+}
+
+// Find All Webhooks
+func (service *WebhookServiceImpl) FindAll(ctx context.Context) ([]web.WebhookResponse, error) {
+	ctx, span := startSpan(ctx, "WebhookService.FindAll")
+	defer span.End()
+
+	webhooks, err := service.WebhookRepository.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	span.SetAttributes(countAttribute(len(webhooks)))
+	return helper.ToWebhookResponses(webhooks), nil
+}
+
+// Find the most recent Deliveries of a Webhook
+func (service *WebhookServiceImpl) FindDeliveries(ctx context.Context, webhookId uint64) ([]web.WebhookDeliveryResponse, error) {
+	ctx, span := startSpan(ctx, "WebhookService.FindDeliveries", idAttribute("webhook", webhookId))
+	defer span.End()
+
+	if _, err := service.findWebhook(ctx, webhookId); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := service.WebhookRepository.FindDeliveries(ctx, webhookId, webhookDeliveryLogLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	span.SetAttributes(countAttribute(len(deliveries)))
+	return helper.ToWebhookDeliveryResponses(deliveries), nil
+}
+
+// Redeliver queues a Delivery again with a fresh set of attempts, e.g. once a dead-lettered endpoint is fixed
+func (service *WebhookServiceImpl) Redeliver(ctx context.Context, webhookId uint64, deliveryId uint64) (web.WebhookDeliveryResponse, error) {
+	ctx, span := startSpan(ctx, "WebhookService.Redeliver", idAttribute("webhook", webhookId), idAttribute("delivery", deliveryId))
+	defer span.End()
+
+	delivery, err := service.WebhookRepository.FindDeliveryById(ctx, deliveryId)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && delivery.WebhookId != webhookId) {
+		return web.WebhookDeliveryResponse{}, exception.NewNotFoundError("Delivery not found")
+	} else if err != nil {
+		return web.WebhookDeliveryResponse{}, err
+	}
+	if delivery.Status == domain.DeliveryPending {
+		return web.WebhookDeliveryResponse{}, exception.NewBadRequestError("Delivery is still pending")
+	}
+
+	delivery.Status = domain.DeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = time.Now()
+	delivery.LastStatusCode = 0
+	delivery.LastError = ""
+	delivery.DeliveredAt = nil
+	if err := service.WebhookRepository.UpdateDelivery(ctx, delivery); err != nil {
+		return web.WebhookDeliveryResponse{}, err
+	}
+
+	return helper.ToWebhookDeliveryResponse(delivery), nil
+}
+
+func (service *WebhookServiceImpl) findWebhook(ctx context.Context, webhookId uint64) (domain.Webhook, error) {
+	webhook, err := service.WebhookRepository.FindById(ctx, webhookId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.Webhook{}, exception.NewNotFoundError("Webhook not found")
+	}
+	return webhook, err
+}
+
+// newWebhookSecret returns 32 random bytes, hex encoded
+func newWebhookSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
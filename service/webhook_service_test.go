@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"github.com/aronipurwanto/go-restful-api/exception"
+	"github.com/aronipurwanto/go-restful-api/model/domain"
+	"github.com/aronipurwanto/go-restful-api/model/web"
+	"github.com/aronipurwanto/go-restful-api/repository/mocks"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+	"testing"
+)
+
+func TestCreateWebhook(t *testing.T) {
+	inactive := false
+
+	tests := []struct {
+		name      string
+		request   web.WebhookCreateRequest
+		mock      func(mockRepo *mocks.MockWebhookRepository)
+		expectErr bool
+	}{
+		{
+			name:    "Generates Secret",
+			request: web.WebhookCreateRequest{Url: "https://shop.example.com/hooks", Events: []string{"PriceChanged", "StockChanged"}},
+			mock: func(mockRepo *mocks.MockWebhookRepository) {
+				mockRepo.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, webhook domain.Webhook) (domain.Webhook, error) {
+					assert.Len(t, webhook.Secret, 64)
+					assert.Equal(t, "PriceChanged,StockChanged", webhook.Events)
+					assert.True(t, webhook.Active)
+					webhook.Id = 1
+					return webhook, nil
+				})
+			},
+		},
+		{
+			name:    "Inactive With Own Secret",
+			request: web.WebhookCreateRequest{Url: "https://shop.example.com/hooks", Secret: "0123456789abcdef", Active: &inactive},
+			mock: func(mockRepo *mocks.MockWebhookRepository) {
+				mockRepo.EXPECT().Save(gomock.Any(), domain.Webhook{Url: "https://shop.example.com/hooks", Secret: "0123456789abcdef"}).
+					Return(domain.Webhook{Id: 2, Url: "https://shop.example.com/hooks", Secret: "0123456789abcdef"}, nil)
+			},
+		},
+		{
+			name:      "Unknown Event",
+			request:   web.WebhookCreateRequest{Url: "https://shop.example.com/hooks", Events: []string{"OrderShipped"}},
+			mock:      func(mockRepo *mocks.MockWebhookRepository) {},
+			expectErr: true,
+		},
+		{
+			name:      "Invalid Url",
+			request:   web.WebhookCreateRequest{Url: "not a url"},
+			mock:      func(mockRepo *mocks.MockWebhookRepository) {},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockRepo := mocks.NewMockWebhookRepository(ctrl)
+			tt.mock(mockRepo)
+
+			service := NewWebhookService(mockRepo, validator.New())
+			result, err := service.Create(context.Background(), tt.request)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotEmpty(t, result.Secret)
+		})
+	}
+}
+
+func TestUpdateWebhookKeepsSecret(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := mocks.NewMockWebhookRepository(ctrl)
+
+	stored := domain.Webhook{Id: 1, Url: "https://old.example.com", Secret: "0123456789abcdef", Events: "PriceChanged", Active: true}
+	mockRepo.EXPECT().FindById(gomock.Any(), uint64(1)).Return(stored, nil)
+	mockRepo.EXPECT().Update(gomock.Any(), domain.Webhook{Id: 1, Url: "https://new.example.com", Secret: "0123456789abcdef", Active: true}).
+		DoAndReturn(func(ctx context.Context, webhook domain.Webhook) (domain.Webhook, error) {
+			return webhook, nil
+		})
+
+	service := NewWebhookService(mockRepo, validator.New())
+	result, err := service.Update(context.Background(), web.WebhookUpdateRequest{Id: 1, Url: "https://new.example.com"})
+	assert.NoError(t, err)
+	assert.Empty(t, result.Secret)
+	assert.Nil(t, result.Events)
+}
+
+func TestRedeliverWebhook(t *testing.T) {
+	tests := []struct {
+		name string
+		mock func(mockRepo *mocks.MockWebhookRepository)
+		err  error
+	}{
+		{
+			name: "Dead Delivery Is Queued Again",
+			mock: func(mockRepo *mocks.MockWebhookRepository) {
+				mockRepo.EXPECT().FindDeliveryById(gomock.Any(), uint64(3)).
+					Return(domain.WebhookDelivery{Id: 3, WebhookId: 1, Status: domain.DeliveryDead, Attempts: 8, LastError: "endpoint answered 500"}, nil)
+				mockRepo.EXPECT().UpdateDelivery(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, delivery domain.WebhookDelivery) error {
+					assert.Equal(t, domain.DeliveryPending, delivery.Status)
+					assert.Zero(t, delivery.Attempts)
+					assert.Empty(t, delivery.LastError)
+					return nil
+				})
+			},
+		},
+		{
+			name: "Still Pending",
+			mock: func(mockRepo *mocks.MockWebhookRepository) {
+				mockRepo.EXPECT().FindDeliveryById(gomock.Any(), uint64(3)).
+					Return(domain.WebhookDelivery{Id: 3, WebhookId: 1, Status: domain.DeliveryPending}, nil)
+			},
+			err: exception.NewBadRequestError("Delivery is still pending"),
+		},
+		{
+			name: "Delivery Of Another Webhook",
+			mock: func(mockRepo *mocks.MockWebhookRepository) {
+				mockRepo.EXPECT().FindDeliveryById(gomock.Any(), uint64(3)).
+					Return(domain.WebhookDelivery{Id: 3, WebhookId: 2, Status: domain.DeliveryDead}, nil)
+			},
+			err: exception.NewNotFoundError("Delivery not found"),
+		},
+		{
+			name: "Not Found",
+			mock: func(mockRepo *mocks.MockWebhookRepository) {
+				mockRepo.EXPECT().FindDeliveryById(gomock.Any(), uint64(3)).Return(domain.WebhookDelivery{}, gorm.ErrRecordNotFound)
+			},
+			err: exception.NewNotFoundError("Delivery not found"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockRepo := mocks.NewMockWebhookRepository(ctrl)
+			tt.mock(mockRepo)
+
+			service := NewWebhookService(mockRepo, validator.New())
+			_, err := service.Redeliver(context.Background(), 1, 3)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}